@@ -3,7 +3,7 @@
 This package provides:
 - DebugAdapter: Abstract base class for language-specific adapters
 - DAPClient: Low-level DAP protocol client
-- AdapterFactory: Factory for creating language-specific adapters
+- AdapterRegistry: Adapter descriptors and selection by name, extension or language
 - Language-specific adapters (debugpy for Python, etc.)
 """
 
@@ -16,10 +16,15 @@ from polybugger_mcp.adapters.base import (
 from polybugger_mcp.adapters.dap_client import DAPClient
 from polybugger_mcp.adapters.debugpy_adapter import DebugpyAdapter
 from polybugger_mcp.adapters.factory import (
+    AdapterDescriptor,
+    AdapterRegistry,
+    UnknownAdapterError,
     UnsupportedLanguageError,
+    adapter_registry,
     create_adapter,
     get_supported_languages,
     is_language_supported,
+    register,
     register_adapter,
 )
 
@@ -32,11 +37,16 @@ __all__ = [
     "AttachConfig",
     "Language",
     # Factory
+    "AdapterDescriptor",
+    "AdapterRegistry",
+    "adapter_registry",
     "create_adapter",
+    "register",
     "register_adapter",
     "get_supported_languages",
     "is_language_supported",
     "UnsupportedLanguageError",
+    "UnknownAdapterError",
     # Adapters
     "DebugpyAdapter",
 ]
@@ -5,21 +5,42 @@ must implement. The abstraction allows supporting multiple languages
 (Python, Node.js, Go, etc.) through the same MCP interface.
 """
 
+import asyncio
+import contextlib
+import os
+import signal
 from abc import ABC, abstractmethod
+from collections import deque
 from collections.abc import Callable, Coroutine
 from dataclasses import dataclass
 from enum import Enum
 from typing import Any
 
+from polybugger_mcp.adapters.dap_client import DAPClient, connect_tcp
+from polybugger_mcp.adapters.renderers.base import ValueRenderer
+from polybugger_mcp.core.exceptions import (
+    AdapterExitedError,
+    CapabilityNotSupportedError,
+    DAPConnectionError,
+    ServerShuttingDownError,
+    StdinUnavailableError,
+)
 from polybugger_mcp.models.dap import (
     Breakpoint,
     Scope,
     SourceBreakpoint,
     StackFrame,
+    TcpTransport,
     Thread,
     Variable,
 )
 from polybugger_mcp.models.events import EventType
+from polybugger_mcp.utils.processes import (
+    register_process_group,
+    signal_group,
+    stop_process,
+    unregister_process_group,
+)
 
 
 class Language(str, Enum):
@@ -48,7 +69,7 @@ class LaunchConfig:
     program: str | None = None
     args: list[str] | None = None
     cwd: str | None = None
-    env: dict[str, str] | None = None
+    env: dict[str, str | None] | None = None  # None values unset inherited variables
     stop_on_entry: bool = False
 
     # Language-specific options (passed through to adapter)
@@ -84,6 +105,32 @@ class DebugAdapter(ABC):
     The DAPClient handles the low-level protocol communication.
     """
 
+    # Whether the adapter can hand the debuggee's start to us (DAP runInTerminal),
+    # which is what makes its stdin writable
+    supports_stdin_pipe: bool = False
+
+    # Whether steps that stop on another thread are re-issued on the stepped
+    # one by default (see Session.step_over); for goroutine schedulers
+    sticky_steps: bool = False
+
+    # Whether the adapter honours LaunchConfig.just_my_code itself; otherwise
+    # the session filters stdlib and third-party code out of steps
+    supports_just_my_code: bool = False
+
+    # Whether launches stop at entry so breakpoints are sent before any user
+    # code runs; the session resumes that stop unless stop_on_entry was asked
+    # for. For adapters whose programs can finish before configurationDone
+    launch_stops_at_entry: bool = False
+
+    # Structured value rendering (render="summary"/"json"); raw text only by default
+    renderer: ValueRenderer = ValueRenderer()
+
+    # stderr lines kept from the adapter process for crash reports
+    STDERR_TAIL_LINES = 20
+
+    # Seconds an adapter process we started has to start listening
+    LOCAL_CONNECT_TIMEOUT = 10.0
+
     def __init__(
         self,
         session_id: str,
@@ -102,6 +149,20 @@ class DebugAdapter(ABC):
         self._output_callback = output_callback
         self._event_callback = event_callback
 
+        # Debuggee process we started for a runInTerminal request
+        self._terminal_process: asyncio.subprocess.Process | None = None
+        self._stdin_mode = "inherit"
+
+        # Adapter process we started, if any (see _supervise)
+        self._process: asyncio.subprocess.Process | None = None
+        self.stderr_tail: deque[str] = deque(maxlen=self.STDERR_TAIL_LINES)
+        self._stderr_task: asyncio.Task[None] | None = None
+        self._exit_reported = False
+
+        # Set before initialize() to connect to a DAP server already listening
+        # there instead of starting the adapter (the "tcp" session transport)
+        self.remote: TcpTransport | None = None
+
     @property
     @abstractmethod
     def language(self) -> Language:
@@ -217,15 +278,15 @@ class DebugAdapter(ABC):
     @abstractmethod
     async def set_function_breakpoints(
         self,
-        names: list[str],
+        breakpoints: list[dict[str, Any]],
     ) -> list[Breakpoint]:
-        """Set breakpoints on function names.
+        """Replace all function breakpoints (requires supportsFunctionBreakpoints).
 
         Args:
-            names: List of function names
+            breakpoints: DAP FunctionBreakpoint objects (name, condition, hitCondition)
 
         Returns:
-            List of actual breakpoints
+            One result per requested function breakpoint
         """
         ...
 
@@ -233,11 +294,16 @@ class DebugAdapter(ABC):
     async def set_exception_breakpoints(
         self,
         filters: list[str],
-    ) -> None:
+        filter_options: list[dict[str, Any]] | None = None,
+    ) -> list[Breakpoint]:
         """Configure exception breakpoints.
 
         Args:
             filters: Exception filter IDs (language-specific)
+            filter_options: Filters with conditions ({"filterId", "condition"})
+
+        Returns:
+            Per-filter results, in the order filters then filter_options
         """
         ...
 
@@ -340,6 +406,7 @@ class DebugAdapter(ABC):
         variables_reference: int,
         start: int = 0,
         count: int = 0,
+        filter: str | None = None,
     ) -> list[Variable]:
         """Get variables for a scope or container.
 
@@ -347,6 +414,7 @@ class DebugAdapter(ABC):
             variables_reference: Reference from scope or variable
             start: Starting index (for paging)
             count: Maximum to return (0 = all)
+            filter: "indexed" or "named" to limit the children (None = both)
 
         Returns:
             List of variables
@@ -376,36 +444,672 @@ class DebugAdapter(ABC):
     # Optional Methods (default implementations)
     # =========================================================================
 
+    async def send_request(
+        self,
+        command: str,
+        arguments: dict[str, Any] | None = None,
+        timeout: float | None = None,
+    ) -> dict[str, Any]:
+        """Send a raw DAP request to the debug adapter.
+
+        Optional features that map onto a single DAP request are built on
+        this, gated by the adapter's advertised capabilities.
+
+        Args:
+            command: DAP command name
+            arguments: Command arguments
+            timeout: Request timeout (uses default if not specified)
+
+        Returns:
+            Response body dictionary
+        """
+        client = self._require_initialized()
+        return await client.send_request(command, arguments, timeout)
+
+    def _require_initialized(self) -> DAPClient:
+        """Return the DAP client, raising DAPConnectionError before initialize."""
+        raise NotImplementedError(f"{type(self).__name__} does not support raw DAP requests")
+
+    async def _dispatch_event(self, event_type: str, body: dict[str, Any]) -> None:
+        """DAPClient event callback: handle events common to all adapters.
+
+        Everything else goes to the adapter's _handle_event.
+        """
+        # Capabilities can change after launch (e.g. reverse execution under rr)
+        if event_type == "capabilities":
+            self.capabilities.update(body.get("capabilities", {}))
+            return
+        await self._handle_event(event_type, body)
+
+    async def _handle_event(self, event_type: str, body: dict[str, Any]) -> None:
+        """Handle a DAP event from the adapter."""
+
+    async def step_back(self, thread_id: int) -> None:
+        """Step backwards one line (requires supportsStepBack).
+
+        Args:
+            thread_id: Thread to step
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't reverse execute
+        """
+        if not self.capabilities.get("supportsStepBack"):
+            raise CapabilityNotSupportedError("supportsStepBack", "reverse execution")
+        await self.send_request("stepBack", {"threadId": thread_id})
+
+    async def reverse_continue(self, thread_id: int) -> None:
+        """Run backwards to the previous breakpoint (requires supportsStepBack).
+
+        Args:
+            thread_id: Thread to continue
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't reverse execute
+        """
+        if not self.capabilities.get("supportsStepBack"):
+            raise CapabilityNotSupportedError("supportsStepBack", "reverse execution")
+        await self.send_request("reverseContinue", {"threadId": thread_id})
+
+    async def evaluate_full(self, expression: str, frame_id: int | None = None) -> str:
+        """Evaluate an expression and return its complete, untruncated text.
+
+        Uses the "clipboard" context where advertised, which adapters render
+        without their usual length limits. Adapters whose plain rendering is
+        truncated override this with a language-specific expression.
+
+        Args:
+            expression: Expression to evaluate
+            frame_id: Stack frame context
+
+        Returns:
+            Full rendered value
+        """
+        context = "clipboard" if self.capabilities.get("supportsClipboardContext") else "repl"
+        result = await self.evaluate(expression, frame_id, context)
+        return str(result.get("result", ""))
+
+    async def data_breakpoint_info(
+        self,
+        name: str,
+        variables_ref: int | None = None,
+        frame_id: int | None = None,
+    ) -> dict[str, Any]:
+        """Ask whether a variable or expression can be watched (requires supportsDataBreakpoints).
+
+        Args:
+            name: Variable name in variables_ref, or an expression when
+                variables_ref is omitted
+            variables_ref: Container holding the variable
+            frame_id: Frame to evaluate an expression in
+
+        Returns:
+            DAP DataBreakpointInfo response body (dataId, description, accessTypes)
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter has no data breakpoints
+        """
+        if not self.capabilities.get("supportsDataBreakpoints"):
+            raise CapabilityNotSupportedError("supportsDataBreakpoints", "data breakpoints")
+        args: dict[str, Any] = {"name": name}
+        if variables_ref is not None:
+            args["variablesReference"] = variables_ref
+        if frame_id is not None:
+            args["frameId"] = frame_id
+        return await self.send_request("dataBreakpointInfo", args)
+
+    async def set_data_breakpoints(self, breakpoints: list[dict[str, Any]]) -> list[Breakpoint]:
+        """Replace all data breakpoints (requires supportsDataBreakpoints).
+
+        Args:
+            breakpoints: DAP DataBreakpoint objects (dataId, accessType, condition, ...)
+
+        Returns:
+            One result per requested data breakpoint
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter has no data breakpoints
+        """
+        if not self.capabilities.get("supportsDataBreakpoints"):
+            raise CapabilityNotSupportedError("supportsDataBreakpoints", "data breakpoints")
+        body = await self.send_request("setDataBreakpoints", {"breakpoints": breakpoints})
+        return [Breakpoint(**bp) for bp in body.get("breakpoints", [])]
+
+    async def restart(self) -> None:
+        """Restart the debuggee in place (requires supportsRestartRequest).
+
+        The adapter keeps the DAP connection and relaunches with the original
+        launch arguments.
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't restart natively
+        """
+        if not self.capabilities.get("supportsRestartRequest"):
+            raise CapabilityNotSupportedError("supportsRestartRequest", "restart")
+        await self.send_request("restart", {})
+
+    async def set_variable(
+        self,
+        variables_ref: int,
+        name: str,
+        value: str,
+    ) -> dict[str, Any]:
+        """Assign a new value to a variable in a container (requires supportsSetVariable).
+
+        Args:
+            variables_ref: Reference of the scope or variable holding it
+            name: Variable name within that container
+            value: New value as a source literal
+
+        Returns:
+            DAP SetVariable response body (value, type, variablesReference, ...)
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't set variables
+        """
+        if not self.capabilities.get("supportsSetVariable"):
+            raise CapabilityNotSupportedError("supportsSetVariable", "setting variables")
+        return await self.send_request(
+            "setVariable",
+            {"variablesReference": variables_ref, "name": name, "value": value},
+        )
+
+    async def set_expression(
+        self,
+        expression: str,
+        value: str,
+        frame_id: int | None = None,
+    ) -> dict[str, Any]:
+        """Assign a new value to an assignable expression (requires supportsSetExpression).
+
+        Args:
+            expression: Assignable expression, e.g. "obj.items[3]"
+            value: New value as a source literal
+            frame_id: Stack frame context
+
+        Returns:
+            DAP SetExpression response body (value, type, variablesReference, ...)
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't set expressions
+        """
+        if not self.capabilities.get("supportsSetExpression"):
+            raise CapabilityNotSupportedError("supportsSetExpression", "setting expressions")
+        args: dict[str, Any] = {"expression": expression, "value": value}
+        if frame_id is not None:
+            args["frameId"] = frame_id
+        return await self.send_request("setExpression", args)
+
+    async def step_instruction(self, kind: str, thread_id: int) -> None:
+        """Step a single machine instruction.
+
+        Args:
+            kind: "over", "into" or "out"
+            thread_id: Thread to step
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't step by instruction
+        """
+        if not self.capabilities.get("supportsSteppingGranularity"):
+            raise CapabilityNotSupportedError("supportsSteppingGranularity", "instruction stepping")
+        command = {"over": "next", "into": "stepIn", "out": "stepOut"}[kind]
+        await self.send_request(command, {"threadId": thread_id, "granularity": "instruction"})
+
+    async def disassemble(
+        self,
+        memory_reference: str,
+        instruction_offset: int = 0,
+        instruction_count: int = 20,
+    ) -> list[dict[str, Any]]:
+        """Disassemble code around a memory reference.
+
+        Args:
+            memory_reference: Address to start from (e.g. a frame's
+                instructionPointerReference)
+            instruction_offset: Instructions to move from the reference first
+                (negative to include code before it)
+            instruction_count: Instructions to return
+
+        Returns:
+            DAP DisassembledInstruction objects
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't disassemble
+        """
+        if not self.capabilities.get("supportsDisassembleRequest"):
+            raise CapabilityNotSupportedError("supportsDisassembleRequest", "disassembly")
+        body = await self.send_request(
+            "disassemble",
+            {
+                "memoryReference": memory_reference,
+                "instructionOffset": instruction_offset,
+                "instructionCount": instruction_count,
+                "resolveSymbols": True,
+            },
+        )
+        return list(body.get("instructions") or [])
+
+    async def read_memory(
+        self,
+        memory_reference: str,
+        offset: int = 0,
+        count: int = 256,
+    ) -> dict[str, Any]:
+        """Read bytes of the debuggee's memory.
+
+        Args:
+            memory_reference: Address or reference from a variable/evaluate result
+            offset: Bytes to move from the reference first (may be negative)
+            count: Bytes to read
+
+        Returns:
+            DAP ReadMemory response body: address, data (base64, absent when
+            nothing could be read) and unreadableBytes past what was read
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't read memory
+        """
+        if not self.capabilities.get("supportsReadMemoryRequest"):
+            raise CapabilityNotSupportedError("supportsReadMemoryRequest", "reading memory")
+        return await self.send_request(
+            "readMemory",
+            {"memoryReference": memory_reference, "offset": offset, "count": count},
+        )
+
+    async def write_memory(
+        self,
+        memory_reference: str,
+        data: str,
+        offset: int = 0,
+        allow_partial: bool = False,
+    ) -> dict[str, Any]:
+        """Write bytes into the debuggee's memory.
+
+        Args:
+            memory_reference: Address or reference from a variable/evaluate result
+            data: Bytes to write, base64-encoded
+            offset: Bytes to move from the reference first (may be negative)
+            allow_partial: Let the adapter write fewer bytes than given
+                instead of failing
+
+        Returns:
+            DAP WriteMemory response body (offset, bytesWritten when partial)
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't write memory
+        """
+        if not self.capabilities.get("supportsWriteMemoryRequest"):
+            raise CapabilityNotSupportedError("supportsWriteMemoryRequest", "writing memory")
+        return await self.send_request(
+            "writeMemory",
+            {
+                "memoryReference": memory_reference,
+                "offset": offset,
+                "data": data,
+                "allowPartial": allow_partial,
+            },
+        )
+
+    async def get_exception_info(self, thread_id: int) -> dict[str, Any]:
+        """Get details of the exception a thread stopped on (if supported).
+
+        Args:
+            thread_id: Thread that stopped with reason "exception"
+
+        Returns:
+            DAP ExceptionInfo response body, or {} if not supported
+        """
+        if not self.capabilities.get("supportsExceptionInfoRequest"):
+            return {}  # Default: not supported
+        return await self.send_request("exceptionInfo", {"threadId": thread_id})
+
+    async def get_exception_chain(
+        self,
+        thread_id: int,
+        frame_id: int | None,
+        max_depth: int,
+    ) -> list[dict[str, Any]]:
+        """Get the exception a thread stopped on and the exceptions behind it.
+
+        The default follows ExceptionInfo's innerException details. Adapters
+        that can see more of the chain (debugpy follows __cause__ and
+        __context__) override this.
+
+        Args:
+            thread_id: Thread that stopped with reason "exception"
+            frame_id: Top frame of that thread, if known
+            max_depth: Most exceptions to return, counted from the outermost
+
+        Returns:
+            Entries with type, message and frames ({name, file, line}),
+            innermost first; the raw stack_trace text when it can't be split
+            into frames
+        """
+        info = await self.get_exception_info(thread_id)
+        if not info:
+            return []
+
+        chain: list[dict[str, Any]] = []
+        details: dict[str, Any] | None = info.get("details") or {}
+        while details is not None and len(chain) < max_depth:
+            text = details.get("stackTrace")
+            entry: dict[str, Any] = {
+                "type": details.get("fullTypeName") or details.get("typeName"),
+                "message": details.get("message"),
+                "frames": self.parse_stack_trace(text) if text else [],
+            }
+            if text and not entry["frames"]:
+                entry["stack_trace"] = text
+            chain.append(entry)
+            inner = details.get("innerException") or []
+            details = inner[0] if inner else None
+
+        chain[0]["type"] = chain[0]["type"] or info.get("exceptionId")
+        chain[0]["message"] = chain[0]["message"] or info.get("description")
+        chain.reverse()
+        return chain
+
+    def parse_stack_trace(self, text: str) -> list[dict[str, Any]]:
+        """Split an ExceptionInfo stack trace into frames ({name, file, line}).
+
+        The text's format is adapter-specific; the default doesn't parse it.
+        """
+        return []
+
     async def get_completions(
         self,
         text: str,
         frame_id: int | None = None,
-        column: int = 0,
+        column: int | None = None,
     ) -> list[dict[str, Any]]:
         """Get code completions (if supported).
 
         Args:
             text: Text to complete
             frame_id: Stack frame context
-            column: Cursor column position
+            column: 1-based cursor column in text (default: end of text)
 
         Returns:
-            List of completion items
+            DAP CompletionItem objects, or [] if not supported
         """
-        return []  # Default: no completions
+        if not self.capabilities.get("supportsCompletionsRequest"):
+            return []  # Default: no completions
+        args: dict[str, Any] = {
+            "text": text,
+            "column": column if column is not None else len(text) + 1,
+        }
+        if frame_id is not None:
+            args["frameId"] = frame_id
+        body = await self.send_request("completions", args)
+        return list(body.get("targets") or [])
 
     async def get_loaded_sources(self) -> list[dict[str, Any]]:
         """Get list of loaded source files (if supported).
 
         Returns:
-            List of source references
+            List of DAP Source objects, or [] if not supported
         """
-        return []  # Default: not supported
+        if not self.capabilities.get("supportsLoadedSourcesRequest"):
+            return []  # Default: not supported
+        body = await self.send_request("loadedSources", {})
+        return list(body.get("sources") or [])
+
+    async def get_source(self, source_reference: int) -> dict[str, Any]:
+        """Get the content of a source that has no file (DAP Source request).
+
+        Args:
+            source_reference: sourceReference of a frame or loaded source
+
+        Returns:
+            Response body with "content" and optionally "mimeType"
+        """
+        return await self.send_request(
+            "source",
+            {"source": {"sourceReference": source_reference}, "sourceReference": source_reference},
+        )
 
     async def get_modules(self) -> list[dict[str, Any]]:
         """Get loaded modules/libraries (if supported).
 
         Returns:
-            List of module info
+            List of DAP Module objects, or [] if not supported
+        """
+        if not self.capabilities.get("supportsModulesRequest"):
+            return []  # Default: not supported
+        body = await self.send_request("modules", {})
+        return list(body.get("modules") or [])
+
+    # =========================================================================
+    # Debuggee stdin (via runInTerminal)
+    # =========================================================================
+
+    def _launch_in_terminal(self, client: DAPClient, stdin_mode: str) -> None:
+        """Answer the adapter's runInTerminal request by starting the debuggee ourselves.
+
+        Adapters call this before a launch that asks for a terminal console.
+
+        Args:
+            client: DAP client for the session
+            stdin_mode: "pipe" to keep a writable stdin, "closed" for /dev/null
+        """
+        self._stdin_mode = stdin_mode
+        client.set_request_handler("runInTerminal", self._run_in_terminal)
+
+    async def _run_in_terminal(self, arguments: dict[str, Any]) -> dict[str, Any]:
+        """Start the process described by a runInTerminal request.
+
+        Program output still arrives as DAP output events, so only stdin
+        is connected.
+        """
+        argv = [str(a) for a in arguments.get("args") or []]
+        if not argv:
+            raise ValueError("runInTerminal request has no command")
+
+        env = dict(os.environ)
+        for key, value in (arguments.get("env") or {}).items():
+            if value is None:
+                env.pop(key, None)  # null means unset
+            else:
+                env[key] = str(value)
+
+        stdin = (
+            asyncio.subprocess.PIPE if self._stdin_mode == "pipe" else asyncio.subprocess.DEVNULL
+        )
+        options: dict[str, Any] = {
+            "cwd": arguments.get("cwd") or None,
+            "env": env,
+            "stdin": stdin,
+            "stdout": asyncio.subprocess.DEVNULL,
+            "stderr": asyncio.subprocess.DEVNULL,
+            "start_new_session": True,  # Own process group, see stop_process
+        }
+        if arguments.get("argsCanBeInterpretedByShell"):
+            process = await asyncio.create_subprocess_shell(" ".join(argv), **options)
+        else:
+            process = await asyncio.create_subprocess_exec(*argv, **options)
+
+        self._terminal_process = process
+        register_process_group(process)
+        return {"processId": process.pid}
+
+    async def write_stdin(self, data: bytes) -> None:
+        """Write to the debuggee's stdin.
+
+        Args:
+            data: Bytes to write
+
+        Raises:
+            StdinUnavailableError: If we don't own a stdin pipe, or it's closed
+        """
+        process = self._terminal_process
+        if process is None or process.stdin is None:
+            raise StdinUnavailableError(
+                self.session_id, "the program was not started with a stdin pipe"
+            )
+        if process.returncode is not None:
+            raise StdinUnavailableError(self.session_id, "the program has exited")
+        try:
+            process.stdin.write(data)
+            await process.stdin.drain()
+        except (BrokenPipeError, ConnectionResetError):
+            raise StdinUnavailableError(self.session_id, "the program closed its stdin")
+
+    async def _close_terminal_process(self, terminate: bool) -> None:
+        """Release the runInTerminal process, stopping it if requested."""
+        process = self._terminal_process
+        self._terminal_process = None
+        if process is None:
+            return
+        if process.stdin is not None:
+            process.stdin.close()
+        if terminate:
+            await stop_process(process)
+        else:
+            unregister_process_group(process)
+
+    # =========================================================================
+    # Adapter process supervision
+    # =========================================================================
+
+    async def _connect_local(
+        self, port: int, name: str
+    ) -> tuple[asyncio.StreamReader, asyncio.StreamWriter]:
+        """Connect to the adapter process just started, listening on port.
+
+        The attempt is abandoned as soon as the process exits, quoting its
+        stderr, rather than retrying until the timeout.
+
+        Raises:
+            DAPConnectionError: If it isn't reachable within LOCAL_CONNECT_TIMEOUT
+        """
+        process = self._process
+        assert process is not None
+        connect = asyncio.ensure_future(
+            connect_tcp("127.0.0.1", port, timeout=self.LOCAL_CONNECT_TIMEOUT)
+        )
+        exited = asyncio.ensure_future(process.wait())
+        try:
+            await asyncio.wait({connect, exited}, return_when=asyncio.FIRST_COMPLETED)
+        finally:
+            exited.cancel()
+        if not connect.done():
+            connect.cancel()
+            with contextlib.suppress(asyncio.CancelledError, DAPConnectionError):
+                await connect
+            if self._stderr_task is not None:
+                with contextlib.suppress(asyncio.TimeoutError):
+                    await asyncio.wait_for(asyncio.shield(self._stderr_task), timeout=1.0)
+            stderr = "\n".join(self.stderr_tail)[:500]
+            raise DAPConnectionError(
+                f"{name} process exited with code {process.returncode}: {stderr}"
+            )
+        return connect.result()
+
+    async def _connect_transport(self) -> tuple[asyncio.StreamReader, asyncio.StreamWriter]:
+        """Connect to the remote DAP server, retrying until it listens.
+
+        Raises:
+            DAPConnectionError: If it isn't reachable within the connect timeout
+        """
+        remote = self.remote
+        assert remote is not None
+        return await connect_tcp(
+            remote.host,
+            remote.port,
+            timeout=remote.connect_timeout,
+            retries=remote.connect_retries,
+        )
+
+    async def _send_disconnect(self, client: DAPClient, terminate: bool) -> None:
+        """Send the disconnect request, ignoring failures.
+
+        A remote adapter is left alone unless terminating: disconnect would end
+        its debug session (dlv dap exits on it), so only the connection closes.
+        """
+        if self.remote is not None and not terminate:
+            return
+        with contextlib.suppress(Exception):
+            await client.send_request("disconnect", {"terminateDebuggee": terminate}, timeout=5.0)
+
+    async def shutdown(self, terminate: bool, grace_seconds: float) -> None:
+        """Disconnect because the server is stopping, within a deadline.
+
+        Requests still awaiting a response fail with ServerShuttingDownError
+        instead of waiting on an adapter that is going away. The adapter gets
+        grace_seconds to handle the disconnect; after that, when terminating,
+        the process groups we started are killed whatever state they're in.
+        Without terminate only our adapter process goes, never the debuggee.
+        """
+        adapter_process = self._process
+        processes = [p for p in (adapter_process, self._terminal_process) if p is not None]
+        client = getattr(self, "_client", None)
+        if isinstance(client, DAPClient):
+            client.fail_pending(ServerShuttingDownError())
+        with contextlib.suppress(asyncio.TimeoutError):
+            await asyncio.wait_for(self.disconnect(terminate=terminate), timeout=grace_seconds)
+        for process in processes:
+            unregister_process_group(process)
+            if terminate:
+                signal_group(process, signal.SIGKILL)
+            elif process is adapter_process and process.returncode is None:
+                with contextlib.suppress(ProcessLookupError):
+                    process.kill()
+
+    def _supervise(self, process: asyncio.subprocess.Process) -> None:
+        """Watch an adapter process just started with start_new_session.
+
+        Its group is registered for cleanup at exit and its stderr is drained
+        into stderr_tail, which crash reports quote.
+        """
+        self._process = process
+        self._exit_reported = False
+        self.stderr_tail.clear()
+        register_process_group(process)
+        if process.stderr is not None:
+            self._stderr_task = asyncio.create_task(self._drain_stderr(process.stderr))
+
+    async def _drain_stderr(self, stream: asyncio.StreamReader) -> None:
+        """Keep the last stderr lines of the adapter process."""
+        while line := await stream.readline():
+            self.stderr_tail.append(line.decode("utf-8", errors="replace").rstrip())
+
+    async def _stop_adapter_process(self, terminate: bool = True) -> None:
+        """Stop the adapter process we started, and its group when terminating."""
+        process, self._process = self._process, None
+        if process is not None:
+            await stop_process(process, kill_group=terminate)
+        if self._stderr_task is not None:
+            self._stderr_task.cancel()
+            self._stderr_task = None
+
+    async def _connection_lost(self, reason: str) -> None:
+        """DAPClient disconnect callback: the adapter died or hung up.
+
+        Waits briefly for the exit code and the rest of stderr, fails the
+        client's requests with them and reports EventType.SESSION_ENDED.
         """
-        return []  # Default: not supported
+        if self._exit_reported:
+            return
+        self._exit_reported = True
+        exit_code: int | None = None
+        if self._process is not None:
+            with contextlib.suppress(asyncio.TimeoutError):
+                exit_code = await asyncio.wait_for(self._process.wait(), timeout=1.0)
+        if self._stderr_task is not None:
+            with contextlib.suppress(asyncio.TimeoutError, asyncio.CancelledError):
+                await asyncio.wait_for(asyncio.shield(self._stderr_task), timeout=0.5)
+
+        stderr = list(self.stderr_tail)[-5:]
+        error = AdapterExitedError(reason, exit_code, stderr)
+        client = getattr(self, "_client", None)
+        if isinstance(client, DAPClient):
+            client.fail(error)
+        if self._event_callback:
+            await self._event_callback(
+                EventType.SESSION_ENDED,
+                {
+                    "message": error.message,
+                    "reason": reason,
+                    "exit_code": exit_code,
+                    "stderr": stderr,
+                },
+            )
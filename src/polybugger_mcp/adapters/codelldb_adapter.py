@@ -123,7 +123,7 @@ class RustLaunchConfig:
     program: str | None = None  # Path to compiled executable
     args: list[str] | None = None
     cwd: str = "."
-    env: dict[str, str] | None = None
+    env: dict[str, str | None] | None = None
     stop_on_entry: bool = False
 
     # LLDB-specific options
@@ -147,9 +147,17 @@ class RustAttachConfig:
     wait_for: str | None = None  # Process name to wait for
 
 
-@register_adapter(Language.RUST)
-@register_adapter(Language.CPP)
-@register_adapter(Language.C)
+@register_adapter(
+    Language.RUST,
+    Language.CPP,
+    Language.C,
+    name="codelldb",
+    extensions=(".rs", ".c", ".cc", ".cpp", ".cxx"),
+    find_binary=lambda: _find_codelldb()[0],
+    install_hint="Install the vadimcn.vscode-lldb extension or lldb-dap from LLVM",
+    # Programs are compiled executables rather than source files
+    quirks=frozenset({"compiled_programs"}),
+)
 class CodeLLDBAdapter(DebugAdapter):
     """Debug adapter for Rust/C/C++ using CodeLLDB.
 
@@ -167,7 +175,6 @@ class CodeLLDBAdapter(DebugAdapter):
         """Initialize the adapter."""
         super().__init__(session_id, output_callback, event_callback)
 
-        self._process: asyncio.subprocess.Process | None = None
         self._client: DAPClient | None = None
         self._reader: asyncio.StreamReader | None = None
         self._writer: asyncio.StreamWriter | None = None
@@ -202,64 +209,17 @@ class CodeLLDBAdapter(DebugAdapter):
         return self._client
 
     async def initialize(self) -> dict[str, Any]:
-        """Start CodeLLDB or lldb-dap and initialize DAP connection.
+        """Start CodeLLDB or lldb-dap (or connect to a remote one) and initialize DAP.
 
         Returns:
             Debug adapter capabilities
         """
-        # Find CodeLLDB or lldb-dap
-        self._codelldb_path, self._adapter_type = _find_codelldb()
-        if not self._codelldb_path:
-            raise DAPConnectionError(
-                "CodeLLDB or lldb-dap not found. Install options:\n"
-                "1. VS Code extension: vadimcn.vscode-lldb\n"
-                "2. From source: https://github.com/vadimcn/codelldb\n"
-                "3. Install lldb-dap from LLVM: apt install lldb-17"
-            )
-
         try:
-            if self._adapter_type == "codelldb":
-                # CodeLLDB accepts --port to start in multi-session server mode
-                self._port = _get_free_port()
-                self._process = await asyncio.create_subprocess_exec(
-                    self._codelldb_path,
-                    "--port",
-                    str(self._port),
-                    stdin=asyncio.subprocess.DEVNULL,
-                    stdout=asyncio.subprocess.PIPE,
-                    stderr=asyncio.subprocess.PIPE,
-                )
-
-                # Wait for the server to start
-                await asyncio.sleep(0.5)
-
-                # Connect to the DAP server
-                for attempt in range(10):
-                    try:
-                        self._reader, self._writer = await asyncio.wait_for(
-                            asyncio.open_connection("127.0.0.1", self._port),
-                            timeout=2.0,
-                        )
-                        break
-                    except (ConnectionRefusedError, asyncio.TimeoutError):
-                        if attempt == 9:
-                            raise DAPConnectionError(
-                                f"Failed to connect to CodeLLDB on port {self._port}"
-                            )
-                        await asyncio.sleep(0.3)
+            if self.remote is not None:
+                self._port = self.remote.port
+                self._reader, self._writer = await self._connect_transport()
             else:
-                # lldb-dap uses stdin/stdout for DAP communication
-                self._process = await asyncio.create_subprocess_exec(
-                    self._codelldb_path,
-                    stdin=asyncio.subprocess.PIPE,
-                    stdout=asyncio.subprocess.PIPE,
-                    stderr=asyncio.subprocess.PIPE,
-                )
-                # Use process stdin/stdout as reader/writer
-                assert self._process.stdout is not None
-                assert self._process.stdin is not None
-                self._reader = self._process.stdout
-                self._writer = self._process.stdin
+                await self._start_adapter()
 
             # Create DAP client
             assert self._reader is not None
@@ -267,7 +227,8 @@ class CodeLLDBAdapter(DebugAdapter):
             self._client = DAPClient(
                 reader=self._reader,
                 writer=self._writer,
-                event_callback=self._handle_event,
+                event_callback=self._dispatch_event,
+                disconnect_callback=self._connection_lost,
             )
             await self._client.start()
 
@@ -283,6 +244,7 @@ class CodeLLDBAdapter(DebugAdapter):
                     "columnsStartAt1": True,
                     "supportsVariableType": True,
                     "supportsVariablePaging": True,
+                    "supportsMemoryReferences": True,
                     "supportsRunInTerminalRequest": False,
                     "supportsProgressReporting": False,
                 },
@@ -298,10 +260,55 @@ class CodeLLDBAdapter(DebugAdapter):
                 logger.info(f"Session {self.session_id}: {adapter_name} initialized via stdio")
             return self._capabilities
 
+        except DAPConnectionError:
+            await self._cleanup()
+            raise
         except Exception as e:
             await self._cleanup()
             raise DAPConnectionError(f"Failed to initialize LLDB adapter: {e}")
 
+    async def _start_adapter(self) -> None:
+        """Start CodeLLDB on a free port, or lldb-dap on stdio, and connect to it."""
+        # Find CodeLLDB or lldb-dap
+        self._codelldb_path, self._adapter_type = _find_codelldb()
+        if not self._codelldb_path:
+            raise DAPConnectionError(
+                "CodeLLDB or lldb-dap not found. Install options:\n"
+                "1. VS Code extension: vadimcn.vscode-lldb\n"
+                "2. From source: https://github.com/vadimcn/codelldb\n"
+                "3. Install lldb-dap from LLVM: apt install lldb-17"
+            )
+
+        if self._adapter_type == "codelldb":
+            # CodeLLDB accepts --port to start in multi-session server mode
+            self._port = _get_free_port()
+            self._process = await asyncio.create_subprocess_exec(
+                self._codelldb_path,
+                "--port",
+                str(self._port),
+                stdin=asyncio.subprocess.DEVNULL,
+                stdout=asyncio.subprocess.PIPE,
+                stderr=asyncio.subprocess.PIPE,
+                start_new_session=True,  # Own process group, so the debuggee goes with it
+            )
+            self._supervise(self._process)
+            self._reader, self._writer = await self._connect_local(self._port, "CodeLLDB")
+        else:
+            # lldb-dap uses stdin/stdout for DAP communication
+            self._process = await asyncio.create_subprocess_exec(
+                self._codelldb_path,
+                stdin=asyncio.subprocess.PIPE,
+                stdout=asyncio.subprocess.PIPE,
+                stderr=asyncio.subprocess.PIPE,
+                start_new_session=True,
+            )
+            self._supervise(self._process)
+            # Use process stdin/stdout as reader/writer
+            assert self._process.stdout is not None
+            assert self._process.stdin is not None
+            self._reader = self._process.stdout
+            self._writer = self._process.stdin
+
     async def launch(
         self,
         config: RustLaunchConfig | BaseLaunchConfig | Any,
@@ -449,19 +456,12 @@ class CodeLLDBAdapter(DebugAdapter):
     async def disconnect(self, terminate: bool = False) -> None:
         """Disconnect and cleanup."""
         if self._client:
-            try:
-                await self._client.send_request(
-                    "disconnect",
-                    {"terminateDebuggee": terminate},
-                    timeout=5.0,
-                )
-            except Exception:
-                pass
+            await self._send_disconnect(self._client, terminate)
 
-        await self._cleanup()
+        await self._cleanup(terminate)
 
-    async def _cleanup(self) -> None:
-        """Clean up resources."""
+    async def _cleanup(self, terminate: bool = True) -> None:
+        """Clean up resources, stopping the adapter's process group when terminating."""
         if self._client:
             await self._client.stop()
             self._client = None
@@ -475,15 +475,7 @@ class CodeLLDBAdapter(DebugAdapter):
             self._writer = None
             self._reader = None
 
-        if self._process:
-            # Check if process is still running before terminating
-            if self._process.returncode is None:
-                self._process.terminate()
-                try:
-                    await asyncio.wait_for(self._process.wait(), timeout=5.0)
-                except asyncio.TimeoutError:
-                    self._process.kill()
-            self._process = None
+        await self._stop_adapter_process(terminate)
 
         self._initialized = False
         self._launched = False
@@ -527,11 +519,13 @@ class CodeLLDBAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_function_breakpoints(self, names: list[str]) -> list[Breakpoint]:
-        """Set breakpoints on function names."""
+    async def set_function_breakpoints(
+        self,
+        breakpoints: list[dict[str, Any]],
+    ) -> list[Breakpoint]:
+        """Replace all function breakpoints."""
         client = self._require_initialized()
 
-        breakpoints = [{"name": name} for name in names]
         response = await client.send_request(
             "setFunctionBreakpoints",
             {"breakpoints": breakpoints},
@@ -539,20 +533,33 @@ class CodeLLDBAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_exception_breakpoints(self, filters: list[str]) -> None:
+    async def set_exception_breakpoints(
+        self,
+        filters: list[str],
+        filter_options: list[dict[str, Any]] | None = None,
+    ) -> list[Breakpoint]:
         """Configure exception breakpoints.
 
         LLDB exception filters:
         - "cpp_throw": Break on C++ throw
         - "cpp_catch": Break on C++ catch
         - "rust_panic": Break on Rust panic
+
+        Args:
+            filters: Filter IDs to enable without options
+            filter_options: Filters with conditions ({"filterId", "condition"})
+
+        Returns:
+            Per-filter results, in the order filters then filter_options
         """
         client = self._require_initialized()
 
-        await client.send_request(
-            "setExceptionBreakpoints",
-            {"filters": filters},
-        )
+        args: dict[str, Any] = {"filters": filters}
+        if filter_options:
+            args["filterOptions"] = filter_options
+        response = await client.send_request("setExceptionBreakpoints", args)
+
+        return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
     async def continue_execution(self, thread_id: int | None = None) -> None:
         """Continue execution."""
@@ -631,6 +638,7 @@ class CodeLLDBAdapter(DebugAdapter):
         variables_reference: int,
         start: int = 0,
         count: int = 0,
+        filter: str | None = None,
     ) -> list[Variable]:
         """Get variables for a scope or variable reference."""
         client = self._require_initialized()
@@ -640,6 +648,8 @@ class CodeLLDBAdapter(DebugAdapter):
             args["start"] = start
         if count > 0:
             args["count"] = count
+        if filter:
+            args["filter"] = filter
 
         response = await client.send_request("variables", args)
 
@@ -680,6 +690,7 @@ class CodeLLDBAdapter(DebugAdapter):
             "breakpoint": EventType.BREAKPOINT,
             "thread": EventType.THREAD,
             "module": EventType.MODULE,
+            "loadedSource": EventType.LOADED_SOURCE,
         }
 
         # Handle output events specially
@@ -5,18 +5,96 @@ import contextlib
 import json
 import logging
 from collections.abc import Callable, Coroutine
+from dataclasses import dataclass, field
 from typing import Any
 
-from polybugger_mcp.core.exceptions import DAPError, DAPTimeoutError
+from polybugger_mcp.core.exceptions import (
+    AdapterExitedError,
+    DAPClientClosedError,
+    DAPConnectionError,
+    DAPError,
+    DAPTimeoutError,
+)
 
 logger = logging.getLogger(__name__)
 
+# Handler for a request sent by the debug adapter (e.g. runInTerminal)
+RequestHandler = Callable[[dict[str, Any]], Coroutine[Any, Any, dict[str, Any]]]
+
+# Called with a reason when the adapter's stream ends without a disconnect
+DisconnectCallback = Callable[[str], Coroutine[Any, Any, None]]
+
+
+async def connect_tcp(
+    host: str,
+    port: int,
+    timeout: float = 10.0,
+    retries: int | None = None,
+) -> tuple[asyncio.StreamReader, asyncio.StreamWriter]:
+    """Open a TCP connection to a DAP server, retrying while it isn't listening.
+
+    An adapter started just before (or one a remote launcher is still bringing
+    up) refuses connections for a moment, so failed attempts are retried with
+    a backoff of 0.1s doubling up to 2s.
+
+    Args:
+        host: Server host
+        port: Server port
+        timeout: Seconds to keep trying before giving up
+        retries: Retries after the first attempt (None: until the timeout)
+
+    Raises:
+        DAPConnectionError: If no connection was made in time
+    """
+    loop = asyncio.get_running_loop()
+    deadline = loop.time() + timeout
+    delay = 0.1
+    attempts = 0
+    while True:
+        attempts += 1
+        try:
+            return await asyncio.wait_for(
+                asyncio.open_connection(host, port),
+                timeout=max(deadline - loop.time(), 0.1),
+            )
+        except (OSError, asyncio.TimeoutError) as e:
+            last_error = e
+        if (retries is not None and attempts > retries) or loop.time() + delay >= deadline:
+            raise DAPConnectionError(
+                f"no DAP server reachable at {host}:{port} after {attempts} attempt(s): "
+                f"{last_error or 'timed out'}"
+            )
+        await asyncio.sleep(delay)
+        delay = min(delay * 2, 2.0)
+
+
+@dataclass
+class PendingRequest:
+    """A request sent, or queued to be sent, that awaits its response."""
+
+    command: str
+    response: asyncio.Future[dict[str, Any]] = field(
+        default_factory=lambda: asyncio.get_running_loop().create_future()
+    )
+
 
 class DAPClient:
     """Client for communicating via Debug Adapter Protocol.
 
     Handles the DAP message framing (Content-Length headers),
     request/response correlation, and event dispatching.
+
+    Requests don't wait for each other: a writer task sends queued messages
+    in order and the read loop hands each response to its request by seq,
+    so a slow variables request doesn't hold up a pause. Orderings DAP
+    needs (configurationDone after breakpoints, one step at a time) are
+    kept by the adapters and the session, not here. Events are dispatched
+    in order from the read loop; handlers must not await requests.
+
+    If the stream ends or breaks before a disconnect request was sent, the
+    adapter is considered dead: disconnect_callback is told why, and pending
+    and later requests fail (see fail) instead of waiting for their timeout.
+    stop() fails outstanding requests with DAPClientClosedError.
     """
 
     def __init__(
@@ -25,6 +103,7 @@ class DAPClient:
         writer: asyncio.StreamWriter,
         event_callback: Callable[[str, dict[str, Any]], Coroutine[Any, Any, None]] | None = None,
         timeout: float = 30.0,
+        disconnect_callback: DisconnectCallback | None = None,
     ):
         """Initialize the DAP client.
 
@@ -33,17 +112,28 @@ class DAPClient:
             writer: Stream writer for outgoing messages
             event_callback: Async callback for events (event_type, body)
             timeout: Default timeout for requests in seconds
+            disconnect_callback: Async callback when the adapter goes away
+                unexpectedly; it may call fail() with a more detailed error
         """
         self._reader = reader
         self._writer = writer
         self._event_callback = event_callback
+        self._disconnect_callback = disconnect_callback
         self._timeout = timeout
 
         self._seq = 0
-        self._pending: dict[int, asyncio.Future[dict[str, Any]]] = {}
-        self._lock = asyncio.Lock()
+        self._pending: dict[int, PendingRequest] = {}
+        # Messages to write, each with a future set once it has been written
+        self._outgoing: asyncio.Queue[tuple[dict[str, Any], asyncio.Future[None]]] = (
+            asyncio.Queue()
+        )
         self._reader_task: asyncio.Task[None] | None = None
+        self._writer_task: asyncio.Task[None] | None = None
         self._closed = False
+        self._request_handlers: dict[str, RequestHandler] = {}
+        self._handler_tasks: set[asyncio.Task[None]] = set()
+        self._disconnecting = False  # A disconnect request was sent; EOF is expected
+        self._failure: DAPError | None = None  # Raised by requests once the adapter is gone
 
     async def start(self) -> None:
         """Start the message reader loop."""
@@ -53,21 +143,50 @@ class DAPClient:
         """Stop the client and cleanup."""
         self._closed = True
 
-        if self._reader_task:
-            self._reader_task.cancel()
-            with contextlib.suppress(asyncio.CancelledError):
-                await self._reader_task
+        for task in [self._reader_task, self._writer_task]:
+            if task:
+                task.cancel()
+                with contextlib.suppress(asyncio.CancelledError):
+                    await task
+
+        for task in list(self._handler_tasks):
+            task.cancel()
 
-        # Cancel all pending requests
-        for future in self._pending.values():
-            if not future.done():
-                future.cancel()
+        # Fail outstanding requests, including any not written yet
+        for request in self._pending.values():
+            if not request.response.done():
+                request.response.set_exception(DAPClientClosedError(request.command))
         self._pending.clear()
+        while not self._outgoing.empty():
+            message, written = self._outgoing.get_nowait()
+            if not written.done():
+                written.set_exception(DAPClientClosedError(message.get("command", "")))
 
         self._writer.close()
         with contextlib.suppress(Exception):
             await self._writer.wait_closed()
 
+    def fail(self, error: DAPError) -> None:
+        """Fail pending requests with error, and every request sent from now on."""
+        self._failure = error
+        self._closed = True
+        self.fail_pending(error)
+
+    def fail_pending(self, error: DAPError) -> None:
+        """Fail the requests awaiting a response, leaving the client usable."""
+        for request in self._pending.values():
+            if not request.response.done():
+                request.response.set_exception(error)
+
+    def set_request_handler(self, command: str, handler: RequestHandler) -> None:
+        """Answer requests the adapter sends to us (reverse requests).
+
+        Args:
+            command: Reverse request command, e.g. "runInTerminal"
+            handler: Async callable taking the arguments and returning the body
+        """
+        self._request_handlers[command] = handler
+
     async def send_request(
         self,
         command: str,
@@ -86,25 +205,36 @@ class DAPClient:
 
         Raises:
             DAPTimeoutError: If request times out
+            AdapterExitedError: If the adapter has gone away
+            DAPClientClosedError: If the client was stopped first
             DAPError: If request fails
         """
-        async with self._lock:
-            self._seq += 1
-            seq = self._seq
-
-        request = {
+        if self._failure is not None:
+            raise self._failure
+        if self._closed:
+            raise DAPClientClosedError(command)
+        if command == "disconnect":
+            self._disconnecting = True
+
+        self._seq += 1
+        seq = self._seq
+        message = {
             "seq": seq,
             "type": "request",
             "command": command,
             "arguments": arguments or {},
         }
+        request = PendingRequest(command)
+        self._pending[seq] = request
 
-        future: asyncio.Future[dict[str, Any]] = asyncio.Future()
-        self._pending[seq] = future
+        async def exchange() -> dict[str, Any]:
+            await self._send_message(message)
+            return await request.response
 
         try:
-            await self._send_message(request)
-            response = await asyncio.wait_for(future, timeout=timeout or self._timeout)
+            # On timeout or cancellation an unwritten request is never sent,
+            # and a late response is dropped
+            response = await asyncio.wait_for(exchange(), timeout=timeout or self._timeout)
 
             if not response.get("success", False):
                 raise DAPError(
@@ -121,21 +251,56 @@ class DAPClient:
 
         finally:
             self._pending.pop(seq, None)
+            if request.response.done() and not request.response.cancelled():
+                request.response.exception()  # Failed by stop/fail while still being written
 
     async def _send_message(self, message: dict[str, Any]) -> None:
-        """Send a DAP message with Content-Length header."""
-        content = json.dumps(message)
-        content_bytes = content.encode("utf-8")
-        header = f"Content-Length: {len(content_bytes)}\r\n\r\n"
-
-        self._writer.write(header.encode("utf-8"))
-        self._writer.write(content_bytes)
-        await self._writer.drain()
+        """Queue a DAP message for the writer task and wait until it is written.
 
-        logger.debug(f"DAP >> {message.get('command', message.get('type'))}")
+        Raises:
+            AdapterExitedError: If the write failed
+            DAPClientClosedError: If the writer task has ended (the client stopped)
+        """
+        if self._writer_task is None:
+            self._writer_task = asyncio.create_task(self._write_loop())
+        elif self._writer_task.done():
+            raise DAPClientClosedError(message.get("command", message.get("type", "")))
+        written = asyncio.get_running_loop().create_future()
+        self._outgoing.put_nowait((message, written))
+        await written
+
+    async def _write_loop(self) -> None:
+        """Write queued messages one at a time, in the order they were queued."""
+        while True:
+            message, written = await self._outgoing.get()
+            if written.done():
+                continue  # The sender gave up before its turn
+            if self._failure is not None:
+                written.set_exception(self._failure)
+                continue
+            content_bytes = json.dumps(message).encode("utf-8")
+            header = f"Content-Length: {len(content_bytes)}\r\n\r\n"
+            try:
+                self._writer.write(header.encode("utf-8"))
+                self._writer.write(content_bytes)
+                await self._writer.drain()
+            except ConnectionError as e:
+                if not written.done():
+                    written.set_exception(
+                        self._failure or AdapterExitedError(f"write failed: {e}")
+                    )
+                continue
+            except asyncio.CancelledError:
+                if not written.done():
+                    written.set_exception(DAPClientClosedError(message.get("command", "")))
+                raise
+            if not written.done():
+                written.set_result(None)
+            logger.debug(f"DAP >> {message.get('command', message.get('type'))}")
 
     async def _read_loop(self) -> None:
         """Read and dispatch incoming DAP messages."""
+        reason = "the adapter closed the connection"
         while not self._closed:
             try:
                 message = await self._read_message()
@@ -145,11 +310,28 @@ class DAPClient:
                 await self._handle_message(message)
 
             except asyncio.CancelledError:
+                return
+            except (ConnectionError, asyncio.IncompleteReadError) as e:
+                reason = f"connection to the adapter broke: {e}"
                 break
             except Exception as e:
                 if not self._closed:
                     logger.error(f"DAP read error: {e}")
 
+        if not self._closed and not self._disconnecting:
+            await self._connection_lost(reason)
+
+    async def _connection_lost(self, reason: str) -> None:
+        """Report the adapter gone and fail its requests."""
+        logger.warning(f"DAP connection lost: {reason}")
+        if self._disconnect_callback:
+            try:
+                await self._disconnect_callback(reason)
+            except Exception as e:
+                logger.error(f"Disconnect callback error: {e}")
+        if self._failure is None:
+            self.fail(AdapterExitedError(reason))
+
     async def _read_message(self) -> dict[str, Any] | None:
         """Read a single DAP message."""
         # Read headers
@@ -189,9 +371,9 @@ class DAPClient:
             # Match response to pending request
             seq = message.get("request_seq")
             if seq is not None:
-                future = self._pending.get(seq)
-                if future and not future.done():
-                    future.set_result(message)
+                request = self._pending.get(seq)
+                if request and not request.response.done():
+                    request.response.set_result(message)
 
         elif msg_type == "event":
             # Dispatch event to callback
@@ -203,6 +385,39 @@ class DAPClient:
                 except Exception as e:
                     logger.error(f"Event callback error: {e}")
 
+        elif msg_type == "request":
+            # Answer off the read loop; handlers may wait on further messages
+            task = asyncio.create_task(self._answer_request(message))
+            self._handler_tasks.add(task)
+            task.add_done_callback(self._handler_tasks.discard)
+
+    async def _answer_request(self, message: dict[str, Any]) -> None:
+        """Run the handler for a reverse request and send its response."""
+        command = message.get("command", "")
+        response: dict[str, Any] = {
+            "type": "response",
+            "request_seq": message.get("seq"),
+            "command": command,
+            "success": True,
+        }
+
+        handler = self._request_handlers.get(command)
+        if handler is None:
+            response["success"] = False
+            response["message"] = f"Unsupported request '{command}'"
+        else:
+            try:
+                response["body"] = await handler(message.get("arguments", {}))
+            except Exception as e:
+                logger.error(f"Reverse request '{command}' failed: {e}")
+                response["success"] = False
+                response["message"] = str(e)
+
+        self._seq += 1
+        response["seq"] = self._seq
+        with contextlib.suppress(Exception):
+            await self._send_message(response)
+
     @property
     def is_connected(self) -> bool:
         """Check if client is connected and running."""
@@ -1,6 +1,8 @@
 """debugpy subprocess adapter."""
 
+import ast
 import asyncio
+import json
 import logging
 import os
 import signal
@@ -21,8 +23,9 @@ from polybugger_mcp.adapters.base import (
 )
 from polybugger_mcp.adapters.dap_client import DAPClient
 from polybugger_mcp.adapters.factory import register_adapter
+from polybugger_mcp.adapters.renderers import PythonRenderer
 from polybugger_mcp.config import settings
-from polybugger_mcp.core.exceptions import DAPConnectionError, LaunchError
+from polybugger_mcp.core.exceptions import DAPConnectionError, DAPError, LaunchError
 from polybugger_mcp.models.dap import (
     AttachConfig,
     Breakpoint,
@@ -37,6 +40,28 @@ from polybugger_mcp.models.events import EventType
 
 logger = logging.getLogger(__name__)
 
+# Frames and message characters kept per chained exception
+EXCEPTION_MAX_FRAMES = 50
+EXCEPTION_MESSAGE_MAX_CHARS = 2000
+
+# Evaluates to a JSON list of the exception pydevd stored in the frame's
+# __exception__ and its causes, innermost first. Each exception is reached
+# through __cause__ ("raise ... from"), else __context__ unless suppressed.
+EXCEPTION_CHAIN_EXPRESSION = (
+    "(lambda e, j=__import__('json'), t=__import__('traceback'): j.dumps([{{"
+    "'type': type(x).__qualname__ if type(x).__module__ == 'builtins' "
+    "else type(x).__module__ + '.' + type(x).__qualname__, "
+    "'message': str(x)[:{c}], "
+    "'frames': [{{'name': f.name, 'file': f.filename, 'line': f.lineno}} "
+    "for f in t.extract_tb(x.__traceback__)[-{f}:]], "
+    "'relation': r}} "
+    "for x, r in reversed((lambda w: w(w, e, None, {n}))("
+    "lambda w, x, r, k: [(x, r)] + w(w, "
+    "x.__cause__ if x.__cause__ is not None else None if x.__suppress_context__ "
+    "else x.__context__, 'cause' if x.__cause__ is not None else 'context', k - 1) "
+    "if x is not None and k > 0 else []))]))(__exception__[1])"
+)
+
 
 def _get_free_port() -> int:
     """Get an available port number."""
@@ -46,7 +71,13 @@ def _get_free_port() -> int:
         return port
 
 
-@register_adapter(Language.PYTHON)
+@register_adapter(
+    Language.PYTHON,
+    name="debugpy",
+    extensions=(".py", ".pyw"),
+    find_binary=lambda: settings.default_python_path or sys.executable,
+    install_hint="pip install debugpy",
+)
 class DebugpyAdapter(DebugAdapter):
     """Adapter for communicating with debugpy via DAP.
 
@@ -54,6 +85,10 @@ class DebugpyAdapter(DebugAdapter):
     debugging operations to DAP protocol messages.
     """
 
+    supports_stdin_pipe = True
+    supports_just_my_code = True
+    renderer = PythonRenderer()
+
     def __init__(
         self,
         session_id: str,
@@ -70,7 +105,6 @@ class DebugpyAdapter(DebugAdapter):
         """
         super().__init__(session_id, output_callback, event_callback)
 
-        self._process: asyncio.subprocess.Process | None = None
         self._client: DAPClient | None = None
         self._reader: asyncio.StreamReader | None = None
         self._writer: asyncio.StreamWriter | None = None
@@ -91,11 +125,59 @@ class DebugpyAdapter(DebugAdapter):
         return self._client is not None and self._initialized
 
     async def initialize(self) -> dict[str, Any]:
-        """Start debugpy and initialize DAP connection.
+        """Start debugpy (or connect to a remote adapter) and initialize DAP connection.
 
         Returns:
             Debugger capabilities dictionary
         """
+        try:
+            if self.remote is not None:
+                self._port = self.remote.port
+                self._reader, self._writer = await self._connect_transport()
+            else:
+                await self._start_adapter()
+
+            # Create DAP client with socket streams
+            assert self._reader is not None
+            assert self._writer is not None
+            self._client = DAPClient(
+                reader=self._reader,
+                writer=self._writer,
+                event_callback=self._dispatch_event,
+                timeout=settings.dap_timeout_seconds,
+                disconnect_callback=self._connection_lost,
+            )
+            await self._client.start()
+
+            # Send initialize request
+            self._capabilities = await self._client.send_request(
+                "initialize",
+                {
+                    "clientID": "python-debugger-mcp",
+                    "clientName": "Python Debugger MCP",
+                    "adapterID": "python",
+                    "pathFormat": "path",
+                    "linesStartAt1": True,
+                    "columnsStartAt1": True,
+                    "supportsVariableType": True,
+                    "supportsVariablePaging": True,
+                    "supportsRunInTerminalRequest": True,  # Used for stdin_mode pipe/closed
+                    "supportsProgressReporting": False,
+                },
+            )
+        except DAPConnectionError:
+            await self._cleanup()
+            raise
+        except Exception as e:
+            await self._cleanup()
+            raise DAPConnectionError(f"Failed to initialize debugpy: {e}")
+
+        self._initialized = True
+        logger.info(f"Session {self.session_id}: debugpy initialized on port {self._port}")
+        return self._capabilities
+
+    async def _start_adapter(self) -> None:
+        """Start debugpy.adapter on a free port and connect to it."""
         # Get a free port for debugpy to listen on
         self._port = _get_free_port()
         python_path = settings.default_python_path or sys.executable
@@ -141,67 +223,8 @@ class DebugpyAdapter(DebugAdapter):
             start_new_session=True,
             preexec_fn=_detach_from_tty if sys.platform != "win32" else None,
         )
-
-        # Wait for debugpy to start listening
-        # We retry connection a few times with backoff
-        max_attempts = 10
-        last_error: Exception | None = None
-
-        for attempt in range(max_attempts):
-            try:
-                self._reader, self._writer = await asyncio.wait_for(
-                    asyncio.open_connection("127.0.0.1", self._port),
-                    timeout=1.0,
-                )
-                break
-            except (ConnectionRefusedError, asyncio.TimeoutError, OSError) as e:
-                last_error = e
-                # Check if process died
-                if self._process.returncode is not None:
-                    stderr = ""
-                    if self._process.stderr:
-                        stderr_bytes = await self._process.stderr.read()
-                        stderr = stderr_bytes.decode()[:500]
-                    raise DAPConnectionError(
-                        f"debugpy process exited with code {self._process.returncode}: {stderr}"
-                    )
-                await asyncio.sleep(0.1 * (attempt + 1))
-        else:
-            raise DAPConnectionError(
-                f"Failed to connect to debugpy after {max_attempts} attempts: {last_error}"
-            )
-
-        # Create DAP client with socket streams
-        assert self._reader is not None
-        assert self._writer is not None
-        self._client = DAPClient(
-            reader=self._reader,
-            writer=self._writer,
-            event_callback=self._handle_event,
-            timeout=settings.dap_timeout_seconds,
-        )
-        await self._client.start()
-
-        # Send initialize request
-        self._capabilities = await self._client.send_request(
-            "initialize",
-            {
-                "clientID": "python-debugger-mcp",
-                "clientName": "Python Debugger MCP",
-                "adapterID": "python",
-                "pathFormat": "path",
-                "linesStartAt1": True,
-                "columnsStartAt1": True,
-                "supportsVariableType": True,
-                "supportsVariablePaging": True,
-                "supportsRunInTerminalRequest": False,
-                "supportsProgressReporting": False,
-            },
-        )
-
-        self._initialized = True
-        logger.info(f"Session {self.session_id}: debugpy initialized on port {self._port}")
-        return self._capabilities
+        self._supervise(self._process)
+        self._reader, self._writer = await self._connect_local(self._port, "debugpy")
 
     async def launch(
         self,
@@ -247,12 +270,19 @@ class DebugpyAdapter(DebugAdapter):
             "TERM": "dumb",  # Disable terminal features
         }
 
+        console = config.console
+        stdin_mode = getattr(config, "stdin_mode", "inherit")
+        if stdin_mode != "inherit" and console == "internalConsole":
+            # debugpy then asks us to start the program, so we own its stdin
+            console = "integratedTerminal"
+            self._launch_in_terminal(client, stdin_mode)
+
         args: dict[str, Any] = {
             "cwd": str(config.cwd),
             "env": env,
             "stopOnEntry": config.stop_on_entry,
-            "justMyCode": False,  # Always debug all code
-            "console": config.console,
+            "justMyCode": getattr(config, "just_my_code", False),
+            "console": console,
             "redirectOutput": True,
             "redirectInput": config.redirect_input,
         }
@@ -367,13 +397,15 @@ class DebugpyAdapter(DebugAdapter):
             initialized_event = self._initialized_event
 
             async def send_attach() -> None:
-                await client.send_request("attach", args)
+                await client.send_request(
+                    "attach", args, timeout=settings.dap_attach_timeout_seconds
+                )
 
             async def wait_configure_done() -> None:
                 try:
                     await asyncio.wait_for(
                         initialized_event.wait(),
-                        timeout=settings.dap_launch_timeout_seconds,
+                        timeout=settings.dap_attach_timeout_seconds,
                     )
                     if configure_callback:
                         await configure_callback()
@@ -397,15 +429,13 @@ class DebugpyAdapter(DebugAdapter):
             terminate: Whether to terminate the debuggee (default True)
         """
         if self._client:
-            try:
-                await self._client.send_request(
-                    "disconnect",
-                    {"terminateDebuggee": terminate},
-                    timeout=5.0,
-                )
-            except Exception:
-                pass
+            await self._send_disconnect(self._client, terminate)
+
+        await self._cleanup(terminate)
 
+    async def _cleanup(self, terminate: bool = True) -> None:
+        """Clean up resources, stopping the adapter's process group when terminating."""
+        if self._client:
             await self._client.stop()
             self._client = None
 
@@ -419,13 +449,9 @@ class DebugpyAdapter(DebugAdapter):
             self._writer = None
             self._reader = None
 
-        if self._process:
-            self._process.terminate()
-            try:
-                await asyncio.wait_for(self._process.wait(), timeout=5.0)
-            except asyncio.TimeoutError:
-                self._process.kill()
-            self._process = None
+        await self._close_terminal_process(terminate)
+
+        await self._stop_adapter_process(terminate)
 
         self._initialized = False
         self._launched = False
@@ -478,31 +504,36 @@ class DebugpyAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_exception_breakpoints(self, filters: list[str]) -> None:
+    async def set_exception_breakpoints(
+        self,
+        filters: list[str],
+        filter_options: list[dict[str, Any]] | None = None,
+    ) -> list[Breakpoint]:
         """Set exception breakpoints.
 
         Args:
             filters: List of exception filters ("raised", "uncaught", etc.)
+            filter_options: Filters with conditions ({"filterId", "condition"})
+
+        Returns:
+            Per-filter results, in the order filters then filter_options
         """
         client = self._require_initialized()
 
-        await client.send_request(
-            "setExceptionBreakpoints",
-            {"filters": filters},
-        )
-
-    async def set_function_breakpoints(self, names: list[str]) -> list[Breakpoint]:
-        """Set breakpoints on function names.
+        args: dict[str, Any] = {"filters": filters}
+        if filter_options:
+            args["filterOptions"] = filter_options
+        response = await client.send_request("setExceptionBreakpoints", args)
 
-        Args:
-            names: List of function names
+        return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-        Returns:
-            List of actual breakpoints
-        """
+    async def set_function_breakpoints(
+        self,
+        breakpoints: list[dict[str, Any]],
+    ) -> list[Breakpoint]:
+        """Replace all function breakpoints."""
         client = self._require_initialized()
 
-        breakpoints = [{"name": name} for name in names]
         response = await client.send_request(
             "setFunctionBreakpoints",
             {"breakpoints": breakpoints},
@@ -652,6 +683,7 @@ class DebugpyAdapter(DebugAdapter):
         variables_reference: int,
         start: int = 0,
         count: int = 100,
+        filter: str | None = None,
     ) -> list[Variable]:
         """Get variables for a scope or variable reference.
 
@@ -659,20 +691,21 @@ class DebugpyAdapter(DebugAdapter):
             variables_reference: Variable reference ID
             start: Starting index
             count: Maximum variables to return (0 = all)
+            filter: "indexed" or "named" to page only those children
 
         Returns:
             List of variables
         """
         client = self._require_initialized()
 
-        response = await client.send_request(
-            "variables",
-            {
-                "variablesReference": variables_reference,
-                "start": start,
-                "count": count if count > 0 else 100,
-            },
-        )
+        args: dict[str, Any] = {
+            "variablesReference": variables_reference,
+            "start": start,
+            "count": count if count > 0 else 100,
+        }
+        if filter:
+            args["filter"] = filter
+        response = await client.send_request("variables", args)
 
         return [Variable(**v) for v in response.get("variables", [])]
 
@@ -713,6 +746,48 @@ class DebugpyAdapter(DebugAdapter):
 
         return await client.send_request("evaluate", args)
 
+    async def evaluate_full(self, expression: str, frame_id: int | None = None) -> str:
+        """Evaluate an expression and return its complete repr.
+
+        debugpy clips long reprs outside the clipboard context; without it,
+        repr() is taken in the debuggee and the resulting string literal
+        decoded here.
+        """
+        if self.capabilities.get("supportsClipboardContext"):
+            return await super().evaluate_full(expression, frame_id)
+        result = await self.evaluate(f"repr({expression})", frame_id, "repl")
+        text = str(result.get("result", ""))
+        try:
+            decoded = ast.literal_eval(text)
+        except (ValueError, SyntaxError):
+            return text
+        return decoded if isinstance(decoded, str) else text
+
+    async def get_exception_chain(
+        self,
+        thread_id: int,
+        frame_id: int | None,
+        max_depth: int,
+    ) -> list[dict[str, Any]]:
+        """Walk the exception's __cause__/__context__ chain in the debuggee.
+
+        Entries also carry "relation": how the next exception out came from
+        this one ("cause" or "context"), None for the outermost. Falls back
+        to ExceptionInfo when __exception__ isn't available in the frame.
+        """
+        if frame_id is not None:
+            expression = EXCEPTION_CHAIN_EXPRESSION.format(
+                n=max_depth, f=EXCEPTION_MAX_FRAMES, c=EXCEPTION_MESSAGE_MAX_CHARS
+            )
+            try:
+                chain: list[dict[str, Any]] = json.loads(
+                    ast.literal_eval(await self.evaluate_full(expression, frame_id))
+                )
+                return chain
+            except (DAPError, ValueError, SyntaxError) as e:
+                logger.debug(f"Could not walk exception chain: {e}")
+        return await super().get_exception_chain(thread_id, frame_id, max_depth)
+
     async def _handle_event(self, event_type: str, body: dict[str, Any]) -> None:
         """Handle DAP events from debugpy."""
         # Handle 'initialized' event for launch sequence coordination
@@ -730,6 +805,7 @@ class DebugpyAdapter(DebugAdapter):
             "breakpoint": EventType.BREAKPOINT,
             "thread": EventType.THREAD,
             "module": EventType.MODULE,
+            "loadedSource": EventType.LOADED_SOURCE,
         }
 
         # Handle output events specially
@@ -11,6 +11,7 @@ Delve supports DAP natively via: dlv dap --listen=:port
 
 import asyncio
 import logging
+import re
 import shutil
 import socket
 from collections.abc import Callable, Coroutine
@@ -27,8 +28,9 @@ from polybugger_mcp.adapters.base import (
 from polybugger_mcp.adapters.base import (
     LaunchConfig as BaseLaunchConfig,
 )
-from polybugger_mcp.adapters.dap_client import DAPClient
+from polybugger_mcp.adapters.dap_client import DAPClient, connect_tcp
 from polybugger_mcp.adapters.factory import register_adapter
+from polybugger_mcp.config import settings
 from polybugger_mcp.core.exceptions import DAPConnectionError, LaunchError
 from polybugger_mcp.models.dap import (
     Breakpoint,
@@ -42,6 +44,10 @@ from polybugger_mcp.models.events import EventType
 
 logger = logging.getLogger(__name__)
 
+# One goroutine frame in delve's ExceptionInfo stack trace:
+#   "\t0  0x00000000004a1b2c in main.main\n\t    at /src/main.go:12"
+_STACK_FRAME_PATTERN = re.compile(r"in (?P<name>\S+)\n\s+at (?P<file>.+?):(?P<line>\d+)$", re.M)
+
 
 def _get_free_port() -> int:
     """Get an available port number."""
@@ -58,7 +64,7 @@ class GoLaunchConfig:
     program: str | None = None  # Path to main package or .go file
     args: list[str] | None = None
     cwd: str = "."
-    env: dict[str, str] | None = None
+    env: dict[str, str | None] | None = None
     stop_on_entry: bool = False
 
     # Go-specific options
@@ -66,6 +72,7 @@ class GoLaunchConfig:
     build_flags: list[str] | None = None  # Flags passed to go build
     dlv_flags: list[str] | None = None  # Flags passed to dlv
     output: str | None = None  # Output path for compiled binary
+    backend: str | None = None  # default, native, lldb, or rr (enables reverse execution)
 
 
 @dataclass
@@ -78,7 +85,15 @@ class GoAttachConfig:
     mode: str = "local"  # local or remote
 
 
-@register_adapter(Language.GO)
+@register_adapter(
+    Language.GO,
+    name="delve",
+    extensions=(".go",),
+    find_binary=lambda: shutil.which(DelveAdapter.DLV_CLI),
+    install_hint="go install github.com/go-delve/delve/cmd/dlv@latest",
+    # Threads are goroutines (often hundreds); program may be a package directory
+    quirks=frozenset({"goroutine_threads", "package_programs"}),
+)
 class DelveAdapter(DebugAdapter):
     """Debug adapter for Go using delve.
 
@@ -89,6 +104,12 @@ class DelveAdapter(DebugAdapter):
     # Name of the delve CLI command
     DLV_CLI = "dlv"
 
+    # A step whose goroutine blocks (e.g. on a channel) can stop on another one
+    sticky_steps = True
+
+    # A short program can run to completion before its breakpoints are set
+    launch_stops_at_entry = True
+
     def __init__(
         self,
         session_id: str,
@@ -99,7 +120,6 @@ class DelveAdapter(DebugAdapter):
         """Initialize the adapter."""
         super().__init__(session_id, output_callback, event_callback)
 
-        self._process: asyncio.subprocess.Process | None = None
         self._client: DAPClient | None = None
         self._reader: asyncio.StreamReader | None = None
         self._writer: asyncio.StreamWriter | None = None
@@ -132,11 +152,31 @@ class DelveAdapter(DebugAdapter):
         return self._client
 
     async def initialize(self) -> dict[str, Any]:
-        """Start delve DAP server and initialize connection.
+        """Start delve's DAP server (or connect to a remote one) and initialize.
 
         Returns:
             Debug adapter capabilities
         """
+        try:
+            if self.remote is not None:
+                self._port = self.remote.port
+                self._reader, self._writer = await self._connect_transport()
+            else:
+                await self._start_adapter()
+
+            await self._start_client()
+            logger.info(f"Session {self.session_id}: dlv initialized on port {self._port}")
+            return self._capabilities
+
+        except DAPConnectionError:
+            await self._cleanup()
+            raise
+        except Exception as e:
+            await self._cleanup()
+            raise DAPConnectionError(f"Failed to initialize dlv: {e}")
+
+    async def _start_adapter(self) -> None:
+        """Start `dlv dap` on a free port and connect to it."""
         # Find dlv CLI
         dlv_path = shutil.which(self.DLV_CLI)
         if not dlv_path:
@@ -147,68 +187,65 @@ class DelveAdapter(DebugAdapter):
         # Get a free port for the DAP server
         self._port = _get_free_port()
 
-        try:
-            # Start dlv in DAP mode
-            # dlv dap starts a DAP server that waits for launch/attach requests
-            self._process = await asyncio.create_subprocess_exec(
-                dlv_path,
-                "dap",
-                f"--listen=127.0.0.1:{self._port}",
-                stdin=asyncio.subprocess.DEVNULL,
-                stdout=asyncio.subprocess.PIPE,
-                stderr=asyncio.subprocess.PIPE,
-            )
-
-            # Wait for the server to start
-            await asyncio.sleep(0.3)
+        # Start dlv in DAP mode
+        # dlv dap starts a DAP server that waits for launch/attach requests
+        self._process = await asyncio.create_subprocess_exec(
+            dlv_path,
+            "dap",
+            f"--listen=127.0.0.1:{self._port}",
+            stdin=asyncio.subprocess.DEVNULL,
+            stdout=asyncio.subprocess.PIPE,
+            stderr=asyncio.subprocess.PIPE,
+            start_new_session=True,  # Own process group, so the debuggee goes with it
+        )
+        self._supervise(self._process)
+        self._reader, self._writer = await self._connect_local(self._port, "dlv")
+
+    async def _start_client(self) -> None:
+        """Create the DAP client on the open connection and send initialize."""
+        assert self._reader is not None
+        assert self._writer is not None
+        self._client = DAPClient(
+            reader=self._reader,
+            writer=self._writer,
+            event_callback=self._dispatch_event,
+            disconnect_callback=self._connection_lost,
+        )
+        await self._client.start()
 
-            # Connect to the DAP server
-            for attempt in range(10):
-                try:
-                    self._reader, self._writer = await asyncio.wait_for(
-                        asyncio.open_connection("127.0.0.1", self._port),
-                        timeout=2.0,
-                    )
-                    break
-                except (ConnectionRefusedError, asyncio.TimeoutError):
-                    if attempt == 9:
-                        raise DAPConnectionError(f"Failed to connect to dlv on port {self._port}")
-                    await asyncio.sleep(0.2)
-
-            # Create DAP client
-            assert self._reader is not None
-            assert self._writer is not None
-            self._client = DAPClient(
-                reader=self._reader,
-                writer=self._writer,
-                event_callback=self._handle_event,
-            )
-            await self._client.start()
-
-            # Send initialize request
-            self._capabilities = await self._client.send_request(
-                "initialize",
-                {
-                    "clientID": "polybugger-mcp",
-                    "clientName": "Python Debugger MCP",
-                    "adapterID": "dlv-dap",
-                    "pathFormat": "path",
-                    "linesStartAt1": True,
-                    "columnsStartAt1": True,
-                    "supportsVariableType": True,
-                    "supportsVariablePaging": True,
-                    "supportsRunInTerminalRequest": False,
-                    "supportsProgressReporting": False,
-                },
-            )
+        self._capabilities = await self._client.send_request(
+            "initialize",
+            {
+                "clientID": "polybugger-mcp",
+                "clientName": "Python Debugger MCP",
+                "adapterID": "dlv-dap",
+                "pathFormat": "path",
+                "linesStartAt1": True,
+                "columnsStartAt1": True,
+                "supportsVariableType": True,
+                "supportsVariablePaging": True,
+                "supportsMemoryReferences": True,
+                "supportsRunInTerminalRequest": False,
+                "supportsProgressReporting": False,
+            },
+        )
+        self._initialized = True
 
-            self._initialized = True
-            logger.info(f"Session {self.session_id}: dlv initialized on port {self._port}")
-            return self._capabilities
+    async def _connect_remote(self, host: str, port: int) -> None:
+        """Replace the local dlv server with a connection to a headless one.
 
-        except Exception as e:
-            await self._cleanup()
-            raise DAPConnectionError(f"Failed to initialize dlv: {e}")
+        Used for attach in remote mode, where `dlv --headless` already runs
+        the target and serves DAP on host:port.
+        """
+        await self._cleanup()
+        try:
+            self._reader, self._writer = await connect_tcp(
+                host, port, timeout=settings.dap_attach_timeout_seconds
+            )
+        except DAPConnectionError as e:
+            raise LaunchError(e.message, {"host": host, "port": port})
+        await self._start_client()
+        logger.info(f"Session {self.session_id}: connected to headless dlv at {host}:{port}")
 
     async def launch(
         self,
@@ -227,6 +264,7 @@ class DelveAdapter(DebugAdapter):
                 cwd=config.cwd or ".",
                 env=config.env,
                 stop_on_entry=config.stop_on_entry,
+                mode=getattr(config, "mode", "debug"),
             )
 
         if not config.program:
@@ -253,6 +291,9 @@ class DelveAdapter(DebugAdapter):
         if hasattr(config, "output") and config.output:
             args["output"] = config.output
 
+        if getattr(config, "backend", None):
+            args["backend"] = config.backend
+
         try:
             self._initialized_event = asyncio.Event()
             initialized_event = self._initialized_event
@@ -284,8 +325,13 @@ class DelveAdapter(DebugAdapter):
         configure_callback: Callable[[], Coroutine[Any, Any, None]] | None = None,
         **kwargs: Any,
     ) -> None:
-        """Attach to a running Go process."""
-        client = self._require_initialized()
+        """Attach to a running Go process.
+
+        With a process ID the local dlv server attaches to that process.
+        Otherwise host/port name a `dlv --headless` server running the target,
+        which is connected to directly and attached in remote mode.
+        """
+        self._require_initialized()
 
         # Handle base AttachConfig
         if isinstance(config, BaseAttachConfig):
@@ -295,16 +341,15 @@ class DelveAdapter(DebugAdapter):
                 process_id=config.process_id,
             )
 
-        args: dict[str, Any] = {
-            "request": "attach",
-            "mode": getattr(config, "mode", "local"),
-        }
+        args: dict[str, Any] = {"request": "attach"}
 
         if config.process_id:
+            args["mode"] = "local"
             args["processId"] = config.process_id
         elif config.port:
-            args["host"] = config.host
-            args["port"] = config.port
+            await self._connect_remote(config.host, config.port)
+            args["mode"] = "remote"
+        client = self._require_initialized()
 
         try:
             self._initialized_event = asyncio.Event()
@@ -334,19 +379,12 @@ class DelveAdapter(DebugAdapter):
     async def disconnect(self, terminate: bool = False) -> None:
         """Disconnect and cleanup."""
         if self._client:
-            try:
-                await self._client.send_request(
-                    "disconnect",
-                    {"terminateDebuggee": terminate},
-                    timeout=5.0,
-                )
-            except Exception:
-                pass
+            await self._send_disconnect(self._client, terminate)
 
-        await self._cleanup()
+        await self._cleanup(terminate)
 
-    async def _cleanup(self) -> None:
-        """Clean up resources."""
+    async def _cleanup(self, terminate: bool = True) -> None:
+        """Clean up resources, stopping the adapter's process group when terminating."""
         if self._client:
             await self._client.stop()
             self._client = None
@@ -360,13 +398,7 @@ class DelveAdapter(DebugAdapter):
             self._writer = None
             self._reader = None
 
-        if self._process:
-            self._process.terminate()
-            try:
-                await asyncio.wait_for(self._process.wait(), timeout=5.0)
-            except asyncio.TimeoutError:
-                self._process.kill()
-            self._process = None
+        await self._stop_adapter_process(terminate)
 
         self._initialized = False
         self._launched = False
@@ -410,11 +442,13 @@ class DelveAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_function_breakpoints(self, names: list[str]) -> list[Breakpoint]:
-        """Set breakpoints on function names."""
+    async def set_function_breakpoints(
+        self,
+        breakpoints: list[dict[str, Any]],
+    ) -> list[Breakpoint]:
+        """Replace all function breakpoints."""
         client = self._require_initialized()
 
-        breakpoints = [{"name": name} for name in names]
         response = await client.send_request(
             "setFunctionBreakpoints",
             {"breakpoints": breakpoints},
@@ -422,19 +456,32 @@ class DelveAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_exception_breakpoints(self, filters: list[str]) -> None:
+    async def set_exception_breakpoints(
+        self,
+        filters: list[str],
+        filter_options: list[dict[str, Any]] | None = None,
+    ) -> list[Breakpoint]:
         """Configure exception breakpoints.
 
         Go/delve exception filters:
         - "panic": Break on panic
         - "fatal": Break on fatal errors
+
+        Args:
+            filters: Filter IDs to enable without options
+            filter_options: Filters with conditions ({"filterId", "condition"})
+
+        Returns:
+            Per-filter results, in the order filters then filter_options
         """
         client = self._require_initialized()
 
-        await client.send_request(
-            "setExceptionBreakpoints",
-            {"filters": filters},
-        )
+        args: dict[str, Any] = {"filters": filters}
+        if filter_options:
+            args["filterOptions"] = filter_options
+        response = await client.send_request("setExceptionBreakpoints", args)
+
+        return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
     async def continue_execution(self, thread_id: int | None = None) -> None:
         """Continue execution."""
@@ -513,6 +560,7 @@ class DelveAdapter(DebugAdapter):
         variables_reference: int,
         start: int = 0,
         count: int = 0,
+        filter: str | None = None,
     ) -> list[Variable]:
         """Get variables for a scope or variable reference."""
         client = self._require_initialized()
@@ -522,6 +570,8 @@ class DelveAdapter(DebugAdapter):
             args["start"] = start
         if count > 0:
             args["count"] = count
+        if filter:
+            args["filter"] = filter
 
         response = await client.send_request("variables", args)
 
@@ -545,6 +595,13 @@ class DelveAdapter(DebugAdapter):
 
         return await client.send_request("evaluate", args)
 
+    def parse_stack_trace(self, text: str) -> list[dict[str, Any]]:
+        """Split the panicking goroutine's stack, deferred calls included, into frames."""
+        return [
+            {"name": m["name"], "file": m["file"], "line": int(m["line"])}
+            for m in _STACK_FRAME_PATTERN.finditer(text)
+        ]
+
     async def _handle_event(self, event_type: str, body: dict[str, Any]) -> None:
         """Handle DAP events from delve."""
         # Handle 'initialized' event for launch sequence coordination
@@ -562,6 +619,7 @@ class DelveAdapter(DebugAdapter):
             "breakpoint": EventType.BREAKPOINT,
             "thread": EventType.THREAD,
             "module": EventType.MODULE,
+            "loadedSource": EventType.LOADED_SOURCE,
         }
 
         # Handle output events specially
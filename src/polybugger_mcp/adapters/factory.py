@@ -1,10 +1,15 @@
-"""Debug adapter factory.
+"""Debug adapter factory and registry.
 
-This module provides factory functions for creating language-specific
-debug adapters based on the target language.
+Adapters register an AdapterDescriptor describing which languages and file
+extensions they handle, how to find their binary and any protocol quirks.
+Sessions pick an adapter by explicit name, by the program's file extension
+or by language. Downstream packages can add adapters with register() (or the
+@register_adapter decorator) without touching launch code.
 """
 
+import os
 from collections.abc import Callable, Coroutine
+from dataclasses import dataclass, field
 from typing import Any
 
 from polybugger_mcp.adapters.base import DebugAdapter, Language
@@ -23,31 +28,189 @@ class UnsupportedLanguageError(DebugRelayError):
         )
 
 
-# Registry of adapter classes by language
-_ADAPTER_REGISTRY: dict[Language, type[DebugAdapter]] = {}
+class UnknownAdapterError(DebugRelayError):
+    """Raised when no registered adapter matches a name or file."""
 
+    def __init__(self, reason: str, available: list[str]):
+        super().__init__(
+            code="UNKNOWN_ADAPTER",
+            message=f"{reason}. Available adapters: {', '.join(available) or 'none'}",
+            details={"available": available},
+        )
+
+
+@dataclass(frozen=True)
+class AdapterDescriptor:
+    """Registration entry for a debug adapter.
+
+    The adapter class builds its own launch/attach arguments; the descriptor
+    carries what is needed to choose it and report on it.
+    """
+
+    name: str
+    adapter_class: type[DebugAdapter]
+    languages: tuple[Language, ...]
+    # Lowercase file extensions (with dot) whose programs this adapter debugs
+    extensions: tuple[str, ...] = ()
+    # Returns the adapter binary's path, or None if it isn't installed
+    find_binary: Callable[[], str | None] | None = None
+    install_hint: str | None = None
+    # Deviations from plain DAP behaviour that callers may need to know about
+    quirks: frozenset[str] = field(default_factory=frozenset)
+
+    def is_available(self) -> bool:
+        """Whether the adapter binary can be found (True if there's no check)."""
+        return self.find_binary is None or self.find_binary() is not None
+
+    def describe(self) -> dict[str, Any]:
+        """Summary for listing tools."""
+        return {
+            "name": self.name,
+            "languages": [lang.value for lang in self.languages],
+            "extensions": list(self.extensions),
+            "available": self.is_available(),
+            "install_hint": self.install_hint,
+            "quirks": sorted(self.quirks),
+        }
+
+
+class AdapterRegistry:
+    """Registered adapter descriptors, looked up by name, language or file."""
+
+    def __init__(self) -> None:
+        self._descriptors: dict[str, AdapterDescriptor] = {}
+
+    def register(self, descriptor: AdapterDescriptor) -> None:
+        """Add an adapter, replacing any registered under the same name.
+
+        Earlier registrations keep precedence for shared languages and
+        extensions, so built-in adapters stay the default.
+        """
+        self._descriptors[descriptor.name] = descriptor
+
+    def names(self) -> list[str]:
+        """Names of all registered adapters."""
+        return list(self._descriptors)
+
+    def descriptors(self) -> list[AdapterDescriptor]:
+        """All registered descriptors in registration order."""
+        return list(self._descriptors.values())
+
+    def get(self, name: str) -> AdapterDescriptor:
+        """Look up an adapter by name.
+
+        Raises:
+            UnknownAdapterError: If no adapter has that name
+        """
+        descriptor = self._descriptors.get(name.lower())
+        if descriptor is None:
+            raise UnknownAdapterError(f"No adapter named '{name}'", self.names())
+        return descriptor
+
+    def for_language(self, language: Language) -> AdapterDescriptor | None:
+        """First adapter registered for a language."""
+        for descriptor in self._descriptors.values():
+            if language in descriptor.languages:
+                return descriptor
+        return None
+
+    def for_file(self, path: str) -> AdapterDescriptor | None:
+        """First adapter registered for a file's extension (None without one).
+
+        Raises:
+            UnknownAdapterError: If the file has an extension no adapter handles
+        """
+        extension = os.path.splitext(path)[1].lower()
+        if not extension:
+            return None
+        for descriptor in self._descriptors.values():
+            if extension in descriptor.extensions:
+                return descriptor
+        raise UnknownAdapterError(
+            f"No adapter handles '{extension}' files (pass adapter explicitly)", self.names()
+        )
+
+    def languages(self) -> list[Language]:
+        """Languages with at least one registered adapter."""
+        seen: list[Language] = []
+        for descriptor in self._descriptors.values():
+            seen.extend(lang for lang in descriptor.languages if lang not in seen)
+        return seen
+
+
+# Process-wide registry; built-in adapters register themselves on import
+adapter_registry = AdapterRegistry()
 
-def register_adapter(language: Language) -> Callable[[type[DebugAdapter]], type[DebugAdapter]]:
-    """Decorator to register an adapter class for a language.
+
+def register(descriptor: AdapterDescriptor) -> None:
+    """Register an adapter descriptor with the process-wide registry."""
+    adapter_registry.register(descriptor)
+
+
+def register_adapter(
+    *languages: Language,
+    name: str,
+    extensions: tuple[str, ...] = (),
+    find_binary: Callable[[], str | None] | None = None,
+    install_hint: str | None = None,
+    quirks: frozenset[str] = frozenset(),
+) -> Callable[[type[DebugAdapter]], type[DebugAdapter]]:
+    """Decorator to register an adapter class.
 
     Usage:
-        @register_adapter(Language.PYTHON)
+        @register_adapter(Language.PYTHON, name="debugpy", extensions=(".py",))
         class DebugpyAdapter(DebugAdapter):
             ...
     """
 
     def decorator(cls: type[DebugAdapter]) -> type[DebugAdapter]:
-        _ADAPTER_REGISTRY[language] = cls
+        register(
+            AdapterDescriptor(
+                name=name,
+                adapter_class=cls,
+                languages=languages,
+                extensions=extensions,
+                find_binary=find_binary,
+                install_hint=install_hint,
+                quirks=quirks,
+            )
+        )
         return cls
 
     return decorator
 
 
+def get_descriptor(language: str | Language, adapter: str | None = None) -> AdapterDescriptor:
+    """Resolve the descriptor for an explicit adapter name or a language.
+
+    Raises:
+        UnknownAdapterError: If the named adapter isn't registered
+        UnsupportedLanguageError: If no adapter handles the language
+    """
+    if adapter is not None:
+        return adapter_registry.get(adapter)
+
+    # Normalize language to enum
+    if isinstance(language, str):
+        try:
+            lang = Language(language.lower())
+        except ValueError:
+            raise UnsupportedLanguageError(language)
+    else:
+        lang = language
+
+    descriptor = adapter_registry.for_language(lang)
+    if descriptor is None:
+        raise UnsupportedLanguageError(lang.value)
+    return descriptor
+
+
 def create_adapter(
     language: str | Language,
     session_id: str,
     output_callback: Callable[[str, str], Any] | None = None,
     event_callback: Callable[[EventType, dict[str, Any]], Coroutine[Any, Any, None]] | None = None,
+    adapter: str | None = None,
 ) -> DebugAdapter:
     """Create a debug adapter for the specified language.
 
@@ -56,28 +219,17 @@ def create_adapter(
         session_id: Unique session identifier
         output_callback: Callback for program output
         event_callback: Async callback for debug events
+        adapter: Registered adapter name, overriding the language default
 
     Returns:
         Language-specific debug adapter instance
 
     Raises:
         UnsupportedLanguageError: If language is not supported
+        UnknownAdapterError: If the named adapter isn't registered
     """
-    # Normalize language to enum
-    if isinstance(language, str):
-        try:
-            lang = Language(language.lower())
-        except ValueError:
-            raise UnsupportedLanguageError(language)
-    else:
-        lang = language
-
-    # Get adapter class from registry
-    adapter_class = _ADAPTER_REGISTRY.get(lang)
-    if adapter_class is None:
-        raise UnsupportedLanguageError(lang.value)
-
-    return adapter_class(
+    descriptor = get_descriptor(language, adapter)
+    return descriptor.adapter_class(
         session_id=session_id,
         output_callback=output_callback,
         event_callback=event_callback,
@@ -90,7 +242,7 @@ def get_supported_languages() -> list[str]:
     Returns:
         List of language strings that have registered adapters
     """
-    return [lang.value for lang in _ADAPTER_REGISTRY]
+    return [lang.value for lang in adapter_registry.languages()]
 
 
 def is_language_supported(language: str) -> bool:
@@ -104,7 +256,7 @@ def is_language_supported(language: str) -> bool:
     """
     try:
         lang = Language(language.lower())
-        return lang in _ADAPTER_REGISTRY
+        return adapter_registry.for_language(lang) is not None
     except ValueError:
         return False
 
@@ -63,7 +63,7 @@ class NodeLaunchConfig:
     program: str | None = None
     args: list[str] | None = None
     cwd: str = "."
-    env: dict[str, str] | None = None
+    env: dict[str, str | None] | None = None
     stop_on_entry: bool = False
 
     # Node-specific options
@@ -85,8 +85,16 @@ class NodeAttachConfig:
     timeout: int = 30000
 
 
-@register_adapter(Language.JAVASCRIPT)
-@register_adapter(Language.TYPESCRIPT)
+@register_adapter(
+    Language.JAVASCRIPT,
+    Language.TYPESCRIPT,
+    name="js-debug",
+    extensions=(".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx"),
+    find_binary=lambda: shutil.which(NodeAdapter.JS_DEBUG_CLI),
+    install_hint="npm install -g @vscode/js-debug-cli",
+    # TypeScript breakpoints bind through source maps of the compiled output
+    quirks=frozenset({"source_maps"}),
+)
 class NodeAdapter(DebugAdapter):
     """Debug adapter for Node.js using vscode-js-debug.
 
@@ -107,7 +115,6 @@ class NodeAdapter(DebugAdapter):
         """Initialize the adapter."""
         super().__init__(session_id, output_callback, event_callback)
 
-        self._process: asyncio.subprocess.Process | None = None
         self._client: DAPClient | None = None
         self._reader: asyncio.StreamReader | None = None
         self._writer: asyncio.StreamWriter | None = None
@@ -140,50 +147,17 @@ class NodeAdapter(DebugAdapter):
         return self._client
 
     async def initialize(self) -> dict[str, Any]:
-        """Start vscode-js-debug and initialize DAP connection.
+        """Start vscode-js-debug (or connect to a remote one) and initialize DAP.
 
         Returns:
             Debug adapter capabilities
         """
-        # Find js-debug CLI
-        js_debug_path = shutil.which(self.JS_DEBUG_CLI)
-        if not js_debug_path:
-            raise DAPConnectionError(
-                f"'{self.JS_DEBUG_CLI}' not found. Install with: npm install -g @vscode/js-debug-cli"
-            )
-
-        # Get a free port for the DAP server
-        self._port = _get_free_port()
-
         try:
-            # Start js-debug in DAP server mode
-            self._process = await asyncio.create_subprocess_exec(
-                js_debug_path,
-                "dap",
-                "--host=127.0.0.1",
-                f"--port={self._port}",
-                stdin=asyncio.subprocess.DEVNULL,
-                stdout=asyncio.subprocess.PIPE,
-                stderr=asyncio.subprocess.PIPE,
-            )
-
-            # Wait for the server to start
-            await asyncio.sleep(0.5)
-
-            # Connect to the DAP server
-            for attempt in range(10):
-                try:
-                    self._reader, self._writer = await asyncio.wait_for(
-                        asyncio.open_connection("127.0.0.1", self._port),
-                        timeout=2.0,
-                    )
-                    break
-                except (ConnectionRefusedError, asyncio.TimeoutError):
-                    if attempt == 9:
-                        raise DAPConnectionError(
-                            f"Failed to connect to js-debug on port {self._port}"
-                        )
-                    await asyncio.sleep(0.3)
+            if self.remote is not None:
+                self._port = self.remote.port
+                self._reader, self._writer = await self._connect_transport()
+            else:
+                await self._start_adapter()
 
             # Create DAP client (reader/writer guaranteed non-None here)
             assert self._reader is not None
@@ -191,7 +165,8 @@ class NodeAdapter(DebugAdapter):
             self._client = DAPClient(
                 reader=self._reader,
                 writer=self._writer,
-                event_callback=self._handle_event,
+                event_callback=self._dispatch_event,
+                disconnect_callback=self._connection_lost,
             )
             await self._client.start()
 
@@ -216,10 +191,39 @@ class NodeAdapter(DebugAdapter):
             logger.info(f"Session {self.session_id}: js-debug initialized on port {self._port}")
             return self._capabilities
 
+        except DAPConnectionError:
+            await self._cleanup()
+            raise
         except Exception as e:
             await self._cleanup()
             raise DAPConnectionError(f"Failed to initialize js-debug: {e}")
 
+    async def _start_adapter(self) -> None:
+        """Start js-debug's DAP server on a free port and connect to it."""
+        # Find js-debug CLI
+        js_debug_path = shutil.which(self.JS_DEBUG_CLI)
+        if not js_debug_path:
+            raise DAPConnectionError(
+                f"'{self.JS_DEBUG_CLI}' not found. Install with: npm install -g @vscode/js-debug-cli"
+            )
+
+        # Get a free port for the DAP server
+        self._port = _get_free_port()
+
+        # Start js-debug in DAP server mode
+        self._process = await asyncio.create_subprocess_exec(
+            js_debug_path,
+            "dap",
+            "--host=127.0.0.1",
+            f"--port={self._port}",
+            stdin=asyncio.subprocess.DEVNULL,
+            stdout=asyncio.subprocess.PIPE,
+            stderr=asyncio.subprocess.PIPE,
+            start_new_session=True,  # Own process group, so the debuggee goes with it
+        )
+        self._supervise(self._process)
+        self._reader, self._writer = await self._connect_local(self._port, "js-debug")
+
     async def launch(
         self,
         config: NodeLaunchConfig | BaseLaunchConfig | Any,
@@ -354,19 +358,12 @@ class NodeAdapter(DebugAdapter):
     async def disconnect(self, terminate: bool = False) -> None:
         """Disconnect and cleanup."""
         if self._client:
-            try:
-                await self._client.send_request(
-                    "disconnect",
-                    {"terminateDebuggee": terminate},
-                    timeout=5.0,
-                )
-            except Exception:
-                pass
+            await self._send_disconnect(self._client, terminate)
 
-        await self._cleanup()
+        await self._cleanup(terminate)
 
-    async def _cleanup(self) -> None:
-        """Clean up resources."""
+    async def _cleanup(self, terminate: bool = True) -> None:
+        """Clean up resources, stopping the adapter's process group when terminating."""
         if self._client:
             await self._client.stop()
             self._client = None
@@ -380,13 +377,7 @@ class NodeAdapter(DebugAdapter):
             self._writer = None
             self._reader = None
 
-        if self._process:
-            self._process.terminate()
-            try:
-                await asyncio.wait_for(self._process.wait(), timeout=5.0)
-            except asyncio.TimeoutError:
-                self._process.kill()
-            self._process = None
+        await self._stop_adapter_process(terminate)
 
         self._initialized = False
         self._launched = False
@@ -430,11 +421,13 @@ class NodeAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_function_breakpoints(self, names: list[str]) -> list[Breakpoint]:
-        """Set breakpoints on function names."""
+    async def set_function_breakpoints(
+        self,
+        breakpoints: list[dict[str, Any]],
+    ) -> list[Breakpoint]:
+        """Replace all function breakpoints."""
         client = self._require_initialized()
 
-        breakpoints = [{"name": name} for name in names]
         response = await client.send_request(
             "setFunctionBreakpoints",
             {"breakpoints": breakpoints},
@@ -442,19 +435,32 @@ class NodeAdapter(DebugAdapter):
 
         return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
-    async def set_exception_breakpoints(self, filters: list[str]) -> None:
+    async def set_exception_breakpoints(
+        self,
+        filters: list[str],
+        filter_options: list[dict[str, Any]] | None = None,
+    ) -> list[Breakpoint]:
         """Configure exception breakpoints.
 
         Node.js exception filters:
         - "all": Break on all exceptions
         - "uncaught": Break on uncaught exceptions
+
+        Args:
+            filters: Filter IDs to enable without options
+            filter_options: Filters with conditions ({"filterId", "condition"})
+
+        Returns:
+            Per-filter results, in the order filters then filter_options
         """
         client = self._require_initialized()
 
-        await client.send_request(
-            "setExceptionBreakpoints",
-            {"filters": filters},
-        )
+        args: dict[str, Any] = {"filters": filters}
+        if filter_options:
+            args["filterOptions"] = filter_options
+        response = await client.send_request("setExceptionBreakpoints", args)
+
+        return [Breakpoint(**bp) for bp in response.get("breakpoints", [])]
 
     async def continue_execution(self, thread_id: int | None = None) -> None:
         """Continue execution."""
@@ -533,6 +539,7 @@ class NodeAdapter(DebugAdapter):
         variables_reference: int,
         start: int = 0,
         count: int = 0,
+        filter: str | None = None,
     ) -> list[Variable]:
         """Get variables for a scope or variable reference."""
         client = self._require_initialized()
@@ -542,6 +549,8 @@ class NodeAdapter(DebugAdapter):
             args["start"] = start
         if count > 0:
             args["count"] = count
+        if filter:
+            args["filter"] = filter
 
         response = await client.send_request("variables", args)
 
@@ -582,6 +591,7 @@ class NodeAdapter(DebugAdapter):
             "breakpoint": EventType.BREAKPOINT,
             "thread": EventType.THREAD,
             "module": EventType.MODULE,
+            "loadedSource": EventType.LOADED_SOURCE,
         }
 
         # Handle output events specially
@@ -0,0 +1,16 @@
+"""Structured rendering of values, one renderer per debug adapter.
+
+A renderer turns a variable or expression into something more readable
+than the adapter's repr (see ValueRenderer). Adapters pick theirs with
+the DebugAdapter.renderer class attribute.
+"""
+
+from polybugger_mcp.adapters.renderers.base import RENDER_MODES, RenderError, ValueRenderer
+from polybugger_mcp.adapters.renderers.python import PythonRenderer
+
+__all__ = [
+    "RENDER_MODES",
+    "PythonRenderer",
+    "RenderError",
+    "ValueRenderer",
+]
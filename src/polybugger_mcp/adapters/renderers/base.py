@@ -0,0 +1,50 @@
+"""Renderer interface shared by the language-specific renderers."""
+
+from collections.abc import Awaitable, Callable
+from typing import Any
+
+# Every render mode a tool accepts; a renderer supports a subset
+RENDER_MODES = ("raw", "summary", "json")
+
+# Evaluates an expression in the debuggee and returns its complete repr
+Evaluate = Callable[[str], Awaitable[str]]
+
+
+class RenderError(Exception):
+    """A value couldn't be rendered in the requested mode."""
+
+
+class ValueRenderer:
+    """Renders values as the adapter's own text, with no structured modes.
+
+    Subclasses add modes by building expressions for the debuggee to
+    evaluate, so that only a bounded result crosses the wire.
+    """
+
+    modes: tuple[str, ...] = ("raw",)
+
+    async def render(
+        self,
+        evaluate: Evaluate,
+        expression: str,
+        mode: str,
+        max_items: int,
+        json_max_chars: int,
+    ) -> dict[str, Any]:
+        """Render expression's value in mode.
+
+        Args:
+            evaluate: Evaluates an expression in the right frame
+            expression: Expression naming the value
+            mode: One of modes, other than "raw"
+            max_items: Items previewed per container
+            json_max_chars: JSON text returned before it is cut off
+
+        Returns:
+            {"summary": {...}} or {"json": value}; a cut off JSON rendering
+            is {"json_text": prefix, "json_truncated": True} instead
+
+        Raises:
+            RenderError: If the value can't be rendered this way
+        """
+        raise RenderError(f"render mode '{mode}' is not supported by this debug adapter")
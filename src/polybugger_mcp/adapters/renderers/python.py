@@ -0,0 +1,118 @@
+"""Renderer for Python values (debugpy).
+
+Each mode evaluates one expression template in the debuggee. The
+templates are single expressions, so they work in any evaluate context,
+and they bound what they produce: containers are sliced to max_items and
+JSON is encoded incrementally until json_max_chars is reached.
+"""
+
+import ast
+import json
+from typing import Any
+
+from polybugger_mcp.adapters.renderers.base import Evaluate, RenderError, ValueRenderer
+from polybugger_mcp.core.exceptions import DAPError
+
+# Characters kept from each previewed item's repr
+ITEM_MAX_CHARS = 100
+
+# Evaluates to (kind, type name); kind selects the summary template
+KIND_EXPRESSION = (
+    "(lambda v: (lambda t: ("
+    "'dataframe' if t.__module__.startswith('pandas') and t.__name__ == 'DataFrame' "
+    "else 'ndarray' if t.__module__ == 'numpy' and t.__name__ == 'ndarray' "
+    "else 'dataclass' if hasattr(t, '__dataclass_fields__') "
+    "else 'mapping' if isinstance(v, dict) "
+    "else 'collection' if isinstance(v, (list, tuple, set, frozenset)) "
+    "else 'other', t.__name__))(type(v)))({expr})"
+)
+
+# Summary templates by kind; each evaluates to a JSON string
+SUMMARY_EXPRESSIONS: dict[str, str] = {
+    "collection": (
+        "(lambda v, i=__import__('itertools'): __import__('json').dumps({{"
+        "'len': len(v), "
+        "'items': [repr(x)[:{c}] for x in i.islice(v, {n})]}}))({expr})"
+    ),
+    "mapping": (
+        "(lambda v, i=__import__('itertools'): __import__('json').dumps({{"
+        "'len': len(v), "
+        "'items': {{repr(k)[:{c}]: repr(x)[:{c}] for k, x in i.islice(v.items(), {n})}}}}))"
+        "({expr})"
+    ),
+    "dataframe": (
+        "(lambda v, i=__import__('itertools'): __import__('json').dumps({{"
+        "'shape': list(v.shape), "
+        "'columns': len(v.columns), "
+        "'dtypes': {{str(k): str(d) for k, d in i.islice(v.dtypes.items(), {n})}}}}))({expr})"
+    ),
+    "ndarray": (
+        "(lambda v: __import__('json').dumps({{"
+        "'shape': list(v.shape), 'dtype': str(v.dtype), 'size': int(v.size)}}))({expr})"
+    ),
+    "dataclass": (
+        "(lambda v, i=__import__('itertools'): __import__('json').dumps({{"
+        "'field_count': len(v.__dataclass_fields__), "
+        "'fields': list(i.islice(v.__dataclass_fields__, {n}))}}))({expr})"
+    ),
+}
+
+# Evaluates to the value's JSON text, stopping at the first chunk past {cap}
+JSON_EXPRESSION = (
+    "(lambda a, b, i: ''.join(c for _, c in i.takewhile("
+    "lambda p: p[0] - len(p[1]) <= {cap}, zip(i.accumulate(map(len, a)), b))))"
+    "(*__import__('itertools').tee(__import__('json').JSONEncoder().iterencode({expr})), "
+    "__import__('itertools'))"
+)
+
+
+class PythonRenderer(ValueRenderer):
+    """Summaries for containers, DataFrames, ndarrays and dataclasses; JSON for the rest."""
+
+    modes = ("raw", "summary", "json")
+
+    async def render(
+        self,
+        evaluate: Evaluate,
+        expression: str,
+        mode: str,
+        max_items: int,
+        json_max_chars: int,
+    ) -> dict[str, Any]:
+        """Render expression's value as a summary or as JSON."""
+        if mode == "summary":
+            kind, type_name = await self._evaluate_literal(
+                evaluate, KIND_EXPRESSION.format(expr=expression)
+            )
+            template = SUMMARY_EXPRESSIONS.get(kind)
+            if template is None:
+                raise RenderError(f"no summary for values of type '{type_name}'")
+            text = await self._evaluate_literal(
+                evaluate, template.format(expr=expression, n=max_items, c=ITEM_MAX_CHARS)
+            )
+            summary: dict[str, Any] = {"kind": kind, "type": type_name, **json.loads(text)}
+            return {"summary": summary}
+
+        if mode == "json":
+            text = await self._evaluate_literal(
+                evaluate, JSON_EXPRESSION.format(expr=expression, cap=json_max_chars)
+            )
+            if len(text) > json_max_chars:
+                return {"json_text": text[:json_max_chars], "json_truncated": True}
+            return {"json": json.loads(text)}
+
+        return await super().render(evaluate, expression, mode, max_items, json_max_chars)
+
+    @staticmethod
+    async def _evaluate_literal(evaluate: Evaluate, expression: str) -> Any:
+        """Evaluate a template and decode the Python literal of its result."""
+        try:
+            text = await evaluate(expression)
+        except DAPError as e:
+            # The adapter reports a traceback; its last line names the problem
+            lines = [line for line in e.message.splitlines() if line.strip()]
+            raise RenderError(lines[-1].strip() if lines else e.message) from e
+        try:
+            return ast.literal_eval(text)
+        except (ValueError, SyntaxError) as e:
+            raise RenderError(f"unexpected rendering result: {text[:ITEM_MAX_CHARS]}") from e
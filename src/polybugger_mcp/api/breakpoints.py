@@ -50,8 +50,10 @@ async def set_breakpoints(
                 column=bp.column,
                 message=bp.message,
                 source=request.source,
+                condition=requested.condition,
+                hit_condition=requested.hit_condition,
             )
-            for bp in results
+            for bp, requested in zip(results, [b for b in breakpoints if b.enabled])
         ]
     )
 
@@ -68,6 +70,8 @@ async def list_breakpoints(session: SessionDep) -> BreakpointListResponse:
                 line=bp.line,
                 column=bp.column,
                 source=file_path,
+                condition=bp.condition,
+                hit_condition=bp.hit_condition,
             )
             for bp in breakpoints
         ]
@@ -8,19 +8,29 @@ from typing import Any
 from fastapi import FastAPI, Request
 from fastapi.responses import JSONResponse
 
+from polybugger_mcp.adapters.factory import UnknownAdapterError
 from polybugger_mcp.core.exceptions import (
     BreakpointNotFoundError,
+    CapabilityNotSupportedError,
     DAPConnectionError,
     DAPTimeoutError,
     DebugRelayError,
     FrameNotFoundError,
     InvalidSessionStateError,
     LaunchError,
+    NoSymbolAtPositionError,
+    NotStoppedOnExceptionError,
+    PreLaunchError,
+    ProgramExitedError,
+    ServerShuttingDownError,
     SessionExpiredError,
     SessionLimitError,
     SessionNotFoundError,
+    StdinUnavailableError,
     ThreadNotFoundError,
+    UnverifiedBreakpointError,
     VariableNotFoundError,
+    WatchNotFoundError,
 )
 
 logger = logging.getLogger(__name__)
@@ -35,9 +45,19 @@ EXCEPTION_STATUS_MAP = {
     ThreadNotFoundError: 404,
     FrameNotFoundError: 404,
     VariableNotFoundError: 404,
+    WatchNotFoundError: 404,
     DAPTimeoutError: 504,
     DAPConnectionError: 502,
     LaunchError: 500,
+    ProgramExitedError: 422,
+    PreLaunchError: 422,
+    CapabilityNotSupportedError: 501,
+    StdinUnavailableError: 409,
+    NotStoppedOnExceptionError: 409,
+    NoSymbolAtPositionError: 422,
+    UnverifiedBreakpointError: 422,
+    UnknownAdapterError: 400,
+    ServerShuttingDownError: 503,
 }
 
 
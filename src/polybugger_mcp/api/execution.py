@@ -3,8 +3,14 @@
 from fastapi import APIRouter
 
 from polybugger_mcp.api.deps import SessionDep
-from polybugger_mcp.models.requests import ContinueRequest, PauseRequest, StepRequest
-from polybugger_mcp.models.responses import ExecutionResponse, LocationResponse
+from polybugger_mcp.models.requests import (
+    ContinueRequest,
+    PauseRequest,
+    RunToLineRequest,
+    SendStdinRequest,
+    StepRequest,
+)
+from polybugger_mcp.models.responses import ExecutionResponse, LocationResponse, StdinResponse
 
 router = APIRouter(prefix="/sessions/{session_id}", tags=["Execution"])
 
@@ -56,7 +62,9 @@ async def step_over(
 ) -> ExecutionResponse:
     """Step over to the next line."""
     thread_id = request.thread_id if request else None
-    await session.step_over(thread_id)
+    sticky = request.sticky_goroutine if request else None
+    granularity = request.granularity if request else "line"
+    await session.step_over(thread_id, sticky, granularity)
     return ExecutionResponse(
         status=session.state.value,
         location=_make_location(session),
@@ -70,7 +78,9 @@ async def step_into(
 ) -> ExecutionResponse:
     """Step into a function call."""
     thread_id = request.thread_id if request else None
-    await session.step_into(thread_id)
+    sticky = request.sticky_goroutine if request else None
+    granularity = request.granularity if request else "line"
+    await session.step_into(thread_id, sticky, granularity)
     return ExecutionResponse(
         status=session.state.value,
         location=_make_location(session),
@@ -84,8 +94,62 @@ async def step_out(
 ) -> ExecutionResponse:
     """Step out of the current function."""
     thread_id = request.thread_id if request else None
-    await session.step_out(thread_id)
+    sticky = request.sticky_goroutine if request else None
+    granularity = request.granularity if request else "line"
+    await session.step_out(thread_id, sticky, granularity)
     return ExecutionResponse(
         status=session.state.value,
         location=_make_location(session),
     )
+
+
+@router.post("/step-back", response_model=ExecutionResponse)
+async def step_back(
+    session: SessionDep,
+    request: StepRequest | None = None,
+) -> ExecutionResponse:
+    """Step backwards to the previous line (requires reverse execution)."""
+    thread_id = request.thread_id if request else None
+    await session.step_back(thread_id)
+    return ExecutionResponse(
+        status=session.state.value,
+        location=_make_location(session),
+    )
+
+
+@router.post("/reverse-continue", response_model=ExecutionResponse)
+async def reverse_continue(
+    session: SessionDep,
+    request: ContinueRequest | None = None,
+) -> ExecutionResponse:
+    """Run backwards to the previous breakpoint (requires reverse execution)."""
+    thread_id = request.thread_id if request else None
+    await session.reverse_continue(thread_id)
+    return ExecutionResponse(
+        status=session.state.value,
+        location=_make_location(session),
+    )
+
+
+@router.post("/run-to-line", response_model=ExecutionResponse)
+async def run_to_line(
+    session: SessionDep,
+    request: RunToLineRequest,
+) -> ExecutionResponse:
+    """Continue until the given line via a temporary breakpoint."""
+    await session.run_to_line(request.file, request.line, request.thread_id)
+    return ExecutionResponse(
+        status=session.state.value,
+        location=_make_location(session),
+    )
+
+
+@router.post("/stdin", response_model=StdinResponse)
+async def send_stdin(
+    session: SessionDep,
+    request: SendStdinRequest,
+) -> StdinResponse:
+    """Write to the program's stdin (needs stdin_mode "pipe")."""
+    data = request.data + "\n" if request.newline else request.data
+    written = await session.send_stdin(data.encode("utf-8"))
+    return StdinResponse(bytes_written=written)
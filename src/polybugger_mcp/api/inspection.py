@@ -1,5 +1,7 @@
 """State inspection endpoints."""
 
+from typing import Literal
+
 from fastapi import APIRouter, Query
 
 from polybugger_mcp.api.deps import SessionDep
@@ -21,10 +23,22 @@ router = APIRouter(prefix="/sessions/{session_id}", tags=["Inspection"])
 
 
 @router.get("/threads", response_model=ThreadListResponse)
-async def get_threads(session: SessionDep) -> ThreadListResponse:
-    """Get all threads."""
+async def get_threads(
+    session: SessionDep,
+    limit: int = Query(100, ge=1, le=10000, description="Max threads to return"),
+    offset: int = Query(0, ge=0, description="Number of threads to skip"),
+) -> ThreadListResponse:
+    """Get threads, marking the one that reported the last stop."""
     threads = await session.get_threads()
-    return ThreadListResponse(threads=[ThreadResponse(id=t.id, name=t.name) for t in threads])
+    page = threads[offset : offset + limit]
+    return ThreadListResponse(
+        threads=[
+            ThreadResponse(id=t.id, name=t.name, stopped=t.id == session.current_thread_id)
+            for t in page
+        ],
+        stopped_thread_id=session.current_thread_id,
+        total=len(threads),
+    )
 
 
 @router.get("/stacktrace", response_model=StackTraceResponse)
@@ -81,10 +95,15 @@ async def get_variables(
     variables_ref: int = Query(..., alias="ref", description="Variable reference"),
     start: int = Query(0, ge=0, description="Starting index"),
     count: int = Query(100, ge=1, le=1000, description="Number of variables"),
+    filter: Literal["indexed", "named"] | None = Query(None, description="Child kind"),
 ) -> VariablesResponse:
     """Get variables for a scope or compound variable."""
-    variables = await session.get_variables(variables_ref, start, count)
+    variables = await session.get_variables(variables_ref, start, count, filter)
+    counts = session.variable_counts(variables_ref)
     return VariablesResponse(
+        start=start,
+        named_variables=counts["named"],
+        indexed_variables=counts["indexed"],
         variables=[
             VariableResponse(
                 name=v.name,
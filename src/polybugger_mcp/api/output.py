@@ -20,12 +20,20 @@ async def get_output(
     limit: int = Query(1000, ge=1, le=10000, description="Maximum lines"),
     category: str | None = Query(None, description="Filter by category"),
     since: int | None = Query(None, ge=0, description="Get lines after this line number"),
+    logpoints_only: bool = Query(False, description="Only return logpoint messages"),
+    logpoint_id: int | None = Query(None, description="Only return output from this logpoint"),
 ) -> OutputResponse:
     """Get captured output from the debug target."""
     if since is not None:
-        page = session.output_buffer.get_since(since, limit)
+        page = session.output_buffer.get_since(since, limit, category)
     else:
-        page = session.output_buffer.get_page(offset, limit, category)
+        page = session.output_buffer.get_page(
+            offset,
+            limit,
+            category,
+            logpoints_only=logpoints_only,
+            logpoint_id=logpoint_id,
+        )
 
     return OutputResponse(
         lines=[
@@ -34,6 +42,7 @@ async def get_output(
                 category=line.category,
                 content=line.content,
                 timestamp=line.timestamp,
+                logpoint_id=line.logpoint_id,
             )
             for line in page.lines
         ],
@@ -1,6 +1,6 @@
 """Session management endpoints."""
 
-from fastapi import APIRouter, status
+from fastapi import APIRouter, Query, status
 
 from polybugger_mcp.api.deps import SessionDep, SessionManagerDep
 from polybugger_mcp.models.dap import AttachConfig, LaunchConfig
@@ -11,6 +11,7 @@ from polybugger_mcp.models.requests import (
 )
 from polybugger_mcp.models.responses import (
     ExecutionResponse,
+    RestartResponse,
     SessionListResponse,
     SessionResponse,
 )
@@ -56,9 +57,12 @@ async def get_session(session: SessionDep) -> SessionResponse:
 async def terminate_session(
     session_id: str,
     session_manager: SessionManagerDep,
+    terminate_debuggee: bool | None = Query(
+        None, description="Kill the debug target (default: only if launched, not attached)"
+    ),
 ) -> None:
     """Terminate a debug session."""
-    await session_manager.terminate_session(session_id)
+    await session_manager.terminate_session(session_id, terminate_debuggee)
 
 
 @router.post("/{session_id}/launch", response_model=ExecutionResponse)
@@ -77,6 +81,12 @@ async def launch_program(
         python_path=request.python_path,
         stop_on_entry=request.stop_on_entry,
         stop_on_exception=request.stop_on_exception,
+        stdin_mode=request.stdin_mode,
+        adapter=request.adapter,
+        path_mappings=request.path_mappings,
+        just_my_code=request.just_my_code,
+        step_filters=request.step_filters,
+        pre_launch=request.pre_launch,
     )
     await session.launch(config)
     return ExecutionResponse(status=session.state.value)
@@ -92,6 +102,14 @@ async def attach_to_process(
         process_id=request.process_id,
         host=request.host,
         port=request.port,
+        path_mappings=request.path_mappings,
     )
     await session.attach(config)
     return ExecutionResponse(status=session.state.value)
+
+
+@router.post("/{session_id}/restart", response_model=RestartResponse)
+async def restart_program(session: SessionDep) -> RestartResponse:
+    """Restart the launched program, replaying breakpoints."""
+    report = await session.restart()
+    return RestartResponse(status=session.state.value, **report)
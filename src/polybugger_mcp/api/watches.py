@@ -3,6 +3,7 @@
 from fastapi import APIRouter
 
 from polybugger_mcp.api.deps import SessionDep
+from polybugger_mcp.core.session import Session
 from polybugger_mcp.models.requests import (
     AddWatchRequest,
     EvaluateWatchesRequest,
@@ -10,6 +11,7 @@ from polybugger_mcp.models.requests import (
 )
 from polybugger_mcp.models.responses import (
     WatchListResponse,
+    WatchResponse,
     WatchResultResponse,
     WatchResultsResponse,
 )
@@ -17,10 +19,21 @@ from polybugger_mcp.models.responses import (
 router = APIRouter(prefix="/sessions/{session_id}/watches", tags=["Watches"])
 
 
+def _watch_list(session: Session) -> WatchListResponse:
+    """Build the watch list response for a session."""
+    return WatchListResponse(
+        expressions=session.list_watches(),
+        watches=[
+            WatchResponse(id=w["id"], expression=w["expression"])
+            for w in session.describe_watches()
+        ],
+    )
+
+
 @router.get("", response_model=WatchListResponse)
 async def list_watches(session: SessionDep) -> WatchListResponse:
     """List all watch expressions for a session."""
-    return WatchListResponse(expressions=session.list_watches())
+    return _watch_list(session)
 
 
 @router.post("", response_model=WatchListResponse)
@@ -29,8 +42,8 @@ async def add_watch(
     request: AddWatchRequest,
 ) -> WatchListResponse:
     """Add a watch expression."""
-    expressions = session.add_watch(request.expression)
-    return WatchListResponse(expressions=expressions)
+    session.add_watch(request.expression)
+    return _watch_list(session)
 
 
 @router.delete("", response_model=WatchListResponse)
@@ -39,8 +52,8 @@ async def remove_watch(
     request: RemoveWatchRequest,
 ) -> WatchListResponse:
     """Remove a watch expression."""
-    expressions = session.remove_watch(request.expression)
-    return WatchListResponse(expressions=expressions)
+    session.remove_watch(request.expression)
+    return _watch_list(session)
 
 
 @router.delete("/all", response_model=WatchListResponse)
@@ -64,6 +77,7 @@ async def evaluate_watches(
     return WatchResultsResponse(
         results=[
             WatchResultResponse(
+                id=r["id"],
                 expression=r["expression"],
                 result=r["result"],
                 type=r["type"],
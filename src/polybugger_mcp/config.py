@@ -33,12 +33,58 @@ class Settings(BaseSettings):
         le=500 * 1024 * 1024,  # Max 500MB
     )
 
+    # Output streaming (MCP notifications)
+    output_stream_interval_seconds: float = Field(default=0.1, ge=0.01, le=10.0)
+    output_stream_max_bytes: int = Field(default=64 * 1024, ge=1024, le=4 * 1024 * 1024)
+
+    # Output log file (debug_launch output_log_path): size-based rotation
+    output_log_max_bytes: int = Field(
+        default=10 * 1024 * 1024,  # 10MB per file
+        ge=64 * 1024,
+        le=1024 * 1024 * 1024,
+    )
+    output_log_backup_count: int = Field(default=3, ge=0, le=100)
+    output_log_queue_size: int = Field(default=10000, ge=100, le=1_000_000)
+
+    # Value rendering: evaluate/get_variables truncate, get_full_value pages
+    value_max_length: int = Field(default=1000, ge=16, le=1024 * 1024)
+    full_value_chunk_chars: int = Field(default=32 * 1024, ge=1024, le=1024 * 1024)
+
+    # get_stacktrace include_source_context: source bytes attached per call
+    stack_source_max_bytes: int = Field(default=64 * 1024, ge=1024, le=16 * 1024 * 1024)
+
+    # Variables one expand_variable call may return, across all levels
+    expand_max_nodes: int = Field(default=500, ge=10, le=10000)
+
+    # render="summary"/"json": items previewed per container, and JSON
+    # characters returned before the rendering is cut off
+    render_max_items: int = Field(default=10, ge=1, le=1000)
+    render_json_max_chars: int = Field(default=16 * 1024, ge=256, le=1024 * 1024)
+
+    # Times a sticky step is re-issued before reporting a stop on another thread
+    sticky_step_max_retries: int = Field(default=5, ge=0, le=100)
+
+    # Step-outs taken to leave filtered code before stopping there anyway
+    step_filter_max_steps: int = Field(default=20, ge=0, le=1000)
+
+    # Session history: entries kept per session, and seconds a terminated
+    # session's history stays readable before it is discarded
+    history_max_entries: int = Field(default=2000, ge=100, le=100_000)
+    history_retention_seconds: int = Field(default=900, ge=0, le=86400)
+
+    # Server shutdown: seconds each adapter gets to disconnect before the
+    # processes we started are killed, and whether attached and remote
+    # debuggees are terminated too (by default they're left running)
+    shutdown_grace_seconds: float = Field(default=5.0, ge=0.0, le=60.0)
+    shutdown_terminate_attached: bool = False
+
     # Persistence
     data_dir: Path = Field(default_factory=lambda: Path.home() / ".polybugger-mcp")
 
     # DAP settings
     dap_timeout_seconds: float = Field(default=30.0, ge=1.0, le=300.0)
     dap_launch_timeout_seconds: float = Field(default=60.0, ge=5.0, le=600.0)
+    dap_attach_timeout_seconds: float = Field(default=15.0, ge=1.0, le=300.0)
 
     # Python settings
     default_python_path: str | None = None
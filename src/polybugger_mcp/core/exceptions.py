@@ -35,13 +35,33 @@ class SessionNotFoundError(SessionError):
         )
 
 
+class SessionRequiredError(SessionError):
+    """No session ID given and there isn't exactly one session to default to."""
+
+    def __init__(self, session_ids: list[str]):
+        if session_ids:
+            super().__init__(
+                code="SESSION_AMBIGUOUS",
+                message=f"{len(session_ids)} sessions are active; specify session_id "
+                f"(one of: {', '.join(session_ids)})",
+                details={"session_ids": session_ids},
+            )
+        else:
+            super().__init__(
+                code="NO_SESSION",
+                message="No active debug session; create one first",
+                details={"session_ids": []},
+            )
+
+
 class SessionLimitError(SessionError):
     """Maximum concurrent sessions reached."""
 
     def __init__(self, max_sessions: int):
         super().__init__(
             code="SESSION_LIMIT_REACHED",
-            message=f"Maximum of {max_sessions} concurrent sessions reached",
+            message=f"Too many sessions: the maximum of {max_sessions} concurrent "
+            "sessions is reached; terminate one first",
             details={"max_sessions": max_sessions},
         )
 
@@ -101,6 +121,43 @@ class DAPConnectionError(DAPError):
         )
 
 
+class AdapterExitedError(DAPError):
+    """The debug adapter process died or dropped its connection."""
+
+    def __init__(self, reason: str, exit_code: int | None = None, stderr: list[str] | None = None):
+        message = f"Debug adapter exited unexpectedly: {reason}"
+        if exit_code is not None:
+            message += f" (exit code {exit_code})"
+        if stderr:
+            message += f"; last stderr: {stderr[-1]}"
+        super().__init__(
+            code="ADAPTER_EXITED",
+            message=message,
+            details={"reason": reason, "exit_code": exit_code, "stderr": stderr or []},
+        )
+
+
+class DAPClientClosedError(DAPError):
+    """A request was outstanding, or sent, after the DAP connection was closed."""
+
+    def __init__(self, command: str):
+        super().__init__(
+            code="DAP_CLIENT_CLOSED",
+            message=f"'{command}' was cancelled: the debug adapter connection was closed",
+            details={"command": command},
+        )
+
+
+class ServerShuttingDownError(DAPError):
+    """The server is shutting down, ending every session."""
+
+    def __init__(self) -> None:
+        super().__init__(
+            code="SERVER_SHUTTING_DOWN",
+            message="The server is shutting down; debug sessions are being ended",
+        )
+
+
 class LaunchError(DAPError):
     """Failed to launch debug target."""
 
@@ -112,6 +169,86 @@ class LaunchError(DAPError):
         )
 
 
+class ProgramExitedError(DAPError):
+    """The program exited before the launch handshake finished."""
+
+    def __init__(self, exit_code: int | None, output: str):
+        code_text = f"code {exit_code}" if exit_code is not None else "an unknown code"
+        super().__init__(
+            code="PROGRAM_EXITED",
+            message=f"The program exited with {code_text} before launch completed",
+            details={"exit_code": exit_code, "output": output},
+        )
+
+
+class PreLaunchError(DAPError):
+    """A pre-launch step failed, so the adapter was never started."""
+
+    def __init__(
+        self,
+        step: int,
+        command: str,
+        reason: str,
+        exit_code: int | None = None,
+        stderr_tail: str = "",
+    ):
+        super().__init__(
+            code="PRE_LAUNCH_FAILED",
+            message=f"Pre-launch step {step} ({command}) {reason}",
+            details={
+                "step": step,
+                "command": command,
+                "exit_code": exit_code,
+                "stderr_tail": stderr_tail,
+            },
+        )
+
+
+class LaunchConfigError(DebugRelayError):
+    """A launch.json entry can't be read or turned into a launch."""
+
+    def __init__(self, reason: str, details: dict[str, Any] | None = None):
+        super().__init__(
+            code="INVALID_LAUNCH_CONFIG",
+            message=reason,
+            details=details or {},
+        )
+
+
+class CapabilityNotSupportedError(DebugRelayError):
+    """Debug adapter does not advertise a required capability."""
+
+    def __init__(self, capability: str, feature: str):
+        super().__init__(
+            code="CAPABILITY_NOT_SUPPORTED",
+            message=f"The debug adapter does not support {feature}",
+            details={"capability": capability, "feature": feature},
+        )
+
+
+class StdinUnavailableError(DebugRelayError):
+    """The debuggee's stdin can't be written to."""
+
+    def __init__(self, session_id: str, reason: str):
+        super().__init__(
+            code="STDIN_UNAVAILABLE",
+            message=f"Cannot send stdin in session '{session_id}': {reason}",
+            details={"session_id": session_id, "reason": reason},
+        )
+
+
+class NotStoppedOnExceptionError(DebugRelayError):
+    """Exception details were requested but the last stop wasn't an exception."""
+
+    def __init__(self, session_id: str, stop_reason: str | None):
+        super().__init__(
+            code="NOT_STOPPED_ON_EXCEPTION",
+            message=f"Session '{session_id}' is not stopped on an exception "
+            f"(last stop reason: {stop_reason})",
+            details={"session_id": session_id, "stop_reason": stop_reason},
+        )
+
+
 class PersistenceError(DebugRelayError):
     """Persistence layer errors."""
 
@@ -135,6 +272,49 @@ class BreakpointNotFoundError(BreakpointError):
         )
 
 
+class UnverifiedBreakpointError(BreakpointError):
+    """The adapter could not place a breakpoint on the requested line."""
+
+    def __init__(self, file_path: str, line: int, reason: str | None = None):
+        super().__init__(
+            code="BREAKPOINT_UNVERIFIED",
+            message=f"Cannot break at {file_path}:{line}: {reason or 'breakpoint not verified'}",
+            details={"file": file_path, "line": line, "reason": reason},
+        )
+
+
+class DataBreakpointError(BreakpointError):
+    """The adapter can't watch the requested data."""
+
+    def __init__(self, target: str, reason: str, access_types: list[str] | None = None):
+        super().__init__(
+            code="DATA_BREAKPOINT_UNAVAILABLE",
+            message=f"Cannot watch '{target}': {reason}",
+            details={"target": target, "reason": reason, "access_types": access_types},
+        )
+
+
+class InvalidExceptionFilterError(BreakpointError):
+    """Exception breakpoint filter not offered by the adapter."""
+
+    def __init__(self, reason: str, available_filters: list[str]):
+        super().__init__(
+            code="INVALID_EXCEPTION_FILTER",
+            message=reason,
+            details={"available_filters": available_filters},
+        )
+
+
+class NoBreakpointsError(BreakpointError):
+    """Nothing would stop the program: no enabled breakpoints and no probes."""
+
+    def __init__(self) -> None:
+        super().__init__(
+            code="NO_BREAKPOINTS",
+            message="No breakpoints to stop at: set some or pass probes",
+        )
+
+
 class ThreadNotFoundError(DebugRelayError):
     """Thread with given ID does not exist."""
 
@@ -149,11 +329,16 @@ class ThreadNotFoundError(DebugRelayError):
 class FrameNotFoundError(DebugRelayError):
     """Stack frame with given ID does not exist."""
 
-    def __init__(self, session_id: str, frame_id: int):
+    def __init__(self, session_id: str, frame_id: int, stale: bool = False):
+        message = (
+            f"Frame '{frame_id}' is no longer valid; execution resumed since it was fetched"
+            if stale
+            else f"Frame '{frame_id}' not found in session '{session_id}'"
+        )
         super().__init__(
             code="FRAME_NOT_FOUND",
-            message=f"Frame '{frame_id}' not found in session '{session_id}'",
-            details={"session_id": session_id, "frame_id": frame_id},
+            message=message,
+            details={"session_id": session_id, "frame_id": frame_id, "stale": stale},
         )
 
 
@@ -168,6 +353,40 @@ class VariableNotFoundError(DebugRelayError):
         )
 
 
+class WatchNotFoundError(DebugRelayError):
+    """Watch expression with given ID does not exist."""
+
+    def __init__(self, session_id: str, watch_id: int):
+        super().__init__(
+            code="WATCH_NOT_FOUND",
+            message=f"Watch '{watch_id}' not found in session '{session_id}'",
+            details={"session_id": session_id, "watch_id": watch_id},
+        )
+
+
+class ContinuationTokenError(DebugRelayError):
+    """Continuation token is unknown or from before the last resume."""
+
+    def __init__(self, session_id: str, token: str):
+        super().__init__(
+            code="INVALID_TOKEN",
+            message=f"Continuation token '{token}' is unknown or expired in session "
+            f"'{session_id}'",
+            details={"session_id": session_id, "token": token},
+        )
+
+
+class NoSymbolAtPositionError(DebugRelayError):
+    """No identifier at the source position given for hover inspection."""
+
+    def __init__(self, file_path: str, line: int, column: int, reason: str):
+        super().__init__(
+            code="NO_SYMBOL_AT_POSITION",
+            message=f"No symbol at {file_path}:{line}:{column}: {reason}",
+            details={"file": file_path, "line": line, "column": column, "reason": reason},
+        )
+
+
 class EvaluateError(DebugRelayError):
     """Expression evaluation failed."""
 
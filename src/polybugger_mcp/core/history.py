@@ -0,0 +1,106 @@
+"""Per-session timeline of debug events and tool calls, for post-mortem review."""
+
+from collections import deque
+from dataclasses import dataclass, field
+from datetime import datetime, timezone
+from typing import Any
+
+# Longest string kept from a tool argument or result
+SUMMARY_MAX_CHARS = 200
+
+
+@dataclass
+class HistoryEntry:
+    """One thing that happened in a session."""
+
+    seq: int  # Increases by one per entry, starting at 1
+    kind: str  # "event" (from the debug adapter) or "tool" (an MCP tool call)
+    type: str  # Event type, e.g. "stopped", or the tool name
+    data: dict[str, Any]
+    timestamp: datetime = field(default_factory=lambda: datetime.now(timezone.utc))
+
+    def to_dict(self) -> dict[str, Any]:
+        """JSON-friendly form of the entry."""
+        return {
+            "seq": self.seq,
+            "timestamp": self.timestamp.isoformat(),
+            "kind": self.kind,
+            "type": self.type,
+            "data": self.data,
+        }
+
+
+def summarize(value: Any) -> Any:
+    """Shorten long strings, recursing through lists and dicts."""
+    if isinstance(value, str):
+        if len(value) > SUMMARY_MAX_CHARS:
+            return value[:SUMMARY_MAX_CHARS] + "..."
+        return value
+    if isinstance(value, list):
+        return [summarize(v) for v in value]
+    if isinstance(value, dict):
+        return {k: summarize(v) for k, v in value.items()}
+    return value
+
+
+class SessionHistory:
+    """Append-only log of a session's events, bounded like a ring buffer.
+
+    Once max_entries is reached the oldest entries are dropped; seq keeps
+    counting, so a reader using "since" can tell that it missed some.
+    Consecutive output events are folded into a single summary entry.
+    """
+
+    def __init__(self, max_entries: int = 2000):
+        self.max_entries = max_entries
+        self._entries: deque[HistoryEntry] = deque(maxlen=max_entries)
+        self._seq = 0
+
+    def record(self, kind: str, type: str, data: dict[str, Any]) -> HistoryEntry:
+        """Append an entry and return it."""
+        self._seq += 1
+        entry = HistoryEntry(seq=self._seq, kind=kind, type=type, data=summarize(data))
+        self._entries.append(entry)
+        return entry
+
+    def record_output(self, category: str, text: str) -> None:
+        """Count output, adding to the summary entry if the last entry is one."""
+        last = self._entries[-1] if self._entries else None
+        if last is None or last.type != "output":
+            last = self.record(
+                "event", "output", {"events": 0, "chars": 0, "categories": [], "first": text}
+            )
+        last.data["events"] += 1
+        last.data["chars"] += len(text)
+        if category not in last.data["categories"]:
+            last.data["categories"].append(category)
+
+    def entries(
+        self,
+        since: int = 0,
+        event_type: str | None = None,
+        limit: int | None = None,
+    ) -> list[HistoryEntry]:
+        """Entries after seq since, oldest first.
+
+        Args:
+            since: Only entries with a larger seq
+            event_type: Only entries of this type, or of this kind ("event", "tool")
+            limit: Return at most this many
+        """
+        found = [
+            e
+            for e in self._entries
+            if e.seq > since and (event_type is None or event_type in (e.type, e.kind))
+        ]
+        return found[:limit] if limit is not None else found
+
+    @property
+    def last_seq(self) -> int:
+        """Seq of the newest entry (0 if nothing was recorded)."""
+        return self._seq
+
+    @property
+    def dropped(self) -> int:
+        """Entries pushed out by the max_entries bound."""
+        return self._seq - len(self._entries)
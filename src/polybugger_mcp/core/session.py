@@ -1,22 +1,52 @@
 """Session lifecycle management."""
 
 import asyncio
+import base64
 import contextlib
 import logging
+import os
+import sys
 import uuid
+from collections import deque
+from collections.abc import Awaitable, Callable, Coroutine
 from datetime import datetime, timezone
 from enum import Enum
 from pathlib import Path
 from typing import Any
 
 from polybugger_mcp.adapters.base import DebugAdapter
-from polybugger_mcp.adapters.factory import create_adapter
+from polybugger_mcp.adapters.factory import (
+    UnknownAdapterError,
+    adapter_registry,
+    get_descriptor,
+)
+from polybugger_mcp.adapters.renderers import RenderError
 from polybugger_mcp.config import settings
 from polybugger_mcp.core.events import EventQueue
+from polybugger_mcp.core.history import HistoryEntry, SessionHistory
 from polybugger_mcp.core.exceptions import (
+    BreakpointNotFoundError,
+    CapabilityNotSupportedError,
+    ContinuationTokenError,
+    DAPError,
+    DataBreakpointError,
+    EvaluateError,
+    FrameNotFoundError,
+    InvalidExceptionFilterError,
     InvalidSessionStateError,
+    LaunchError,
+    NoBreakpointsError,
+    NoSymbolAtPositionError,
+    NotStoppedOnExceptionError,
+    ProgramExitedError,
     SessionLimitError,
     SessionNotFoundError,
+    SessionRequiredError,
+    ServerShuttingDownError,
+    StdinUnavailableError,
+    UnverifiedBreakpointError,
+    VariableNotFoundError,
+    WatchNotFoundError,
 )
 from polybugger_mcp.models.dap import (
     AttachConfig,
@@ -25,6 +55,8 @@ from polybugger_mcp.models.dap import (
     Scope,
     SourceBreakpoint,
     StackFrame,
+    StepFilters,
+    TcpTransport,
     Thread,
     Variable,
 )
@@ -32,10 +64,26 @@ from polybugger_mcp.models.events import EventType
 from polybugger_mcp.models.session import SessionConfig, SessionInfo
 from polybugger_mcp.persistence.breakpoints import BreakpointStore
 from polybugger_mcp.persistence.sessions import PersistedSession, SessionStore
-from polybugger_mcp.utils.output_buffer import OutputBuffer
+from polybugger_mcp.utils.output_buffer import OutputBuffer, OutputLine
+from polybugger_mcp.utils.output_log import OutputLog
+from polybugger_mcp.utils.path_mapper import PathMapper
+from polybugger_mcp.utils.prelaunch import PreLaunchRunner
+from polybugger_mcp.utils.step_filter import StepFilter
+from polybugger_mcp.utils.test_runner import build_test_launch, missing_test_message
 
 logger = logging.getLogger(__name__)
 
+# Trailing output characters reported when the program exits during launch
+_LAUNCH_EXIT_OUTPUT_CHARS = 4000
+
+# Exceptions of a chain fetched looking for its root cause, outermost first
+_EXCEPTION_CHAIN_WALK_LIMIT = 100
+
+
+def _is_true(value: str) -> bool:
+    """Whether an evaluated result is boolean true (Python, Go, JS and C renderings)."""
+    return value.strip().lower() in ("true", "1")
+
 
 class SessionState(str, Enum):
     """Possible session states."""
@@ -58,12 +106,18 @@ class Session:
         name: str | None = None,
         timeout_minutes: int = 60,
         language: str = "python",
+        remote: TcpTransport | None = None,
     ):
         self.id = session_id
         self.project_root = project_root
         self.name = name or f"session-{session_id[:8]}"
         self.timeout_minutes = timeout_minutes
         self.language = language
+        # Registered name of the adapter in use (see adapters.factory)
+        self.adapter_name: str | None = None
+        # DAP server to connect to instead of starting the adapter ("tcp" transport);
+        # ending the session then leaves it, and its debuggee, running by default
+        self.remote = remote
 
         self._state = SessionState.CREATED
         self._state_lock = asyncio.Lock()
@@ -75,22 +129,153 @@ class Session:
         self.adapter: DebugAdapter | None = None
         self.output_buffer = OutputBuffer(max_size=settings.output_buffer_max_bytes)
         self.event_queue = EventQueue()
+        self.history = SessionHistory(max_entries=settings.history_max_entries)
+        self._output_listeners: list[Callable[[str, OutputLine], None]] = []
+        self.output_log: OutputLog | None = None  # Set by launch(output_log_path=...)
+        self._pre_launch: PreLaunchRunner | None = None  # Steps of a launch in progress
 
         # Debug state
+        self.attached = False  # Attached to an existing process (not launched)
+        self.target: str | None = None  # Launched program or attach target
+        self.stdin_mode: str | None = None  # Launch stdin_mode ("pipe", "inherit", "closed")
+        self.launch_cwd: str | None = None  # Resolved working directory of the launch
+        self.launch_env: dict[str, str] | None = None  # Effective debuggee environment
+        self._launch_config: LaunchConfig | None = None  # Kept for restart
+        # Set by launch_test: {"framework", "test_id"}. test_not_found is the
+        # runner's message once it has exited without finding that test
+        self.test_run: dict[str, str] | None = None
+        self.test_not_found: str | None = None
+        self._run_output_start = 0  # Output line number the current run started after
+        self.exit_code: int | None = None  # From the last exited event
+        # While launching: resolved by the first exited/terminated event
+        self._launch_exit: asyncio.Future[None] | None = None
+        # The entry stop the session asked for on the user's behalf is still
+        # to come; it is resumed unseen whenever it arrives (see launch).
+        # _entry_resume is that resume while launch is there to await it
+        self._hidden_entry = False
+        self._entry_resume: asyncio.Task[None] | None = None
+        # Set when the adapter process died: message, reason, exit_code, stderr
+        self.adapter_exit: dict[str, Any] | None = None
+        self._restarting = False  # Native restart in progress; exits don't end the session
+        self._shutting_down = False  # Ended by server shutdown; waits fail rather than return
         self.current_thread_id: int | None = None
         self.stop_reason: str | None = None
+        self.stop_description: str | None = None  # e.g. which watchpoint fired
         self.stop_location: dict[str, Any] | None = None
+        self.exception_info: dict[str, Any] | None = None
+        self._stop_count = 0  # Stopped events seen, to detect stops racing a resume
+        # Latest stop whose follow-up requests (top frame, exception info,
+        # watches) have completed; waiters wake only once their stop is settled
+        self._settled_stops = 0
+        # A continue or step request awaits its response; DAP requests run
+        # concurrently, so a second resume is refused here rather than queued
+        self._resuming = False
+        # Step in flight: {"kind", "thread_id", "sticky", "retries", "skipped"}.
+        # A step stop reported on another thread (delve goroutines) is
+        # re-issued on the original one when sticky, otherwise annotated via
+        # step_thread_id; one landing in filtered code is stepped out of
+        self._pending_step: dict[str, Any] | None = None
+        self.step_thread_id: int | None = None  # Set when a step stopped elsewhere
+        self.skipped_frames = 0  # Filtered frames the last step stepped out of
+        self.step_filter = StepFilter(project_root=project_root)
+        # Notified on every settled stop or exit; waiters compare _settled_stops,
+        # so concurrent waits all see the same stop rather than consuming it
+        self._stop_changed = asyncio.Condition()
 
         # Breakpoints (file path -> list of breakpoints)
         self._breakpoints: dict[str, list[SourceBreakpoint]] = {}
 
-        # Watch expressions (evaluated on each stop)
+        # Adapter-side breakpoint state, keyed by (file path, requested line)
+        self._breakpoint_status: dict[tuple[str, int], Breakpoint] = {}
+        self._breakpoint_ids: dict[int, tuple[str, int]] = {}
+        self._hit_counts: dict[tuple[str, int], int] = {}
+        # Id reported for each breakpoint; stays put across resends and while disabled
+        self._known_breakpoint_ids: dict[tuple[str, int], int] = {}
+
+        # Temporary run-to-line breakpoint (file path, line); never persisted
+        self._run_to_line: tuple[str, int] | None = None
+        # Temporary breakpoints of a continue_until in progress (file -> lines)
+        self._probes: dict[str, set[int]] = {}
+
+        # Local <-> debuggee source paths; breakpoints and frames stay local here
+        self.path_mapper = PathMapper()
+
+        # Data breakpoints (watchpoints) in the order sent; they belong to the
+        # running process, so they are neither persisted nor replayed
+        self._data_breakpoints: list[dict[str, Any]] = []
+        # Function breakpoints, one per name in the order set. They name
+        # symbols rather than lines, so they are replayed on every launch and
+        # restart, and kept apart from the per-file lists setBreakpoints replaces
+        self._function_breakpoints: list[dict[str, Any]] = []
+
+        # Loaded sources (keyed by path or source reference) and modules (by
+        # id), kept current from loadedSource/module events between requests
+        self._loaded_sources: dict[str, dict[str, Any]] = {}
+        self._modules: dict[str, dict[str, Any]] = {}
+        # Fetched content of sources without a file, by sourceReference; the
+        # references only hold for one debuggee run (see get_source)
+        self._source_cache: dict[int, dict[str, Any]] = {}
+
+        # Exception breakpoint filters (None = use launch config default)
+        self._exception_filters: list[str] | None = None
+        self._exception_conditions: dict[str, str] = {}
+
+        # variablesReference -> child counts, valid only until the session resumes
+        self._variable_cache: dict[int, dict[str, int | None]] = {}
+        self._stale_variable_refs: set[int] = set()
+
+        # Frame each reference was reached from, and adapter evaluateNames by
+        # (container, name), so a listed variable can be re-evaluated
+        self._reference_frames: dict[int, int] = {}
+        self._evaluate_names: dict[tuple[int, str], str] = {}
+
+        # Full values fetched by get_full_value, by continuation token
+        self._full_values: dict[str, str] = {}
+
+        # Frame IDs from stack traces fetched during the current stop
+        self._frame_ids: set[int] = set()
+        self._stale_frame_ids: set[int] = set()
+        # Frame ID -> instructionPointerReference, for frame-based disassembly
+        self._instruction_pointers: dict[int, str] = {}
+
+        # Fire-and-forget tasks spawned from event handling
+        self._background_tasks: set[asyncio.Task[None]] = set()
+
+        # Watch expressions (evaluated on each stop), with stable IDs
         self._watch_expressions: list[str] = []
+        self._watch_ids: dict[str, int] = {}
+        self._next_watch_id = 1
+        self._watch_results: dict[int, dict[str, Any]] = {}
 
     @property
     def state(self) -> SessionState:
         return self._state
 
+    @property
+    def uptime_seconds(self) -> float:
+        """Seconds since the session was created."""
+        return (datetime.now(timezone.utc) - self.created_at).total_seconds()
+
+    @property
+    def idle_remaining_seconds(self) -> float:
+        """Seconds until the session ends for inactivity (see SessionManager)."""
+        idle = (datetime.now(timezone.utc) - self.last_activity).total_seconds()
+        return max(0.0, self.timeout_minutes * 60 - idle)
+
+    @property
+    def lifetime_remaining_seconds(self) -> float:
+        """Seconds until the session reaches settings.session_max_lifetime_seconds."""
+        return max(0.0, settings.session_max_lifetime_seconds - self.uptime_seconds)
+
+    @property
+    def expiry_reason(self) -> str | None:
+        """"idle_timeout" or "max_lifetime" once a limit is reached, else None."""
+        if self.lifetime_remaining_seconds <= 0:
+            return "max_lifetime"
+        if self.idle_remaining_seconds <= 0:
+            return "idle_timeout"
+        return None
+
     async def transition_to(self, new_state: SessionState) -> None:
         """Thread-safe state transition."""
         async with self._state_lock:
@@ -139,58 +324,377 @@ class Session:
         """Update last activity timestamp."""
         self.last_activity = datetime.now(timezone.utc)
 
-    async def initialize_adapter(self) -> None:
-        """Create and initialize the debug adapter for the configured language."""
-        self.adapter = create_adapter(
-            language=self.language,
+    async def initialize_adapter(self, adapter_name: str | None = None) -> None:
+        """Create and initialize the debug adapter.
+
+        Args:
+            adapter_name: Registered adapter to use instead of the language default
+        """
+        descriptor = get_descriptor(self.language, adapter_name)
+        # Output is captured from OUTPUT events (see _handle_event) rather than
+        # the plain output callback so the full event body is available
+        self.adapter = descriptor.adapter_class(
             session_id=self.id,
-            output_callback=self._handle_output,
             event_callback=self._handle_event,
         )
+        self.adapter.remote = self.remote
+        self.adapter_name = descriptor.name
         await self.adapter.initialize()
 
+    def _resolve_launch_environment(self, config: LaunchConfig) -> LaunchConfig:
+        """Resolve the launch cwd and record the environment the debuggee gets.
+
+        A relative cwd is taken from the project root. The env overrides are
+        merged over this process's environment, with None values removing
+        the variable; the overrides themselves go to the adapter unchanged.
+
+        Raises:
+            LaunchError: If the working directory doesn't exist
+        """
+        cwd = Path(config.cwd).expanduser()
+        if not cwd.is_absolute():
+            cwd = self.project_root / cwd
+        cwd = cwd.resolve()
+        if not cwd.is_dir():
+            raise LaunchError(
+                f"Working directory does not exist: {cwd}",
+                {"cwd": config.cwd, "resolved": str(cwd)},
+            )
+
+        env = dict(os.environ)
+        for key, value in config.env.items():
+            if value is None:
+                env.pop(key, None)
+            else:
+                env[key] = value
+
+        self.launch_cwd = str(cwd)
+        self.launch_env = env
+        return config.model_copy(update={"cwd": str(cwd)})
+
+    async def _run_pre_launch(self, config: LaunchConfig) -> None:
+        """Run config.pre_launch in order, their output tagged "prelaunch".
+
+        Steps run from the launch cwd unless they name their own, with the
+        debuggee's environment. cleanup() cancels a step still running.
+
+        Raises:
+            PreLaunchError: If a step fails; the adapter is not started
+        """
+        if not config.pre_launch:
+            return
+        runner = PreLaunchRunner(lambda text: self._handle_output("prelaunch", text))
+        self._pre_launch = runner
+        try:
+            await runner.run(
+                config.pre_launch, self.project_root, Path(config.cwd), self.launch_env
+            )
+        finally:
+            self._pre_launch = None
+
+    async def _open_output_log(self, config: LaunchConfig) -> None:
+        """Start teeing output to config.output_log_path, if given.
+
+        A relative path is taken from the project root. Relaunching with the
+        same path keeps appending to the open log.
+
+        Raises:
+            LaunchError: If the log file can't be created
+        """
+        if config.output_log_path is None:
+            return
+        path = Path(config.output_log_path).expanduser()
+        if not path.is_absolute():
+            path = self.project_root / path
+        path = path.resolve()
+        if self.output_log is not None:
+            if self.output_log.path == path:
+                return
+            await self.output_log.close()
+            self.output_log = None
+
+        log = OutputLog(path)
+        try:
+            log.start()
+        except OSError as e:
+            raise LaunchError(
+                f"Cannot create output log {path}: {e.strerror or e}",
+                {"output_log_path": config.output_log_path, "resolved": str(path)},
+            ) from e
+        self.output_log = log
+
+    async def _select_adapter(self, config: LaunchConfig) -> None:
+        """Switch adapters if the launch names one or the program needs another.
+
+        An explicit config.adapter wins; otherwise the program's file
+        extension decides. Programs without an extension (Go packages,
+        compiled binaries, python -m) or with one no adapter registers
+        (app.exe, pkg.test) keep the session's adapter.
+
+        Raises:
+            UnknownAdapterError: If the adapter name is unknown, or the
+                extension is and the session has no adapter to fall back on
+            LaunchError: If the selected adapter isn't installed
+        """
+        if config.adapter:
+            descriptor = adapter_registry.get(config.adapter)
+        elif config.program:
+            try:
+                found = adapter_registry.for_file(config.program)
+            except UnknownAdapterError:
+                if self.adapter_name is None:
+                    raise
+                return
+            if found is None:
+                return
+            descriptor = found
+        else:
+            return
+        if descriptor.name == self.adapter_name:
+            return
+        if not descriptor.is_available():
+            raise LaunchError(
+                f"{descriptor.name} adapter not found. Install with: {descriptor.install_hint}",
+                {"adapter": descriptor.name},
+            )
+
+        logger.info(f"Session {self.id}: switching adapter to {descriptor.name}")
+        if self.adapter is not None:
+            await self.adapter.disconnect(terminate=True)
+            self.adapter = None
+        if self.language not in {lang.value for lang in descriptor.languages}:
+            self.language = descriptor.languages[0].value
+        await self.initialize_adapter(descriptor.name)
+
     async def launch(self, config: LaunchConfig) -> None:
-        """Launch the debug target."""
+        """Launch the debug target.
+
+        The adapter may be switched first to match config.adapter or the
+        program's file extension (see _select_adapter), after any
+        config.pre_launch steps have succeeded.
+        """
         self.require_state(SessionState.CREATED)
         await self.transition_to(SessionState.LAUNCHING)
 
         try:
+            config = self._resolve_launch_environment(config)
+            await self._open_output_log(config)
+            await self._run_pre_launch(config)
+            await self._select_adapter(config)
+            self.path_mapper = PathMapper(
+                (m.local_root, m.remote_root) for m in config.path_mappings
+            )
+            self.step_filter = self._launch_step_filter(config)
+            self.target = config.program or (f"-m {config.module}" if config.module else None)
+            self.stdin_mode = config.stdin_mode
+            self._launch_config = config
+            self.test_not_found = None
+            self.exit_code = None
+            self._run_output_start = self.output_buffer.last_line_number
+            loop = asyncio.get_running_loop()
+            self._launch_exit = loop.create_future()
+            # Adapters that can start running before breakpoints are in place are
+            # stopped at entry; that stop is resumed unseen unless it was asked for
+            hidden_entry = (
+                getattr(self.adapter, "launch_stops_at_entry", False) and not config.stop_on_entry
+            )
+            self._hidden_entry = hidden_entry
+
             if self.adapter is None:
                 raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
 
             async def configure_breakpoints() -> None:
                 """Configure breakpoints during DAP configuration phase."""
-                # Set source breakpoints
-                for file_path, breakpoints in self._breakpoints.items():
-                    await self.adapter.set_breakpoints(file_path, breakpoints)  # type: ignore
+                # Set source breakpoints (at the entry stop when there is one)
+                if not hidden_entry:
+                    await self._send_source_breakpoints()
 
                 # Set exception breakpoints if configured
-                if config.stop_on_exception:
+                if self._exception_filters is not None:
+                    await self._apply_exception_filters()
+                elif config.stop_on_exception:
                     await self.adapter.set_exception_breakpoints(["uncaught"])  # type: ignore
 
-            await self.adapter.launch(config, configure_callback=configure_breakpoints)
+            adapter_config = (
+                config.model_copy(update={"stop_on_entry": True}) if hidden_entry else config
+            )
+            await self._until_exit(
+                self.adapter.launch(adapter_config, configure_callback=configure_breakpoints)
+            )
+            if self._entry_resume is not None:
+                await self._until_exit(self._entry_resume)
             # Only transition to RUNNING if not already PAUSED (breakpoint hit during launch)
             if self._state == SessionState.LAUNCHING:
                 await self.transition_to(SessionState.RUNNING)
+        except ProgramExitedError:
+            raise  # The exit already marked the session terminated
         except Exception:
             await self.transition_to(SessionState.FAILED)
             raise
+        finally:
+            self._launch_exit = None
+            self._entry_resume = None
+
+    async def _send_source_breakpoints(self) -> None:
+        """Send every file's source breakpoints, then the function breakpoints."""
+        for file_path, breakpoints in self._breakpoints.items():
+            await self._send_breakpoints(file_path, breakpoints)
+        if self._function_breakpoints:
+            await self._send_function_breakpoints()
+
+    async def _until_exit(self, step: Coroutine[Any, Any, Any]) -> Any:
+        """Run one step of the launch handshake, abandoning it if the program exits.
+
+        Raises:
+            ProgramExitedError: If an exited or terminated event came first
+        """
+        assert self._launch_exit is not None
+        task = asyncio.ensure_future(step)
+        await asyncio.wait({task, self._launch_exit}, return_when=asyncio.FIRST_COMPLETED)
+        if self._launch_exit.done():
+            task.cancel()
+            await asyncio.wait({task})
+            if not task.cancelled():
+                task.exception()  # Its failure is a consequence of the exit
+            raise self._program_exited_error()
+        return task.result()
+
+    async def _resume_hidden_entry(self, thread_id: int | None) -> None:
+        """Place source breakpoints at the internal entry stop, then continue.
+
+        During launch, failures fail the launch; an entry stop reported after
+        launch returned can only log them.
+        """
+        assert self.adapter is not None
+        try:
+            await self._send_source_breakpoints()
+            await self.adapter.continue_execution(thread_id)
+        except Exception as e:
+            if asyncio.current_task() is self._entry_resume:
+                raise
+            logger.warning(f"Session {self.id}: resuming the entry stop failed: {e}")
+
+    def _program_exited_error(self) -> ProgramExitedError:
+        """Error for a program that exited during launch, with its output."""
+        page = self.output_buffer.get_since(self._run_output_start, limit=sys.maxsize)
+        output = "".join(line.content for line in page.lines if line.category != "console")
+        return ProgramExitedError(self.exit_code, output[-_LAUNCH_EXIT_OUTPUT_CHARS:])
+
+    async def launch_test(
+        self,
+        framework: str,
+        test_id: str,
+        args: list[str] | None = None,
+        env: dict[str, str | None] | None = None,
+        stop_on_entry: bool = False,
+    ) -> None:
+        """Launch a single test with the framework's runner (see utils.test_runner).
+
+        Raises:
+            LaunchConfigError: If the framework is unknown or test_id is empty
+        """
+        launch_kwargs = build_test_launch(framework, test_id, self.project_root, args)
+        self.test_run = {"framework": framework, "test_id": test_id}
+        await self.launch(
+            LaunchConfig(**launch_kwargs, env=env or {}, stop_on_entry=stop_on_entry)
+        )
+
+    def _check_test_found(self) -> dict[str, Any] | None:
+        """Event data for a test run whose runner didn't find the test, else None."""
+        if self.test_run is None or self.test_not_found is not None:
+            return None
+        page = self.output_buffer.get_since(self._run_output_start, limit=sys.maxsize)
+        output = "".join(line.content for line in page.lines if line.category != "console")
+        message = missing_test_message(self.test_run["framework"], output)
+        if message is None:
+            return None
+        self.test_not_found = message
+        return {**self.test_run, "message": message}
+
+    def _launch_step_filter(self, config: LaunchConfig) -> StepFilter:
+        """Step filter for a launch.
+
+        just_my_code falls back to filtering stdlib and third-party code when
+        the adapter can't honour it itself.
+        """
+        filters = config.step_filters
+        fallback = config.just_my_code and not (
+            self.adapter is not None and self.adapter.supports_just_my_code
+        )
+        return StepFilter(
+            filters.patterns,
+            skip_stdlib=filters.skip_stdlib or fallback,
+            skip_site_packages=filters.skip_site_packages or fallback,
+            project_root=self.project_root,
+        )
+
+    def set_step_filters(
+        self,
+        patterns: list[str] | None = None,
+        skip_stdlib: bool = False,
+        skip_site_packages: bool = False,
+    ) -> dict[str, Any]:
+        """Replace the step filter rules; the next step uses them.
+
+        The rules are kept in the launch config so a restart keeps them.
+
+        Returns:
+            The rules now in effect
+        """
+        filters = StepFilters(
+            patterns=patterns or [],
+            skip_stdlib=skip_stdlib,
+            skip_site_packages=skip_site_packages,
+        )
+        self.step_filter = StepFilter(
+            filters.patterns,
+            skip_stdlib=skip_stdlib,
+            skip_site_packages=skip_site_packages,
+            project_root=self.project_root,
+        )
+        if self._launch_config is not None:
+            self._launch_config = self._launch_config.model_copy(
+                update={"step_filters": filters, "just_my_code": False}
+            )
+        return self.step_filter.describe()
 
     async def attach(self, config: AttachConfig) -> None:
-        """Attach to a running process."""
+        """Attach to a running process.
+
+        Attached sessions leave the target running when they end unless
+        termination is explicitly requested (see cleanup).
+        """
         self.require_state(SessionState.CREATED)
         await self.transition_to(SessionState.LAUNCHING)
+        self.path_mapper = PathMapper((m.local_root, m.remote_root) for m in config.path_mappings)
+        self.target = (
+            f"pid {config.process_id}" if config.process_id else f"{config.host}:{config.port}"
+        )
 
         try:
             if self.adapter is None:
                 raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+            if config.process_id:
+                self._check_attachable(config.process_id)
 
             async def configure_breakpoints() -> None:
                 """Configure breakpoints during DAP configuration phase."""
-                for file_path, breakpoints in self._breakpoints.items():
-                    await self.adapter.set_breakpoints(file_path, breakpoints)  # type: ignore
+                await self._send_source_breakpoints()
 
-            await self.adapter.attach(config, configure_callback=configure_breakpoints)
+                if self._exception_filters is not None:
+                    await self._apply_exception_filters()
+
+            try:
+                await asyncio.wait_for(
+                    self.adapter.attach(config, configure_callback=configure_breakpoints),
+                    timeout=settings.dap_attach_timeout_seconds,
+                )
+            except asyncio.TimeoutError:
+                raise LaunchError(
+                    f"Timed out attaching to {self.target}; is a debug server listening there?",
+                    config.model_dump(),
+                )
+            self.attached = True
             # Only transition to RUNNING if not already PAUSED (breakpoint hit during attach)
             if self._state == SessionState.LAUNCHING:
                 await self.transition_to(SessionState.RUNNING)
@@ -198,6 +702,205 @@ class Session:
             await self.transition_to(SessionState.FAILED)
             raise
 
+    async def restart(self) -> dict[str, Any]:
+        """Restart the launched program, keeping breakpoints and exception filters.
+
+        Uses the adapter's native restart request when advertised; otherwise,
+        or when the launch has pre-launch steps to run again, the debuggee is
+        terminated and relaunched with the original launch config on a fresh
+        adapter. Either way every breakpoint is sent again so the report
+        reflects the new run: a relaunch sends them in the configuration
+        phase, before the program runs, but a native restart only once the
+        adapter's restart request returns, with the program already running
+        on whatever breakpoints the adapter kept.
+
+        Returns:
+            Dict with the method used, whether breakpoints were replayed before
+            the program started, the number replayed and the breakpoints that
+            failed to verify or moved to another line
+
+        Raises:
+            InvalidSessionStateError: If the session was attached or never launched
+        """
+        self.touch()
+        if self.attached or self._launch_config is None:
+            raise InvalidSessionStateError(
+                self.id,
+                "attached" if self.attached else self._state.value,
+                ["launched"],
+            )
+
+        # Pre-launch steps (a rebuild) only run on a relaunch
+        native = (
+            self.adapter is not None
+            and self.adapter.is_connected
+            and self._state in (SessionState.RUNNING, SessionState.PAUSED)
+            and self.adapter.capabilities.get("supportsRestartRequest", False)
+            and not self._launch_config.pre_launch
+        )
+        if native:
+            await self._restart_native()
+        else:
+            await self._relaunch(self._launch_config)
+
+        report = self._breakpoint_report()
+        return {
+            "method": "native" if native else "relaunch",
+            "replayed_before_start": not native,
+            **report,
+        }
+
+    async def _restart_native(self) -> None:
+        """Restart through the adapter, then resend breakpoints for fresh results.
+
+        The new run starts before they are resent, so it relies on the adapter
+        keeping its breakpoints across the restart until then.
+        """
+        assert self.adapter is not None
+        stops_before = self._stop_count
+        self._invalidate_variables()
+        self._run_to_line = None
+        self.stop_reason = None
+        self.stop_location = None
+        self.exception_info = None
+        self._hit_counts.clear()
+        self._data_breakpoints = []
+        self._reset_function_breakpoints()
+        self._source_cache.clear()
+        self.test_not_found = None
+        self._run_output_start = self.output_buffer.last_line_number
+
+        # Adapters may report the old process exiting during the restart
+        self._restarting = True
+        try:
+            await self.adapter.restart()
+        finally:
+            self._restarting = False
+
+        for file_path, breakpoints in list(self._breakpoints.items()):
+            await self._send_breakpoints(file_path, breakpoints)
+        if self._function_breakpoints:
+            await self._send_function_breakpoints()
+        if self._exception_filters is not None:
+            await self._apply_exception_filters()
+
+        if self._stop_count == stops_before and self._state == SessionState.PAUSED:
+            await self.transition_to(SessionState.RUNNING)
+
+    async def _relaunch(self, config: LaunchConfig) -> None:
+        """Terminate the debuggee and launch it again on a new adapter."""
+        for task in list(self._background_tasks):
+            task.cancel()
+        if self._state not in (SessionState.TERMINATED, SessionState.FAILED):
+            await self.transition_to(SessionState.TERMINATED)
+        if self.adapter is not None:
+            await self.adapter.disconnect(terminate=True)
+            self.adapter = None
+
+        self.current_thread_id = None
+        self.stop_reason = None
+        self.stop_location = None
+        self.exception_info = None
+        self._run_to_line = None
+        self._pending_step = None
+        self.step_thread_id = None
+        self.adapter_exit = None
+        self._breakpoint_status.clear()
+        self._breakpoint_ids.clear()
+        self._known_breakpoint_ids.clear()
+        self._hit_counts.clear()
+        self._data_breakpoints = []
+        self._reset_function_breakpoints()
+        self._loaded_sources.clear()
+        self._modules.clear()
+        self._source_cache.clear()
+        self._invalidate_variables()
+
+        # Terminated sessions may move anywhere; CREATED lets launch run again
+        await self.transition_to(SessionState.CREATED)
+        await self.initialize_adapter(self.adapter_name)
+        await self.launch(config)
+
+    def _breakpoint_report(self) -> dict[str, Any]:
+        """Summarize the adapter's verdict on every enabled breakpoint."""
+        unverified: list[dict[str, Any]] = []
+        moved: list[dict[str, Any]] = []
+        replayed = 0
+        for path, bps in self._breakpoints.items():
+            for bp in bps:
+                if not bp.enabled:
+                    continue
+                replayed += 1
+                status = self._breakpoint_status.get((path, bp.line))
+                if status is None or not status.verified:
+                    unverified.append(
+                        {
+                            "file": path,
+                            "line": bp.line,
+                            "message": status.message if status else None,
+                        }
+                    )
+                elif status.line is not None and status.line != bp.line:
+                    moved.append({"file": path, "requested_line": bp.line, "line": status.line})
+        return {"breakpoints_replayed": replayed, "unverified": unverified, "moved": moved}
+
+    async def send_stdin(self, data: bytes) -> int:
+        """Write to the launched program's stdin.
+
+        Returns:
+            Number of bytes written
+
+        Raises:
+            InvalidSessionStateError: If the program isn't running or paused
+            StdinUnavailableError: If this session has no stdin pipe
+        """
+        self.require_state(SessionState.RUNNING, SessionState.PAUSED)
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        reason = self._stdin_unavailable_reason()
+        if reason:
+            raise StdinUnavailableError(self.id, reason)
+        await self.adapter.write_stdin(data)
+        return len(data)
+
+    @property
+    def stdin_available(self) -> bool:
+        """Whether send_stdin can reach the program."""
+        return self.adapter is not None and self._stdin_unavailable_reason() is None
+
+    def _stdin_unavailable_reason(self) -> str | None:
+        if self.attached:
+            return "an attached process keeps the stdin of whatever started it"
+        if self.stdin_mode != "pipe":
+            return (
+                f"the program was launched with stdin_mode '{self.stdin_mode}'; "
+                "relaunch with stdin_mode 'pipe'"
+            )
+        if not getattr(self.adapter, "supports_stdin_pipe", False):
+            return (
+                f"the {self.language} debug adapter starts the program itself, "
+                "so its stdin can't be reached"
+            )
+        return None
+
+    def _check_attachable(self, pid: int) -> None:
+        """Fail fast for PIDs that don't exist or belong to another user.
+
+        Adapters report these as opaque injection/ptrace failures otherwise.
+        """
+        if sys.platform == "win32":
+            return  # Signal 0 is CTRL_C_EVENT on Windows, not a probe
+        try:
+            os.kill(pid, 0)
+        except ProcessLookupError:
+            raise LaunchError(f"No process with PID {pid}", {"process_id": pid})
+        except PermissionError:
+            raise LaunchError(
+                f"Permission denied for PID {pid}: attaching requires running as the "
+                "same user as the target (or ptrace privileges)",
+                {"process_id": pid},
+            )
+
     async def set_breakpoints(
         self,
         file_path: str,
@@ -209,121 +912,2273 @@ class Session:
 
         # If already launched, set them immediately
         if self.adapter and self.adapter.is_launched:
-            return await self.adapter.set_breakpoints(file_path, breakpoints)
+            to_send, added = self._with_temporary_breakpoints(file_path, breakpoints)
+            results = await self._send_breakpoints(file_path, to_send)
+            if added:
+                results = results[: sum(1 for bp in breakpoints if bp.enabled)]
+            return results
+
+        # Otherwise, return unverified breakpoints (enabled ones, as adapters would)
+        self._record_breakpoint_results(file_path, breakpoints, [])
+        return [
+            Breakpoint(verified=False, line=bp.line, message="Pending launch")
+            for bp in breakpoints
+            if bp.enabled
+        ]
+
+    async def add_breakpoints(
+        self,
+        file_path: str,
+        breakpoints: list[SourceBreakpoint],
+    ) -> list[Breakpoint]:
+        """Add breakpoints to a file, keeping the ones already set on other lines.
+
+        A new breakpoint replaces an existing one on the same line. The
+        file's whole list is sent, since setBreakpoints replaces a source's
+        breakpoints; if that fails, the previous list is kept.
+
+        Returns:
+            The result for each given breakpoint, in order
+        """
+        file_path = self._breakpoint_file_key(file_path)
+        previous = self._breakpoints.get(file_path, [])
+        added = {bp.line: bp for bp in breakpoints}
+        merged = [added.pop(bp.line, bp) for bp in previous] + list(added.values())
+
+        try:
+            results = await self.set_breakpoints(file_path, merged)
+        except Exception:
+            if previous:
+                self._breakpoints[file_path] = previous
+            else:
+                self._breakpoints.pop(file_path, None)
+            raise
 
-        # Otherwise, return unverified breakpoints
+        enabled = [bp for bp in merged if bp.enabled]
+        by_line = {bp.line: result for bp, result in zip(enabled, results)}
         return [
-            Breakpoint(verified=False, line=bp.line, message="Pending launch") for bp in breakpoints
+            by_line.get(bp.line) or Breakpoint(verified=False, line=bp.line, message="Disabled")
+            for bp in breakpoints
+        ]
+
+    async def set_breakpoints_enabled(
+        self,
+        enabled: bool,
+        ids: list[int] | None = None,
+        file_path: str | None = None,
+    ) -> list[dict[str, Any]]:
+        """Enable or disable breakpoints without removing them.
+
+        Breakpoints are picked by id, by file, or all of them when neither
+        is given. Disabled breakpoints stay stored with their id and hit
+        count but aren't sent to the adapter; each changed file is resent
+        when the program is running.
+
+        Returns:
+            The breakpoints whose state changed (file, line, id)
+
+        Raises:
+            BreakpointNotFoundError: If an id names no breakpoint
+        """
+        self.touch()
+        user_keys = {(path, bp.line) for path, bps in self._breakpoints.items() for bp in bps}
+        if ids is not None:
+            by_id = {bp_id: key for key, bp_id in self._known_breakpoint_ids.items()}
+            selected: set[tuple[str, int]] = set()
+            for bp_id in ids:
+                key = by_id.get(bp_id) or self._breakpoint_ids.get(bp_id)
+                if key is None or key not in user_keys:
+                    raise BreakpointNotFoundError(self.id, str(bp_id))
+                selected.add(key)
+        elif file_path is not None:
+            file_path = self._breakpoint_file_key(file_path)
+            selected = {key for key in user_keys if key[0] == file_path}
+        else:
+            selected = user_keys
+
+        changed: list[dict[str, Any]] = []
+        for path, bps in list(self._breakpoints.items()):
+            updated = [
+                bp.model_copy(update={"enabled": enabled})
+                if (path, bp.line) in selected and bp.enabled != enabled
+                else bp
+                for bp in bps
+            ]
+            flipped = [new for new, old in zip(updated, bps) if new is not old]
+            if not flipped:
+                continue
+            self._breakpoints[path] = updated
+            changed.extend(
+                {
+                    "file": path,
+                    "line": bp.line,
+                    "id": self._known_breakpoint_ids.get((path, bp.line)),
+                }
+                for bp in flipped
+            )
+            if self.adapter and self.adapter.is_launched:
+                to_send, _ = self._with_temporary_breakpoints(path, updated)
+                await self._send_breakpoints(path, to_send)
+        return changed
+
+    async def run_to_line(
+        self,
+        file_path: str,
+        line: int,
+        thread_id: int | None = None,
+    ) -> Breakpoint:
+        """Continue until a line is reached, via a temporary breakpoint.
+
+        The temporary breakpoint is removed at the next stop, whatever the
+        reason, or when the program exits. It is never persisted, and user
+        breakpoints on the same line are restored when it goes.
+
+        Returns:
+            The adapter's breakpoint for the target line
+
+        Raises:
+            UnverifiedBreakpointError: If the line can't hold a breakpoint
+                (execution is not resumed)
+        """
+        self._require_paused_adapter()
+        file_path = self._breakpoint_file_key(file_path)
+        await self._clear_run_to_line()
+
+        self._run_to_line = (file_path, line)
+        to_send, _ = self._with_temporary_breakpoints(
+            file_path, self._breakpoints.get(file_path, [])
+        )
+        try:
+            await self._send_breakpoints(file_path, to_send)
+        except Exception:
+            self._run_to_line = None
+            raise
+
+        temp = self._breakpoint_status.get((file_path, line))
+        if temp is None or not temp.verified:
+            await self._clear_run_to_line()
+            raise UnverifiedBreakpointError(file_path, line, temp.message if temp else None)
+
+        await self.continue_(thread_id)
+        return temp
+
+    def _with_temporary_breakpoints(
+        self,
+        file_path: str,
+        breakpoints: list[SourceBreakpoint],
+    ) -> tuple[list[SourceBreakpoint], bool]:
+        """Add the run-to-line target and continue_until probes to a file's breakpoints.
+
+        A user breakpoint on a temporary line is swapped for a plain one while
+        the temporary one is active, so the stop doesn't depend on its condition.
+
+        Returns:
+            Breakpoints to send, and whether any were added after the
+            enabled user breakpoints rather than swapped in
+        """
+        real_path = os.path.realpath(file_path)
+        lines = {
+            line
+            for path, probe_lines in self._probes.items()
+            if os.path.realpath(path) == real_path
+            for line in probe_lines
+        }
+        target = self._run_to_line
+        if target is not None and os.path.realpath(target[0]) == real_path:
+            lines.add(target[1])
+        if not lines:
+            return breakpoints, False
+
+        swapped = [
+            SourceBreakpoint(line=bp.line) if bp.enabled and bp.line in lines else bp
+            for bp in breakpoints
+        ]
+        covered = {bp.line for bp in breakpoints if bp.enabled}
+        added = [SourceBreakpoint(line=line) for line in sorted(lines - covered)]
+        return [*swapped, *added], bool(added)
+
+    async def _clear_run_to_line(self) -> None:
+        """Remove the temporary breakpoint, restoring the file's user breakpoints."""
+        target = self._run_to_line
+        if target is None:
+            return
+        self._run_to_line = None
+
+        file_path = target[0]
+        if self.adapter is None or not self.adapter.is_launched:
+            return
+        to_send, _ = self._with_temporary_breakpoints(
+            file_path, self._breakpoints.get(file_path, [])
+        )
+        try:
+            await self._send_breakpoints(file_path, to_send)
+        except Exception as e:
+            logger.warning(f"Session {self.id}: could not remove run-to-line breakpoint: {e}")
+
+    def _breakpoint_file_key(self, file_path: str) -> str:
+        """Return the key user breakpoints use for this file, if any.
+
+        setBreakpoints replaces every breakpoint in a source, so the temporary
+        breakpoint must be sent under the same path as the user's.
+        """
+        real = os.path.realpath(file_path)
+        for key in self._breakpoints:
+            if os.path.realpath(key) == real:
+                return key
+        return file_path
+
+    async def continue_until(
+        self,
+        expression: str,
+        probes: dict[str, list[int]] | None = None,
+        max_iterations: int = 1000,
+        timeout: float = 60.0,
+        thread_id: int | None = None,
+    ) -> dict[str, Any]:
+        """Continue until a boolean expression is true at a stop.
+
+        At every stop the expression is evaluated in the top frame; while it
+        isn't true execution is continued again. Probes are temporary
+        breakpoints ({file: [lines]}) added to the user's for the duration;
+        without them only the user's breakpoints stop the program. Exception
+        stops end the run whatever the expression says.
+
+        Returns:
+            The last wait_for_stop result with "condition_met",
+            "auto_continues", and "evaluation_errors"/"last_error" when the
+            expression failed to evaluate at some stops. status is
+            "max_iterations" or "timeout" when a limit ended it at a stop,
+            "still_running" when the timeout passed while running
+
+        Raises:
+            NoBreakpointsError: If there are neither probes nor enabled breakpoints
+        """
+        self._require_resumable_adapter()
+        if not probes and not any(bp.enabled for bps in self._breakpoints.values() for bp in bps):
+            raise NoBreakpointsError()
+
+        self._probes = {
+            self._breakpoint_file_key(path): set(lines) for path, lines in (probes or {}).items()
+        }
+        await self._send_probes(self._probes)
+
+        loop = asyncio.get_running_loop()
+        deadline = loop.time() + timeout
+        continues = 0
+        errors = 0
+        last_error: str | None = None
+
+        def outcome(stop: dict[str, Any], met: bool) -> dict[str, Any]:
+            result = {**stop, "condition_met": met, "auto_continues": continues}
+            if errors:
+                result["evaluation_errors"] = errors
+                result["last_error"] = last_error
+            return result
+
+        try:
+            while True:
+                stops_before = self._stop_count
+                await self.continue_(thread_id)
+                stop = await self.wait_for_stop(stops_before, max(deadline - loop.time(), 0.0))
+                if stop["status"] != "stopped" or stop["reason"] == "exception":
+                    return outcome(stop, False)
+
+                frames = await self.get_stack_trace(stop["thread_id"], levels=1)
+                try:
+                    value = await self.evaluate(
+                        expression, frames[0].id if frames else None, "watch"
+                    )
+                    met = _is_true(str(value.get("result", "")))
+                except DAPError as e:
+                    errors += 1
+                    last_error = e.message
+                    met = False
+                if met:
+                    return outcome(stop, True)
+                if continues >= max_iterations:
+                    return outcome({**stop, "status": "max_iterations"}, False)
+                if loop.time() >= deadline:
+                    return outcome({**stop, "status": "timeout"}, False)
+                continues += 1
+                thread_id = stop["thread_id"]
+        finally:
+            probed, self._probes = self._probes, {}
+            if self._state in (SessionState.RUNNING, SessionState.PAUSED):
+                await self._send_probes(probed)
+
+    async def _send_probes(self, probes: dict[str, set[int]]) -> None:
+        """Resend the breakpoints of the files holding probes (see continue_until)."""
+        for path in probes:
+            to_send, _ = self._with_temporary_breakpoints(path, self._breakpoints.get(path, []))
+            await self._send_breakpoints(path, to_send)
+
+    @property
+    def exception_breakpoint_filters(self) -> list[dict[str, Any]]:
+        """Exception filters advertised by the adapter at initialize time."""
+        if self.adapter is None:
+            return []
+        filters: list[dict[str, Any]] = self.adapter.capabilities.get(
+            "exceptionBreakpointFilters", []
+        )
+        return filters
+
+    async def set_exception_breakpoints(
+        self,
+        filters: list[str],
+        conditions: dict[str, str] | None = None,
+    ) -> dict[str, Any]:
+        """Configure which exceptions pause the debuggee.
+
+        Filters are validated against the adapter's advertised
+        exceptionBreakpointFilters. Before launch they are stored and sent
+        during the configuration phase instead of the launch default.
+
+        Args:
+            filters: Filter IDs to enable (replaces the previous set)
+            conditions: Optional condition per filter ID
+
+        Returns:
+            Dict with applied filter IDs, rejected filters and a pending flag
+        """
+        self.touch()
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+
+        conditions = conditions or {}
+        available = {f["filter"]: f for f in self.exception_breakpoint_filters}
+        unknown = [f for f in filters if f not in available]
+        if unknown:
+            raise InvalidExceptionFilterError(
+                f"Unknown exception filter(s): {', '.join(unknown)}", list(available)
+            )
+        for filter_id in conditions:
+            if filter_id not in filters:
+                raise InvalidExceptionFilterError(
+                    f"Condition given for filter '{filter_id}' which is not enabled",
+                    list(available),
+                )
+            if not available[filter_id].get("supportsCondition"):
+                raise InvalidExceptionFilterError(
+                    f"Filter '{filter_id}' does not support conditions", list(available)
+                )
+
+        self._exception_filters = list(filters)
+        self._exception_conditions = dict(conditions)
+
+        if not self.adapter.is_launched:
+            return {"applied": list(filters), "rejected": [], "pending": True}
+
+        applied, rejected = await self._apply_exception_filters()
+        return {"applied": applied, "rejected": rejected, "pending": False}
+
+    async def _apply_exception_filters(self) -> tuple[list[str], list[dict[str, Any]]]:
+        """Send the stored exception filters and split the adapter's verdicts."""
+        assert self.adapter is not None
+        filters = self._exception_filters or []
+        plain = [f for f in filters if f not in self._exception_conditions]
+        options = [{"filterId": f, "condition": c} for f, c in self._exception_conditions.items()]
+
+        results = await self.adapter.set_exception_breakpoints(plain, options or None)
+
+        # Adapters that predate per-filter results accept everything silently
+        ordered = plain + [o["filterId"] for o in options]
+        if not results:
+            return ordered, []
+        applied = [f for f, r in zip(ordered, results) if r.verified]
+        rejected = [
+            {"filter": f, "message": r.message} for f, r in zip(ordered, results) if not r.verified
         ]
+        return applied, rejected
+
+    async def set_data_breakpoint(
+        self,
+        name: str,
+        variables_ref: int | None = None,
+        frame_id: int | None = None,
+        access_type: str = "write",
+        condition: str | None = None,
+        hit_condition: str | None = None,
+    ) -> dict[str, Any]:
+        """Break when a variable (or an expression's storage) is accessed.
+
+        The adapter's DataBreakpointInfo decides whether the data can be
+        watched and with which access types. Setting the same data again
+        replaces its watchpoint.
+
+        Args:
+            name: Variable name within variables_ref, or an expression (or
+                address, for delve) evaluated in frame_id
+            variables_ref: Container holding the variable
+            frame_id: Frame for expressions
+            access_type: "write", "read" or "readWrite"
+            condition: Optional condition expression
+            hit_condition: Optional hit count condition
+
+        Returns:
+            Description of the installed watchpoint
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter has no data breakpoints
+            DataBreakpointError: If the data can't be watched as requested
+        """
+        adapter = self._require_paused_adapter()
+        self.touch()
+        if not adapter.capabilities.get("supportsDataBreakpoints"):
+            raise CapabilityNotSupportedError("supportsDataBreakpoints", "data breakpoints")
+        if variables_ref is not None and variables_ref in self._stale_variable_refs:
+            raise VariableNotFoundError(self.id, variables_ref)
+        self._check_frame(frame_id)
+
+        info = await adapter.data_breakpoint_info(name, variables_ref, frame_id)
+        data_id = info.get("dataId")
+        access_types: list[str] | None = info.get("accessTypes")
+        if not data_id:
+            raise DataBreakpointError(name, info.get("description") or "not watchable")
+        if access_types and access_type not in access_types:
+            raise DataBreakpointError(
+                name,
+                f"access type '{access_type}' not supported; use {', '.join(access_types)}",
+                access_types,
+            )
+
+        entry: dict[str, Any] = {
+            "data_id": data_id,
+            "name": name,
+            "description": info.get("description") or name,
+            "access_type": access_type,
+            "condition": condition,
+            "hit_condition": hit_condition,
+            "id": None,
+            "verified": False,
+            "message": None,
+        }
+        previous = self._data_breakpoints
+        self._data_breakpoints = [e for e in previous if e["data_id"] != data_id] + [entry]
+        await self._send_data_breakpoints()
+
+        if not entry["verified"]:
+            self._data_breakpoints.remove(entry)
+            with contextlib.suppress(Exception):
+                await self._send_data_breakpoints()
+            raise DataBreakpointError(name, entry["message"] or "not verified", access_types)
+        return self._describe_data_breakpoint(entry)
+
+    async def clear_data_breakpoints(self) -> None:
+        """Remove every data breakpoint."""
+        had_any = bool(self._data_breakpoints)
+        self._data_breakpoints = []
+        if (
+            had_any
+            and self.adapter is not None
+            and self.adapter.is_launched
+            and self.adapter.capabilities.get("supportsDataBreakpoints")
+        ):
+            await self.adapter.set_data_breakpoints([])
+
+    def describe_data_breakpoints(self) -> list[dict[str, Any]]:
+        """Describe the installed data breakpoints."""
+        return [self._describe_data_breakpoint(e) for e in self._data_breakpoints]
+
+    async def _send_data_breakpoints(self) -> None:
+        """Send every data breakpoint and record the adapter's results."""
+        assert self.adapter is not None
+        requested: list[dict[str, Any]] = []
+        for entry in self._data_breakpoints:
+            bp: dict[str, Any] = {"dataId": entry["data_id"], "accessType": entry["access_type"]}
+            if entry["condition"]:
+                bp["condition"] = entry["condition"]
+            if entry["hit_condition"]:
+                bp["hitCondition"] = entry["hit_condition"]
+            requested.append(bp)
+
+        results = await self.adapter.set_data_breakpoints(requested)
+        for entry, result in zip(self._data_breakpoints, results):
+            entry["id"] = result.id
+            entry["verified"] = result.verified
+            entry["message"] = result.message
+
+    @staticmethod
+    def _describe_data_breakpoint(entry: dict[str, Any]) -> dict[str, Any]:
+        return {k: v for k, v in entry.items() if k != "data_id"}
+
+    def _fired_data_breakpoints(self, data: dict[str, Any]) -> list[dict[str, Any]]:
+        """Watchpoints behind a "data breakpoint" stop.
+
+        Adapters that omit hitBreakpointIds are matched only when a single
+        watchpoint is installed.
+        """
+        hit_ids = data.get("hitBreakpointIds") or []
+        fired = [e for e in self._data_breakpoints if e["id"] is not None and e["id"] in hit_ids]
+        if not fired and not hit_ids and len(self._data_breakpoints) == 1:
+            fired = list(self._data_breakpoints)
+        return [self._describe_data_breakpoint(e) for e in fired]
+
+    async def set_function_breakpoint(
+        self,
+        name: str,
+        condition: str | None = None,
+        hit_condition: str | None = None,
+    ) -> dict[str, Any]:
+        """Break on entry to a function, named the way the adapter resolves symbols.
+
+        Names go to the adapter exactly as given, so qualified names like
+        "mypackage.handler.process_request" or delve's "main.(*Server).Handle"
+        round-trip unchanged. Setting a name again replaces its breakpoint.
+        Before launch it is stored and sent during the configuration phase.
+
+        Args:
+            name: Function name
+            condition: Optional condition expression
+            hit_condition: Optional hit count condition
+
+        Returns:
+            Description of the function breakpoint
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter has no function breakpoints
+        """
+        self.touch()
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        if not self.adapter.capabilities.get("supportsFunctionBreakpoints"):
+            raise CapabilityNotSupportedError("supportsFunctionBreakpoints", "function breakpoints")
+
+        entry: dict[str, Any] = {
+            "name": name,
+            "condition": condition,
+            "hit_condition": hit_condition,
+            **self._unbound_function_breakpoint("Pending launch"),
+            "hit_count": 0,
+        }
+        previous = self._function_breakpoints
+        self._function_breakpoints = [e for e in previous if e["name"] != name] + [entry]
+        if self.adapter.is_launched:
+            try:
+                await self._send_function_breakpoints()
+            except Exception:
+                self._function_breakpoints = previous
+                raise
+        return dict(entry)
+
+    async def clear_function_breakpoints(self) -> None:
+        """Remove every function breakpoint."""
+        had_any = bool(self._function_breakpoints)
+        self._function_breakpoints = []
+        if had_any and self.adapter is not None and self.adapter.is_launched:
+            await self.adapter.set_function_breakpoints([])
+
+    def describe_function_breakpoints(self) -> list[dict[str, Any]]:
+        """Describe the function breakpoints with adapter state and hit counts."""
+        return [dict(e) for e in self._function_breakpoints]
+
+    async def _send_function_breakpoints(self) -> None:
+        """Send every function breakpoint and record the adapter's results.
+
+        An adapter switched in at launch may lack the capability; the
+        breakpoints then stay listed as unverified.
+        """
+        assert self.adapter is not None
+        if not self.adapter.capabilities.get("supportsFunctionBreakpoints"):
+            message = f"{self.adapter_name} does not support function breakpoints"
+            for entry in self._function_breakpoints:
+                entry.update(self._unbound_function_breakpoint(message))
+            return
+        requested: list[dict[str, Any]] = []
+        for entry in self._function_breakpoints:
+            bp: dict[str, Any] = {"name": entry["name"]}
+            if entry["condition"]:
+                bp["condition"] = entry["condition"]
+            if entry["hit_condition"]:
+                bp["hitCondition"] = entry["hit_condition"]
+            requested.append(bp)
+
+        results = await self.adapter.set_function_breakpoints(requested)
+        for entry, result in zip(self._function_breakpoints, results):
+            entry.update(self._unbound_function_breakpoint(None))
+            self._bind_function_breakpoint(entry, result.model_dump())
+
+    def _reset_function_breakpoints(self) -> None:
+        """Forget adapter state and hits of function breakpoints for a new run."""
+        for entry in self._function_breakpoints:
+            entry.update(self._unbound_function_breakpoint(None), hit_count=0)
+
+    @staticmethod
+    def _unbound_function_breakpoint(message: str | None) -> dict[str, Any]:
+        return {
+            "id": None,
+            "verified": False,
+            "bound_file": None,
+            "bound_line": None,
+            "message": message,
+        }
+
+    def _bind_function_breakpoint(self, entry: dict[str, Any], body: dict[str, Any]) -> None:
+        """Apply an adapter result or breakpoint event body to a function breakpoint.
+
+        Event bodies carry only what changed, so absent keys keep their value.
+        """
+        for key, field in (("id", "id"), ("verified", "verified"), ("line", "bound_line")):
+            if key in body:
+                entry[field] = body[key]
+        if "message" in body:
+            entry["message"] = body["message"]
+        elif body.get("verified"):
+            entry["message"] = None  # e.g. "Pending" no longer applies
+        path = (body.get("source") or {}).get("path")
+        if path:
+            entry["bound_file"] = self.path_mapper.to_local(path)
+
+    def _apply_function_breakpoint_event(self, data: dict[str, Any]) -> dict[str, Any] | None:
+        """Update a function breakpoint from a breakpoint event naming its id.
+
+        Returns:
+            Data for a breakpointVerified event if the breakpoint was
+            unverified until now, otherwise None
+        """
+        body = data.get("breakpoint") or {}
+        bp_id = body.get("id")
+        entry = next(
+            (e for e in self._function_breakpoints if bp_id is not None and e["id"] == bp_id),
+            None,
+        )
+        if entry is None:
+            return None
+        if data.get("reason") == "removed":
+            entry.update(self._unbound_function_breakpoint("Removed by the debug adapter"))
+            return None
+
+        was_verified = entry["verified"]
+        self._bind_function_breakpoint(entry, body)
+        if not entry["verified"] or was_verified:
+            return None
+        return {
+            "id": bp_id,
+            "function": entry["name"],
+            "file": entry["bound_file"],
+            "line": entry["bound_line"],
+            "verified": True,
+            "message": entry["message"],
+        }
+
+    async def _send_breakpoints(
+        self,
+        file_path: str,
+        breakpoints: list[SourceBreakpoint],
+    ) -> list[Breakpoint]:
+        """Send a file's breakpoints under the debuggee's path and record the results.
+
+        Results keep the local path in their source, so returned breakpoints
+        can be matched against what the client set.
+        """
+        assert self.adapter is not None
+        results = await self.adapter.set_breakpoints(
+            self.path_mapper.to_remote(file_path), breakpoints
+        )
+        for result in results:
+            if result.source and result.source.get("path"):
+                result.source = {
+                    **result.source,
+                    "path": self.path_mapper.to_local(result.source["path"]),
+                }
+        self._record_breakpoint_results(file_path, breakpoints, results)
+        return results
+
+    async def _adapter_stack_trace(
+        self,
+        thread_id: int,
+        start_frame: int = 0,
+        levels: int = 20,
+    ) -> list[StackFrame]:
+        """Fetch frames from the adapter with sources translated to local paths."""
+        assert self.adapter is not None
+        frames = await self.adapter.get_stack_trace(thread_id, start_frame, levels)
+        if self.path_mapper:
+            for frame in frames:
+                if frame.source and frame.source.path:
+                    frame.source.path = self.path_mapper.to_local(frame.source.path)
+        return frames
+
+    def _record_breakpoint_results(
+        self,
+        file_path: str,
+        breakpoints: list[SourceBreakpoint],
+        results: list[Breakpoint],
+    ) -> None:
+        """Remember what the adapter reported for a file's breakpoints.
+
+        Adapters only receive enabled breakpoints, so results line up with
+        the enabled subset of the requested list. Hit counts and ids survive
+        a resend for breakpoints that are still requested at the same line:
+        adapters like debugpy number every resent breakpoint afresh, so
+        results are relabelled with the id first reported for their line.
+        """
+        requested_lines = {bp.line for bp in breakpoints}
+        for key in [k for k in self._breakpoint_status if k[0] == file_path]:
+            del self._breakpoint_status[key]
+        for bp_id in [i for i, k in self._breakpoint_ids.items() if k[0] == file_path]:
+            del self._breakpoint_ids[bp_id]
+        for held in (self._hit_counts, self._known_breakpoint_ids):
+            for key in [k for k in held if k[0] == file_path]:
+                if key[1] not in requested_lines:
+                    del held[key]
+
+        enabled = [bp for bp in breakpoints if bp.enabled]
+        for requested, result in zip(enabled, results):
+            key = (file_path, requested.line)
+            self._breakpoint_status[key] = result
+            if result.id is not None:
+                # Events and stops name the adapter's id
+                self._breakpoint_ids[result.id] = key
+                result.id = self._known_breakpoint_ids.setdefault(key, result.id)
+
+    @staticmethod
+    def describe_binding(requested_line: int, status: Breakpoint | None) -> dict[str, Any]:
+        """Where the adapter bound a breakpoint, next to where it was requested.
+
+        Adapters move breakpoints to the nearest executable line; "moved"
+        is set when the bound line differs from the requested one.
+        """
+        verified = status.verified if status else False
+        bound_line = status.line if status and verified else None
+        return {
+            "verified": verified,
+            "bound_line": bound_line,
+            "bound_column": status.column if status and verified else None,
+            "moved": bound_line is not None and bound_line != requested_line,
+            "message": status.message if status else None,
+        }
+
+    def describe_breakpoints(
+        self, reset_hit_counts: bool = False
+    ) -> dict[str, list[dict[str, Any]]]:
+        """Describe all breakpoints with adapter state and hit counts.
+
+        Args:
+            reset_hit_counts: Zero all hit counters after reporting them
+
+        Returns:
+            Dict mapping file paths to breakpoint descriptions
+        """
+        files: dict[str, list[dict[str, Any]]] = {}
+        for path, bps in self._breakpoints.items():
+            entries: list[dict[str, Any]] = []
+            for bp in bps:
+                status = self._breakpoint_status.get((path, bp.line))
+                entries.append(
+                    {
+                        "id": self._known_breakpoint_ids.get((path, bp.line)),
+                        "line": bp.line,
+                        "enabled": bp.enabled,
+                        "condition": bp.condition,
+                        "hit_condition": bp.hit_condition,
+                        "log_message": bp.log_message,
+                        **self.describe_binding(bp.line, status),
+                        "hit_count": self._hit_counts.get((path, bp.line), 0),
+                    }
+                )
+            files[path] = entries
+
+        if reset_hit_counts:
+            self._hit_counts.clear()
+        return files
+
+    def _apply_breakpoint_event(self, data: dict[str, Any]) -> dict[str, Any] | None:
+        """Update a breakpoint's adapter state from a breakpoint event.
+
+        Adapters report later changes by breakpoint id: delve binds once the
+        binary has loaded, js-debug once source maps resolve. Ids of no line
+        breakpoint may name a function breakpoint; breakpoints the adapter
+        created itself are not tracked.
+
+        Returns:
+            Data for a breakpointVerified event if the breakpoint was
+            unverified until now, otherwise None
+        """
+        body = data.get("breakpoint") or {}
+        bp_id = body.get("id")
+        key = self._breakpoint_ids.get(bp_id) if bp_id is not None else None
+        if key is None:
+            return self._apply_function_breakpoint_event(data)
+        if data.get("reason") == "removed":
+            self._breakpoint_status.pop(key, None)
+            self._known_breakpoint_ids.pop(key, None)
+            del self._breakpoint_ids[bp_id]
+            return None
+
+        previous = self._breakpoint_status.get(key)
+        update = {k: body[k] for k in ("verified", "line", "column", "message") if k in body}
+        if update.get("verified") and "message" not in update:
+            update["message"] = None  # e.g. "Pending" no longer applies
+        status = (previous or Breakpoint(id=bp_id, verified=False)).model_copy(update=update)
+        self._breakpoint_status[key] = status
+        if not status.verified or (previous is not None and previous.verified):
+            return None
+
+        file_path, line = key
+        return {
+            "id": self._known_breakpoint_ids.get(key, bp_id),
+            "file": file_path,
+            "line": line,
+            **self.describe_binding(line, status),
+        }
+
+    def _count_breakpoint_hits(self, hit_ids: list[int]) -> None:
+        """Increment hit counters for breakpoints named in a stopped event."""
+        for bp_id in hit_ids:
+            key = self._breakpoint_ids.get(bp_id)
+            if key is not None:
+                self._hit_counts[key] = self._hit_counts.get(key, 0) + 1
+        for entry in self._function_breakpoints:
+            if entry["id"] is not None and entry["id"] in hit_ids:
+                entry["hit_count"] += 1
+
+    @staticmethod
+    def _needs_location_hits(data: dict[str, Any]) -> bool:
+        """Whether a stopped event's breakpoint hits must be found by location."""
+        return (
+            data.get("reason") == "breakpoint"
+            and not data.get("hitBreakpointIds")
+            and data.get("threadId") is not None
+        )
+
+    def _count_hits_at_frame(self, frame: StackFrame) -> None:
+        """Attribute a breakpoint stop by location when the adapter omits ids.
+
+        Every breakpoint bound to the stopped frame's line is counted, which
+        covers several requested lines that the adapter moved onto the same line.
+        """
+        if not frame.source or not frame.source.path:
+            return
+        stop_path = os.path.realpath(frame.source.path)
+        for key, status in self._breakpoint_status.items():
+            bound_line = status.line if status.line is not None else key[1]
+            if bound_line == frame.line and os.path.realpath(key[0]) == stop_path:
+                self._hit_counts[key] = self._hit_counts.get(key, 0) + 1
+
+    def _spawn(self, coro: Coroutine[Any, Any, None]) -> asyncio.Task[None]:
+        """Run a coroutine in the background, keeping a reference until done."""
+        task = asyncio.create_task(coro)
+        self._background_tasks.add(task)
+        task.add_done_callback(self._background_tasks.discard)
+        return task
+
+    async def _resume(self, request: Coroutine[Any, Any, None]) -> None:
+        """Send a continue/step request and mark the session running.
+
+        The adapter may report the next stop (or termination) before the
+        request's response is processed; the session then keeps the state that
+        event set rather than being marked running after the fact.
+        """
+        stops_before = self._stop_count
+        self._invalidate_variables()
+        self._resuming = True
+        try:
+            await request
+        finally:
+            self._resuming = False
+        if self._stop_count == stops_before and self._state == SessionState.PAUSED:
+            await self.transition_to(SessionState.RUNNING)
+
+    @property
+    def stop_count(self) -> int:
+        """Stopped events seen so far; pass to wait_for_stop before resuming."""
+        return self._stop_count
+
+    async def wait_for_stop(self, stops_before: int, timeout: float) -> dict[str, Any]:
+        """Wait for a stop after stops_before, or for the program to end.
+
+        Args:
+            stops_before: stop_count read before the resume request was sent
+            timeout: Seconds to wait
+
+        Returns:
+            Dict with status "stopped" (plus reason, thread and location),
+            "terminated" or "still_running"
+        """
+
+        def settled() -> bool:
+            return self._settled_stops > stops_before or self._state in (
+                SessionState.TERMINATED,
+                SessionState.FAILED,
+            )
+
+        try:
+            async with self._stop_changed:
+                await asyncio.wait_for(self._stop_changed.wait_for(settled), timeout)
+        except asyncio.TimeoutError:
+            return {"status": "still_running", "state": self._state.value}
+
+        if self._shutting_down:
+            raise ServerShuttingDownError()
+        if self._state != SessionState.PAUSED:
+            ended: dict[str, Any] = {"status": "terminated", "state": self._state.value}
+            if self.exit_code is not None:
+                ended["exit_code"] = self.exit_code
+            if self.adapter_exit is not None:
+                ended["adapter_exit"] = self.adapter_exit
+            if self.test_not_found is not None:
+                ended["test_not_found"] = self.test_not_found
+            return ended
+
+        result: dict[str, Any] = {
+            "status": "stopped",
+            "state": self._state.value,
+            "reason": self.stop_reason,
+            "description": self.stop_description,
+            "thread_id": self.current_thread_id,
+            "location": self.stop_location,
+        }
+        if self.step_thread_id is not None:
+            result["step_thread_id"] = self.step_thread_id
+        if self.skipped_frames:
+            result["skipped_frames"] = self.skipped_frames
+        if self.exception_info is not None:
+            result["exception"] = self.exception_info
+        return result
+
+    async def _top_frame(self, thread_id: int | None) -> StackFrame | None:
+        """A thread's top frame, or None if it can't be fetched."""
+        if self.adapter is None or thread_id is None:
+            return None
+        try:
+            frames = await self._adapter_stack_trace(thread_id, 0, 1)
+        except Exception as e:
+            logger.debug(f"Session {self.id}: could not resolve stop location: {e}")
+            return None
+        return frames[0] if frames else None
+
+    @staticmethod
+    def _frame_location(frame: StackFrame) -> dict[str, Any]:
+        """File, line and function of a frame."""
+        return {
+            "file": frame.source.path if frame.source else None,
+            "line": frame.line,
+            "function": frame.name,
+        }
+
+    def _require_paused_adapter(self) -> DebugAdapter:
+        """Raise unless paused with an adapter, otherwise return the adapter."""
+        self.require_state(SessionState.PAUSED)
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        return self.adapter
+
+    def _require_resumable_adapter(self) -> DebugAdapter:
+        """Like _require_paused_adapter, also refusing while a resume is in flight."""
+        adapter = self._require_paused_adapter()
+        if self._resuming:
+            raise InvalidSessionStateError(self.id, "resuming", [SessionState.PAUSED.value])
+        return adapter
+
+    async def continue_(self, thread_id: int | None = None) -> None:
+        """Continue execution."""
+        adapter = self._require_resumable_adapter()
+        tid = thread_id or self.current_thread_id or 1
+        self.stop_reason = None
+        self.stop_location = None
+        self._pending_step = None
+        await self._resume(adapter.continue_execution(tid))
+
+    async def pause(self, thread_id: int | None = None) -> None:
+        """Pause execution."""
+        self.require_state(SessionState.RUNNING)
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+
+        tid = thread_id or self.current_thread_id or 1
+        await self.adapter.pause(tid)
+
+    async def step_over(
+        self,
+        thread_id: int | None = None,
+        sticky: bool | None = None,
+        granularity: str = "line",
+    ) -> None:
+        """Step over (next line).
+
+        Args:
+            thread_id: Thread to step (default: the stopped thread)
+            sticky: Re-issue the step if it stops on another thread
+                (default: the adapter's sticky_steps)
+            granularity: "line", or "instruction" for one machine instruction
+
+        Raises:
+            CapabilityNotSupportedError: If instruction steps aren't supported
+        """
+        await self._step("over", thread_id, sticky, granularity)
+
+    async def step_into(
+        self,
+        thread_id: int | None = None,
+        sticky: bool | None = None,
+        granularity: str = "line",
+    ) -> None:
+        """Step into function (see step_over for sticky and granularity)."""
+        await self._step("into", thread_id, sticky, granularity)
+
+    async def step_out(
+        self,
+        thread_id: int | None = None,
+        sticky: bool | None = None,
+        granularity: str = "line",
+    ) -> None:
+        """Step out of function (see step_over for sticky and granularity)."""
+        await self._step("out", thread_id, sticky, granularity)
+
+    async def _step(
+        self,
+        kind: str,
+        thread_id: int | None,
+        sticky: bool | None,
+        granularity: str,
+    ) -> None:
+        """Record the pending step, then send it."""
+        adapter = self._require_resumable_adapter()
+        # Checked up front so a refused step leaves references valid
+        if granularity == "instruction" and not self.has_capability(
+            "supportsSteppingGranularity"
+        ):
+            raise CapabilityNotSupportedError("supportsSteppingGranularity", "instruction stepping")
+        tid = thread_id or self.current_thread_id or 1
+        if sticky is None:
+            sticky = adapter.sticky_steps
+        # Set before sending: the stop can arrive before the step's response
+        self._pending_step = {
+            "kind": kind,
+            "thread_id": tid,
+            "sticky": sticky,
+            "granularity": granularity,
+            "retries": 0,
+            "skipped": 0,
+        }
+        await self._resume(self._step_request(adapter, self._pending_step))
+
+    @staticmethod
+    def _step_request(adapter: DebugAdapter, step: dict[str, Any]) -> Coroutine[Any, Any, None]:
+        """Build the adapter request for a pending step."""
+        kind, thread_id = step["kind"], step["thread_id"]
+        if step.get("granularity") == "instruction":
+            return adapter.step_instruction(kind, thread_id)
+        if kind == "into":
+            return adapter.step_into(thread_id)
+        if kind == "out":
+            return adapter.step_out(thread_id)
+        return adapter.step_over(thread_id)
+
+    def _redirect_step_stop(self, data: dict[str, Any]) -> dict[str, Any] | None:
+        """Handle a stop that ends the pending step.
+
+        Returns:
+            The stop data to publish (annotated with stepThreadId when the
+            step landed on another thread), or None when the step was
+            re-issued on its own thread, or is being checked against the
+            step filter, and the stop should be swallowed
+        """
+        step, self._pending_step = self._pending_step, None
+        stop_thread = data.get("threadId")
+        if step is None or data.get("reason") != "step" or stop_thread is None:
+            return data
+        if stop_thread == step["thread_id"]:
+            if self.step_filter and step.get("granularity") != "instruction":
+                # Checking the location takes a request, which can't be
+                # awaited from inside the DAP read loop
+                self._pending_step = step
+                self._spawn(self._skip_filtered_step(step, data))
+                return None
+            return self._annotate_skipped(step, data)
+
+        if step["sticky"] and step["retries"] < settings.sticky_step_max_retries:
+            self._pending_step = {**step, "retries": step["retries"] + 1}
+            self._spawn(self._reissue_step(self._pending_step, data))
+            return None
+
+        return {
+            **data,
+            "stepThreadId": step["thread_id"],
+            "description": data.get("description")
+            or f"Step on thread {step['thread_id']} stopped on thread {stop_thread}",
+        }
+
+    @staticmethod
+    def _annotate_skipped(step: dict[str, Any], data: dict[str, Any]) -> dict[str, Any]:
+        """Mark a step stop reached by stepping out of filtered code."""
+        skipped = step.get("skipped", 0)
+        if not skipped:
+            return data
+        return {
+            **data,
+            "autoStepped": True,
+            "skippedFrames": skipped,
+            "description": data.get("description")
+            or f"Stepped out of {skipped} filtered frame(s)",
+        }
+
+    async def _skip_filtered_step(self, step: dict[str, Any], data: dict[str, Any]) -> None:
+        """Step out of filtered code, or publish the stop where it landed.
+
+        Gives up after settings.step_filter_max_steps step-outs and stops
+        in the filtered code, saying so in the description.
+        """
+        filtered = False
+        try:
+            frames = await self._adapter_stack_trace(step["thread_id"], 0, 1)
+            if frames and frames[0].source:
+                filtered = self.step_filter.matches(frames[0].source.path)
+        except Exception as e:
+            logger.debug(f"Session {self.id}: could not check step location: {e}")
+        if self._pending_step is not step:
+            return  # Ended meanwhile (terminated, relaunched)
+
+        if filtered and step["skipped"] < settings.step_filter_max_steps:
+            self._pending_step = {**step, "kind": "out", "skipped": step["skipped"] + 1}
+            try:
+                if self.adapter is None:
+                    raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+                await self._step_request(self.adapter, self._pending_step)
+                return
+            except Exception as e:
+                logger.debug(f"Session {self.id}: could not step out of filtered code: {e}")
+
+        self._pending_step = None
+        data = self._annotate_skipped(step, data)
+        if filtered:
+            data = {
+                **data,
+                "description": f"Stopped in filtered code after {step['skipped']} automatic "
+                "step(s)",
+            }
+        await self._handle_event(EventType.STOPPED, data)
+
+    async def _reissue_step(self, step: dict[str, Any], data: dict[str, Any]) -> None:
+        """Step the original thread again after its step stopped elsewhere.
+
+        If the request fails (e.g. the goroutine exited), the swallowed stop
+        is published after all, marked as having landed on another thread.
+        """
+        try:
+            if self.adapter is None:
+                raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+            await self._step_request(self.adapter, step)
+        except Exception as e:
+            logger.debug(f"Session {self.id}: could not re-issue step: {e}")
+            if self._pending_step is step:
+                self._pending_step = {**step, "sticky": False}
+                await self._handle_event(EventType.STOPPED, data)
+
+    @property
+    def supports_reverse_execution(self) -> bool:
+        """Whether the adapter can step back / reverse continue."""
+        return self.has_capability("supportsStepBack")
+
+    def has_capability(self, capability: str) -> bool:
+        """Whether the adapter advertised a DAP capability flag."""
+        return bool(self.adapter and self.adapter.capabilities.get(capability))
+
+    async def step_back(self, thread_id: int | None = None) -> None:
+        """Step backwards one line (reverse execution)."""
+        adapter = self._require_resumable_adapter()
+        tid = thread_id or self.current_thread_id or 1
+        await self._resume(adapter.step_back(tid))
+
+    async def reverse_continue(self, thread_id: int | None = None) -> None:
+        """Run backwards to the previous breakpoint (reverse execution)."""
+        adapter = self._require_resumable_adapter()
+        tid = thread_id or self.current_thread_id or 1
+        self.stop_reason = None
+        self.stop_location = None
+        self._pending_step = None
+        await self._resume(adapter.reverse_continue(tid))
+
+    async def get_threads(self) -> list[Thread]:
+        """Get all threads."""
+        if self.adapter is None:
+            return []
+        return await self.adapter.get_threads()
+
+    async def dump_goroutines(self, max_frames: int = 10) -> dict[str, Any]:
+        """Dump all goroutines with identical stacks grouped, like runtime.Stack.
+
+        A running program is paused first (and left paused). Wait reasons
+        come from the stacks (see utils.goroutines); groups waiting on a
+        channel or lock are flagged as potential deadlock participants.
+
+        Args:
+            max_frames: Top frames kept, and compared, per goroutine
+
+        Returns:
+            Dict with goroutine_count, groups, potential_deadlock (any group
+            flagged) and paused (whether this call paused the program)
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter isn't delve
+            InvalidSessionStateError: If the program isn't running or paused,
+                or ended instead of pausing
+        """
+        from polybugger_mcp.utils.goroutines import (
+            group_goroutines,
+            parse_goroutine_name,
+            wait_reason,
+        )
+
+        if self.adapter_name is None or (
+            "goroutine_threads" not in adapter_registry.get(self.adapter_name).quirks
+        ):
+            raise CapabilityNotSupportedError("goroutine_threads", "goroutine dumps")
+        self.require_state(SessionState.RUNNING, SessionState.PAUSED)
+        paused = False
+        if self._state == SessionState.RUNNING:
+            stops_before = self._stop_count
+            await self.pause()
+            stop = await self.wait_for_stop(stops_before, settings.dap_timeout_seconds)
+            if stop["status"] != "stopped":
+                raise InvalidSessionStateError(self.id, self._state.value, ["paused"])
+            paused = True
+        adapter = self._require_paused_adapter()
+        self.touch()
+
+        threads = await adapter.get_threads()
+        # Goroutines can number in the thousands; keep a bounded number in flight
+        limit = asyncio.Semaphore(16)
+
+        async def describe(thread: Thread) -> dict[str, Any]:
+            async with limit:
+                try:
+                    # One extra frame tells whether the stack goes deeper
+                    frames = await self._adapter_stack_trace(thread.id, 0, max_frames + 1)
+                except Exception as e:
+                    logger.debug(f"Session {self.id}: no stack for thread {thread.id}: {e}")
+                    frames = []
+            name = parse_goroutine_name(thread.name)
+            reason, blocking = wait_reason([f.name for f in frames])
+            if reason is not None:
+                state = "waiting"
+            elif name["os_thread"] is not None:
+                state = "running"
+            else:
+                state = None
+            return {
+                "goroutine_id": name["goroutine_id"] or thread.id,
+                "state": state,
+                "wait_reason": reason,
+                "potential_deadlock": blocking,
+                "frames": [
+                    {
+                        "name": f.name,
+                        "file": f.source.path if f.source else None,
+                        "line": f.line,
+                    }
+                    for f in frames
+                ],
+            }
+
+        goroutines = await asyncio.gather(*(describe(t) for t in threads))
+        groups = group_goroutines(list(goroutines), max_frames)
+        return {
+            "goroutine_count": len(goroutines),
+            "groups": groups,
+            "potential_deadlock": any(g["potential_deadlock"] for g in groups),
+            "paused": paused,
+        }
+
+    async def get_stack_trace(
+        self,
+        thread_id: int | None = None,
+        start_frame: int = 0,
+        levels: int = 20,
+    ) -> list[StackFrame]:
+        """Get stack trace for a thread."""
+        if self.adapter is None:
+            return []
+
+        tid = thread_id or self.current_thread_id or 1
+        frames = await self._adapter_stack_trace(tid, start_frame, levels)
+        for frame in frames:
+            self._frame_ids.add(frame.id)
+            self._stale_frame_ids.discard(frame.id)
+            if frame.instruction_pointer_reference:
+                self._instruction_pointers[frame.id] = frame.instruction_pointer_reference
+        return frames
+
+    async def get_exception_chain(self, max_depth: int = 10) -> dict[str, Any]:
+        """Describe the exception execution stopped on, with its chained causes.
+
+        Args:
+            max_depth: Most exceptions to report; a longer chain keeps its
+                root cause and outermost exceptions, dropping the ones between
+
+        Returns:
+            Dict with thread_id, exceptions (type, message, frames; innermost
+            first) and truncated
+
+        Raises:
+            InvalidSessionStateError: If session is not paused
+            NotStoppedOnExceptionError: If the last stop wasn't an exception
+                (or a Go panic)
+        """
+        adapter = self._require_paused_adapter()
+        if self.stop_reason not in ("exception", "panic"):
+            raise NotStoppedOnExceptionError(self.id, self.stop_reason)
+        self.touch()
+
+        tid = self.current_thread_id or 1
+        frame_id = None
+        try:
+            frames = await self._adapter_stack_trace(tid, 0, 1)
+            frame_id = frames[0].id if frames else None
+        except Exception as e:
+            logger.debug(f"Session {self.id}: could not resolve top frame: {e}")
+
+        chain = await adapter.get_exception_chain(tid, frame_id, _EXCEPTION_CHAIN_WALK_LIMIT)
+        truncated = len(chain) > max_depth
+        if truncated and max_depth == 1:
+            chain = chain[-1:]  # Just the exception stopped on
+        elif truncated:
+            chain = [chain[0], *chain[len(chain) - max_depth + 1 :]]
+        for entry in chain:
+            for frame in entry.get("frames") or []:
+                if frame.get("file"):
+                    frame["file"] = self.path_mapper.to_local(frame["file"])
+        return {"thread_id": tid, "exceptions": chain, "truncated": truncated}
+
+    async def disassemble(
+        self,
+        memory_reference: str | None = None,
+        frame_id: int | None = None,
+        instructions_before: int = 10,
+        instructions_after: int = 20,
+    ) -> tuple[str, list[dict[str, Any]]]:
+        """Disassemble around an address, a frame's instruction or the stop.
+
+        Args:
+            memory_reference: Address to disassemble around
+            frame_id: Frame whose current instruction to use instead
+            instructions_before: Instructions to include before the address
+            instructions_after: Instructions to include after it
+
+        Returns:
+            (memory reference used, listing) where the listing interleaves
+            {"type": "source"} markers, whenever the adapter reports a new
+            source line, with {"type": "instruction"} entries
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't disassemble
+            FrameNotFoundError: If the frame is from before the last resume
+            InvalidSessionStateError: If not paused, or no instruction pointer
+                is known for the frame
+        """
+        adapter = self._require_paused_adapter()
+        if not self.has_capability("supportsDisassembleRequest"):
+            raise CapabilityNotSupportedError("supportsDisassembleRequest", "disassembly")
+        reference = memory_reference or await self._instruction_pointer(frame_id)
+
+        instructions = await adapter.disassemble(
+            reference,
+            instruction_offset=-instructions_before,
+            instruction_count=instructions_before + 1 + instructions_after,
+        )
+        return reference, self._interleave_source(instructions, reference)
+
+    async def _instruction_pointer(self, frame_id: int | None) -> str:
+        """Memory reference of a frame's instruction (default: the stop's top frame)."""
+        self._check_frame(frame_id)
+        if frame_id is None:
+            frames = await self.get_stack_trace(levels=1)
+            frame_id = frames[0].id if frames else None
+        elif frame_id not in self._instruction_pointers:
+            # Frames from other listings (e.g. snapshots) aren't recorded here
+            await self.get_stack_trace()
+        reference = self._instruction_pointers.get(frame_id) if frame_id is not None else None
+        if reference is None:
+            raise InvalidSessionStateError(
+                self.id, "no instruction pointer for the frame", ["paused in compiled code"]
+            )
+        return reference
+
+    def _interleave_source(
+        self, instructions: list[dict[str, Any]], reference: str
+    ) -> list[dict[str, Any]]:
+        """Turn DAP instructions into a listing with source line markers."""
+        from polybugger_mcp.utils.source_reader import get_source_line
+
+        listing: list[dict[str, Any]] = []
+        # Adapters may omit location after the first instruction of a file
+        file: str | None = None
+        marker: tuple[str | None, int] | None = None
+        for inst in instructions:
+            location = inst.get("location") or {}
+            if location.get("path"):
+                file = self.path_mapper.to_local(location["path"])
+            line = inst.get("line")
+            if line is not None and (file, line) != marker:
+                marker = (file, line)
+                listing.append(
+                    {
+                        "type": "source",
+                        "file": file,
+                        "line": line,
+                        "text": get_source_line(file, line) if file else None,
+                    }
+                )
+            listing.append(
+                {
+                    "type": "instruction",
+                    "address": inst.get("address"),
+                    "instruction": inst.get("instruction"),
+                    "bytes": inst.get("instructionBytes"),
+                    "symbol": inst.get("symbol"),
+                    "current": inst.get("address") == reference,
+                }
+            )
+        return listing
+
+    async def read_memory(
+        self,
+        memory_reference: str | None = None,
+        expression: str | None = None,
+        frame_id: int | None = None,
+        offset: int = 0,
+        count: int = 256,
+    ) -> dict[str, Any]:
+        """Read the debuggee's memory at an address or a pointer's target.
+
+        A read running into unmapped memory isn't an error: bytes_read is
+        what was read and unreadable_bytes what follows it.
+
+        Args:
+            memory_reference: Address or memory reference to read at
+            expression: Expression whose value's memory reference to use instead
+            frame_id: Frame to evaluate the expression in
+            offset: Bytes to move from the reference first
+            count: Bytes to read
+
+        Returns:
+            Dict with memory_reference, address, data (bytes), bytes_read and
+            unreadable_bytes
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't read memory
+            EvaluateError: If the expression's value has no memory reference
+            InvalidSessionStateError: If session is not paused
+        """
+        adapter = self._require_paused_adapter()
+        if not self.has_capability("supportsReadMemoryRequest"):
+            raise CapabilityNotSupportedError("supportsReadMemoryRequest", "reading memory")
+        self.touch()
+        reference = await self._memory_reference(memory_reference, expression, frame_id)
+
+        body = await adapter.read_memory(reference, offset, count)
+        data = base64.b64decode(body.get("data") or "")
+        return {
+            "memory_reference": reference,
+            "address": body.get("address"),
+            "data": data,
+            "bytes_read": len(data),
+            "unreadable_bytes": body.get("unreadableBytes", 0),
+        }
+
+    async def write_memory(
+        self,
+        data: bytes,
+        memory_reference: str | None = None,
+        expression: str | None = None,
+        frame_id: int | None = None,
+        offset: int = 0,
+        allow_partial: bool = False,
+    ) -> dict[str, Any]:
+        """Write bytes into the debuggee's memory at an address or a pointer's target.
+
+        Watches are re-evaluated afterwards, as after an assignment.
+
+        Returns:
+            Dict with memory_reference, offset and bytes_written
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't write memory
+            EvaluateError: If the expression's value has no memory reference
+            InvalidSessionStateError: If session is not paused
+        """
+        adapter = self._require_paused_adapter()
+        if not self.has_capability("supportsWriteMemoryRequest"):
+            raise CapabilityNotSupportedError("supportsWriteMemoryRequest", "writing memory")
+        self.touch()
+        reference = await self._memory_reference(memory_reference, expression, frame_id)
+
+        body = await adapter.write_memory(
+            reference, base64.b64encode(data).decode("ascii"), offset, allow_partial
+        )
+        await self._after_assignment({}, frame_id)
+        return {
+            "memory_reference": reference,
+            "offset": body.get("offset", offset),
+            "bytes_written": body.get("bytesWritten", len(data)),
+        }
+
+    async def _memory_reference(
+        self,
+        memory_reference: str | None,
+        expression: str | None,
+        frame_id: int | None,
+    ) -> str:
+        """The given memory reference, or the one the adapter reports for an expression.
+
+        Expressions are evaluated in the top frame unless frame_id is given.
+        """
+        if memory_reference:
+            return memory_reference
+        assert expression is not None
+        if frame_id is None:
+            frames = await self.get_stack_trace(levels=1)
+            frame_id = frames[0].id if frames else None
+        result = await self.evaluate(expression, frame_id)
+        reference = result.get("memoryReference")
+        if not reference:
+            raise EvaluateError(expression, "the adapter reported no memory reference for it")
+        return str(reference)
+
+    async def list_loaded_sources(self, filter: str | None = None) -> list[dict[str, Any]]:
+        """List the source files the debuggee has loaded.
+
+        Adapters supporting the LoadedSources request are asked afresh;
+        otherwise the list is what loadedSource events have reported so far.
+
+        Args:
+            filter: Only keep sources whose name or path contains this text
+        """
+        if self.adapter is not None and self.has_capability("supportsLoadedSourcesRequest"):
+            sources = await self.adapter.get_loaded_sources()
+            self._loaded_sources = {
+                key: source for source in sources if (key := self._source_key(source))
+            }
+        entries = [self._describe_source(source) for source in self._loaded_sources.values()]
+        entries.sort(key=lambda e: e["path"] or e["name"] or "")
+        return [e for e in entries if self._matches(filter, e["name"], e["path"])]
+
+    async def list_modules(self, filter: str | None = None) -> list[dict[str, Any]]:
+        """List the modules (libraries, packages) the debuggee has loaded.
+
+        Adapters supporting the Modules request are asked afresh; otherwise
+        the list is what module events have reported so far.
+
+        Args:
+            filter: Only keep modules whose name or path contains this text
+        """
+        if self.adapter is not None and self.has_capability("supportsModulesRequest"):
+            modules = await self.adapter.get_modules()
+            self._modules = {str(m["id"]): m for m in modules if m.get("id") is not None}
+        entries = [self._describe_module(module) for module in self._modules.values()]
+        entries.sort(key=lambda e: e["name"] or "")
+        return [e for e in entries if self._matches(filter, e["name"], e["path"])]
+
+    @staticmethod
+    def _source_key(source: dict[str, Any]) -> str | None:
+        """Identify a DAP Source by path, falling back to its reference."""
+        if source.get("path"):
+            return str(source["path"])
+        if source.get("sourceReference"):
+            return f"ref:{source['sourceReference']}"
+        return None
+
+    @staticmethod
+    def _matches(text: str | None, *fields: str | None) -> bool:
+        """Check whether any field contains text (no text matches everything)."""
+        return not text or any(text in f for f in fields if f)
+
+    def _local_file(self, path: str | None) -> tuple[str | None, bool]:
+        """Map a debuggee path to a local one and check it exists here."""
+        if not path:
+            return None, False
+        local = self.path_mapper.to_local(path)
+        return local, Path(local).is_file()
+
+    def _describe_source(self, source: dict[str, Any]) -> dict[str, Any]:
+        """Convert a DAP Source to a loaded-source entry."""
+        path, on_disk = self._local_file(source.get("path"))
+        reference = source.get("sourceReference") or None
+        return {
+            "name": source.get("name") or (Path(path).name if path else None),
+            "path": path,
+            "source_reference": reference,
+            "sources_available": on_disk or reference is not None,
+            "origin": source.get("origin"),
+        }
+
+    async def get_source(self, source_reference: int) -> dict[str, Any]:
+        """Fetch the content of a source that has no file, by sourceReference.
+
+        Generated code, frozen modules and remote files come with a reference
+        instead of a readable path. Content is cached until the debuggee is
+        relaunched or restarted, or the session ends.
+
+        Returns:
+            Dict with source_reference, content, mime_type and line_count
+
+        Raises:
+            InvalidSessionStateError: If there's no adapter
+            DAPError: If the adapter doesn't know the reference
+        """
+        cached = self._source_cache.get(source_reference)
+        if cached is not None:
+            return cached
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        self.touch()
+
+        body = await self.adapter.get_source(source_reference)
+        content = str(body.get("content") or "")
+        cached = {
+            "source_reference": source_reference,
+            "content": content,
+            "mime_type": body.get("mimeType"),
+            "line_count": len(content.splitlines()),
+        }
+        self._source_cache[source_reference] = cached
+        return cached
+
+    @staticmethod
+    def source_kind(frame: StackFrame) -> str:
+        """How a frame's source can be read.
+
+        Returns:
+            "file" (read its path), "virtual" (fetch with get_source using
+            its source_reference) or "unavailable"
+        """
+        source = frame.source
+        if source is None:
+            return "unavailable"
+        if source.path and Path(source.path).is_file():
+            return "file"
+        if source.source_reference:
+            return "virtual"
+        return "unavailable"
+
+    async def _frame_source_context(
+        self, frame: StackFrame, context_lines: int
+    ) -> dict[str, Any] | None:
+        """Source lines around a frame's line (see get_source_context).
+
+        Read from the frame's file, or fetched from the adapter for a virtual
+        source; None if the frame has neither a path nor a reference.
+        """
+        from polybugger_mcp.utils.source_reader import get_lines_context, get_source_context
+
+        source = frame.source
+        if source is None:
+            return None
+        if self.source_kind(frame) == "virtual" and source.source_reference:
+            fetched = await self.get_source(source.source_reference)
+            return get_lines_context(fetched["content"].splitlines(), frame.line, context_lines)
+        if source.path:
+            return get_source_context(source.path, frame.line, context_lines)
+        return None
+
+    async def stack_source_contexts(
+        self,
+        frames: list[StackFrame],
+        context_lines: int,
+        max_bytes: int | None = None,
+    ) -> tuple[list[dict[str, Any]], bool]:
+        """Source around each frame's line, for attaching to a stack trace.
+
+        Files are read through the source reader's cache and virtual sources
+        through get_source's, so frames in the same file share one read. Once
+        max_bytes of source text has been attached, later frames get none.
+
+        Args:
+            frames: Frames as returned by get_stack_trace
+            context_lines: Lines before and after each frame's line
+            max_bytes: Cap on attached source (default:
+                settings.stack_source_max_bytes)
+
+        Returns:
+            Per frame, either source/context/line_numbers fields or a
+            source_unavailable reason; and whether the cap was reached
+        """
+        budget = settings.stack_source_max_bytes if max_bytes is None else max_bytes
+        contexts: list[dict[str, Any]] = []
+        capped = False
+        for frame in frames:
+            if capped:
+                contexts.append({"source_unavailable": "source size limit reached"})
+                continue
+            try:
+                context = await self._frame_source_context(frame, context_lines)
+            except DAPError as e:
+                contexts.append({"source_unavailable": f"source request failed: {e.message}"})
+                continue
+            if context is None:
+                contexts.append({"source_unavailable": "frame has no source"})
+                continue
+            if context.get("current") is None:
+                contexts.append({"source_unavailable": "source not readable"})
+                continue
+
+            lines = [*context["before"], context["current"], *context["after"]]
+            size = sum(len(line.encode("utf-8")) + 1 for line in lines)
+            if size > budget:
+                capped = True
+                contexts.append({"source_unavailable": "source size limit reached"})
+                continue
+            budget -= size
+            contexts.append(
+                {
+                    "source": context["current"],
+                    "context": {"before": context["before"], "after": context["after"]},
+                    "line_numbers": context["line_numbers"],
+                }
+            )
+        return contexts, capped
+
+    def _describe_module(self, module: dict[str, Any]) -> dict[str, Any]:
+        """Convert a DAP Module to a module entry."""
+        path, on_disk = self._local_file(module.get("path"))
+        return {
+            "id": module.get("id"),
+            "name": module.get("name"),
+            "path": path,
+            "version": module.get("version"),
+            "sources_available": on_disk,
+            "is_user_code": module.get("isUserCode"),
+            "symbol_status": module.get("symbolStatus"),
+        }
+
+    @staticmethod
+    def _track_loaded(
+        cache: dict[str, dict[str, Any]],
+        key: str | None,
+        item: dict[str, Any],
+        reason: str | None,
+    ) -> None:
+        """Apply a loadedSource/module event (new, changed, removed) to a cache."""
+        if key is None:
+            return
+        if reason == "removed":
+            cache.pop(key, None)
+        else:
+            cache[key] = {**cache.get(key, {}), **item}
+
+    def _check_frame(self, frame_id: int | None) -> None:
+        """Reject frame IDs that belong to an earlier stop.
+
+        Adapters may reuse frame IDs after a resume, so an ID only counts as
+        stale until a new stack trace reports it again. IDs never seen are
+        left for the adapter to judge.
+
+        Raises:
+            FrameNotFoundError: If the frame is from before the last resume
+        """
+        if frame_id is None:
+            return
+        if frame_id in self._stale_frame_ids and frame_id not in self._frame_ids:
+            raise FrameNotFoundError(self.id, frame_id, stale=True)
+
+    async def get_scopes(self, frame_id: int) -> list[Scope]:
+        """Get scopes for a frame.
+
+        Raises:
+            FrameNotFoundError: If the frame is from before the last resume
+        """
+        if self.adapter is None:
+            return []
+        self._check_frame(frame_id)
+        scopes = await self.adapter.get_scopes(frame_id)
+        for scope in scopes:
+            self._remember_reference(
+                scope.variables_reference, scope.named_variables, scope.indexed_variables
+            )
+            self._reference_frames[scope.variables_reference] = frame_id
+        return scopes
+
+    @staticmethod
+    def select_scope(scopes: list[Scope], name: str | None = None) -> Scope | None:
+        """Pick a frame's scope by name or presentation hint.
+
+        Args:
+            scopes: Scopes of the frame, in adapter order
+            name: Scope name or hint, case-insensitive ("globals", "registers",
+                "Closure"); None picks the locals, else the first cheap scope
+        """
+        if name is not None:
+            wanted = name.lower()
+            return next(
+                (s for s in scopes if wanted in (s.name.lower(), s.presentation_hint)),
+                next((s for s in scopes if s.name.lower().startswith(wanted)), None),
+            )
+        return next(
+            (s for s in scopes if s.presentation_hint == "locals"),
+            next((s for s in scopes if not s.expensive), None),
+        )
+
+    async def get_variables(
+        self,
+        variables_ref: int,
+        start: int = 0,
+        count: int = 100,
+        filter: str | None = None,
+    ) -> list[Variable]:
+        """Get variables for a scope.
+
+        Raises:
+            VariableNotFoundError: If the reference is from before the last resume
+        """
+        if self.adapter is None:
+            return []
+        if variables_ref in self._stale_variable_refs:
+            raise VariableNotFoundError(self.id, variables_ref)
+        variables = await self.adapter.get_variables(variables_ref, start, count, filter)
+        frame_id = self._reference_frames.get(variables_ref)
+        for v in variables:
+            self._remember_reference(v.variables_reference, v.named_variables, v.indexed_variables)
+            if v.variables_reference and frame_id is not None:
+                self._reference_frames[v.variables_reference] = frame_id
+            if v.evaluate_name:
+                self._evaluate_names[(variables_ref, v.name)] = v.evaluate_name
+        return variables
+
+    def variable_counts(self, variables_ref: int) -> dict[str, int | None]:
+        """Child counts reported for a reference ({"named", "indexed"}), if known."""
+        return self._variable_cache.get(variables_ref, {"named": None, "indexed": None})
+
+    async def expand_variable(
+        self,
+        variables_ref: int,
+        depth: int = 2,
+        max_children: int = 50,
+        max_nodes: int | None = None,
+        value_limit: int | None = None,
+    ) -> dict[str, Any]:
+        """Expand a reference's children breadth-first, down to depth levels.
+
+        Each container is fetched once: one reached again (a cycle, or an
+        object shared by two parents) gets "cycle" set to its reference
+        instead of children. Containers left partly or wholly unexpanded
+        are marked "truncated" and listed with the reason: "depth",
+        "max_children" (more children than fetched per level) or "max_nodes"
+        (the cap on variables returned in total).
+
+        Raises:
+            VariableNotFoundError: If the reference is from before the last resume
+        """
+        max_nodes = max_nodes or settings.expand_max_nodes
+        value_limit = value_limit or settings.value_max_length
+        root: dict[str, Any] = {"variables_reference": variables_ref}
+        queue: deque[tuple[dict[str, Any], list[str], int]] = deque([(root, [], 1)])
+        visited = {variables_ref}
+        truncated: list[dict[str, Any]] = []
+        nodes = 0
+
+        def cut(node: dict[str, Any], path: list[str], reason: str) -> None:
+            node["truncated"] = reason
+            truncated.append(
+                {"path": path, "variables_reference": node["variables_reference"], "reason": reason}
+            )
+
+        while queue:
+            node, path, level = queue.popleft()
+            budget = min(max_children, max_nodes - nodes)
+            if budget <= 0:
+                cut(node, path, "max_nodes")
+                continue
+            # One extra child tells whether there are more when counts are unknown
+            variables = await self.get_variables(node["variables_reference"], count=budget + 1)
+            node["children"] = []
+            for v in variables[:budget]:
+                child: dict[str, Any] = {
+                    "name": v.name,
+                    "value": v.value[:value_limit],
+                    "type": v.type,
+                    "variables_reference": v.variables_reference,
+                }
+                if len(v.value) > value_limit:
+                    child["value_truncated"] = True
+                node["children"].append(child)
+                nodes += 1
+                ref = v.variables_reference
+                if ref <= 0:
+                    continue
+                if ref in visited:
+                    child["cycle"] = ref
+                elif level >= depth:
+                    cut(child, [*path, v.name], "depth")
+                else:
+                    visited.add(ref)
+                    queue.append((child, [*path, v.name], level + 1))
+
+            if len(variables) > budget:
+                cut(node, path, "max_children" if budget == max_children else "max_nodes")
+
+        return {"tree": root, "nodes": nodes, "depth": depth, "truncated": truncated}
+
+    def _remember_reference(
+        self,
+        variables_ref: int,
+        named: int | None,
+        indexed: int | None,
+    ) -> None:
+        """Cache a container reference's child counts for paging."""
+        if variables_ref > 0:
+            self._variable_cache[variables_ref] = {"named": named, "indexed": indexed}
+            self._stale_variable_refs.discard(variables_ref)
+
+    def _invalidate_variables(self) -> None:
+        """Forget variable references and frame IDs; adapters invalidate them on resume."""
+        self._stale_variable_refs.update(self._variable_cache)
+        self._variable_cache.clear()
+        self._stale_frame_ids.update(self._frame_ids)
+        self._frame_ids.clear()
+        self._instruction_pointers.clear()
+        self._reference_frames.clear()
+        self._evaluate_names.clear()
+        self._full_values.clear()
+
+    async def get_completions(
+        self,
+        text: str,
+        column: int | None = None,
+        frame_id: int | None = None,
+    ) -> list[dict[str, Any]]:
+        """Complete a partial expression against the stopped program.
+
+        Args:
+            text: Partial expression, e.g. "request.hea"
+            column: 1-based cursor column in text (default: end of text)
+            frame_id: Frame to complete in (default: topmost)
+
+        Returns:
+            DAP CompletionItem objects in the adapter's sort order ([] when
+            the adapter doesn't support completions)
+
+        Raises:
+            FrameNotFoundError: If the frame is from before the last resume
+            InvalidSessionStateError: If not paused
+        """
+        adapter = self._require_paused_adapter()
+        self._check_frame(frame_id)
+        items = await adapter.get_completions(text, frame_id, column)
+        return sorted(items, key=lambda i: (i.get("sortText") or i["label"], i["label"]))
+
+    async def evaluate(
+        self,
+        expression: str,
+        frame_id: int | None = None,
+        context: str = "watch",
+    ) -> dict[str, Any]:
+        """Evaluate an expression.
+
+        Raises:
+            FrameNotFoundError: If the frame is from before the last resume
+        """
+        if self.adapter is None:
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        self._check_frame(frame_id)
+        result = await self.adapter.evaluate(expression, frame_id, context)
+        self._remember_reference(
+            result.get("variablesReference", 0),
+            result.get("namedVariables"),
+            result.get("indexedVariables"),
+        )
+        if result.get("variablesReference") and frame_id is not None:
+            self._reference_frames[result["variablesReference"]] = frame_id
+        return result
+
+    async def inspect_symbol(self, file_path: str, line: int, column: int) -> dict[str, Any]:
+        """Evaluate the symbol at a source position, as an editor hover would.
+
+        The identifier (with its dotted prefix) is read from the source line
+        and evaluated in the "hover" context, in the innermost frame of the
+        current thread that is in file_path, or the top frame if none is.
+
+        Args:
+            file_path: Source file; relative paths resolve against the project root
+            line: 1-based line
+            column: 1-based column, in characters
+
+        Returns:
+            Dict with expression, result, type, variables_reference, frame_id
+            and frame_matched (whether a frame in file_path was used)
+
+        Raises:
+            InvalidSessionStateError: If session is not paused
+            NoSymbolAtPositionError: If there's no identifier at the position
+                or the line can't be read
+        """
+        from polybugger_mcp.utils.source_reader import extract_symbol_at, get_source_line
+
+        self._require_paused_adapter()
+        self.touch()
+        path = Path(file_path).expanduser()
+        if not path.is_absolute():
+            path = self.project_root / path
+        path = path.resolve()
+
+        source_line = get_source_line(str(path), line)
+        if source_line is None:
+            raise NoSymbolAtPositionError(file_path, line, column, "line not readable")
+        expression = extract_symbol_at(source_line, column)
+        if expression is None:
+            raise NoSymbolAtPositionError(file_path, line, column, "not an identifier")
+
+        frames = await self.get_stack_trace(self.current_thread_id)
+        frame = next(
+            (
+                f
+                for f in frames
+                if f.source and f.source.path and Path(f.source.path).resolve() == path
+            ),
+            None,
+        )
+        frame_matched = frame is not None
+        if frame is None and frames:
+            frame = frames[0]
+        frame_id = frame.id if frame else None
+
+        result = await self.evaluate(expression, frame_id, context="hover")
+        return {
+            "expression": expression,
+            "result": result.get("result", ""),
+            "type": result.get("type"),
+            "variables_reference": result.get("variablesReference", 0),
+            "frame_id": frame_id,
+            "frame_matched": frame_matched,
+        }
 
-    async def continue_(self, thread_id: int | None = None) -> None:
-        """Continue execution."""
-        self.require_state(SessionState.PAUSED)
+    @property
+    def render_modes(self) -> tuple[str, ...]:
+        """Render modes the adapter's renderer supports ("raw" at least)."""
         if self.adapter is None:
-            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+            return ("raw",)
+        return self.adapter.renderer.modes
 
-        tid = thread_id or self.current_thread_id or 1
-        await self.adapter.continue_execution(tid)
-        await self.transition_to(SessionState.RUNNING)
-        self.stop_reason = None
-        self.stop_location = None
+    async def render_value(
+        self,
+        expression: str,
+        frame_id: int | None,
+        mode: str,
+    ) -> dict[str, Any]:
+        """Render a value through the adapter's renderer.
 
-    async def pause(self, thread_id: int | None = None) -> None:
-        """Pause execution."""
-        self.require_state(SessionState.RUNNING)
+        Returns:
+            The renderer's fields with "render" set to mode; when the value
+            can't be rendered that way, {"render": "raw", "render_error": ...}
+            so callers keep the raw value
+
+        Raises:
+            FrameNotFoundError: If the frame is from before the last resume
+        """
         if self.adapter is None:
             raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        self._check_frame(frame_id)
+        adapter = self.adapter
 
-        tid = thread_id or self.current_thread_id or 1
-        await self.adapter.pause(tid)
+        async def evaluate(text: str) -> str:
+            return await adapter.evaluate_full(text, frame_id)
 
-    async def step_over(self, thread_id: int | None = None) -> None:
-        """Step over (next line)."""
-        self.require_state(SessionState.PAUSED)
-        if self.adapter is None:
-            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        try:
+            rendered = await adapter.renderer.render(
+                evaluate,
+                expression,
+                mode,
+                max_items=settings.render_max_items,
+                json_max_chars=settings.render_json_max_chars,
+            )
+        except RenderError as e:
+            return {"render": "raw", "render_error": str(e)}
+        return {"render": mode, **rendered}
 
-        tid = thread_id or self.current_thread_id or 1
-        await self.adapter.step_over(tid)
-        await self.transition_to(SessionState.RUNNING)
+    async def render_variables(
+        self,
+        variables_ref: int,
+        variables: list[Variable],
+        mode: str,
+    ) -> dict[str, dict[str, Any]]:
+        """Render the compound variables fetched from a container, by name.
 
-    async def step_into(self, thread_id: int | None = None) -> None:
-        """Step into function."""
-        self.require_state(SessionState.PAUSED)
-        if self.adapter is None:
-            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        Scalars are left out: their raw value is already what a summary
+        would say. The renderings are requested concurrently.
 
-        tid = thread_id or self.current_thread_id or 1
-        await self.adapter.step_into(tid)
-        await self.transition_to(SessionState.RUNNING)
+        Raises:
+            VariableNotFoundError: If the container is from before the last resume
+        """
+        compound = [v for v in variables if v.variables_reference > 0]
+        rendered = await asyncio.gather(
+            *(
+                self.render_value(*self._variable_expression(variables_ref, v.name), mode)
+                for v in compound
+            )
+        )
+        return {v.name: r for v, r in zip(compound, rendered)}
 
-    async def step_out(self, thread_id: int | None = None) -> None:
-        """Step out of function."""
-        self.require_state(SessionState.PAUSED)
-        if self.adapter is None:
-            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+    def _variable_expression(self, variables_ref: int, name: str) -> tuple[str, int | None]:
+        """Expression and frame for re-evaluating a variable of a container.
 
-        tid = thread_id or self.current_thread_id or 1
-        await self.adapter.step_out(tid)
-        await self.transition_to(SessionState.RUNNING)
+        Uses the adapter's evaluateName, falling back to the name, in the
+        frame the container came from.
 
-    async def get_threads(self) -> list[Thread]:
-        """Get all threads."""
-        if self.adapter is None:
-            return []
-        return await self.adapter.get_threads()
+        Raises:
+            VariableNotFoundError: If the container is from before the last resume
+        """
+        if variables_ref in self._stale_variable_refs:
+            raise VariableNotFoundError(self.id, variables_ref)
+        expression = self._evaluate_names.get((variables_ref, name), name)
+        return expression, self._reference_frames.get(variables_ref)
 
-    async def get_stack_trace(
+    async def get_full_value(
         self,
-        thread_id: int | None = None,
-        start_frame: int = 0,
-        levels: int = 20,
-    ) -> list[StackFrame]:
-        """Get stack trace for a thread."""
-        if self.adapter is None:
-            return []
+        expression: str | None = None,
+        variables_ref: int | None = None,
+        name: str | None = None,
+        frame_id: int | None = None,
+    ) -> tuple[str, str]:
+        """Fetch a value's complete text, bypassing the adapter's truncation.
 
-        tid = thread_id or self.current_thread_id or 1
-        return await self.adapter.get_stack_trace(tid, start_frame, levels)
+        Give an expression, or a variable by its container and name. Variables
+        are re-evaluated through the adapter's evaluateName (falling back to
+        the name) in the frame their container came from. The text is kept
+        until execution resumes so it can be read in chunks.
 
-    async def get_scopes(self, frame_id: int) -> list[Scope]:
-        """Get scopes for a frame."""
+        Returns:
+            (continuation token, full text)
+
+        Raises:
+            VariableNotFoundError: If the container is from before the last resume
+            FrameNotFoundError: If the frame is from before the last resume
+        """
         if self.adapter is None:
-            return []
-        return await self.adapter.get_scopes(frame_id)
+            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
+        self.require_state(SessionState.PAUSED)
+        if expression is None:
+            if variables_ref is None or name is None:
+                raise ValueError("Provide expression, or variables_reference and name")
+            expression, container_frame = self._variable_expression(variables_ref, name)
+            if frame_id is None:
+                frame_id = container_frame
+        self._check_frame(frame_id)
+
+        text = await self.adapter.evaluate_full(expression, frame_id)
+        token = uuid.uuid4().hex[:12]
+        self._full_values[token] = text
+        return token, text
+
+    def full_value_text(self, token: str) -> str:
+        """Text fetched by get_full_value.
 
-    async def get_variables(
+        Raises:
+            ContinuationTokenError: If the token is unknown or expired
+        """
+        text = self._full_values.get(token)
+        if text is None:
+            raise ContinuationTokenError(self.id, token)
+        return text
+
+    async def set_variable(
         self,
         variables_ref: int,
-        start: int = 0,
-        count: int = 100,
-    ) -> list[Variable]:
-        """Get variables for a scope."""
-        if self.adapter is None:
-            return []
-        return await self.adapter.get_variables(variables_ref, start, count)
+        name: str,
+        value: str,
+    ) -> dict[str, Any]:
+        """Assign a new value to a variable while paused.
 
-    async def evaluate(
+        Raises:
+            VariableNotFoundError: If the reference is from before the last resume
+            CapabilityNotSupportedError: If the adapter can't set variables
+            DAPError: If the adapter rejects the value (message is the adapter's)
+        """
+        adapter = self._require_paused_adapter()
+        if variables_ref in self._stale_variable_refs:
+            raise VariableNotFoundError(self.id, variables_ref)
+        body = await adapter.set_variable(variables_ref, name, value)
+        await self._after_assignment(body, None)
+        return body
+
+    async def set_expression(
         self,
         expression: str,
+        value: str,
         frame_id: int | None = None,
-        context: str = "watch",
     ) -> dict[str, Any]:
-        """Evaluate an expression."""
-        if self.adapter is None:
-            raise InvalidSessionStateError(self.id, "no adapter", ["initialized"])
-        return await self.adapter.evaluate(expression, frame_id, context)
+        """Assign a new value to an assignable expression while paused.
+
+        Raises:
+            CapabilityNotSupportedError: If the adapter can't set expressions
+            DAPError: If the adapter rejects the value (message is the adapter's)
+        """
+        adapter = self._require_paused_adapter()
+        self._check_frame(frame_id)
+        body = await adapter.set_expression(expression, value, frame_id)
+        await self._after_assignment(body, frame_id)
+        return body
+
+    async def _after_assignment(self, body: dict[str, Any], frame_id: int | None) -> None:
+        """Refresh cached state that an assignment may have changed."""
+        # The old child reference (if any) is replaced by the one in the response
+        self._remember_reference(
+            body.get("variablesReference", 0),
+            body.get("namedVariables"),
+            body.get("indexedVariables"),
+        )
+        if self._watch_expressions:
+            await self._evaluate_watch_list(frame_id)
+
+    async def cleanup(self, terminate_debuggee: bool | None = None) -> None:
+        """Clean up session resources.
+
+        Args:
+            terminate_debuggee: Kill the debug target on disconnect. Defaults to
+                True for launched programs and False for attached processes
+                and remote (tcp transport) adapters.
+        """
+        for task in list(self._background_tasks):
+            task.cancel()
+        if self._pre_launch is not None:
+            await self._pre_launch.cancel()
 
-    async def cleanup(self) -> None:
-        """Clean up session resources."""
         if self.adapter:
-            await self.adapter.disconnect()
+            if terminate_debuggee is None:
+                terminate_debuggee = not self.attached and self.remote is None
+            await self.adapter.disconnect(terminate=terminate_debuggee)
             self.adapter = None
 
+        if self.output_log is not None:
+            await self.output_log.close()
+        self._output_listeners.clear()
         self.output_buffer.clear()
         self.event_queue.clear()
+        self._source_cache.clear()
         logger.info(f"Session {self.id}: cleaned up")
 
+    async def shutdown(
+        self,
+        grace_seconds: float,
+        terminate_debuggee: bool | None = None,
+    ) -> None:
+        """End the session because the server is stopping.
+
+        Unlike cleanup, the adapter gets only grace_seconds to disconnect
+        before the processes we started are killed, and calls still waiting
+        on it (requests, wait_for_stop) fail with ServerShuttingDownError.
+
+        Args:
+            grace_seconds: Time the adapter has to disconnect
+            terminate_debuggee: As for cleanup
+        """
+        self._shutting_down = True
+        if self._pre_launch is not None:
+            await self._pre_launch.cancel()
+        if self.adapter:
+            if terminate_debuggee is None:
+                terminate_debuggee = not self.attached and self.remote is None
+            try:
+                await self.adapter.shutdown(
+                    terminate=terminate_debuggee, grace_seconds=grace_seconds
+                )
+            except Exception as e:
+                logger.warning(f"Session {self.id}: adapter shutdown failed: {e}")
+            self.adapter = None
+        with contextlib.suppress(InvalidSessionStateError):
+            await self.transition_to(SessionState.TERMINATED)
+        async with self._stop_changed:
+            self._stop_changed.notify_all()
+        await self.cleanup()
+
     # Watch expression methods
 
     def add_watch(self, expression: str) -> list[str]:
@@ -338,8 +3193,14 @@ class Session:
         self.touch()
         if expression not in self._watch_expressions:
             self._watch_expressions.append(expression)
+            self._watch_ids[expression] = self._next_watch_id
+            self._next_watch_id += 1
         return self._watch_expressions.copy()
 
+    def watch_id(self, expression: str) -> int | None:
+        """Get the stable ID of a watch expression."""
+        return self._watch_ids.get(expression)
+
     def remove_watch(self, expression: str) -> list[str]:
         """Remove a watch expression.
 
@@ -352,8 +3213,27 @@ class Session:
         self.touch()
         if expression in self._watch_expressions:
             self._watch_expressions.remove(expression)
+            watch_id = self._watch_ids.pop(expression)
+            self._watch_results.pop(watch_id, None)
         return self._watch_expressions.copy()
 
+    def remove_watch_by_id(self, watch_id: int) -> list[str]:
+        """Remove a watch expression by its ID.
+
+        Args:
+            watch_id: ID returned when the watch was added
+
+        Returns:
+            Current list of watch expressions
+
+        Raises:
+            WatchNotFoundError: If no watch has that ID
+        """
+        for expression, expr_id in self._watch_ids.items():
+            if expr_id == watch_id:
+                return self.remove_watch(expression)
+        raise WatchNotFoundError(self.id, watch_id)
+
     def list_watches(self) -> list[str]:
         """Get all watch expressions.
 
@@ -366,6 +3246,30 @@ class Session:
         """Clear all watch expressions."""
         self.touch()
         self._watch_expressions.clear()
+        self._watch_ids.clear()
+        self._watch_results.clear()
+
+    def describe_watches(self) -> list[dict[str, Any]]:
+        """Describe watches with the values from the most recent stop.
+
+        Returns:
+            List of dicts with id, expression and last result/type/error
+            (None until the session has stopped with the watch registered)
+        """
+        watches: list[dict[str, Any]] = []
+        for expr in self._watch_expressions:
+            watch_id = self._watch_ids[expr]
+            last = self._watch_results.get(watch_id, {})
+            watches.append(
+                {
+                    "id": watch_id,
+                    "expression": expr,
+                    "result": last.get("result"),
+                    "type": last.get("type"),
+                    "error": last.get("error"),
+                }
+            )
+        return watches
 
     async def evaluate_watches(
         self,
@@ -381,13 +3285,24 @@ class Session:
         """
         if not self.adapter or self._state != SessionState.PAUSED:
             return []
+        self._check_frame(frame_id)
+        return await self._evaluate_watch_list(frame_id)
+
+    async def _evaluate_watch_list(self, frame_id: int | None) -> list[dict[str, Any]]:
+        """Evaluate every watch, isolating failures to the failing watch.
 
+        The "watch" context lets adapters such as debugpy suppress side
+        effects (e.g. property getters) where they can.
+        """
+        assert self.adapter is not None
         results: list[dict[str, Any]] = []
-        for expr in self._watch_expressions:
+        for expr in list(self._watch_expressions):
+            watch_id = self._watch_ids.get(expr)
             try:
                 result = await self.adapter.evaluate(expr, frame_id, "watch")
                 results.append(
                     {
+                        "id": watch_id,
                         "expression": expr,
                         "result": result.get("result", ""),
                         "type": result.get("type"),
@@ -398,6 +3313,7 @@ class Session:
             except Exception as e:
                 results.append(
                     {
+                        "id": watch_id,
                         "expression": expr,
                         "result": None,
                         "type": None,
@@ -405,6 +3321,10 @@ class Session:
                         "error": str(e),
                     }
                 )
+
+        for r in results:
+            if r["id"] is not None:
+                self._watch_results[r["id"]] = r
         return results
 
     # Smart inspection methods
@@ -478,10 +3398,7 @@ class Session:
         Raises:
             InvalidSessionStateError: If session is not paused
         """
-        from polybugger_mcp.utils.source_reader import (
-            extract_call_expression,
-            get_source_context,
-        )
+        from polybugger_mcp.utils.source_reader import extract_call_expression
 
         self.require_state(SessionState.PAUSED)
 
@@ -491,7 +3408,7 @@ class Session:
         self.touch()
 
         tid = thread_id or self.current_thread_id or 1
-        frames = await self.adapter.get_stack_trace(tid, start_frame=0, levels=100)
+        frames = await self._adapter_stack_trace(tid, start_frame=0, levels=100)
 
         call_chain: list[dict[str, Any]] = []
 
@@ -506,11 +3423,16 @@ class Session:
                 "file": file_path,
                 "line": line,
                 "column": frame.column,
+                "source_kind": self.source_kind(frame),
+                "source_reference": frame.source.source_reference if frame.source else None,
             }
 
-            # Add source context if requested and file is available
-            if include_source_context and file_path:
-                context = get_source_context(file_path, line, context_lines)
+            # Add source context if requested and the source is readable
+            context = None
+            if include_source_context:
+                with contextlib.suppress(DAPError):
+                    context = await self._frame_source_context(frame, context_lines)
+            if context is not None:
                 frame_data["source"] = context.get("current")
                 frame_data["context"] = {
                     "before": context.get("before", []),
@@ -534,6 +3456,135 @@ class Session:
             "entry_point": call_chain[-1]["function"] if call_chain else None,
         }
 
+    async def get_stop_snapshot(
+        self,
+        max_frames: int = 5,
+        context_lines: int = 5,
+        max_variables: int = 50,
+    ) -> dict[str, Any]:
+        """Collect what is usually fetched after a stop in one call.
+
+        Each section is gathered independently: a failing request (or an
+        unreadable source file) leaves that section empty and records why
+        under "errors" instead of failing the snapshot.
+
+        Args:
+            max_frames: Frames of the stopped thread to include
+            context_lines: Source lines before and after the stopped line
+            max_variables: Locals of the top frame to include
+
+        Returns:
+            Dict with reason, thread_id, frames, locals, scopes (listed, not
+            expanded), watches, source and errors
+
+        Raises:
+            InvalidSessionStateError: If session is not paused
+        """
+        self._require_paused_adapter()
+        self.touch()
+
+        tid = self.current_thread_id or 1
+        errors: dict[str, str] = {}
+        snapshot: dict[str, Any] = {
+            "reason": self.stop_reason,
+            "description": self.stop_description,
+            "thread_id": tid,
+            "exception": self.exception_info,
+            "frames": [],
+            "locals": [],
+            "scopes": [],
+            "watches": [],
+            "source": None,
+            "errors": errors,
+        }
+
+        try:
+            frames = await self.get_stack_trace(tid, levels=max_frames)
+        except Exception as e:
+            errors["frames"] = str(e)
+            frames = []
+        snapshot["frames"] = [
+            {
+                "id": f.id,
+                "name": f.name,
+                "file": f.source.path if f.source else None,
+                "line": f.line,
+                "column": f.column,
+                "source_kind": self.source_kind(f),
+                "source_reference": f.source.source_reference if f.source else None,
+            }
+            for f in frames
+        ]
+        if not frames:
+            errors.setdefault("frames", "no frames reported for the stopped thread")
+            return snapshot
+        top = frames[0]
+        top_path = top.source.path if top.source else None
+        self.stop_location = {"file": top_path, "line": top.line, "function": top.name}
+
+        try:
+            scopes = await self.get_scopes(top.id)
+            # Other scopes are listed, not expanded: some (registers) are expensive
+            snapshot["scopes"] = [
+                {
+                    "name": s.name,
+                    "variables_reference": s.variables_reference,
+                    "expensive": s.expensive,
+                }
+                for s in scopes
+            ]
+            local_scope = self.select_scope(scopes)
+            if local_scope is None:
+                errors["locals"] = "frame has no scopes"
+            else:
+                variables = await self.get_variables(
+                    local_scope.variables_reference, count=max_variables
+                )
+                snapshot["locals"] = [
+                    {
+                        "name": v.name,
+                        "value": v.value,
+                        "type": v.type,
+                        "variables_reference": v.variables_reference,
+                    }
+                    for v in variables
+                ]
+        except Exception as e:
+            errors["locals"] = str(e)
+
+        if self._watch_expressions:
+            try:
+                results = await self._evaluate_watch_list(top.id)
+                snapshot["watches"] = [
+                    {k: r[k] for k in ("id", "expression", "result", "type", "error")}
+                    for r in results
+                ]
+            except Exception as e:
+                errors["watches"] = str(e)
+
+        # Frame paths are already local (see _adapter_stack_trace)
+        try:
+            context = await self._frame_source_context(top, context_lines)
+        except DAPError as e:
+            errors["source"] = f"could not fetch the source: {e.message}"
+        else:
+            reference = top.source.source_reference if top.source else None
+            if context is None:
+                errors["source"] = "top frame has no source path"
+            elif context.get("current") is None:
+                location = top_path or f"source reference {reference}"
+                errors["source"] = f"could not read line {top.line} of {location}"
+            else:
+                snapshot["source"] = {
+                    "file": top_path,
+                    "source_reference": reference,
+                    "line": top.line,
+                    "start_line": context["line_numbers"]["start"],
+                    "lines": [*context["before"], context["current"], *context["after"]],
+                }
+
+        return snapshot
+
     # Persistence methods
 
     def to_persisted(self, server_shutdown: bool = False) -> PersistedSession:
@@ -553,6 +3604,7 @@ class Session:
             project_root=str(self.project_root),
             state=self._state.value,
             language=self.language,
+            remote=self.remote.model_dump() if self.remote else None,
             created_at=self.created_at,
             last_activity=self.last_activity,
             breakpoints={
@@ -581,6 +3633,7 @@ class Session:
             project_root=Path(data.project_root),
             name=data.name,
             language=data.language,
+            remote=TcpTransport(**data.remote) if data.remote else None,
         )
 
         # Restore breakpoints
@@ -588,35 +3641,305 @@ class Session:
             session._breakpoints[path] = [SourceBreakpoint(**bp) for bp in bps]
 
         # Restore watch expressions
-        session._watch_expressions = data.watch_expressions.copy()
+        for expression in data.watch_expressions:
+            session.add_watch(expression)
 
         return session
 
-    def _handle_output(self, category: str, content: str) -> None:
+    def _handle_output(
+        self,
+        category: str,
+        content: str,
+        logpoint_id: int | None = None,
+    ) -> None:
         """Handle output from debugpy."""
-        self.output_buffer.append(category, content)
+        line = self.output_buffer.append(category, content, logpoint_id=logpoint_id)
+        if self.output_log is not None:
+            self.output_log.push(self.id, line)
+        for listener in list(self._output_listeners):
+            try:
+                listener(self.id, line)
+            except Exception as e:
+                logger.warning(f"Session {self.id}: output listener failed: {e}")
+
+    def add_output_listener(self, listener: Callable[[str, OutputLine], None]) -> None:
+        """Call listener(session_id, line) for each new output line."""
+        if listener not in self._output_listeners:
+            self._output_listeners.append(listener)
+
+    def remove_output_listener(self, listener: Callable[[str, OutputLine], None]) -> None:
+        """Stop calling a listener added with add_output_listener."""
+        if listener in self._output_listeners:
+            self._output_listeners.remove(listener)
+
+    def _logpoint_for_output(self, data: dict[str, Any]) -> int | None:
+        """Find the logpoint that emitted an output event, if any.
+
+        Adapters attach the logpoint's source and line to the output event,
+        which is matched against both the requested and the bound line.
+        """
+        source = data.get("source") or {}
+        path = source.get("path")
+        line = data.get("line")
+        if not path or line is None:
+            return None
+
+        out_path = os.path.realpath(self.path_mapper.to_local(path))
+        for file_path, bps in self._breakpoints.items():
+            if os.path.realpath(file_path) != out_path:
+                continue
+            for bp in bps:
+                if not bp.log_message:
+                    continue
+                status = self._breakpoint_status.get((file_path, bp.line))
+                if status is None or status.id is None:
+                    continue
+                if line in (bp.line, status.line):
+                    return status.id
+        return None
+
+    async def _settle_stop(
+        self,
+        data: dict[str, Any],
+        stop_number: int,
+        entry: HistoryEntry | None,
+        deferred: bool,
+    ) -> None:
+        """Resolve a stop's follow-up details, then wake stop waiters.
+
+        The top frame is fetched once and serves the stop location, the
+        history entry, hits matched by location and the watches' frame.
+        Deferred stops are queued once everything is resolved. Details of a
+        stop superseded meanwhile don't overwrite the newer stop's.
+        """
+        try:
+            thread_id = data.get("threadId")
+            top = await self._top_frame(thread_id)
+            if top is not None:
+                location = self._frame_location(top)
+                if entry is not None:
+                    entry.data["location"] = location
+                if stop_number == self._stop_count:
+                    self.stop_location = location
+                if self._needs_location_hits(data):
+                    self._count_hits_at_frame(top)
+            if data.get("reason") == "exception":
+                data = await self._with_exception_info(data, thread_id)
+                if stop_number == self._stop_count:
+                    self.exception_info = data.get("exception")
+            if deferred and self._watch_expressions and self.adapter is not None:
+                results = await self._evaluate_watch_list(top.id if top is not None else None)
+                data = {
+                    **data,
+                    "watches": [
+                        {k: r[k] for k in ("id", "expression", "result", "type", "error")}
+                        for r in results
+                    ],
+                }
+            if deferred:
+                await self.event_queue.put(EventType.STOPPED, data)
+        finally:
+            self._settled_stops = max(self._settled_stops, stop_number)
+            async with self._stop_changed:
+                self._stop_changed.notify_all()
+
+    async def _with_exception_info(
+        self, data: dict[str, Any], thread_id: int | None
+    ) -> dict[str, Any]:
+        """Attach ExceptionInfo to an exception stop's event data."""
+        info: dict[str, Any] = {}
+        if self.adapter is not None and thread_id is not None:
+            try:
+                info = await self.adapter.get_exception_info(thread_id)
+            except Exception as e:
+                logger.debug(f"Session {self.id}: could not fetch exception info: {e}")
+
+        if info:
+            details = info.get("details") or {}
+            exception = {
+                "type": details.get("fullTypeName")
+                or details.get("typeName")
+                or info.get("exceptionId"),
+                "message": details.get("message") or info.get("description"),
+                "traceback": details.get("stackTrace"),
+                "break_mode": info.get("breakMode"),
+            }
+            data = {**data, "exception": exception}
+        return data
+
+    async def _handle_adapter_exit(self, data: dict[str, Any]) -> None:
+        """End the session after its adapter died or dropped the connection.
+
+        Adapters that simply exit after the program ended are only recorded.
+        Otherwise a sessionEnded event is queued and the reason is written
+        to the output as a console line, so output streams see it too.
+        """
+        self.adapter_exit = data
+        if self._restarting or self._state in (SessionState.TERMINATED, SessionState.FAILED):
+            return
+        self._invalidate_variables()
+        self._pending_step = None
+        self._run_to_line = None
+        await self.event_queue.put(EventType.SESSION_ENDED, data)
+        self._handle_output("console", f"{data['message']}\n")
+        with contextlib.suppress(InvalidSessionStateError):
+            await self.transition_to(SessionState.TERMINATED)
+        async with self._stop_changed:
+            self._stop_changed.notify_all()
+
+    def _record_event(self, event_type: EventType, data: dict[str, Any]) -> HistoryEntry | None:
+        """Add a summary of a debug event to the session history.
+
+        Output is folded into running totals; thread, module and other
+        bookkeeping events aren't recorded. Returns the entry added, whose
+        location _settle_stop fills in for stops.
+        """
+        if event_type == EventType.OUTPUT:
+            self.history.record_output(data.get("category", "stdout"), data.get("output", ""))
+            return None
+        if event_type == EventType.STOPPED:
+            summary = {
+                "reason": data.get("reason"),
+                "thread_id": data.get("threadId"),
+                "description": data.get("description"),
+                "hit_breakpoint_ids": data.get("hitBreakpointIds"),
+            }
+        elif event_type == EventType.CONTINUED:
+            summary = {"thread_id": data.get("threadId")}
+        elif event_type == EventType.EXITED:
+            summary = {"exit_code": data.get("exitCode")}
+        elif event_type == EventType.BREAKPOINT:
+            bp = data.get("breakpoint") or {}
+            summary = {
+                "reason": data.get("reason"),
+                **{k: bp.get(k) for k in ("id", "verified", "line", "message")},
+            }
+        elif event_type in (
+            EventType.TERMINATED,
+            EventType.SESSION_ENDED,
+            EventType.BREAKPOINT_VERIFIED,
+            EventType.TEST_NOT_FOUND,
+        ):
+            summary = data
+        else:
+            return None
+        return self.history.record(
+            "event", event_type.value, {k: v for k, v in summary.items() if v is not None}
+        )
 
     async def _handle_event(self, event_type: EventType, data: dict[str, Any]) -> None:
         """Handle debug events from debugpy."""
-        await self.event_queue.put(event_type, data)
+        if event_type == EventType.SESSION_ENDED:
+            self._record_event(event_type, data)
+            await self._handle_adapter_exit(data)
+            return
+
+        if event_type == EventType.STOPPED and self._hidden_entry:
+            if data.get("reason") == "entry":
+                # The session's own entry stop (see launch); nobody else sees it
+                self._hidden_entry = False
+                resume = self._spawn(self._resume_hidden_entry(data.get("threadId")))
+                if self._launch_exit is not None:
+                    self._entry_resume = resume
+                return
+
+        if event_type == EventType.STOPPED:
+            redirected = self._redirect_step_stop(data)
+            if redirected is None:
+                return
+            data = redirected
+
+        if event_type == EventType.STOPPED and data.get("reason") == "data breakpoint":
+            fired = self._fired_data_breakpoints(data)
+            if fired:
+                data = {**data, "data_breakpoints": fired}
+                data.setdefault("description", f"Data breakpoint on {fired[0]['description']}")
+
+        entry = self._record_event(event_type, data)
+
+        # Stops that need follow-up requests are queued once those complete
+        deferred_stop = event_type == EventType.STOPPED and (
+            data.get("reason") == "exception"
+            or bool(self._watch_expressions)
+            or self._needs_location_hits(data)
+        )
+        if not deferred_stop:
+            await self.event_queue.put(event_type, data)
+
+        if event_type == EventType.OUTPUT:
+            self._handle_output(
+                data.get("category", "stdout"),
+                data.get("output", ""),
+                logpoint_id=self._logpoint_for_output(data),
+            )
 
         if event_type == EventType.STOPPED:
+            self._stop_count += 1
             self.current_thread_id = data.get("threadId")
             self.stop_reason = data.get("reason")
-            # Update state to paused
-            try:
-                await self.transition_to(SessionState.PAUSED)
-            except InvalidSessionStateError:
-                pass  # May already be paused or terminated
+            self.stop_description = data.get("description")
+            self.step_thread_id = data.get("stepThreadId")
+            self.skipped_frames = data.get("skippedFrames", 0)
+            self.stop_location = None
+            self.exception_info = None
+            if self._run_to_line is not None:
+                self._spawn(self._clear_run_to_line())
+            if self.stop_reason in ("breakpoint", "function breakpoint"):
+                hit_ids = data.get("hitBreakpointIds")
+                if hit_ids:
+                    self._count_breakpoint_hits(hit_ids)
+            # Update state to paused. Adapters can report the same stop twice
+            # (e.g. rr replaying onto the current frame), so PAUSED is kept as is
+            if self._state != SessionState.PAUSED:
+                with contextlib.suppress(InvalidSessionStateError):
+                    await self.transition_to(SessionState.PAUSED)  # May be terminated
+            # Follow-up requests can't be awaited here, in the adapter's read loop
+            self._spawn(self._settle_stop(data, self._stop_count, entry, deferred_stop))
+
+        elif event_type == EventType.BREAKPOINT:
+            verified = self._apply_breakpoint_event(data)
+            if verified is not None:
+                self._record_event(EventType.BREAKPOINT_VERIFIED, verified)
+                await self.event_queue.put(EventType.BREAKPOINT_VERIFIED, verified)
+
+        elif event_type == EventType.LOADED_SOURCE:
+            source = data.get("source") or {}
+            self._track_loaded(
+                self._loaded_sources, self._source_key(source), source, data.get("reason")
+            )
+
+        elif event_type == EventType.MODULE:
+            module = data.get("module") or {}
+            key = str(module["id"]) if module.get("id") is not None else None
+            self._track_loaded(self._modules, key, module, data.get("reason"))
 
         elif event_type == EventType.CONTINUED:
+            self._invalidate_variables()
             with contextlib.suppress(InvalidSessionStateError):
                 await self.transition_to(SessionState.RUNNING)
 
         elif event_type in (EventType.TERMINATED, EventType.EXITED):
+            self._invalidate_variables()
+            self._pending_step = None
+            if self._restarting:
+                return
+            if event_type == EventType.EXITED:
+                self.exit_code = data.get("exitCode")
+            self._hidden_entry = False
+            if self._launch_exit is not None and not self._launch_exit.done():
+                self._launch_exit.set_result(None)
+            self._run_to_line = None
+            missing = self._check_test_found()
+            if missing is not None:
+                self._record_event(EventType.TEST_NOT_FOUND, missing)
+                await self.event_queue.put(EventType.TEST_NOT_FOUND, missing)
             with contextlib.suppress(InvalidSessionStateError):
                 await self.transition_to(SessionState.TERMINATED)
 
+        if event_type in (EventType.TERMINATED, EventType.EXITED):
+            async with self._stop_changed:
+                self._stop_changed.notify_all()
+
     def to_info(self) -> SessionInfo:
         """Convert to API response model."""
         return SessionInfo(
@@ -647,6 +3970,13 @@ class SessionManager:
         self._cleanup_task: asyncio.Task[None] | None = None
         self._persist_task: asyncio.Task[None] | None = None
         self._recoverable_sessions: dict[str, PersistedSession] = {}
+        # Histories of removed sessions with when they were removed, kept for
+        # settings.history_retention_seconds
+        self._ended_histories: dict[str, tuple[SessionHistory, datetime]] = {}
+        # Called with (session, reason) when a session ends on a time limit
+        self._end_listeners: list[Callable[[Session, str], Awaitable[None]]] = []
+        # Set once shutdown() starts; later calls await the same task
+        self._shutdown_task: asyncio.Task[None] | None = None
 
     async def start(self) -> None:
         """Start the session manager and background tasks."""
@@ -662,6 +3992,42 @@ class SessionManager:
 
     async def stop(self) -> None:
         """Stop the session manager and cleanup all sessions."""
+        await self.shutdown()
+
+    @property
+    def shutting_down(self) -> bool:
+        """Whether shutdown has started; sessions can no longer be used."""
+        return self._shutdown_task is not None
+
+    async def shutdown(
+        self,
+        grace_seconds: float | None = None,
+        terminate_attached: bool | None = None,
+    ) -> None:
+        """End every session because the server is stopping.
+
+        Idempotent: a signal handler and the lifespan exit may both call it,
+        and every caller waits for the one shutdown. Sessions are persisted
+        for recovery, then all disconnected at once, each killed after
+        grace_seconds if its adapter doesn't go.
+
+        Args:
+            grace_seconds: Default settings.shutdown_grace_seconds
+            terminate_attached: Also terminate attached and remote debuggees
+                (default settings.shutdown_terminate_attached)
+        """
+        if self._shutdown_task is None:
+            if grace_seconds is None:
+                grace_seconds = settings.shutdown_grace_seconds
+            if terminate_attached is None:
+                terminate_attached = settings.shutdown_terminate_attached
+            self._shutdown_task = asyncio.create_task(
+                self._shutdown(grace_seconds, terminate_attached)
+            )
+        await asyncio.shield(self._shutdown_task)
+
+    async def _shutdown(self, grace_seconds: float, terminate_attached: bool) -> None:
+        """Run the shutdown; see shutdown()."""
         # Cancel background tasks
         for task in [self._cleanup_task, self._persist_task]:
             if task:
@@ -669,26 +4035,41 @@ class SessionManager:
                 with contextlib.suppress(asyncio.CancelledError):
                     await task
 
-        # Persist and terminate all sessions
+        # Persist and remove all sessions; disconnects run outside the lock
         async with self._lock:
-            for session in list(self._sessions.values()):
-                # Save session state for recovery
-                try:
-                    persisted = session.to_persisted(server_shutdown=True)
-                    await self._session_store.save(persisted)
-                except Exception as e:
-                    logger.warning(f"Failed to persist session {session.id}: {e}")
-
-                # Save breakpoints
-                await self._breakpoint_store.save(session.project_root, session._breakpoints)
-                await session.cleanup()
+            sessions = list(self._sessions.values())
             self._sessions.clear()
+        for session in sessions:
+            # Save session state for recovery
+            try:
+                persisted = session.to_persisted(server_shutdown=True)
+                await self._session_store.save(persisted)
+            except Exception as e:
+                logger.warning(f"Failed to persist session {session.id}: {e}")
 
-        logger.info("SessionManager stopped (sessions persisted for recovery)")
+            # Save breakpoints
+            await self._breakpoint_store.save(session.project_root, session._breakpoints)
+
+        results = await asyncio.gather(
+            *(
+                session.shutdown(grace_seconds, True if terminate_attached else None)
+                for session in sessions
+            ),
+            return_exceptions=True,
+        )
+        for session, result in zip(sessions, results):
+            if isinstance(result, Exception):
+                logger.warning(f"Session {session.id}: shutdown failed: {result}")
+
+        logger.info(
+            f"SessionManager stopped ({len(sessions)} sessions ended, persisted for recovery)"
+        )
 
     async def create_session(self, config: SessionConfig) -> Session:
         """Create a new debug session."""
         async with self._lock:
+            if self.shutting_down:
+                raise ServerShuttingDownError()
             if len(self._sessions) >= settings.max_sessions:
                 raise SessionLimitError(settings.max_sessions)
 
@@ -699,6 +4080,7 @@ class SessionManager:
                 name=config.name,
                 timeout_minutes=config.timeout_minutes,
                 language=config.language,
+                remote=config.remote(),
             )
 
             # Initialize adapter
@@ -715,28 +4097,123 @@ class SessionManager:
     async def get_session(self, session_id: str) -> Session:
         """Get a session by ID."""
         async with self._lock:
+            if self.shutting_down:
+                raise ServerShuttingDownError()
             session = self._sessions.get(session_id)
             if not session:
                 raise SessionNotFoundError(session_id)
             session.touch()
             return session
 
+    async def resolve_session(self, session_id: str | None = None) -> Session:
+        """Get a session by ID, defaulting to the only session if none is given.
+
+        Raises:
+            ServerShuttingDownError: If the server is shutting down
+            SessionNotFoundError: If the given ID doesn't exist
+            SessionRequiredError: If no ID is given and there isn't exactly one session
+        """
+        if session_id is not None:
+            return await self.get_session(session_id)
+        async with self._lock:
+            if self.shutting_down:
+                raise ServerShuttingDownError()
+            if len(self._sessions) != 1:
+                raise SessionRequiredError(list(self._sessions))
+            session = next(iter(self._sessions.values()))
+            session.touch()
+            return session
+
     async def list_sessions(self) -> list[Session]:
         """List all active sessions."""
         async with self._lock:
             return list(self._sessions.values())
 
-    async def terminate_session(self, session_id: str) -> None:
-        """Terminate and remove a session."""
+    async def terminate_session(
+        self,
+        session_id: str | None = None,
+        terminate_debuggee: bool | None = None,
+    ) -> str:
+        """Terminate and remove a session.
+
+        Cleanup runs outside the manager lock so a slow disconnect doesn't
+        block requests for other sessions.
+
+        Args:
+            session_id: Session to terminate (default: the only session)
+            terminate_debuggee: Kill the debug target (default: only if launched)
+
+        Returns:
+            ID of the terminated session
+        """
+        session = await self.resolve_session(session_id)
         async with self._lock:
-            session = self._sessions.pop(session_id, None)
-            if not session:
-                raise SessionNotFoundError(session_id)
+            if self._sessions.pop(session.id, None) is None:
+                raise SessionNotFoundError(session.id)  # Terminated concurrently
 
-            # Save breakpoints before cleanup
-            await self._breakpoint_store.save(session.project_root, session._breakpoints)
-            await session.cleanup()
-            logger.info(f"Terminated session {session_id}")
+        # Save breakpoints before cleanup
+        await self._breakpoint_store.save(session.project_root, session._breakpoints)
+        await session.cleanup(terminate_debuggee)
+        self._retain_history(session)
+        logger.info(f"Terminated session {session.id}")
+        return session.id
+
+    def _retain_history(self, session: Session) -> None:
+        """Keep a removed session's history readable for the retention period."""
+        if settings.history_retention_seconds > 0:
+            self._ended_histories[session.id] = (session.history, datetime.now(timezone.utc))
+
+    async def get_history(self, session_id: str | None = None) -> tuple[SessionHistory, bool]:
+        """History of a session, including recently terminated ones.
+
+        Returns:
+            The history, and whether its session has been removed
+
+        Raises:
+            SessionNotFoundError: If the session is unknown or its history expired
+            SessionRequiredError: If no ID is given and there isn't exactly one session
+        """
+        if session_id is not None and session_id in self._ended_histories:
+            return self._ended_histories[session_id][0], True
+        session = await self.resolve_session(session_id)
+        return session.history, False
+
+    def record_tool_call(
+        self,
+        tool: str,
+        arguments: dict[str, Any],
+        result: Any,
+    ) -> None:
+        """Add a tool call to the history of the session it acted on.
+
+        The session is the one named in the arguments or result, or the only
+        session. Calls that can't be attributed aren't recorded.
+        """
+        session_id = arguments.get("session_id")
+        if session_id is None and isinstance(result, dict):
+            session_id = result.get("session_id")
+        if session_id is None and len(self._sessions) == 1:
+            session_id = next(iter(self._sessions))
+        if session_id in self._sessions:
+            history = self._sessions[session_id].history
+        elif session_id in self._ended_histories:
+            history = self._ended_histories[session_id][0]
+        else:
+            return
+
+        data: dict[str, Any] = {
+            "arguments": {k: v for k, v in arguments.items() if v is not None},
+        }
+        if isinstance(result, dict):
+            if "error" in result:
+                data["error"] = {"message": result["error"], "code": result.get("code")}
+            else:
+                data["result"] = {
+                    k: result[k]
+                    for k in ("status", "state", "result", "reason", "location")
+                    if k in result
+                }
+        history.record("tool", tool, data)
 
     async def save_breakpoints(self, session: Session) -> None:
         """Save session breakpoints to persistence."""
@@ -749,22 +4226,48 @@ class SessionManager:
             await self._cleanup_stale_sessions()
 
     async def _cleanup_stale_sessions(self) -> None:
-        """Remove sessions that have exceeded timeout."""
+        """End sessions past their idle timeout or maximum lifetime.
+
+        As in terminate_session, cleanup (which kills a launched debuggee)
+        runs outside the lock. End listeners are told why each session ended.
+        """
         now = datetime.now(timezone.utc)
         async with self._lock:
-            stale_ids: list[str] = []
-            for session_id, session in self._sessions.items():
-                idle_seconds = (now - session.last_activity).total_seconds()
-                timeout_seconds = session.timeout_minutes * 60
-
-                if idle_seconds > timeout_seconds:
-                    stale_ids.append(session_id)
-                    logger.info(f"Session {session_id} expired (idle {idle_seconds:.0f}s)")
-
-            for session_id in stale_ids:
-                session = self._sessions.pop(session_id)
-                await self._breakpoint_store.save(session.project_root, session._breakpoints)
-                await session.cleanup()
+            expired: list[tuple[Session, str]] = []
+            for session_id, session in list(self._sessions.items()):
+                reason = session.expiry_reason
+                if reason is not None:
+                    expired.append((self._sessions.pop(session_id), reason))
+                    logger.info(
+                        f"Session {session_id} ended ({reason}; "
+                        f"up {session.uptime_seconds:.0f}s, "
+                        f"idle {(now - session.last_activity).total_seconds():.0f}s)"
+                    )
+
+        for session, reason in expired:
+            await self._breakpoint_store.save(session.project_root, session._breakpoints)
+            await session.cleanup()
+            session.history.record("event", "session_ended", {"reason": reason})
+            self._retain_history(session)
+            for listener in list(self._end_listeners):
+                try:
+                    await listener(session, reason)
+                except Exception as e:
+                    logger.warning(f"Session {session.id}: end listener failed: {e}")
+
+        retention = settings.history_retention_seconds
+        for session_id, (_, ended_at) in list(self._ended_histories.items()):
+            if (now - ended_at).total_seconds() > retention:
+                del self._ended_histories[session_id]
+
+    def add_end_listener(self, listener: Callable[[Session, str], Awaitable[None]]) -> None:
+        """Await listener(session, reason) when a session is ended for a time limit.
+
+        reason is "idle_timeout" or "max_lifetime"; the session has already
+        been removed and cleaned up.
+        """
+        if listener not in self._end_listeners:
+            self._end_listeners.append(listener)
 
     @property
     def active_count(self) -> int:
@@ -12,20 +12,61 @@ Usage:
     python-debugger-mcp-server
 """
 
+import asyncio
+import base64
+import functools
+import inspect
 import logging
-from contextlib import asynccontextmanager
+import os
+import signal
+import sys
+from collections.abc import Awaitable, Callable
+from contextlib import asynccontextmanager, suppress
+from pathlib import Path
 from typing import Any
 
-from mcp.server.fastmcp import FastMCP
+from mcp.server.fastmcp import Context, FastMCP
 
+from polybugger_mcp.adapters.factory import UnknownAdapterError
+from polybugger_mcp.adapters.renderers import RENDER_MODES
+from polybugger_mcp.config import settings
 from polybugger_mcp.core.exceptions import (
+    BreakpointNotFoundError,
+    CapabilityNotSupportedError,
+    ContinuationTokenError,
+    DAPError,
+    DataBreakpointError,
+    EvaluateError,
+    FrameNotFoundError,
+    InvalidExceptionFilterError,
     InvalidSessionStateError,
+    LaunchConfigError,
+    NoBreakpointsError,
+    NoSymbolAtPositionError,
+    NotStoppedOnExceptionError,
+    PreLaunchError,
+    ProgramExitedError,
     SessionLimitError,
     SessionNotFoundError,
+    SessionRequiredError,
+    ServerShuttingDownError,
+    StdinUnavailableError,
+    UnverifiedBreakpointError,
+    VariableNotFoundError,
+    WatchNotFoundError,
+)
+from polybugger_mcp.core.session import Session, SessionManager
+from polybugger_mcp.models.dap import (
+    AttachConfig,
+    LaunchConfig,
+    PathMapping,
+    PreLaunchStep,
+    SourceBreakpoint,
+    StepFilters,
 )
-from polybugger_mcp.core.session import SessionManager
-from polybugger_mcp.models.dap import LaunchConfig, SourceBreakpoint
 from polybugger_mcp.models.session import SessionConfig
+from polybugger_mcp.utils import launch_json, test_runner
+from polybugger_mcp.utils.output_streamer import OutputStreamer
 from polybugger_mcp.utils.tui_formatter import TUIFormatter
 
 logger = logging.getLogger(__name__)
@@ -36,6 +77,19 @@ _session_manager: SessionManager | None = None
 # Global TUI formatter instance
 _tui_formatter: TUIFormatter | None = None
 
+# Output streams to MCP clients, keyed by debug session ID
+_output_streams: dict[str, OutputStreamer] = {}
+
+# MCP logger name used for streamed output notifications
+OUTPUT_LOGGER = "polybugger.output"
+
+# MCP clients to tell when their session ends on a time limit, keyed by
+# session ID (registered by debug_stream_output and debug_keep_alive)
+_session_clients: dict[str, Any] = {}
+
+# MCP logger name used for session lifecycle notifications
+SESSION_LOGGER = "polybugger.session"
+
 
 def _get_formatter() -> TUIFormatter:
     """Get the TUI formatter, creating if needed."""
@@ -45,28 +99,146 @@ def _get_formatter() -> TUIFormatter:
     return _tui_formatter
 
 
+# Time in-flight tool calls get to send their shutdown errors before a
+# signal is re-raised to exit
+_SHUTDOWN_FLUSH_SECONDS = 0.2
+
+# Tasks handling a shutdown signal, referenced so they aren't collected
+_signal_tasks: set[asyncio.Task[None]] = set()
+
+
 @asynccontextmanager
 async def lifespan(app: FastMCP):  # type: ignore[no-untyped-def]
     """Manage the lifecycle of the session manager."""
     global _session_manager
     _session_manager = SessionManager()
+    _session_manager.add_end_listener(_notify_session_ended)
     await _session_manager.start()
+    signals = _install_signal_handlers()
     logger.info("MCP Debug Server started")
     try:
         yield {"session_manager": _session_manager}
     finally:
-        await _session_manager.stop()
+        loop = asyncio.get_running_loop()
+        for sig in signals:
+            loop.remove_signal_handler(sig)
+        await _shutdown_server()
         logger.info("MCP Debug Server stopped")
 
 
+async def _shutdown_server() -> None:
+    """End output streams and every debug session; safe to call repeatedly."""
+    for session_id in list(_output_streams):
+        await _stop_output_stream(session_id)
+    if _session_manager is not None:
+        await _session_manager.shutdown()
+
+
+def _install_signal_handlers() -> list[signal.Signals]:
+    """Shut every session down on SIGTERM and SIGINT before exiting.
+
+    By default SIGTERM exits at once, leaving debuggees running. Handlers
+    can only be installed from the main thread, so none are elsewhere.
+
+    Returns:
+        Signals handled, for removal when the server stops
+    """
+    if sys.platform == "win32":
+        return []
+    loop = asyncio.get_running_loop()
+    installed = []
+    for sig in (signal.SIGTERM, signal.SIGINT):
+        try:
+            loop.add_signal_handler(sig, _on_shutdown_signal, sig)
+        except (RuntimeError, ValueError):
+            continue
+        installed.append(sig)
+    return installed
+
+
+def _on_shutdown_signal(sig: signal.Signals) -> None:
+    """Start the shutdown for a signal; a repeated signal joins the same one."""
+    task = asyncio.create_task(_shutdown_and_exit(sig))
+    _signal_tasks.add(task)
+    task.add_done_callback(_signal_tasks.discard)
+
+
+async def _shutdown_and_exit(sig: signal.Signals) -> None:
+    """End every session, then exit with the signal's default action."""
+    logger.info(f"Received {sig.name}, ending debug sessions")
+    try:
+        await _shutdown_server()
+        await asyncio.sleep(_SHUTDOWN_FLUSH_SECONDS)
+    finally:
+        signal.signal(sig, signal.SIG_DFL)
+        os.kill(os.getpid(), sig)
+
+
 # Create the MCP server
 mcp = FastMCP(
     name="polybugger",
-    instructions="""Multi-language debugger supporting Python, JavaScript/TypeScript, Go, and Rust. Workflow: list_languages (optional) -> create_session(language) -> set_breakpoints -> launch -> poll_events -> get_stacktrace/variables/evaluate -> step/continue. Use watches to track expressions.""",
+    instructions="""Multi-language debugger supporting Python, JavaScript/TypeScript, Go, and Rust. Workflow: list_languages (optional) -> create_session(language) -> set_breakpoints -> launch -> poll_events -> get_stacktrace/variables/evaluate -> step/continue. Use watches to track expressions. session_id may be omitted while only one session exists.""",
     lifespan=lifespan,
 )
 
 
+def _parse_path_mappings(raw: list[dict[str, str]] | None) -> list[PathMapping]:
+    """Validate path_mappings tool input.
+
+    Raises:
+        ValueError: If an entry lacks local_root or remote_root
+    """
+    mappings = []
+    for entry in raw or []:
+        if not entry.get("local_root") or not entry.get("remote_root"):
+            raise ValueError(f"Invalid path mapping {entry}; expected local_root and remote_root")
+        mappings.append(
+            PathMapping(local_root=entry["local_root"], remote_root=entry["remote_root"])
+        )
+    return mappings
+
+
+# Disassembly and instruction steps need an adapter for compiled code
+_NATIVE_CODE_HINT = "Supported by the delve (Go) and codelldb (Rust, C, C++) adapters"
+
+# Deepest expansion debug_expand_variable performs in one call
+_EXPAND_MAX_DEPTH = 5
+
+
+def _check_wait(wait_for_stop_seconds: float | None) -> dict[str, Any] | None:
+    """Return an error response if wait_for_stop_seconds is out of range."""
+    if wait_for_stop_seconds is not None and not 0 < wait_for_stop_seconds <= 300:
+        return {
+            "error": "wait_for_stop_seconds must be between 0 and 300",
+            "code": "INVALID_ARGS",
+        }
+    return None
+
+
+def _clip_value(value: str, max_length: int) -> tuple[str, bool]:
+    """Truncate a rendered value to max_length characters."""
+    if len(value) <= max_length:
+        return value, False
+    return value[:max_length], True
+
+
+def _invalid_render(render: str) -> dict[str, Any]:
+    """Error for a render mode no renderer knows."""
+    return {
+        "error": f"Invalid render: {render}. Use one of {', '.join(RENDER_MODES)}",
+        "code": "INVALID_RENDER",
+    }
+
+
+def _unsupported_render(session: Session, render: str) -> dict[str, Any]:
+    """Error for a render mode the session's debug adapter lacks."""
+    return {
+        "error": f"render '{render}' is not supported for {session.language}",
+        "code": "NOT_SUPPORTED",
+        "supported": list(session.render_modes),
+    }
+
+
 def _get_manager() -> SessionManager:
     """Get the session manager, raising if not initialized."""
     if _session_manager is None:
@@ -74,28 +246,97 @@ def _get_manager() -> SessionManager:
     return _session_manager
 
 
+def _refused_when_shutting_down(
+    tool: Callable[..., Awaitable[dict[str, Any]]],
+) -> Callable[..., Awaitable[dict[str, Any]]]:
+    """Answer a session tool with SERVER_SHUTTING_DOWN once shutdown has begun.
+
+    Covers calls made after it began and calls it interrupts, such as a wait
+    for a stop. Applied by _recorded; unrecorded session tools use it directly.
+    """
+
+    @functools.wraps(tool)
+    async def wrapper(*args: Any, **kwargs: Any) -> dict[str, Any]:
+        try:
+            if _session_manager is not None and _session_manager.shutting_down:
+                raise ServerShuttingDownError()
+            return await tool(*args, **kwargs)
+        except ServerShuttingDownError as e:
+            return {"error": e.message, "code": e.code}
+
+    return wrapper
+
+
+def _recorded(
+    tool: Callable[..., Awaitable[dict[str, Any]]],
+) -> Callable[..., Awaitable[dict[str, Any]]]:
+    """Record each call of a tool in the history of the session it acted on.
+
+    Used on tools that act on or inspect the program; status polling and
+    output reads are left out so they don't crowd out the rest.
+    """
+    signature = inspect.signature(tool)
+    guarded = _refused_when_shutting_down(tool)
+
+    @functools.wraps(tool)
+    async def wrapper(*args: Any, **kwargs: Any) -> dict[str, Any]:
+        result = await guarded(*args, **kwargs)
+        if _session_manager is not None:
+            arguments = signature.bind(*args, **kwargs).arguments
+            _session_manager.record_tool_call(tool.__name__, arguments, result)
+        return result
+
+    return wrapper
+
+
 # =============================================================================
 # Session Management Tools
 # =============================================================================
 
 
 @mcp.tool()
+@_recorded
 async def debug_create_session(
     project_root: str,
     language: str = "python",
     name: str | None = None,
     timeout_minutes: int = 60,
+    transport: str = "stdio",
+    host: str = "127.0.0.1",
+    port: int | None = None,
+    connect_timeout: float = 10.0,
+    connect_retries: int | None = None,
 ) -> dict[str, Any]:
     """Create a debug session. Returns session_id for other operations.
 
+    With transport="tcp" no adapter is started: the session connects to a
+    DAP server already listening at host:port (e.g. `dlv dap --listen` or
+    debugpy's adapter on another machine), retrying while it isn't up yet.
+    Ending such a session closes the connection and leaves the server and
+    its program running unless terminate_debuggee is set.
+
     Args:
         project_root: Project root path
         language: Programming language (python, javascript, go, rust)
         name: Session name (optional)
         timeout_minutes: Timeout (default 60)
+        transport: "stdio" (start the adapter locally) or "tcp"
+        host: DAP server host for transport="tcp"
+        port: DAP server port (required for transport="tcp")
+        connect_timeout: Seconds to keep retrying the connection (default 10)
+        connect_retries: Maximum retries after the first attempt (default: until
+            the timeout)
     """
     from polybugger_mcp.adapters.factory import is_language_supported
 
+    if transport not in ("stdio", "tcp"):
+        return {
+            "error": f"Invalid transport '{transport}'; use stdio or tcp",
+            "code": "INVALID_CONFIG",
+        }
+    if transport == "tcp" and port is None:
+        return {"error": "transport 'tcp' requires a port", "code": "INVALID_CONFIG"}
+
     manager = _get_manager()
     try:
         # Validate language
@@ -108,40 +349,57 @@ async def debug_create_session(
                 "supported": get_supported_languages(),
             }
 
-        config = SessionConfig(
-            project_root=project_root,
-            language=language,
-            name=name,
-            timeout_minutes=timeout_minutes,
-        )
+        try:
+            config = SessionConfig(
+                project_root=project_root,
+                language=language,
+                name=name,
+                timeout_minutes=timeout_minutes,
+                transport=transport,  # type: ignore[arg-type]
+                host=host,
+                port=port,
+                connect_timeout=connect_timeout,
+                connect_retries=connect_retries,
+            )
+        except ValueError as e:
+            return {"error": str(e), "code": "INVALID_CONFIG"}
         session = await manager.create_session(config)
         return {
             "session_id": session.id,
             "name": session.name,
             "project_root": str(session.project_root),
             "language": session.language,
+            "transport": config.transport,
             "state": session.state.value,
             "message": f"Session created for {language}. Set breakpoints and then launch.",
         }
     except SessionLimitError as e:
         return {"error": str(e), "code": "SESSION_LIMIT"}
+    except DAPError as e:
+        return {"error": e.message, "code": e.code}
 
 
 @mcp.tool()
 async def debug_list_languages() -> dict[str, Any]:
-    """List supported programming languages for debugging."""
-    from polybugger_mcp.adapters.factory import get_supported_languages
+    """List supported programming languages and the adapters behind them."""
+    from polybugger_mcp.adapters.factory import adapter_registry, get_supported_languages
 
     return {
         "languages": get_supported_languages(),
+        "adapters": [d.describe() for d in adapter_registry.descriptors()],
         "default": "python",
-        "message": "Use language parameter in debug_create_session to specify language.",
+        "message": "Use language parameter in debug_create_session to specify language. "
+        "debug_launch picks the adapter from the program's extension unless adapter is given.",
     }
 
 
 @mcp.tool()
 async def debug_list_sessions() -> dict[str, Any]:
-    """List all active debug sessions."""
+    """List all active debug sessions.
+
+    Tools default to the only session when session_id is omitted; with several
+    sessions, pass the session_id from this list.
+    """
     manager = _get_manager()
     sessions = await manager.list_sessions()
     return {
@@ -151,8 +409,11 @@ async def debug_list_sessions() -> dict[str, Any]:
                 "name": s.name,
                 "project_root": str(s.project_root),
                 "language": s.language,
+                "target": s.target,
+                "attached": s.attached,
                 "state": s.state.value,
                 "stop_reason": s.stop_reason,
+                "uptime_seconds": round(s.uptime_seconds, 1),
             }
             for s in sessions
         ],
@@ -161,34 +422,219 @@ async def debug_list_sessions() -> dict[str, Any]:
 
 
 @mcp.tool()
-async def debug_get_session(session_id: str) -> dict[str, Any]:
-    """Get session state, stop reason, and location."""
+@_refused_when_shutting_down
+async def debug_get_session(
+    redact_env: bool = False,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get session state, stop reason, and location.
+
+    Launched sessions also report the working directory and the effective
+    environment the program was started with. If the debug adapter process
+    died, adapter_exit gives its exit code and last stderr lines. The
+    *_remaining_seconds fields say how long until the session is ended for
+    inactivity or for reaching its maximum lifetime (see debug_keep_alive).
+
+    Args:
+        redact_env: List environment variable names only, hiding their values
+        session_id: Session ID (optional when only one session exists)
+    """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
+        env = session.launch_env
+        if env is not None and redact_env:
+            env = dict.fromkeys(env, "<redacted>")
         return {
             "session_id": session.id,
             "name": session.name,
             "project_root": str(session.project_root),
             "language": session.language,
+            "adapter": session.adapter_name,
+            "transport": "tcp" if session.remote else "stdio",
+            "remote": session.remote.model_dump() if session.remote else None,
+            "target": session.target,
+            "attached": session.attached,
             "state": session.state.value,
+            "uptime_seconds": round(session.uptime_seconds, 1),
+            "idle_timeout_seconds": session.timeout_minutes * 60,
+            "idle_remaining_seconds": round(session.idle_remaining_seconds, 1),
+            "lifetime_remaining_seconds": round(session.lifetime_remaining_seconds, 1),
             "current_thread_id": session.current_thread_id,
             "stop_reason": session.stop_reason,
             "stop_location": session.stop_location,
+            "stdin_available": session.stdin_available,
+            "cwd": session.launch_cwd,
+            "env": env,
+            "adapter_exit": session.adapter_exit,
+            "capabilities": {
+                "reverse_execution": session.supports_reverse_execution,
+                "set_variable": session.has_capability("supportsSetVariable"),
+                "set_expression": session.has_capability("supportsSetExpression"),
+                "data_breakpoints": session.has_capability("supportsDataBreakpoints"),
+            },
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_refused_when_shutting_down
+async def debug_keep_alive(
+    ctx: Context,  # type: ignore[type-arg]
+    timeout_minutes: int | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Keep a session open during a long stretch without other tool calls.
+
+    Sessions idle for their timeout (create_session timeout_minutes), or
+    older than the server's maximum lifetime, are terminated and their
+    program killed. Any tool call on a session resets the idle timer; this
+    one does only that, optionally changing the timeout. The lifetime limit
+    can't be extended. The calling client then gets a notification (logger
+    "polybugger.session", event "session_ended" with the reason
+    "idle_timeout" or "max_lifetime") if the session is ended.
+
+    Args:
+        timeout_minutes: New idle timeout, 1-1440 (default: unchanged)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if timeout_minutes is not None and not 1 <= timeout_minutes <= 1440:
+        return {"error": "timeout_minutes must be between 1 and 1440", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        if timeout_minutes is not None:
+            session.timeout_minutes = timeout_minutes
+        _session_clients[session.id] = ctx.session
+        return {
+            "session_id": session.id,
+            "idle_timeout_seconds": session.timeout_minutes * 60,
+            "idle_remaining_seconds": round(session.idle_remaining_seconds, 1),
+            "lifetime_remaining_seconds": round(session.lifetime_remaining_seconds, 1),
+            "logger": SESSION_LOGGER,
         }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_refused_when_shutting_down
+async def debug_get_session_history(
+    since: int = 0,
+    event_type: str | None = None,
+    limit: int = 200,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Timeline of what happened in a session, for reviewing a debugging run.
+
+    Entries are debug events (stops with their location, continues, exits
+    with the exit code, breakpoint changes, output totals) and tool calls
+    (arguments and outcome), oldest first, each with a seq number. Pass the
+    returned next_since as since to read only newer entries. Only the most
+    recent entries are kept; dropped counts those pushed out. A terminated
+    session's history stays readable for a while (15 minutes by default).
+
+    Args:
+        since: Only entries with a seq greater than this
+        event_type: Only entries of this type (e.g. "stopped", "exited",
+            "debug_evaluate"), or "event"/"tool" for all of one kind
+        limit: Maximum entries to return (default 200)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if since < 0 or limit < 1:
+        return {"error": "since must be >= 0 and limit >= 1", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        history, ended = await manager.get_history(session_id)
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+    entries = history.entries(since=since, event_type=event_type, limit=limit + 1)
+    has_more = len(entries) > limit
+    entries = entries[:limit]
+    return {
+        "entries": [e.to_dict() for e in entries],
+        "next_since": entries[-1].seq if has_more else max(since, history.last_seq),
+        "has_more": has_more,
+        "dropped": history.dropped,
+        "session_ended": ended,
+    }
+
+
+@mcp.tool()
+@_recorded
+async def debug_terminate_session(
+    terminate_debuggee: bool | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Terminate session and clean up.
+
+    Args:
+        terminate_debuggee: Kill the target process (default: True for launched
+            programs, False for attached processes and tcp transport sessions,
+            which keep running)
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        terminated_id = await manager.terminate_session(session_id, terminate_debuggee)
+        await _stop_output_stream(terminated_id)
+        _session_clients.pop(terminated_id, None)
+        return {"status": "terminated", "session_id": terminated_id}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
 
 
 @mcp.tool()
-async def debug_terminate_session(session_id: str) -> dict[str, Any]:
-    """Terminate session and clean up."""
+@_recorded
+async def debug_restart_session(session_id: str | None = None) -> dict[str, Any]:
+    """Restart the launched program with the same config, keeping breakpoints.
+
+    Breakpoints (with conditions, hit conditions and log messages) and
+    exception filters are replayed. Uses the adapter's native restart when
+    available, else terminates and relaunches. A relaunch replays them
+    before the program runs; a native restart resends them once the new run
+    has started, so a stop right at its start depends on the adapter having
+    kept them (replayed_before_start says which). Works after the program
+    has exited too.
+
+    Args:
+        session_id: Session ID (optional when only one session exists)
+
+    Returns:
+        method ("native" or "relaunch"), replayed_before_start,
+        breakpoints_replayed, unverified breakpoints and moved breakpoints
+        (requested_line -> line)
+    """
     manager = _get_manager()
     try:
-        await manager.terminate_session(session_id)
-        return {"status": "terminated", "session_id": session_id}
+        session = await manager.resolve_session(session_id)
+        report = await session.restart()
+        return {
+            "status": "restarted",
+            "session_id": session.id,
+            "state": session.state.value,
+            **report,
+        }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except Exception as e:
+        return {"error": str(e), "code": "LAUNCH_FAILED"}
 
 
 # =============================================================================
@@ -197,28 +643,36 @@ async def debug_terminate_session(session_id: str) -> dict[str, Any]:
 
 
 @mcp.tool()
+@_recorded
 async def debug_set_breakpoints(
-    session_id: str,
     file_path: str,
     lines: list[int],
     conditions: list[str | None] | None = None,
     hit_conditions: list[str | None] | None = None,
     log_messages: list[str | None] | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Set breakpoints in a file with optional conditions, hit counts, and log messages.
 
+    Replaces the file's breakpoints; debug_set_breakpoints_batch adds to them.
+    Each result gives the requested line and where the adapter bound it:
+    bound_line differs ("moved") when a line can't hold a breakpoint, and
+    unverified ones may bind later, reported by a breakpointVerified event.
+
     Args:
-        session_id: Session ID
         file_path: Source file path
         lines: Line numbers
         conditions: Optional conditions per line (e.g., "x > 5", "len(items) == 0")
         hit_conditions: Optional hit count conditions per line (e.g., ">=5", "==10", "%3==0")
         log_messages: Optional log messages per line (logpoints). Can include {expressions}.
                       Example: "Value is {x}, length is {len(items)}"
+        session_id: Session ID (optional when only one session exists)
+
+    Returns code INVALID_CONDITION with a "rejected" list when the adapter refuses a condition.
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
 
         # Build breakpoint list
         breakpoints = []
@@ -247,13 +701,13 @@ async def debug_set_breakpoints(
         await manager.save_breakpoints(session)
 
         # Return breakpoint info including conditions
-        return {
+        response: dict[str, Any] = {
             "file": file_path,
             "breakpoints": [
                 {
-                    "line": bp.line,
-                    "verified": bp.verified,
-                    "message": bp.message,
+                    "id": bp.id,
+                    "line": breakpoints[i].line,
+                    **session.describe_binding(breakpoints[i].line, bp),
                     "condition": breakpoints[i].condition,
                     "hit_condition": breakpoints[i].hit_condition,
                     "log_message": breakpoints[i].log_message,
@@ -261,48 +715,168 @@ async def debug_set_breakpoints(
                 for i, bp in enumerate(result)
             ],
         }
+
+        # Surface conditions the adapter refused (e.g. syntax errors in the
+        # target language) instead of leaving a silently unverified breakpoint
+        launched = session.adapter is not None and session.adapter.is_launched
+        rejected = [
+            {
+                "line": breakpoints[i].line,
+                "condition": breakpoints[i].condition,
+                "hit_condition": breakpoints[i].hit_condition,
+                "message": bp.message,
+            }
+            for i, bp in enumerate(result)
+            if launched
+            and not bp.verified
+            and bp.message
+            and (breakpoints[i].condition or breakpoints[i].hit_condition)
+        ]
+        if rejected:
+            response["error"] = "Adapter rejected breakpoint condition(s)"
+            response["code"] = "INVALID_CONDITION"
+            response["rejected"] = rejected
+
+        return response
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except DAPError as e:
+        return {
+            "error": e.message,
+            "code": "BREAKPOINT_REJECTED",
+            "details": e.details.get("message", e.message),
+        }
 
 
 @mcp.tool()
-async def debug_get_breakpoints(session_id: str) -> dict[str, Any]:
-    """Get all breakpoints organized by file, including conditions, hit counts, and log messages."""
+@_recorded
+async def debug_set_breakpoints_batch(
+    breakpoints: list[dict[str, Any]],
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Add many breakpoints across files in one call, keeping those already set.
+
+    Unlike debug_set_breakpoints, which replaces a file's breakpoints, each
+    entry is added to its file (replacing only a breakpoint on the same
+    line). One request is sent per file.
+
+    Args:
+        breakpoints: Entries of {file, line, condition?, hit_condition?, log_message?}
+        session_id: Session ID (optional when only one session exists)
+
+    Returns a result per entry in input order: the same fields as
+    debug_set_breakpoints, or error/code for entries that were invalid or
+    whose file the adapter refused. Other entries stay installed.
+    """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        return {
-            "files": {
-                path: [
-                    {
+        session = await manager.resolve_session(session_id)
+
+        results: list[dict[str, Any]] = [{} for _ in breakpoints]
+        by_file: dict[str, list[tuple[int, SourceBreakpoint]]] = {}
+        for i, entry in enumerate(breakpoints):
+            file_path = entry.get("file")
+            line = entry.get("line")
+            try:
+                if not isinstance(file_path, str) or not isinstance(line, int) or line < 1:
+                    raise ValueError("each entry needs a file and a line >= 1")
+                bp = SourceBreakpoint(
+                    line=line,
+                    condition=entry.get("condition"),
+                    hit_condition=entry.get("hit_condition"),
+                    log_message=entry.get("log_message"),
+                )
+            except ValueError as e:
+                results[i] = {
+                    "file": file_path,
+                    "line": line,
+                    "error": str(e),
+                    "code": "INVALID_ARGS",
+                }
+                continue
+            by_file.setdefault(file_path, []).append((i, bp))
+
+        for file_path, entries in by_file.items():
+            try:
+                installed = await session.add_breakpoints(file_path, [bp for _, bp in entries])
+            except DAPError as e:
+                for i, bp in entries:
+                    results[i] = {
+                        "file": file_path,
                         "line": bp.line,
-                        "condition": bp.condition,
-                        "hit_condition": bp.hit_condition,
-                        "log_message": bp.log_message,
+                        "error": e.message,
+                        "code": "BREAKPOINT_REJECTED",
                     }
-                    for bp in bps
-                ]
-                for path, bps in session._breakpoints.items()
-            }
+                continue
+            for (i, bp), result in zip(entries, installed):
+                results[i] = {
+                    "file": file_path,
+                    "id": result.id,
+                    "line": bp.line,
+                    **session.describe_binding(bp.line, result),
+                    "condition": bp.condition,
+                    "hit_condition": bp.hit_condition,
+                    "log_message": bp.log_message,
+                }
+
+        await manager.save_breakpoints(session)
+        return {
+            "breakpoints": results,
+            "files": len(by_file),
+            "failed": sum(1 for r in results if "error" in r),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_get_breakpoints(
+    reset_hit_counts: bool = False,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get all breakpoints organized by file, including conditions, hit counts, and log messages.
+
+    "line" is the requested line; bound_line/bound_column are where the
+    adapter actually placed a verified breakpoint.
+
+    Args:
+        reset_hit_counts: Zero hit counters after reporting (measure hits between two points)
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        return {
+            "files": session.describe_breakpoints(reset_hit_counts=reset_hit_counts),
+            "function_breakpoints": session.describe_function_breakpoints(),
+            "data_breakpoints": session.describe_data_breakpoints(),
         }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
 
 
 @mcp.tool()
+@_recorded
 async def debug_clear_breakpoints(
-    session_id: str,
     file_path: str | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Clear breakpoints from file or all files.
 
     Args:
-        session_id: Session ID
-        file_path: File path (None = all files)
+        file_path: File path (None = all files, function and data breakpoints)
+        session_id: Session ID (optional when only one session exists)
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
 
         if file_path:
             await session.set_breakpoints(file_path, [])
@@ -310,327 +884,1883 @@ async def debug_clear_breakpoints(
         else:
             for path in list(session._breakpoints.keys()):
                 await session.set_breakpoints(path, [])
+            await session.clear_function_breakpoints()
+            await session.clear_data_breakpoints()
             return {"status": "cleared", "files": "all"}
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
 
 
-# =============================================================================
-# Launch and Execution Tools
-# =============================================================================
+async def _toggle_breakpoints(
+    enabled: bool,
+    breakpoint_ids: list[int] | None,
+    file_path: str | None,
+    all_breakpoints: bool,
+    session_id: str | None,
+) -> dict[str, Any]:
+    """Shared body of debug_enable_breakpoints and debug_disable_breakpoints."""
+    if sum((breakpoint_ids is not None, file_path is not None, all_breakpoints)) != 1:
+        return {
+            "error": "Provide exactly one of breakpoint_ids, file_path or all_breakpoints",
+            "code": "INVALID_ARGS",
+        }
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        changed = await session.set_breakpoints_enabled(enabled, breakpoint_ids, file_path)
+        await manager.save_breakpoints(session)
+        return {"status": "enabled" if enabled else "disabled", "changed": changed}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except BreakpointNotFoundError as e:
+        return {"error": e.message, "code": "NOT_FOUND"}
 
 
 @mcp.tool()
-async def debug_launch(
-    session_id: str,
-    program: str | None = None,
-    module: str | None = None,
-    args: list[str] | None = None,
-    cwd: str | None = None,
-    env: dict[str, str] | None = None,
-    stop_on_entry: bool = False,
-    stop_on_exception: bool = True,
+@_recorded
+async def debug_enable_breakpoints(
+    breakpoint_ids: list[int] | None = None,
+    file_path: str | None = None,
+    all_breakpoints: bool = False,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Launch program for debugging. Use program OR module.
+    """Re-enable disabled breakpoints, resending them to the adapter.
+
+    Ids are those listed by debug_get_breakpoints; they and hit counts
+    are kept while a breakpoint is disabled.
 
     Args:
-        session_id: Session ID
-        program: Script path
-        module: Module to run with -m
-        args: Arguments
-        cwd: Working directory
-        env: Environment variables
-        stop_on_entry: Stop at first line
-        stop_on_exception: Stop on exceptions
+        breakpoint_ids: Breakpoints to enable
+        file_path: Enable every breakpoint in this file instead
+        all_breakpoints: Enable every breakpoint in every file instead
+        session_id: Session ID (optional when only one session exists)
+
+    Returns the breakpoints that changed under "changed".
     """
-    manager = _get_manager()
-    try:
-        session = await manager.get_session(session_id)
+    return await _toggle_breakpoints(True, breakpoint_ids, file_path, all_breakpoints, session_id)
 
-        if not program and not module:
-            return {"error": "Either program or module must be specified"}
 
-        launch_kwargs: dict[str, Any] = {
-            "program": program,
-            "module": module,
-            "args": args or [],
-            "env": env or {},
-            "stop_on_entry": stop_on_entry,
-            "stop_on_exception": stop_on_exception,
-        }
-        if cwd is not None:
-            launch_kwargs["cwd"] = cwd
+@mcp.tool()
+@_recorded
+async def debug_disable_breakpoints(
+    breakpoint_ids: list[int] | None = None,
+    file_path: str | None = None,
+    all_breakpoints: bool = False,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Disable breakpoints without deleting them; they no longer stop the program.
 
-        config = LaunchConfig(**launch_kwargs)
+    Disabled breakpoints stay listed (enabled=false) with their id,
+    conditions and hit count until re-enabled or cleared.
 
-        await session.launch(config)
+    Args:
+        breakpoint_ids: Breakpoints to disable
+        file_path: Disable every breakpoint in this file instead
+        all_breakpoints: Disable every breakpoint in every file instead
+        session_id: Session ID (optional when only one session exists)
 
-        return {
-            "status": "launched",
-            "session_id": session_id,
-            "state": session.state.value,
-            "message": "Program launched. Poll events or wait for stopped state.",
-        }
-    except SessionNotFoundError:
-        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
-    except InvalidSessionStateError as e:
-        return {"error": str(e), "code": "INVALID_STATE"}
-    except Exception as e:
-        return {"error": str(e), "code": "LAUNCH_FAILED"}
+    Returns the breakpoints that changed under "changed".
+    """
+    return await _toggle_breakpoints(False, breakpoint_ids, file_path, all_breakpoints, session_id)
 
 
 @mcp.tool()
-async def debug_continue(
-    session_id: str,
-    thread_id: int | None = None,
+@_recorded
+async def debug_set_exception_breakpoints(
+    filters: list[str],
+    conditions: dict[str, str] | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Continue until next breakpoint or end."""
+    """Break when exceptions are raised. Replaces previous exception filters.
+
+    Filter IDs are adapter-specific (e.g. "raised"/"uncaught" for Python,
+    "panic" for Go); call with an unknown filter to list the available ones.
+    Exception stops include type, message and traceback in the event.
+
+    Args:
+        filters: Filter IDs to enable ([] = don't break on exceptions)
+        conditions: Optional condition per filter ID, e.g. {"raised": "ValueError"}
+        session_id: Session ID (optional when only one session exists)
+    """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        await session.continue_(thread_id)
-        return {"status": "continued", "state": session.state.value}
+        session = await manager.resolve_session(session_id)
+        result = await session.set_exception_breakpoints(filters, conditions)
+        result["available"] = [
+            {"filter": f["filter"], "label": f.get("label", f["filter"])}
+            for f in session.exception_breakpoint_filters
+        ]
+        return result
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidExceptionFilterError as e:
+        return {
+            "error": e.message,
+            "code": "INVALID_FILTER",
+            "available": e.details["available_filters"],
+        }
     except InvalidSessionStateError as e:
         return {"error": str(e), "code": "INVALID_STATE"}
+    except DAPError as e:
+        return {"error": e.message, "code": "DAP_ERROR"}
 
 
 @mcp.tool()
-async def debug_step(
-    session_id: str,
-    mode: str,
-    thread_id: int | None = None,
+@_recorded
+async def debug_set_function_breakpoint(
+    name: str,
+    condition: str | None = None,
+    hit_condition: str | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Step execution: over (next line), into (enter function), out (exit function).
+    """Break when a function is entered, by symbol name instead of file and line.
+
+    Name functions as the language does: "mypackage.handler.process_request"
+    for Python, "main.calculate" or "main.(*Server).Handle" for Go. Setting a
+    name again replaces its breakpoint; they survive edits to the file and
+    restarts. Stops report reason "function breakpoint". List them with
+    debug_get_breakpoints, remove them with debug_clear_breakpoints.
 
     Args:
-        session_id: Session ID
-        mode: "over", "into", or "out"
-        thread_id: Thread ID (default: current)
+        name: Function name
+        condition: Optional condition expression
+        hit_condition: Optional hit count condition
+        session_id: Session ID (optional when only one session exists)
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-
-        if mode == "over":
-            await session.step_over(thread_id)
-        elif mode == "into":
-            await session.step_into(thread_id)
-        elif mode == "out":
-            await session.step_out(thread_id)
-        else:
-            return {
-                "error": f"Invalid mode: {mode}. Use 'over', 'into', or 'out'",
-                "code": "INVALID_MODE",
-            }
-
-        return {"status": "stepping", "mode": mode}
+        session = await manager.resolve_session(session_id)
+        function_breakpoint = await session.set_function_breakpoint(
+            name, condition=condition, hit_condition=hit_condition
+        )
+        return {"status": "set", **function_breakpoint}
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
     except InvalidSessionStateError as e:
         return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {
+            "error": f"{e.message} ({session.adapter_name} as installed does not "
+            "advertise supportsFunctionBreakpoints)",
+            "code": "NOT_SUPPORTED",
+            "hint": "set a line breakpoint with debug_set_breakpoints instead",
+        }
+    except DAPError as e:
+        return {"error": e.message, "code": "DAP_ERROR"}
 
 
 @mcp.tool()
-async def debug_pause(
-    session_id: str,
-    thread_id: int | None = None,
+@_recorded
+async def debug_set_data_breakpoint(
+    name: str,
+    variables_reference: int | None = None,
+    frame_id: int | None = None,
+    access_type: str = "write",
+    condition: str | None = None,
+    hit_condition: str | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Pause a running program."""
+    """Break when a variable is written or read (a watchpoint). Session must be paused.
+
+    Watch a variable by the variables_reference it was listed under plus its
+    name, or pass an expression (or address, for delve) as name with a
+    frame_id. Stops report reason "data breakpoint" and which watchpoint fired.
+    Remove them with debug_clear_breakpoints.
+
+    Args:
+        name: Variable name in variables_reference, or an expression
+        variables_reference: Container from debug_get_variables or debug_get_scopes
+        frame_id: Frame to evaluate an expression in
+        access_type: "write" (default), "read" or "readWrite"
+        condition: Optional condition expression
+        hit_condition: Optional hit count condition
+        session_id: Session ID (optional when only one session exists)
+    """
+    if access_type not in ("write", "read", "readWrite"):
+        return {
+            "error": f"Invalid access_type '{access_type}'; use write, read or readWrite",
+            "code": "INVALID_ARGS",
+        }
+
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        await session.pause(thread_id)
-        return {"status": "pausing"}
+        session = await manager.resolve_session(session_id)
+        watchpoint = await session.set_data_breakpoint(
+            name,
+            variables_ref=variables_reference,
+            frame_id=frame_id,
+            access_type=access_type,
+            condition=condition,
+            hit_condition=hit_condition,
+        )
+        return {"status": "set", **watchpoint}
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
     except InvalidSessionStateError as e:
         return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED"}
+    except DataBreakpointError as e:
+        return {"error": e.message, "code": e.code, "access_types": e.details["access_types"]}
+    except VariableNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_REFERENCE",
+            "hint": "call debug_get_scopes again for current references",
+        }
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+    except DAPError as e:
+        return {"error": e.message, "code": "DAP_ERROR"}
+
+
+# =============================================================================
+# Launch and Execution Tools
+# =============================================================================
+
+
+@mcp.tool()
+@_recorded
+async def debug_launch(
+    program: str | None = None,
+    module: str | None = None,
+    args: list[str] | None = None,
+    cwd: str | None = None,
+    env: dict[str, str | None] | None = None,
+    stop_on_entry: bool = False,
+    stop_on_exception: bool = True,
+    stdin_mode: str = "pipe",
+    adapter: str | None = None,
+    path_mappings: list[dict[str, str]] | None = None,
+    config_name: str | None = None,
+    just_my_code: bool | None = None,
+    step_filters: dict[str, Any] | None = None,
+    output_log_path: str | None = None,
+    pre_launch: list[dict[str, Any]] | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Launch program for debugging. Use program OR module, or a config_name.
+
+    The debug adapter follows the program's file extension (.py -> debugpy,
+    .go -> delve, .js/.ts -> js-debug, .rs/.c/.cpp -> codelldb), switching
+    from the session's language default if needed. Binaries with other
+    extensions (app.exe, pkg.test) run under the session's adapter.
+
+    With config_name, the entry of that name in the project's
+    .vscode/launch.json (see debug_list_launch_configs) supplies the program,
+    arguments, cwd, env and adapter. program, module, args, cwd and adapter
+    given here override it, and env is merged over it.
+
+    A program that exits before the launch completes fails it with code
+    PROGRAM_EXITED, its exit_code and its output so far.
+
+    pre_launch steps (a build, go generate) run in order before the adapter
+    starts, their output tagged "prelaunch" in debug_get_output. The first
+    that fails ends the launch with code PRE_LAUNCH_FAILED, its exit_code
+    and stderr_tail; terminating the session kills a step still running.
+
+    Args:
+        program: Script path
+        module: Module to run with -m
+        args: Program arguments
+        cwd: Working directory, relative to the project root (default: the
+            project root); the launch fails if it doesn't exist
+        env: Variables merged over the inherited environment; null unsets one
+        stop_on_entry: Stop at first line
+        stop_on_exception: Stop on exceptions
+        stdin_mode: "pipe" (feed input with debug_send_stdin), "inherit", or
+            "closed" (reads hit end-of-file)
+        adapter: Adapter name from debug_list_languages, overriding the extension
+        path_mappings: [{"local_root", "remote_root"}] pairs when the program
+            sees different paths (e.g. in a container); breakpoints and stack
+            traces use local paths
+        config_name: Name of a launch.json configuration to start from
+        just_my_code: Only stop in user code (native in debugpy; other
+            adapters skip stdlib and third-party code when stepping)
+        step_filters: {"patterns", "skip_stdlib", "skip_site_packages"} code
+            that steps pass through (see debug_set_step_filters)
+        output_log_path: Also write output to this file, relative to the
+            project root, with timestamps; rotated by size, and readable with
+            debug_get_output(from_file=true) after the buffer has dropped it
+        pre_launch: [{"command", "args", "cwd", "timeout"}] commands to run
+            first; cwd is relative to the project root (default: the launch
+            cwd) and timeout in seconds (default 300)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if stdin_mode not in ("pipe", "inherit", "closed"):
+        return {
+            "error": f"Invalid stdin_mode '{stdin_mode}'; use pipe, inherit or closed",
+            "code": "INVALID_CONFIG",
+        }
+    try:
+        mappings = _parse_path_mappings(path_mappings)
+        filters = StepFilters(**step_filters) if step_filters is not None else None
+        steps = [PreLaunchStep(**step) for step in pre_launch] if pre_launch is not None else None
+    except (ValueError, TypeError) as e:
+        return {"error": str(e), "code": "INVALID_CONFIG"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+
+        launch_kwargs: dict[str, Any] = {}
+        if config_name is not None:
+            entry = launch_json.find_configuration(
+                launch_json.read_launch_json(session.project_root), config_name
+            )
+            launch_kwargs = launch_json.resolve_configuration(entry, session.project_root)
+        if program or module:
+            launch_kwargs.update(program=program, module=module)
+        if not launch_kwargs.get("program") and not launch_kwargs.get("module"):
+            return {"error": "Either program or module must be specified"}
+
+        launch_kwargs.update(
+            env={**launch_kwargs.get("env", {}), **(env or {})},
+            stop_on_entry=stop_on_entry or launch_kwargs.get("stop_on_entry", False),
+            stop_on_exception=stop_on_exception,
+            stdin_mode=stdin_mode,
+            path_mappings=mappings,
+        )
+        if args is not None:
+            launch_kwargs["args"] = args
+        if adapter is not None:
+            launch_kwargs["adapter"] = adapter
+        if cwd is not None:
+            launch_kwargs["cwd"] = cwd
+        if just_my_code is not None:
+            launch_kwargs["just_my_code"] = just_my_code
+        if filters is not None:
+            launch_kwargs["step_filters"] = filters
+        if output_log_path is not None:
+            launch_kwargs["output_log_path"] = output_log_path
+        if steps is not None:
+            launch_kwargs["pre_launch"] = steps
+
+        config = LaunchConfig(**launch_kwargs)
+
+        await session.launch(config)
+
+        return {
+            "status": "launched",
+            "session_id": session.id,
+            "state": session.state.value,
+            "language": session.language,
+            "adapter": session.adapter_name,
+            "cwd": session.launch_cwd,
+            "stdin_available": session.stdin_available,
+            "step_filters": session.step_filter.describe(),
+            "output_log": str(session.output_log.path) if session.output_log else None,
+            "message": "Program launched. Poll events or wait for stopped state.",
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except LaunchConfigError as e:
+        return {"error": e.message, "code": e.code, **e.details}
+    except UnknownAdapterError as e:
+        return {"error": e.message, "code": e.code, "available": e.details["available"]}
+    except ProgramExitedError as e:
+        return {"error": e.message, "code": e.code, **e.details}
+    except PreLaunchError as e:
+        return {"error": e.message, "code": e.code, **e.details}
+    except Exception as e:
+        return {"error": str(e), "code": "LAUNCH_FAILED"}
+
+
+@mcp.tool()
+async def debug_list_launch_configs(
+    project_root: str | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """List the configurations in a project's .vscode/launch.json.
+
+    Comments and trailing commas are accepted. Each entry says which adapter
+    it maps to and whether debug_launch(config_name=...) can start it; if
+    not (unknown type, attach request, unresolvable ${...} variables) the
+    reason is given. ignored_keys lists settings that have no effect here.
+
+    Args:
+        project_root: Project to read (default: the session's project root)
+        session_id: Session ID (optional when only one session exists)
+    """
+    try:
+        if project_root is None:
+            session = await _get_manager().resolve_session(session_id)
+            root = session.project_root
+        else:
+            root = Path(project_root).expanduser().resolve()
+        configurations = launch_json.read_launch_json(root)
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except LaunchConfigError as e:
+        return {"error": e.message, "code": e.code, **e.details}
+
+    return {
+        "path": str(root / launch_json.LAUNCH_JSON),
+        "configurations": [launch_json.describe_configuration(c, root) for c in configurations],
+    }
+
+
+@mcp.tool()
+@_recorded
+async def debug_test(
+    project_root: str,
+    framework: str,
+    test_id: str,
+    breakpoints: dict[str, list[int]] | None = None,
+    args: list[str] | None = None,
+    env: dict[str, str | None] | None = None,
+    stop_on_entry: bool = False,
+    wait_for_stop_seconds: float | None = 30.0,
+    name: str | None = None,
+) -> dict[str, Any]:
+    """Create a session and debug a single test by name.
+
+    test_id uses the runner's own syntax: a pytest node id
+    ("tests/test_api.py::test_login"), a unittest dotted name
+    ("tests.test_api.TestLogin.test_expired"), or for go "TestName",
+    "./pkg/store::TestPut" or "TestPut/subtest". Go tests run in delve's
+    test mode, which builds the package's test binary.
+
+    The project's saved breakpoints and those given here are set before the
+    test starts. If the runner exits without finding the test, the result is
+    code TEST_NOT_FOUND with the runner's message, rather than an exit.
+    A launch that fails ends the session it created.
+
+    Args:
+        project_root: Project root path
+        framework: "pytest", "unittest" or "go"
+        test_id: Test to run (see above)
+        breakpoints: {file path: [lines]}; relative paths are taken from
+            the project root
+        args: Extra runner arguments (e.g. ["-s"] or ["-test.v"])
+        env: Variables merged over the inherited environment; null unsets one
+        stop_on_entry: Stop at the runner's first line
+        wait_for_stop_seconds: Block until the first stop or exit, up to this
+            long (default 30); null returns as soon as the test is launched
+        name: Session name (optional)
+    """
+    error = _check_wait(wait_for_stop_seconds)
+    if error:
+        return error
+    if framework not in test_runner.FRAMEWORKS:
+        return {
+            "error": f"Unsupported test framework '{framework}'",
+            "code": "INVALID_CONFIG",
+            "supported": sorted(test_runner.FRAMEWORKS),
+        }
+
+    manager = _get_manager()
+    try:
+        config = SessionConfig(
+            project_root=project_root,
+            language="go" if framework == "go" else "python",
+            name=name,
+        )
+        session = await manager.create_session(config)
+    except ValueError as e:
+        return {"error": str(e), "code": "INVALID_CONFIG"}
+    except SessionLimitError as e:
+        return {"error": str(e), "code": "SESSION_LIMIT"}
+    except DAPError as e:
+        return {"error": e.message, "code": e.code}
+
+    failure: dict[str, Any] | None = None
+    try:
+        for file_path, lines in (breakpoints or {}).items():
+            path = Path(file_path).expanduser()
+            if not path.is_absolute():
+                path = session.project_root / path
+            await session.set_breakpoints(
+                str(path.resolve()), [SourceBreakpoint(line=line) for line in lines]
+            )
+        stops_before = session.stop_count
+        await session.launch_test(framework, test_id, args, env, stop_on_entry)
+    except LaunchConfigError as e:
+        failure = {"error": e.message, "code": e.code, **e.details}
+    except ProgramExitedError as e:
+        # A runner that didn't find the test is reported as such below
+        if session.test_not_found is None:
+            failure = {"error": e.message, "code": e.code, **e.details}
+    except Exception as e:
+        failure = {"error": str(e), "code": "LAUNCH_FAILED"}
+    if failure is not None:
+        # The session was created for this test only
+        with suppress(SessionNotFoundError):
+            await manager.terminate_session(session.id)
+        return {**failure, "session_id": session.id}
+
+    result: dict[str, Any] = {
+        "status": "launched",
+        "session_id": session.id,
+        "framework": framework,
+        "test_id": test_id,
+        "adapter": session.adapter_name,
+        "state": session.state.value,
+    }
+    if wait_for_stop_seconds is not None:
+        result.update(await session.wait_for_stop(stops_before, wait_for_stop_seconds))
+    if session.test_not_found is not None:
+        result.pop("test_not_found", None)
+        return {
+            **result,
+            "error": f"Test not found: {test_id}",
+            "code": "TEST_NOT_FOUND",
+            "runner_message": session.test_not_found,
+        }
+    return result
+
+
+@mcp.tool()
+@_recorded
+async def debug_attach(
+    port: int | None = None,
+    host: str = "localhost",
+    process_id: int | None = None,
+    path_mappings: list[dict[str, str]] | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Attach to an already-running process instead of launching one.
+
+    Either connect to a debug server listening on host:port (debugpy.listen,
+    dlv --headless) or give a local process_id to attach to. The target keeps
+    running after the session ends unless terminated with terminate_debuggee.
+
+    Args:
+        port: Port of a listening debug server
+        host: Host of the debug server (default localhost)
+        process_id: Local PID to attach to (instead of host/port)
+        path_mappings: [{"local_root", "remote_root"}] pairs for a target in a
+            container or on another host
+        session_id: Session ID (optional when only one session exists)
+    """
+    if port is None and process_id is None:
+        return {"error": "Either port or process_id must be specified", "code": "INVALID_CONFIG"}
+    try:
+        mappings = _parse_path_mappings(path_mappings)
+    except ValueError as e:
+        return {"error": str(e), "code": "INVALID_CONFIG"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+
+        config = AttachConfig(host=host, process_id=process_id, path_mappings=mappings)
+        if port is not None:
+            config.port = port
+        await session.attach(config)
+
+        return {
+            "status": "attached",
+            "session_id": session.id,
+            "state": session.state.value,
+            "mode": "process" if process_id else "connect",
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except Exception as e:
+        return {"error": str(e), "code": "ATTACH_FAILED"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_send_stdin(
+    data: str,
+    newline: bool = True,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Send input to the program's stdin (e.g. answers to input() prompts).
+
+    Needs a program launched with stdin_mode "pipe" by an adapter that lets us
+    start it (currently Python).
+
+    Args:
+        data: Text to write
+        newline: Append a trailing newline (default True)
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        payload = (data + "\n" if newline else data).encode("utf-8")
+        written = await session.send_stdin(payload)
+        return {"session_id": session.id, "bytes_written": written}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except StdinUnavailableError as e:
+        return {"error": e.message, "code": "STDIN_UNAVAILABLE"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_continue(
+    thread_id: int | None = None,
+    reverse: bool = False,
+    wait_for_stop_seconds: float | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Continue until next breakpoint or end.
+
+    Args:
+        thread_id: Thread ID (default: current)
+        reverse: Run backwards to the previous breakpoint (needs reverse execution)
+        wait_for_stop_seconds: Block until the next stop or exit, up to this
+            long; returns stop details or status "still_running"
+        session_id: Session ID (optional when only one session exists)
+    """
+    error = _check_wait(wait_for_stop_seconds)
+    if error:
+        return error
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        stops_before = session.stop_count
+        if reverse:
+            await session.reverse_continue(thread_id)
+        else:
+            await session.continue_(thread_id)
+        if wait_for_stop_seconds is not None:
+            stop = await session.wait_for_stop(stops_before, wait_for_stop_seconds)
+            return {**stop, "reverse": reverse}
+        return {"status": "continued", "state": session.state.value, "reverse": reverse}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_run_to_line(
+    file_path: str,
+    line: int,
+    thread_id: int | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Continue until a line is reached, using a temporary breakpoint.
+
+    The temporary breakpoint is removed at the next stop for any reason
+    (including exit) and never shows up in debug_get_breakpoints. Existing
+    breakpoints on the same line are kept. Fails without resuming if the
+    line can't hold a breakpoint.
+
+    Args:
+        file_path: Source file path
+        line: Line number to run to
+        thread_id: Thread ID (default: current)
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        bp = await session.run_to_line(file_path, line, thread_id)
+        return {
+            "status": "continued",
+            "state": session.state.value,
+            "file": file_path,
+            "requested_line": line,
+            "line": bp.line if bp.line is not None else line,
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except UnverifiedBreakpointError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_continue_until(
+    expression: str,
+    probes: dict[str, list[int]] | None = None,
+    max_iterations: int = 1000,
+    timeout_seconds: float = 60.0,
+    thread_id: int | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Continue until a boolean expression is true, e.g. "len(queue) > 100".
+
+    At every stop the expression is evaluated in the top frame; while it
+    isn't true the program is continued automatically. Returns the stop
+    where it became true with condition_met, and auto_continues (how many
+    times execution was resumed). Stops on an exception end the run as is.
+
+    Args:
+        expression: Boolean expression in the program's language
+        probes: {file: [lines]} temporary breakpoints to check the expression
+            at (relative paths are resolved against the project root);
+            default: stop only at the existing breakpoints
+        max_iterations: Most automatic continues before giving up (1-100000,
+            default 1000); the result then has status "max_iterations"
+        timeout_seconds: Wall-clock limit (up to 300, default 60); the
+            result then has status "timeout" (paused) or "still_running"
+        thread_id: Thread to continue (default: current)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if not 1 <= max_iterations <= 100_000:
+        return {"error": "max_iterations must be between 1 and 100000", "code": "INVALID_RANGE"}
+    if not 0 < timeout_seconds <= 300:
+        return {"error": "timeout_seconds must be between 0 and 300", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        resolved: dict[str, list[int]] | None = None
+        if probes is not None:
+            resolved = {}
+            for file_path, lines in probes.items():
+                path = Path(file_path).expanduser()
+                if not path.is_absolute():
+                    path = session.project_root / path
+                resolved[str(path.resolve())] = lines
+        result = await session.continue_until(
+            expression, resolved, max_iterations, timeout_seconds, thread_id
+        )
+        return {"expression": expression, **result}
+    except NoBreakpointsError as e:
+        return {"error": e.message, "code": e.code}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_step(
+    mode: str,
+    thread_id: int | None = None,
+    wait_for_stop_seconds: float | None = None,
+    sticky_goroutine: bool | None = None,
+    granularity: str = "line",
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Step execution: over (next line), into (enter function), out (exit function).
+
+    mode="back" steps backwards when the session reports reverse_execution
+    in its capabilities (e.g. Go under rr).
+
+    A step can stop on a different thread, e.g. a goroutine blocking on a
+    channel. Sticky steps are re-issued on the stepped thread; otherwise the
+    stop reports step_thread_id (the thread that was stepped). A step landing
+    in code excluded by debug_set_step_filters is stepped out of
+    automatically; the stop then reports skipped_frames.
+
+    Args:
+        mode: "over", "into", "out", or "back"
+        thread_id: Thread ID (default: current)
+        wait_for_stop_seconds: Block until the step completes or the program
+            exits, up to this long; returns stop details or status "still_running"
+        sticky_goroutine: Keep stepping the same thread (default: on for Go)
+        granularity: "line", or "instruction" to step one machine instruction
+            (compiled targets; see debug_disassemble)
+        session_id: Session ID (optional when only one session exists)
+    """
+    error = _check_wait(wait_for_stop_seconds)
+    if error:
+        return error
+    if granularity not in ("line", "instruction"):
+        return {
+            "error": f"Invalid granularity '{granularity}'; use 'line' or 'instruction'",
+            "code": "INVALID_ARGS",
+        }
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        stops_before = session.stop_count
+
+        if mode == "over":
+            await session.step_over(thread_id, sticky_goroutine, granularity)
+        elif mode == "into":
+            await session.step_into(thread_id, sticky_goroutine, granularity)
+        elif mode == "out":
+            await session.step_out(thread_id, sticky_goroutine, granularity)
+        elif mode == "back":
+            if granularity != "line":
+                return {
+                    "error": "Reverse steps are line steps only",
+                    "code": "INVALID_ARGS",
+                }
+            await session.step_back(thread_id)
+        else:
+            return {
+                "error": f"Invalid mode: {mode}. Use 'over', 'into', 'out', or 'back'",
+                "code": "INVALID_MODE",
+            }
+
+        if wait_for_stop_seconds is not None:
+            stop = await session.wait_for_stop(stops_before, wait_for_stop_seconds)
+            return {**stop, "mode": mode}
+        return {"status": "stepping", "mode": mode}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        if e.details["capability"] == "supportsSteppingGranularity":
+            return {"error": e.message, "code": "NOT_SUPPORTED", "hint": _NATIVE_CODE_HINT}
+        return {"error": e.message, "code": "NOT_SUPPORTED"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_set_step_filters(
+    patterns: list[str] | None = None,
+    skip_stdlib: bool = False,
+    skip_site_packages: bool = False,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Set which code steps pass through instead of stopping in.
+
+    Replaces the current rules; call with no arguments to clear them. A step
+    that lands in filtered code is followed by automatic step-outs until
+    execution is back in unfiltered code (giving up after a fixed number).
+    Works with every adapter and takes effect on the next step. Breakpoints
+    in filtered code still stop.
+
+    Args:
+        patterns: Globs on source paths, absolute or relative to the project
+            root (e.g. "*/vendor/*", "tests/conftest.py")
+        skip_stdlib: Skip the language's standard library
+        skip_site_packages: Skip installed packages (site-packages,
+            node_modules, the Go module cache)
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        rules = session.set_step_filters(patterns, skip_stdlib, skip_site_packages)
+        return {"status": "ok", "step_filters": rules}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_pause(
+    thread_id: int | None = None,
+    wait_for_stop_seconds: float | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Pause a running program (e.g. one stuck in a loop or deadlock).
+
+    The session stays alive; inspect it, then continue or step as usual.
+
+    Args:
+        thread_id: Thread ID (default: current, or the first thread)
+        wait_for_stop_seconds: Block until the program has paused, up to this
+            long; returns stop details or status "still_running"
+        session_id: Session ID (optional when only one session exists)
+    """
+    error = _check_wait(wait_for_stop_seconds)
+    if error:
+        return error
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        stops_before = session.stop_count
+        await session.pause(thread_id)
+        if wait_for_stop_seconds is not None:
+            return await session.wait_for_stop(stops_before, wait_for_stop_seconds)
+        return {"status": "pausing"}
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+
+
+# =============================================================================
+# Inspection Tools
+# =============================================================================
+
+
+@mcp.tool()
+@_recorded
+async def debug_list_threads(
+    limit: int = 100,
+    offset: int = 0,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """List threads (goroutines for Go), one page at a time.
 
+    The thread that reported the last stop is marked with "stopped": true;
+    pass any thread's id to debug_get_stacktrace or the stepping tools.
 
-# =============================================================================
-# Inspection Tools
-# =============================================================================
+    Args:
+        limit: Max threads to return (default 100)
+        offset: Number of threads to skip
+        session_id: Session ID (optional when only one session exists)
+    """
+    if limit < 1 or offset < 0:
+        return {"error": "limit must be >= 1 and offset >= 0", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        threads = await session.get_threads()
+        page = threads[offset : offset + limit]
+        return {
+            "threads": [
+                {"id": t.id, "name": t.name, "stopped": t.id == session.current_thread_id}
+                for t in page
+            ],
+            "stopped_thread_id": session.current_thread_id,
+            "offset": offset,
+            "count": len(page),
+            "total": len(threads),
+            "has_more": offset + len(page) < len(threads),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_dump_goroutines(
+    max_frames: int = 10,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Dump every goroutine of a Go program, grouping identical stacks.
+
+    For hangs: like a runtime.Stack dump, but goroutines with the same top
+    frames and wait reason ("chan receive", "sync.Mutex.Lock", "select",
+    ...) collapse into one group with a count. Groups waiting on channels
+    or locks are flagged potential_deadlock. A running program is paused
+    first and left paused. Delve sessions only.
+
+    Args:
+        max_frames: Frames kept and compared per goroutine, 1-100 (default 10)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if not 1 <= max_frames <= 100:
+        return {"error": "max_frames must be between 1 and 100", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        return await session.dump_goroutines(max_frames)
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED"}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_list_loaded_sources(
+    filter: str | None = None,
+    limit: int = 200,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """List source files the program has loaded, e.g. to find why a breakpoint won't bind.
+
+    Shows which copy of a file is actually running (your tree or a stale
+    installed one). Adapters without the LoadedSources request report only
+    what their loadedSource events announced ("complete": false).
+
+    Args:
+        filter: Substring of the file name or path to keep
+        limit: Max sources to return (default 200)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if limit < 1:
+        return {"error": "limit must be >= 1", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        sources = await session.list_loaded_sources(filter)
+        return {
+            "sources": sources[:limit],
+            "total": len(sources),
+            "has_more": len(sources) > limit,
+            "complete": session.has_capability("supportsLoadedSourcesRequest"),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_list_modules(
+    filter: str | None = None,
+    limit: int = 200,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """List loaded modules (Python modules, shared libraries) with path and version.
+
+    Adapters without the Modules request report only what their module
+    events announced ("complete": false).
+
+    Args:
+        filter: Substring of the module name or path to keep
+        limit: Max modules to return (default 200)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if limit < 1:
+        return {"error": "limit must be >= 1", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        modules = await session.list_modules(filter)
+        return {
+            "modules": modules[:limit],
+            "total": len(modules),
+            "has_more": len(modules) > limit,
+            "complete": session.has_capability("supportsModulesRequest"),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_get_source(
+    source_reference: int,
+    start_line: int | None = None,
+    end_line: int | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get the code of a source without a file (generated, frozen or remote code).
+
+    Use it for frames and loaded sources whose source_kind is "virtual";
+    content is fetched from the adapter once and cached for the session.
+
+    Args:
+        source_reference: source_reference from debug_get_stacktrace or
+            debug_list_loaded_sources
+        start_line: First line to return (1-based, default 1)
+        end_line: Last line to return (default: the last line)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if source_reference < 1:
+        return {"error": "source_reference must be >= 1", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        source = await session.get_source(source_reference)
+        lines = source["content"].splitlines()
+        start = max(start_line or 1, 1)
+        end = min(end_line or len(lines), len(lines))
+        return {
+            "source_reference": source_reference,
+            "mime_type": source["mime_type"],
+            "line_count": source["line_count"],
+            "start_line": start,
+            "end_line": end,
+            "content": "\n".join(lines[start - 1 : end]),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except DAPError as e:
+        return {"error": e.message, "code": "SOURCE_UNAVAILABLE"}
 
 
 @mcp.tool()
+@_recorded
 async def debug_get_stacktrace(
-    session_id: str,
     thread_id: int | None = None,
     max_frames: int = 20,
     format: str = "tui",
+    include_source_context: bool = False,
+    context_lines: int = 2,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get call stack frames for the stopped thread or any other thread.
+
+    Each frame's source_kind says how to read its code: "file" (its path),
+    "virtual" (generated or remote code; pass source_reference to
+    debug_get_source) or "unavailable".
+
+    With include_source_context, each frame also gets its line ("source")
+    and the lines around it ("context", "line_numbers"), saving a file read
+    per frame. Frames whose source can't be read get "source_unavailable"
+    instead; after a total size cap, later frames get none and
+    "source_truncated" is set.
+
+    Args:
+        thread_id: Thread ID from debug_list_threads (default: the stopped thread)
+        max_frames: Max frames (default 20)
+        format: "json" or "tui"
+        include_source_context: Attach source lines to each frame
+        context_lines: Lines before/after each frame's line, 0-20 (default 2)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if not 0 <= context_lines <= 20:
+        return {"error": "context_lines must be between 0 and 20", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        frames = await session.get_stack_trace(thread_id, levels=max_frames)
+        frame_dicts = [
+            {
+                "id": f.id,
+                "name": f.name,
+                "file": f.source.path if f.source else None,
+                "line": f.line,
+                "column": f.column,
+                "source_name": f.source.name if f.source else None,
+                "source_kind": session.source_kind(f),
+                "source_reference": f.source.source_reference if f.source else None,
+            }
+            for f in frames
+        ]
+
+        result: dict[str, Any] = {
+            "thread_id": thread_id or session.current_thread_id,
+            "frames": frame_dicts,
+            "total": len(frames),
+            "format": format,
+        }
+        if include_source_context:
+            contexts, capped = await session.stack_source_contexts(frames, context_lines)
+            for frame_dict, context in zip(frame_dicts, contexts):
+                frame_dict.update(context)
+            result["source_truncated"] = capped
+
+        if format == "tui":
+            formatter = _get_formatter()
+            result["formatted"] = formatter.format_stack_trace(frame_dicts)
+            result["call_chain"] = formatter.format_call_chain(frame_dicts)
+
+        return result
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_get_exception_info(
+    max_depth: int = 10,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get the exception execution stopped on, including its chained causes.
+
+    "exceptions" lists {type, message, frames} innermost (the root cause)
+    first. Python chains follow __cause__ and __context__, with "relation"
+    saying which; Go reports the panic value and the panicking goroutine's
+    stack.
+
+    Args:
+        max_depth: Most chained exceptions to report (1-50, default 10); a
+            longer chain keeps the root cause and drops exceptions above it
+        session_id: Session ID (optional when only one session exists)
+
+    Returns code NOT_STOPPED_ON_EXCEPTION unless the last stop was an exception.
+    """
+    if not 1 <= max_depth <= 50:
+        return {"error": "max_depth must be between 1 and 50", "code": "INVALID_RANGE"}
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        return await session.get_exception_chain(max_depth)
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except NotStoppedOnExceptionError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_recorded
+async def debug_get_scopes(
+    frame_id: int,
+    format: str = "tui",
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get every scope of a frame: locals, globals, closures, registers (Go), ...
+
+    Pass a scope's variables_reference to debug_get_variables. Expensive
+    scopes (e.g. delve registers) are slow to fetch and are never expanded
+    automatically.
+
+    Args:
+        frame_id: Frame ID from stacktrace
+        format: "json" or "tui"
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        scopes = await session.get_scopes(frame_id)
+        scope_dicts = [
+            {
+                "name": s.name,
+                "presentation_hint": s.presentation_hint,
+                "variables_reference": s.variables_reference,
+                "named_variables": s.named_variables,
+                "indexed_variables": s.indexed_variables,
+                "expensive": s.expensive,
+            }
+            for s in scopes
+        ]
+
+        result: dict[str, Any] = {
+            "scopes": scope_dicts,
+            "format": format,
+        }
+
+        if format == "tui":
+            formatter = _get_formatter()
+            result["formatted"] = formatter.format_scopes(scope_dicts)
+
+        return result
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+
+
+@mcp.tool()
+@_recorded
+async def debug_get_variables(
+    variables_reference: int | None = None,
+    frame_id: int | None = None,
+    scope: str | None = None,
+    start: int = 0,
+    count: int = 100,
+    filter: str | None = None,
+    format: str = "tui",
+    max_length: int | None = None,
+    render: str = "raw",
+    max_count: int | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Get variables from a scope or compound variable, one page at a time.
+
+    Large containers report indexed_variables/named_variables; page through
+    them with start/count. References expire when execution resumes.
+    Passing frame_id instead of a reference lists one of that frame's scopes
+    (its locals unless scope names another), so any frame can be explored
+    without debug_get_scopes.
+
+    Args:
+        variables_reference: Ref from any scope or nested variable
+        frame_id: Frame ID from debug_get_stacktrace (instead of variables_reference)
+        scope: With frame_id, the scope to list by name, e.g. "globals",
+            "registers" (default: locals)
+        start: Index of the first child to return (default 0)
+        count: Page size (default 100)
+        filter: "indexed" or "named" to fetch only one kind of child
+        format: "json" or "tui"
+        max_length: Truncate each value to this many characters (default 1000);
+            truncated values are flagged, fetch them with debug_get_full_value
+        render: "raw" (the adapter's repr), "summary" (per compound variable:
+            length and first items of containers, shape and dtypes of
+            DataFrames/arrays, dataclass fields) or "json" (the value as
+            JSON, cut off at a size cap). Values that can't be rendered keep
+            their raw value and get "render_error". Python only for now.
+        max_count: Deprecated name of count, still accepted
+        session_id: Session ID (optional when only one session exists)
+    """
+    if max_count is not None:
+        count = max_count
+    if filter not in (None, "indexed", "named"):
+        return {"error": "filter must be 'indexed' or 'named'", "code": "INVALID_FILTER"}
+    if start < 0 or count < 1:
+        return {"error": "start must be >= 0 and count >= 1", "code": "INVALID_RANGE"}
+    if max_length is not None and max_length < 1:
+        return {"error": "max_length must be >= 1", "code": "INVALID_RANGE"}
+    limit = max_length or settings.value_max_length
+    if variables_reference is None and frame_id is None:
+        return {"error": "Provide variables_reference or frame_id", "code": "INVALID_ARGS"}
+    if render not in RENDER_MODES:
+        return _invalid_render(render)
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        if render != "raw" and render not in session.render_modes:
+            return _unsupported_render(session, render)
+        if variables_reference is None:
+            assert frame_id is not None
+            scopes = await session.get_scopes(frame_id)
+            if not scopes:
+                return {"error": f"Frame {frame_id} has no scopes", "code": "NO_SCOPES"}
+            selected = session.select_scope(scopes, scope)
+            if selected is None:
+                return {
+                    "error": f"Frame {frame_id} has no scope '{scope}'",
+                    "code": "NO_SCOPES",
+                    "available": [s.name for s in scopes],
+                }
+            variables_reference = selected.variables_reference
+        variables = await session.get_variables(
+            variables_reference, start=start, count=count, filter=filter
+        )
+        var_dicts = []
+        for v in variables:
+            value, truncated = _clip_value(v.value, limit)
+            var_dict: dict[str, Any] = {
+                "name": v.name,
+                "value": value,
+                "type": v.type,
+                "variables_reference": v.variables_reference,
+                "has_children": v.variables_reference > 0,
+                "named_variables": v.named_variables,
+                "indexed_variables": v.indexed_variables,
+            }
+            if truncated:
+                var_dict["truncated"] = True
+                var_dict["length"] = len(v.value)
+            if v.memory_reference:
+                var_dict["memory_reference"] = v.memory_reference
+            var_dicts.append(var_dict)
+        if render != "raw":
+            rendered = await session.render_variables(variables_reference, variables, render)
+            for var_dict in var_dicts:
+                var_dict.update(rendered.get(var_dict["name"], {}))
+
+        counts = session.variable_counts(variables_reference)
+        if filter == "indexed":
+            total = counts["indexed"]
+        elif filter == "named":
+            total = counts["named"]
+        elif counts["indexed"] is not None:
+            total = counts["indexed"] + (counts["named"] or 0)
+        else:
+            total = None
+        if total is not None:
+            has_more = start + len(variables) < total
+        else:
+            has_more = len(variables) >= count
+
+        result: dict[str, Any] = {
+            "variables": var_dicts,
+            "variables_reference": variables_reference,
+            "start": start,
+            "count": len(variables),
+            "indexed_variables": counts["indexed"],
+            "named_variables": counts["named"],
+            "total": total,
+            "has_more": has_more,
+            "format": format,
+            "render": render,
+        }
+
+        if format == "tui":
+            formatter = _get_formatter()
+            result["formatted"] = formatter.format_variables(var_dicts)
+
+        return result
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except VariableNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_REFERENCE",
+            "hint": "Execution resumed since this reference was fetched; "
+            "call debug_get_scopes again",
+        }
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+
+
+@mcp.tool()
+@_recorded
+async def debug_expand_variable(
+    variables_reference: int,
+    depth: int = 2,
+    max_children_per_level: int = 50,
+    max_nodes: int | None = None,
+    max_length: int | None = None,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Expand a compound variable several levels deep in one call.
+
+    Children are fetched breadth-first and returned as a nested tree. A
+    container seen before (a reference cycle) has "cycle" set to its
+    reference instead of children. Where the expansion stopped early the
+    node is marked "truncated" and listed in "truncated" with its path and
+    reason (depth, max_children or max_nodes); continue from its
+    variables_reference with this tool or debug_get_variables.
+
+    Args:
+        variables_reference: Ref from a scope or nested variable
+        depth: Levels of children to expand (1-5, default 2)
+        max_children_per_level: Children fetched per container (default 50)
+        max_nodes: Cap on variables returned in total (default and maximum
+            from the server's expand_max_nodes setting, 500 unless changed)
+        max_length: Truncate each value to this many characters (default 1000)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if not 1 <= depth <= _EXPAND_MAX_DEPTH:
+        return {"error": f"depth must be 1-{_EXPAND_MAX_DEPTH}", "code": "INVALID_RANGE"}
+    if max_children_per_level < 1:
+        return {"error": "max_children_per_level must be >= 1", "code": "INVALID_RANGE"}
+    if max_nodes is not None and max_nodes < 1:
+        return {"error": "max_nodes must be >= 1", "code": "INVALID_RANGE"}
+    if max_length is not None and max_length < 1:
+        return {"error": "max_length must be >= 1", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        return await session.expand_variable(
+            variables_reference,
+            depth=depth,
+            max_children=max_children_per_level,
+            max_nodes=min(max_nodes or settings.expand_max_nodes, settings.expand_max_nodes),
+            value_limit=max_length,
+        )
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except VariableNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_REFERENCE",
+            "hint": "Execution resumed since this reference was fetched; "
+            "call debug_get_scopes again",
+        }
+
+
+@mcp.tool()
+@_recorded
+async def debug_evaluate(
+    expression: str,
+    frame_id: int | None = None,
+    context: str = "repl",
+    max_length: int | None = None,
+    render: str = "raw",
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Evaluate an expression, optionally in a frame further up the stack.
+
+    Frame IDs come from debug_get_stacktrace and expire when execution
+    resumes.
+
+    Args:
+        expression: Expression to evaluate
+        frame_id: Frame ID from debug_get_stacktrace (default: topmost)
+        context: "repl" (statements allowed), "watch" (avoids side effects
+            where the adapter can) or "hover"
+        max_length: Truncate the result to this many characters (default 1000);
+            use debug_get_full_value for the complete value
+        render: "raw", "summary" or "json", as for debug_get_variables. The
+            expression is evaluated again to render it, so prefer one
+            without side effects
+        session_id: Session ID (optional when only one session exists)
+    """
+    if context not in ("repl", "watch", "hover"):
+        return {
+            "error": f"Invalid context: {context}. Use 'repl', 'watch', or 'hover'",
+            "code": "INVALID_CONTEXT",
+        }
+    if max_length is not None and max_length < 1:
+        return {"error": "max_length must be >= 1", "code": "INVALID_RANGE"}
+    if render not in RENDER_MODES:
+        return _invalid_render(render)
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        if render != "raw" and render not in session.render_modes:
+            return _unsupported_render(session, render)
+        result = await session.evaluate(expression, frame_id, context)
+        full = str(result.get("result", ""))
+        value, truncated = _clip_value(full, max_length or settings.value_max_length)
+        response: dict[str, Any] = {
+            "expression": expression,
+            "frame_id": frame_id,
+            "context": context,
+            "result": value,
+            "type": result.get("type"),
+            "variables_reference": result.get("variablesReference", 0),
+        }
+        if truncated:
+            response["truncated"] = True
+            response["length"] = len(full)
+        if result.get("memoryReference"):
+            response["memory_reference"] = result["memoryReference"]
+        if render != "raw":
+            response.update(await session.render_value(expression, frame_id, render))
+        return response
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+    except Exception as e:
+        return {"error": str(e), "code": "EVAL_ERROR"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_inspect_symbol(
+    file: str,
+    line: int,
+    column: int,
+    max_length: int | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Get call stack frames.
+    """Show the value of the symbol at a source position, like an editor hover.
+
+    Saves building an expression by hand when a file/line/column is known
+    from a stack trace or source read. The identifier there, with its dotted
+    prefix ("order.items" on "items"), is evaluated in the innermost frame in
+    that file, or the top frame if none is; frame_matched says which.
 
     Args:
-        session_id: Session ID
-        thread_id: Thread ID (default: current)
-        max_frames: Max frames (default 20)
-        format: "json" or "tui"
+        file: Source file path
+        line: 1-based line
+        column: 1-based column, counted in characters
+        max_length: Truncate the result to this many characters (default 1000)
+        session_id: Session ID (optional when only one session exists)
     """
+    if line < 1 or column < 1:
+        return {"error": "line and column must be >= 1", "code": "INVALID_RANGE"}
+    if max_length is not None and max_length < 1:
+        return {"error": "max_length must be >= 1", "code": "INVALID_RANGE"}
+
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        frames = await session.get_stack_trace(thread_id, levels=max_frames)
-        frame_dicts = [
-            {
-                "id": f.id,
-                "name": f.name,
-                "file": f.source.path if f.source else None,
-                "line": f.line,
-                "column": f.column,
-            }
-            for f in frames
-        ]
-
-        result: dict[str, Any] = {
-            "frames": frame_dicts,
-            "total": len(frames),
-            "format": format,
-        }
-
-        if format == "tui":
-            formatter = _get_formatter()
-            result["formatted"] = formatter.format_stack_trace(frame_dicts)
-            result["call_chain"] = formatter.format_call_chain(frame_dicts)
-
+        session = await manager.resolve_session(session_id)
+        result = await session.inspect_symbol(file, line, column)
+        full = str(result["result"])
+        value, truncated = _clip_value(full, max_length or settings.value_max_length)
+        result["result"] = value
+        if truncated:
+            result["truncated"] = True
+            result["length"] = len(full)
         return result
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except NoSymbolAtPositionError as e:
+        return {"error": e.message, "code": e.code}
+    except Exception as e:
+        return {"error": str(e), "code": "EVAL_ERROR"}
 
 
 @mcp.tool()
-async def debug_get_scopes(
-    session_id: str,
-    frame_id: int,
-    format: str = "tui",
+@_recorded
+async def debug_get_completions(
+    text: str,
+    column: int | None = None,
+    frame_id: int | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Get scopes (locals, globals) for a frame.
+    """Complete a partial expression in the stopped frame, e.g. "user." -> attributes.
+
+    Lists what actually exists on live objects instead of guessing names
+    for debug_evaluate. Requires the program to be paused.
 
     Args:
-        session_id: Session ID
-        frame_id: Frame ID from stacktrace
-        format: "json" or "tui"
+        text: Partial expression to complete
+        column: 1-based cursor position in text (default: end of text)
+        frame_id: Frame ID from debug_get_stacktrace (default: topmost)
+        session_id: Session ID (optional when only one session exists)
     """
+    if column is not None and not 1 <= column <= len(text) + 1:
+        return {
+            "error": f"column must be between 1 and {len(text) + 1}",
+            "code": "INVALID_RANGE",
+        }
+
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        scopes = await session.get_scopes(frame_id)
-        scope_dicts = [
-            {
-                "name": s.name,
-                "variables_reference": s.variables_reference,
-                "expensive": s.expensive,
+        session = await manager.resolve_session(session_id)
+        if not session.has_capability("supportsCompletionsRequest"):
+            return {
+                "text": text,
+                "completions": [],
+                "note": "The debug adapter does not support completions; "
+                "list attributes with debug_get_variables instead",
             }
-            for s in scopes
-        ]
-
-        result: dict[str, Any] = {
-            "scopes": scope_dicts,
-            "format": format,
+        items = await session.get_completions(text, column, frame_id)
+        return {
+            "text": text,
+            "completions": [
+                {
+                    "label": item.get("label"),
+                    "text": item.get("text"),
+                    "type": item.get("type"),
+                    "detail": item.get("detail"),
+                    "sort_text": item.get("sortText"),
+                    "start": item.get("start"),
+                    "length": item.get("length"),
+                }
+                for item in items
+            ],
         }
-
-        if format == "tui":
-            formatter = _get_formatter()
-            result["formatted"] = formatter.format_scopes(scope_dicts)
-
-        return result
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
 
 
 @mcp.tool()
-async def debug_get_variables(
-    session_id: str,
-    variables_reference: int,
-    max_count: int = 100,
-    format: str = "tui",
+@_recorded
+async def debug_get_full_value(
+    expression: str | None = None,
+    variables_reference: int | None = None,
+    name: str | None = None,
+    frame_id: int | None = None,
+    continuation_token: str | None = None,
+    chunk_size: int | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Get variables from a scope or compound variable.
+    """Get a value's complete text in chunks, bypassing display truncation.
+
+    Name the value by expression, or by variables_reference + name as listed
+    by debug_get_variables. While continuation_token is returned, pass it back
+    (alone) for the next chunk. Tokens expire when execution resumes.
 
     Args:
-        session_id: Session ID
-        variables_reference: Ref from scopes or nested variable
-        max_count: Max variables (default 100)
-        format: "json" or "tui"
+        expression: Expression whose value to fetch
+        variables_reference: Container the variable was listed under
+        name: Variable name within variables_reference
+        frame_id: Frame for the expression (default: the variable's frame, or topmost)
+        continuation_token: Token from the previous chunk
+        chunk_size: Characters per chunk (default 32768)
+        session_id: Session ID (optional when only one session exists)
     """
+    if continuation_token is None and expression is None and (
+        variables_reference is None or name is None
+    ):
+        return {
+            "error": "Provide expression, variables_reference and name, or continuation_token",
+            "code": "INVALID_ARGS",
+        }
+    if chunk_size is not None and chunk_size < 1:
+        return {"error": "chunk_size must be >= 1", "code": "INVALID_RANGE"}
+    size = chunk_size or settings.full_value_chunk_chars
+
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        variables = await session.get_variables(variables_reference, count=max_count)
-        var_dicts = [
-            {
-                "name": v.name,
-                "value": v.value,
-                "type": v.type,
-                "variables_reference": v.variables_reference,
-                "has_children": v.variables_reference > 0,
-            }
-            for v in variables
-        ]
+        session = await manager.resolve_session(session_id)
+        if continuation_token is not None:
+            token, _, offset_text = continuation_token.partition(":")
+            if not offset_text.isdigit():
+                raise ContinuationTokenError(session.id, continuation_token)
+            offset = int(offset_text)
+            text = session.full_value_text(token)
+        else:
+            token, text = await session.get_full_value(
+                expression, variables_reference, name, frame_id
+            )
+            offset = 0
 
-        result: dict[str, Any] = {
-            "variables": var_dicts,
-            "format": format,
+        end = min(offset + size, len(text))
+        return {
+            "value": text[offset:end],
+            "offset": offset,
+            "length": len(text),
+            "complete": end >= len(text),
+            "continuation_token": f"{token}:{end}" if end < len(text) else None,
         }
-
-        if format == "tui":
-            formatter = _get_formatter()
-            result["formatted"] = formatter.format_variables(var_dicts)
-
-        return result
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except ContinuationTokenError as e:
+        return {"error": e.message, "code": e.code}
+    except VariableNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_REFERENCE",
+            "hint": "call debug_get_scopes again for current references",
+        }
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+    except Exception as e:
+        return {"error": str(e), "code": "EVAL_ERROR"}
 
 
 @mcp.tool()
-async def debug_evaluate(
-    session_id: str,
-    expression: str,
+@_recorded
+async def debug_set_variable(
+    value: str,
+    name: str | None = None,
+    variables_reference: int | None = None,
+    expression: str | None = None,
     frame_id: int | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
-    """Evaluate a Python expression.
+    """Change a variable's value while paused, then keep debugging.
+
+    Either give variables_reference + name (from debug_get_scopes or
+    debug_get_variables), or an assignable expression such as "obj.items[3]"
+    if the adapter supports it.
 
     Args:
-        session_id: Session ID
-        expression: Expression to evaluate
-        frame_id: Frame ID (default: topmost)
+        value: New value as a source literal, e.g. "42" or "'text'"
+        name: Variable name within variables_reference
+        variables_reference: Ref of the scope or variable containing name
+        expression: Assignable expression (instead of name/variables_reference)
+        frame_id: Frame for expression (default: topmost)
+        session_id: Session ID (optional when only one session exists)
     """
+    if expression is None and (name is None or variables_reference is None):
+        return {
+            "error": "Provide variables_reference and name, or expression",
+            "code": "INVALID_ARGS",
+        }
+    if expression is not None and name is not None:
+        return {"error": "Provide either name or expression, not both", "code": "INVALID_ARGS"}
+
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        result = await session.evaluate(expression, frame_id)
+        session = await manager.resolve_session(session_id)
+        if expression is not None:
+            body = await session.set_expression(expression, value, frame_id)
+        else:
+            assert name is not None and variables_reference is not None
+            body = await session.set_variable(variables_reference, name, value)
         return {
-            "expression": expression,
-            "result": result.get("result", ""),
-            "type": result.get("type"),
-            "variables_reference": result.get("variablesReference", 0),
+            "target": expression if expression is not None else name,
+            "value": body.get("value", ""),
+            "type": body.get("type"),
+            "variables_reference": body.get("variablesReference", 0),
         }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
-    except Exception as e:
-        return {"error": str(e), "code": "EVAL_ERROR"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED"}
+    except VariableNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_REFERENCE",
+            "hint": "Execution resumed since this reference was fetched; "
+            "call debug_get_scopes again",
+        }
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+    except DAPError as e:
+        # Adapter messages (e.g. type mismatches) are passed through unchanged
+        return {"error": e.message, "code": "SET_FAILED"}
 
 
 @mcp.tool()
+@_recorded
 async def debug_inspect_variable(
-    session_id: str,
     variable_name: str,
     frame_id: int | None = None,
     max_preview_rows: int = 5,
     include_statistics: bool = True,
     format: str = "tui",
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Smart inspect DataFrames, arrays, dicts, lists with type-aware metadata.
 
     Args:
-        session_id: Session ID
         variable_name: Variable to inspect
         frame_id: Frame ID (default: topmost)
         max_preview_rows: Preview limit (default 5, max 100)
         include_statistics: Include numeric stats
         format: "json" or "tui"
+        session_id: Session ID (optional when only one session exists)
 
     Returns: name, type, detected_type, structure, preview, statistics, summary, warnings
     """
@@ -638,7 +2768,7 @@ async def debug_inspect_variable(
 
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
 
         # Build options
         options = InspectionOptions(
@@ -667,6 +2797,8 @@ async def debug_inspect_variable(
 
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
     except InvalidSessionStateError as e:
         return {
             "error": str(e),
@@ -689,27 +2821,28 @@ async def debug_inspect_variable(
 
 
 @mcp.tool()
+@_recorded
 async def debug_get_call_chain(
-    session_id: str,
     thread_id: int | None = None,
     include_source_context: bool = True,
     context_lines: int = 2,
     format: str = "tui",
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Get call stack with source context showing path to current location.
 
     Args:
-        session_id: Session ID
         thread_id: Thread ID (default: current)
         include_source_context: Include surrounding lines
         context_lines: Lines before/after (default 2)
         format: "json" or "tui"
+        session_id: Session ID (optional when only one session exists)
 
     Returns: call_chain (frames with depth, function, file, line, source, context), total_frames
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
         result = await session.get_call_chain(
             thread_id=thread_id,
             include_source_context=include_source_context,
@@ -729,6 +2862,8 @@ async def debug_get_call_chain(
 
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
     except InvalidSessionStateError as e:
         return {
             "error": str(e),
@@ -740,67 +2875,323 @@ async def debug_get_call_chain(
         return {"error": str(e), "code": "CALL_CHAIN_ERROR"}
 
 
+@mcp.tool()
+@_recorded
+async def debug_get_stop_snapshot(
+    max_frames: int = 5,
+    context_lines: int = 5,
+    max_variables: int = 50,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Everything about the current stop in one call.
+
+    Returns the stop reason, top frames, the top frame's locals (one level;
+    expand with debug_get_variables), its other scopes (globals, registers;
+    references only), watch values and the source around the stopped line.
+    A section that can't be fetched is left empty and its reason is given
+    under "errors".
+
+    Args:
+        max_frames: Frames to include (default 5)
+        context_lines: Source lines before/after the stopped line (default 5)
+        max_variables: Locals to include (default 50)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if not 1 <= max_frames <= 100 or not 0 <= context_lines <= 50 or max_variables < 1:
+        return {
+            "error": "Need 1 <= max_frames <= 100, 0 <= context_lines <= 50, max_variables >= 1",
+            "code": "INVALID_ARGS",
+        }
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        return await session.get_stop_snapshot(max_frames, context_lines, max_variables)
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {
+            "error": str(e),
+            "code": "INVALID_STATE",
+            "hint": "Session must be stopped; use debug_continue with wait_for_stop_seconds",
+        }
+
+
+@mcp.tool()
+@_recorded
+async def debug_disassemble(
+    memory_reference: str | None = None,
+    frame_id: int | None = None,
+    instructions_before: int = 10,
+    instructions_after: int = 20,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Disassemble machine code around an address or a frame's current instruction.
+
+    For compiled targets (Go, Rust, C/C++). Source line markers
+    ({"type": "source"}) are interleaved with instructions when the adapter
+    maps them to lines; the instruction at the address has "current": true.
+    Step one instruction with debug_step(granularity="instruction").
+
+    Args:
+        memory_reference: Address to disassemble around (e.g. "0x4a2f10")
+        frame_id: Frame from debug_get_stacktrace to use instead
+            (default: the stopped thread's top frame)
+        instructions_before: Instructions before the address (default 10)
+        instructions_after: Instructions after the address (default 20)
+        session_id: Session ID (optional when only one session exists)
+    """
+    if not (0 <= instructions_before <= 500 and 0 <= instructions_after <= 500):
+        return {
+            "error": "instructions_before and instructions_after must be 0-500",
+            "code": "INVALID_RANGE",
+        }
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        reference, listing = await session.disassemble(
+            memory_reference, frame_id, instructions_before, instructions_after
+        )
+        return {
+            "memory_reference": reference,
+            "listing": listing,
+            "instruction_count": sum(1 for e in listing if e["type"] == "instruction"),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED", "hint": _NATIVE_CODE_HINT}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+
+
+def _memory_target_error(
+    memory_reference: str | None, expression: str | None
+) -> dict[str, Any] | None:
+    """Return an error response unless exactly one of the two is given."""
+    if (memory_reference is None) == (expression is None):
+        return {"error": "Provide memory_reference or expression", "code": "INVALID_ARGS"}
+    return None
+
+
+@mcp.tool()
+@_recorded
+async def debug_read_memory(
+    memory_reference: str | None = None,
+    expression: str | None = None,
+    frame_id: int | None = None,
+    offset: int = 0,
+    count: int = 256,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Read raw memory of a compiled target, as base64 and a hex dump.
+
+    Variables and evaluate results carry memory_reference when the adapter
+    reports one; pass it here, or pass a pointer expression instead. A read
+    past mapped memory returns what could be read, with unreadable_bytes
+    counting the rest.
+
+    Args:
+        memory_reference: Address or memory reference (e.g. "0xc000012000")
+        expression: Expression whose value's memory reference to read at
+        frame_id: Frame to evaluate the expression in (default: top frame)
+        offset: Bytes to move from the reference first (may be negative)
+        count: Bytes to read (1-65536, default 256)
+        session_id: Session ID (optional when only one session exists)
+    """
+    error = _memory_target_error(memory_reference, expression)
+    if error:
+        return error
+    if not 1 <= count <= 65536:
+        return {"error": "count must be between 1 and 65536", "code": "INVALID_RANGE"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        result = await session.read_memory(memory_reference, expression, frame_id, offset, count)
+        data: bytes = result.pop("data")
+        return {
+            **result,
+            "data": base64.b64encode(data).decode("ascii"),
+            "hex_dump": _get_formatter().format_hex_dump(data, result["address"]),
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED", "hint": _NATIVE_CODE_HINT}
+    except EvaluateError as e:
+        return {"error": e.message, "code": "EVAL_ERROR"}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+    except DAPError as e:
+        return {"error": e.message, "code": "DAP_ERROR"}
+
+
+@mcp.tool()
+@_recorded
+async def debug_write_memory(
+    data: str,
+    memory_reference: str | None = None,
+    expression: str | None = None,
+    frame_id: int | None = None,
+    offset: int = 0,
+    encoding: str = "hex",
+    allow_partial: bool = False,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Write raw bytes into a compiled target's memory.
+
+    Args:
+        data: Bytes to write, as hex ("2a 00 ff") or base64 (see encoding)
+        memory_reference: Address or memory reference to write at
+        expression: Expression whose value's memory reference to write at
+        frame_id: Frame to evaluate the expression in (default: top frame)
+        offset: Bytes to move from the reference first (may be negative)
+        encoding: "hex" or "base64"
+        allow_partial: Accept writing fewer bytes than given instead of failing
+        session_id: Session ID (optional when only one session exists)
+    """
+    error = _memory_target_error(memory_reference, expression)
+    if error:
+        return error
+    try:
+        if encoding == "hex":
+            raw = bytes.fromhex(data)
+        elif encoding == "base64":
+            raw = base64.b64decode(data, validate=True)
+        else:
+            return {
+                "error": f"Invalid encoding '{encoding}'; use 'hex' or 'base64'",
+                "code": "INVALID_ARGS",
+            }
+    except ValueError as e:
+        return {"error": f"Invalid {encoding} data: {e}", "code": "INVALID_ARGS"}
+    if not raw:
+        return {"error": "data is empty", "code": "INVALID_ARGS"}
+
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        return await session.write_memory(
+            raw, memory_reference, expression, frame_id, offset, allow_partial
+        )
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except InvalidSessionStateError as e:
+        return {"error": str(e), "code": "INVALID_STATE"}
+    except CapabilityNotSupportedError as e:
+        return {"error": e.message, "code": "NOT_SUPPORTED", "hint": _NATIVE_CODE_HINT}
+    except EvaluateError as e:
+        return {"error": e.message, "code": "EVAL_ERROR"}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
+    except DAPError as e:
+        return {"error": e.message, "code": "DAP_ERROR"}
+
+
 # =============================================================================
 # Watch Expression Tools
 # =============================================================================
 
 
 @mcp.tool()
+@_recorded
 async def debug_watch(
-    session_id: str,
     action: str,
     expression: str | None = None,
+    watch_id: int | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Manage watch expressions: add, remove, or list.
 
+    Watches are re-evaluated in the top frame on every stop; results appear
+    in the stopped event's "watches" and in action="list".
+
     Args:
-        session_id: Session ID
         action: "add", "remove", or "list"
-        expression: Expression (required for add/remove)
+        expression: Expression (required for add; remove by expression or watch_id)
+        watch_id: Watch ID returned by add (for remove)
+        session_id: Session ID (optional when only one session exists)
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
 
         if action == "add":
             if not expression:
                 return {"error": "Expression required for add", "code": "MISSING_EXPRESSION"}
-            watches = session.add_watch(expression)
+            session.add_watch(expression)
+            return {"id": session.watch_id(expression), "watches": session.describe_watches()}
         elif action == "remove":
-            if not expression:
-                return {"error": "Expression required for remove", "code": "MISSING_EXPRESSION"}
-            watches = session.remove_watch(expression)
-        elif action == "list":
-            watches = session.list_watches()
-        else:
+            if watch_id is not None:
+                session.remove_watch_by_id(watch_id)
+            elif expression:
+                session.remove_watch(expression)
+            else:
+                return {
+                    "error": "Expression or watch_id required for remove",
+                    "code": "MISSING_EXPRESSION",
+                }
+        elif action != "list":
             return {
                 "error": f"Invalid action: {action}. Use 'add', 'remove', or 'list'",
                 "code": "INVALID_ACTION",
             }
 
-        return {"watches": watches}
+        return {"watches": session.describe_watches()}
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except WatchNotFoundError as e:
+        return {"error": e.message, "code": "WATCH_NOT_FOUND"}
 
 
 @mcp.tool()
+@_recorded
 async def debug_evaluate_watches(
-    session_id: str,
     frame_id: int | None = None,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Evaluate all watch expressions and return results.
 
     Args:
-        session_id: Session ID
         frame_id: Frame ID (default: topmost)
+        session_id: Session ID (optional when only one session exists)
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
         results = await session.evaluate_watches(frame_id)
         return {
             "results": [
                 {
+                    "id": r["id"],
                     "expression": r["expression"],
                     "result": r["result"],
                     "type": r["type"],
@@ -811,6 +3202,14 @@ async def debug_evaluate_watches(
         }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+    except FrameNotFoundError as e:
+        return {
+            "error": e.message,
+            "code": "STALE_FRAME",
+            "hint": "call debug_get_stacktrace again for current frame IDs",
+        }
 
 
 # =============================================================================
@@ -819,19 +3218,23 @@ async def debug_evaluate_watches(
 
 
 @mcp.tool()
+@_refused_when_shutting_down
 async def debug_poll_events(
-    session_id: str,
     timeout_seconds: float = 5.0,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Poll for events (stopped, continued, terminated). Use after launch/step.
 
+    breakpointVerified events report a breakpoint that was unverified when
+    set (e.g. before delve loaded the binary) and is now bound.
+
     Args:
-        session_id: Session ID
         timeout_seconds: Wait time (default 5s)
+        session_id: Session ID (optional when only one session exists)
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
+        session = await manager.resolve_session(session_id)
         events = await session.event_queue.get_all(timeout=timeout_seconds)
         return {
             "events": [
@@ -846,40 +3249,168 @@ async def debug_poll_events(
         }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
 
 
 @mcp.tool()
+@_refused_when_shutting_down
 async def debug_get_output(
-    session_id: str,
     offset: int = 0,
     limit: int = 100,
+    since_seq: int | None = None,
+    category: str | None = None,
+    logpoints_only: bool = False,
+    logpoint_id: int | None = None,
+    from_file: bool = False,
+    session_id: str | None = None,
 ) -> dict[str, Any]:
     """Get program stdout/stderr output.
 
+    Each line's line_number is its sequence number, matching the first_seq /
+    last_seq of streamed output notifications.
+
+    With from_file, lines come from the launch's output_log_path instead of
+    the in-memory buffer, which drops old output once full. Those lines are
+    numbered by their position in the log, carry a timestamp, and
+    "truncated" is true once rotation has deleted the oldest file.
+
     Args:
-        session_id: Session ID
         offset: Start line
         limit: Max lines (default 100)
+        since_seq: Only lines after this sequence number (overrides offset)
+        category: Only "stdout", "stderr", "console" or "prelaunch" lines
+        logpoints_only: Only return messages emitted by logpoints
+        logpoint_id: Only return messages from this logpoint (breakpoint id)
+        from_file: Read the output log file (offset, limit and category apply)
+        session_id: Session ID (optional when only one session exists)
     """
     manager = _get_manager()
     try:
-        session = await manager.get_session(session_id)
-        page = session.output_buffer.get_page(offset, limit)
+        session = await manager.resolve_session(session_id)
+        if from_file:
+            if session.output_log is None:
+                return {
+                    "error": "No output log; launch with output_log_path to write one",
+                    "code": "NO_OUTPUT_LOG",
+                }
+            page = await session.output_log.read(offset, limit, category)
+            return {
+                "lines": [
+                    {
+                        "line_number": line.line_number,
+                        "category": line.category,
+                        "content": line.content,
+                        "timestamp": line.timestamp.isoformat(),
+                    }
+                    for line in page.lines
+                ],
+                "offset": page.offset,
+                "total": page.total,
+                "has_more": page.has_more,
+                "truncated": page.truncated,
+                "dropped_lines": session.output_log.dropped_lines,
+                "path": str(session.output_log.path),
+            }
+        if since_seq is not None:
+            page = session.output_buffer.get_since(since_seq, limit, category)
+        else:
+            page = session.output_buffer.get_page(
+                offset,
+                limit,
+                category,
+                logpoints_only=logpoints_only,
+                logpoint_id=logpoint_id,
+            )
         return {
             "lines": [
                 {
                     "line_number": line.line_number,
                     "category": line.category,
                     "content": line.content,
+                    "logpoint_id": line.logpoint_id,
                 }
                 for line in page.lines
             ],
-            "offset": offset,
+            "offset": page.offset,
             "total": page.total,
             "has_more": page.has_more,
+            "last_seq": session.output_buffer.last_line_number,
+        }
+    except SessionNotFoundError:
+        return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+@mcp.tool()
+@_refused_when_shutting_down
+async def debug_stream_output(
+    ctx: Context,  # type: ignore[type-arg]
+    enabled: bool = True,
+    session_id: str | None = None,
+) -> dict[str, Any]:
+    """Push program output to this client as MCP log notifications.
+
+    Notifications use logger "polybugger.output" and carry session_id,
+    category, first_seq/last_seq and text. Bursts are coalesced and capped;
+    a gap in sequence numbers means lines were skipped, which
+    debug_get_output(since_seq=...) can fill in.
+
+    Args:
+        enabled: Start (True) or stop (False) streaming
+        session_id: Session ID (optional when only one session exists)
+    """
+    manager = _get_manager()
+    try:
+        session = await manager.resolve_session(session_id)
+        await _stop_output_stream(session.id)
+        if enabled:
+            client = ctx.session
+            _session_clients[session.id] = client
+
+            async def send(chunk: dict[str, Any]) -> None:
+                await client.send_log_message(level="info", data=chunk, logger=OUTPUT_LOGGER)
+
+            streamer = OutputStreamer(send)
+            _output_streams[session.id] = streamer
+            session.add_output_listener(streamer.push)
+        return {
+            "session_id": session.id,
+            "streaming": enabled,
+            "logger": OUTPUT_LOGGER,
+            "last_seq": session.output_buffer.last_line_number,
         }
     except SessionNotFoundError:
         return {"error": f"Session {session_id} not found", "code": "NOT_FOUND"}
+    except SessionRequiredError as e:
+        return {"error": e.message, "code": e.code}
+
+
+async def _stop_output_stream(session_id: str) -> None:
+    """Stop streaming a session's output, sending anything still pending."""
+    streamer = _output_streams.pop(session_id, None)
+    if streamer is None:
+        return
+    if _session_manager is not None:
+        # A terminated session has already dropped its listeners
+        with suppress(SessionNotFoundError, ServerShuttingDownError):
+            session = await _session_manager.get_session(session_id)
+            session.remove_output_listener(streamer.push)
+    await streamer.close()
+
+
+async def _notify_session_ended(session: Session, reason: str) -> None:
+    """Tell the session's client it was ended for a time limit."""
+    await _stop_output_stream(session.id)
+    client = _session_clients.pop(session.id, None)
+    if client is None:
+        return
+    await client.send_log_message(
+        level="warning",
+        data={"event": "session_ended", "session_id": session.id, "reason": reason},
+        logger=SESSION_LOGGER,
+    )
 
 
 # =============================================================================
@@ -910,6 +3441,7 @@ async def debug_list_recoverable() -> dict[str, Any]:
 
 
 @mcp.tool()
+@_recorded
 async def debug_recover_session(session_id: str) -> dict[str, Any]:
     """Recover session (restores breakpoints/watches, requires re-launch)."""
     manager = _get_manager()
@@ -937,9 +3469,6 @@ async def debug_recover_session(session_id: str) -> dict[str, Any]:
 
 def main():
     """Run the MCP server via stdio transport."""
-    import signal
-    import sys
-
     # Ignore SIGTTIN/SIGTTOU to prevent suspension when debugpy subprocesses
     # try to access the terminal. This allows the MCP server to continue
     # running even if child processes attempt TTY operations.
@@ -1,6 +1,6 @@
 """Debug Adapter Protocol (DAP) models."""
 
-from typing import Any
+from typing import Any, Literal
 
 from pydantic import BaseModel, Field
 
@@ -39,6 +39,39 @@ class DAPEvent(DAPMessage):
     body: dict[str, Any] = Field(default_factory=dict)
 
 
+class PathMapping(BaseModel):
+    """Local source root and the path the debuggee sees for it."""
+
+    local_root: str
+    remote_root: str
+
+
+class StepFilters(BaseModel):
+    """Code that steps pass through instead of stopping in."""
+
+    patterns: list[str] = Field(default_factory=list)  # Globs on source file paths
+    skip_stdlib: bool = False
+    skip_site_packages: bool = False  # Also node_modules and the Go module cache
+
+
+class PreLaunchStep(BaseModel):
+    """A command run before the adapter starts, such as a build."""
+
+    command: str
+    args: list[str] = Field(default_factory=list)
+    cwd: str | None = None  # Relative to the project root; None: the launch cwd
+    timeout: float = Field(default=300.0, gt=0, le=3600.0)  # Seconds
+
+
+class TcpTransport(BaseModel):
+    """A DAP server already listening on a TCP port, used instead of starting one."""
+
+    host: str = "127.0.0.1"
+    port: int = Field(ge=1, le=65535)
+    connect_timeout: float = Field(default=10.0, gt=0, le=300.0)  # Seconds of retrying
+    connect_retries: int | None = Field(default=None, ge=0)  # None: retry until the timeout
+
+
 class LaunchConfig(BaseModel):
     """Configuration for launching a debug target."""
 
@@ -46,14 +79,30 @@ class LaunchConfig(BaseModel):
     module: str | None = None  # Alternative to program (e.g., "pytest")
     args: list[str] = Field(default_factory=list)
     python_args: list[str] = Field(default_factory=list)  # Args to Python interpreter
-    cwd: str = "."
-    env: dict[str, str] = Field(default_factory=dict)
+    cwd: str = "."  # Relative paths resolve against the project root
+    # Merged over the inherited environment; a None value unsets the variable
+    env: dict[str, str | None] = Field(default_factory=dict)
     python_path: str | None = None
     stop_on_entry: bool = False
     stop_on_exception: bool = True
     # Console mode for debugged process
     console: str = "internalConsole"
     redirect_input: bool = False
+    # Debuggee stdin: "pipe" (writable via send_stdin), "inherit" (adapter's), "closed"
+    stdin_mode: Literal["pipe", "inherit", "closed"] = "pipe"
+    # Registered adapter name; None picks one from the program's extension
+    adapter: str | None = None
+    # Source path translation for containers and remote targets
+    path_mappings: list[PathMapping] = Field(default_factory=list)
+    # Only stop in user code; native in debugpy, step filters elsewhere
+    just_my_code: bool = False
+    step_filters: StepFilters = Field(default_factory=StepFilters)
+    # delve: "test" builds the package's test binary and debugs that instead
+    mode: Literal["debug", "test"] = "debug"
+    # Also write output to this file (rotated by size); relative to the project root
+    output_log_path: str | None = None
+    # Commands run in order before the adapter starts; output is tagged "prelaunch"
+    pre_launch: list[PreLaunchStep] = Field(default_factory=list)
 
 
 class AttachConfig(BaseModel):
@@ -62,6 +111,8 @@ class AttachConfig(BaseModel):
     process_id: int | None = None
     host: str = "localhost"
     port: int = 5678
+    # Source path translation for containers and remote targets
+    path_mappings: list[PathMapping] = Field(default_factory=list)
 
 
 class SourceBreakpoint(BaseModel):
@@ -108,6 +159,8 @@ class StackFrame(BaseModel):
     end_line: int | None = Field(None, alias="endLine")
     end_column: int | None = Field(None, alias="endColumn")
     module_id: str | None = Field(None, alias="moduleId")
+    # Memory reference of the frame's current instruction (compiled targets)
+    instruction_pointer_reference: str | None = Field(None, alias="instructionPointerReference")
 
     class Config:
         populate_by_name = True
@@ -137,6 +190,7 @@ class Variable(BaseModel):
     named_variables: int | None = Field(None, alias="namedVariables")
     indexed_variables: int | None = Field(None, alias="indexedVariables")
     evaluate_name: str | None = Field(None, alias="evaluateName")
+    memory_reference: str | None = Field(None, alias="memoryReference")
 
     class Config:
         populate_by_name = True
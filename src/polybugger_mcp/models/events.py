@@ -17,7 +17,14 @@ class EventType(str, Enum):
     BREAKPOINT = "breakpoint"
     THREAD = "thread"
     MODULE = "module"
+    LOADED_SOURCE = "loadedSource"
     EXITED = "exited"
+    # Ours, not DAP: the adapter process died or dropped its connection
+    SESSION_ENDED = "sessionEnded"
+    # Ours, not DAP: a breakpoint reported unverified has since been bound
+    BREAKPOINT_VERIFIED = "breakpointVerified"
+    # Ours, not DAP: a test launched by name didn't exist when the runner ran
+    TEST_NOT_FOUND = "testNotFound"
 
 
 class StopReason(str, Enum):
@@ -4,6 +4,8 @@ from typing import Any
 
 from pydantic import BaseModel, Field, field_validator
 
+from polybugger_mcp.models.dap import PathMapping, PreLaunchStep, StepFilters
+
 
 class CreateSessionRequest(BaseModel):
     """Request to create a new debug session."""
@@ -21,10 +23,16 @@ class LaunchRequest(BaseModel):
     args: list[str] = Field(default_factory=list)
     python_args: list[str] = Field(default_factory=list)
     cwd: str | None = None
-    env: dict[str, str] = Field(default_factory=dict)
+    env: dict[str, str | None] = Field(default_factory=dict)
     python_path: str | None = None
     stop_on_entry: bool = False
     stop_on_exception: bool = True
+    stdin_mode: str = Field(default="pipe", pattern="^(pipe|inherit|closed)$")
+    adapter: str | None = None
+    path_mappings: list[PathMapping] = Field(default_factory=list)
+    just_my_code: bool = False
+    step_filters: StepFilters = Field(default_factory=StepFilters)
+    pre_launch: list[PreLaunchStep] = Field(default_factory=list)
 
     @field_validator("program", "module")
     @classmethod
@@ -42,6 +50,7 @@ class AttachRequest(BaseModel):
     process_id: int | None = None
     host: str = "localhost"
     port: int = 5678
+    path_mappings: list[PathMapping] = Field(default_factory=list)
 
     def model_post_init(self, __context: Any) -> None:
         if self.process_id is None and self.port is None:
@@ -86,10 +95,28 @@ class PauseRequest(BaseModel):
     thread_id: int | None = None
 
 
+class RunToLineRequest(BaseModel):
+    """Request to continue until a line is reached."""
+
+    file: str
+    line: int = Field(..., ge=1)
+    thread_id: int | None = None
+
+
+class SendStdinRequest(BaseModel):
+    """Request to write to the debuggee's stdin."""
+
+    data: str
+    newline: bool = True
+
+
 class StepRequest(BaseModel):
     """Request for step operations."""
 
     thread_id: int | None = None
+    # Re-issue the step if it stops on another thread (None: adapter default)
+    sticky_goroutine: bool | None = None
+    granularity: str = Field(default="line", pattern="^(line|instruction)$")
 
 
 class AddWatchRequest(BaseModel):
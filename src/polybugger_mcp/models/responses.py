@@ -68,6 +68,8 @@ class BreakpointResponse(BaseModel):
     column: int | None = None
     message: str | None = None
     source: str | None = None
+    condition: str | None = None
+    hit_condition: str | None = None
 
 
 class SetBreakpointsResponse(BaseModel):
@@ -101,6 +103,22 @@ class ExecutionResponse(BaseModel):
     location: LocationResponse | None = None
 
 
+class RestartResponse(BaseModel):
+    """Session restart response."""
+
+    status: str
+    method: str
+    breakpoints_replayed: int
+    unverified: list[dict[str, Any]] = Field(default_factory=list)
+    moved: list[dict[str, Any]] = Field(default_factory=list)
+
+
+class StdinResponse(BaseModel):
+    """Stdin write response."""
+
+    bytes_written: int
+
+
 # Inspection responses
 
 
@@ -109,12 +127,15 @@ class ThreadResponse(BaseModel):
 
     id: int
     name: str
+    stopped: bool = False
 
 
 class ThreadListResponse(BaseModel):
     """List of threads."""
 
     threads: list[ThreadResponse]
+    stopped_thread_id: int | None = None
+    total: int = 0
 
 
 class SourceResponse(BaseModel):
@@ -170,6 +191,9 @@ class VariablesResponse(BaseModel):
     """Variables response."""
 
     variables: list[VariableResponse]
+    start: int = 0
+    named_variables: int | None = None
+    indexed_variables: int | None = None
 
 
 class EvaluateResponse(BaseModel):
@@ -190,6 +214,7 @@ class OutputLineResponse(BaseModel):
     category: str
     content: str
     timestamp: datetime
+    logpoint_id: int | None = None
 
 
 class OutputResponse(BaseModel):
@@ -245,15 +270,24 @@ class InfoResponse(BaseModel):
 # Watch expression responses
 
 
+class WatchResponse(BaseModel):
+    """Watch expression with its stable ID."""
+
+    id: int
+    expression: str
+
+
 class WatchListResponse(BaseModel):
     """List of watch expressions."""
 
     expressions: list[str]
+    watches: list[WatchResponse] = []
 
 
 class WatchResultResponse(BaseModel):
     """Single watch expression result."""
 
+    id: int | None = None
     expression: str
     result: str | None = None
     type: str | None = None
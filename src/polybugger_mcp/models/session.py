@@ -1,8 +1,11 @@
 """Session models."""
 
 from datetime import datetime
+from typing import Literal
 
-from pydantic import BaseModel, Field
+from pydantic import BaseModel, Field, model_validator
+
+from polybugger_mcp.models.dap import TcpTransport
 
 
 class SessionConfig(BaseModel):
@@ -14,6 +17,31 @@ class SessionConfig(BaseModel):
     timeout_minutes: int = Field(default=60, ge=1, le=1440)  # Max 24 hours
     recover_from: str | None = None  # Session ID to recover settings from
 
+    # "stdio" starts the language's adapter here; "tcp" connects to a DAP
+    # server already listening at host:port instead
+    transport: Literal["stdio", "tcp"] = "stdio"
+    host: str = "127.0.0.1"
+    port: int | None = Field(default=None, ge=1, le=65535)
+    connect_timeout: float = Field(default=10.0, gt=0, le=300.0)
+    connect_retries: int | None = Field(default=None, ge=0)  # None: until the timeout
+
+    @model_validator(mode="after")
+    def _check_transport(self) -> "SessionConfig":
+        if self.transport == "tcp" and self.port is None:
+            raise ValueError("transport 'tcp' requires a port")
+        return self
+
+    def remote(self) -> TcpTransport | None:
+        """The server to connect to, or None to start the adapter locally."""
+        if self.transport != "tcp" or self.port is None:
+            return None
+        return TcpTransport(
+            host=self.host,
+            port=self.port,
+            connect_timeout=self.connect_timeout,
+            connect_retries=self.connect_retries,
+        )
+
 
 class SessionInfo(BaseModel):
     """Session information for API responses."""
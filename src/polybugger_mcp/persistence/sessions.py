@@ -26,6 +26,7 @@ class PersistedSession(BaseModel):
     project_root: str
     state: str
     language: str = "python"  # Programming language for debug adapter
+    remote: dict[str, Any] | None = None  # TcpTransport of a "tcp" session
     created_at: datetime
     last_activity: datetime
     breakpoints: dict[str, list[dict[str, Any]]]
@@ -5,6 +5,7 @@ from polybugger_mcp.utils.source_reader import (
     extract_call_expression,
     format_source_with_line_numbers,
     get_function_context,
+    get_lines_context,
     get_source_context,
     get_source_line,
 )
@@ -34,6 +35,7 @@ __all__ = [
     "extract_call_expression",
     "format_source_with_line_numbers",
     "get_function_context",
+    "get_lines_context",
     "get_source_context",
     "get_source_line",
 ]
@@ -0,0 +1,104 @@
+"""Goroutine dump helpers: wait reasons and grouping of identical stacks.
+
+Delve reports goroutines as DAP threads named like "[Go 7] main.worker",
+with " (Thread 1234)" appended while one runs on an OS thread. The DAP
+thread list carries no wait reason, so it is read off the stack: a
+goroutine parked in runtime.chanrecv is waiting on a channel receive, as
+in the "goroutine 7 [chan receive]:" headers of a runtime.Stack dump.
+"""
+
+import re
+from typing import Any
+
+_THREAD_NAME_PATTERN = re.compile(
+    r"^(?:\* )?\[Go (?P<goroutine>\d+)\] (?P<location>.*?)(?: \(Thread (?P<thread>\d+)\))?$"
+)
+
+# (function name fragment, wait reason, whether it can take part in a deadlock),
+# matched against each frame from the top of the stack down
+_WAIT_REASONS: tuple[tuple[str, str, bool], ...] = (
+    ("runtime.chanrecv", "chan receive", True),
+    ("runtime.chansend", "chan send", True),
+    ("runtime.selectgo", "select", True),
+    ("runtime.block", "select (no cases)", True),
+    ("(*Mutex).Lock", "sync.Mutex.Lock", True),
+    ("(*Mutex).lockSlow", "sync.Mutex.Lock", True),
+    ("(*RWMutex).RLock", "sync.RWMutex.RLock", True),
+    ("(*RWMutex).Lock", "sync.RWMutex.Lock", True),
+    ("(*WaitGroup).Wait", "sync.WaitGroup.Wait", True),
+    ("(*Cond).Wait", "sync.Cond.Wait", True),
+    ("time.Sleep", "sleep", False),
+    ("internal/poll.", "IO wait", False),
+)
+
+
+def parse_goroutine_name(name: str) -> dict[str, Any]:
+    """Split a delve thread name into goroutine id, location and OS thread.
+
+    Names in another format come back as the location, with no ids.
+    """
+    match = _THREAD_NAME_PATTERN.match(name)
+    if match is None:
+        return {"goroutine_id": None, "location": name, "os_thread": None}
+    return {
+        "goroutine_id": int(match["goroutine"]),
+        "location": match["location"],
+        "os_thread": int(match["thread"]) if match["thread"] else None,
+    }
+
+
+def wait_reason(functions: list[str]) -> tuple[str | None, bool]:
+    """The wait reason for a stack, given its function names from the top.
+
+    Returns:
+        The reason (None if the stack doesn't show one) and whether it is a
+        channel or lock wait that could be part of a deadlock
+    """
+    for function in functions:
+        for fragment, reason, blocking in _WAIT_REASONS:
+            if fragment in function:
+                return reason, blocking
+    return None, False
+
+
+def group_goroutines(
+    goroutines: list[dict[str, Any]],
+    max_frames: int,
+) -> list[dict[str, Any]]:
+    """Group goroutines whose top max_frames frames and wait reason match.
+
+    Args:
+        goroutines: Dicts with goroutine_id, state, wait_reason,
+            potential_deadlock and frames ({name, file, line}, top first)
+        max_frames: Frames compared and kept per group
+
+    Returns:
+        Groups, largest first, each with count, goroutine_ids, state,
+        wait_reason, potential_deadlock, the shared frames and
+        frames_truncated (whether the stacks go deeper)
+    """
+    groups: dict[tuple[Any, ...], dict[str, Any]] = {}
+    for g in goroutines:
+        frames = g["frames"][:max_frames]
+        key = (
+            g["state"],
+            g["wait_reason"],
+            tuple((f["name"], f["file"], f["line"]) for f in frames),
+        )
+        group = groups.get(key)
+        if group is None:
+            group = groups[key] = {
+                "count": 0,
+                "goroutine_ids": [],
+                "state": g["state"],
+                "wait_reason": g["wait_reason"],
+                "potential_deadlock": g["potential_deadlock"],
+                "frames": frames,
+                "frames_truncated": False,
+            }
+        group["count"] += 1
+        group["goroutine_ids"].append(g["goroutine_id"])
+        group["frames_truncated"] = group["frames_truncated"] or len(g["frames"]) > max_frames
+
+    # Ties keep the order of their first goroutine in the input
+    return sorted(groups.values(), key=lambda group: -group["count"])
@@ -0,0 +1,314 @@
+"""Launch configurations from a project's .vscode/launch.json.
+
+launch.json is JSONC: comments and trailing commas are allowed, and string
+values may use VS Code variables such as ${workspaceFolder} or ${env:HOME}.
+Entries are mapped onto LaunchConfig fields; keys without an equivalent here
+are reported as ignored rather than rejected.
+"""
+
+import json
+import os
+import re
+import shlex
+from pathlib import Path
+from typing import Any
+
+from polybugger_mcp.core.exceptions import LaunchConfigError
+
+LAUNCH_JSON = Path(".vscode") / "launch.json"
+
+# launch.json "type" -> registered adapter name
+ADAPTER_TYPES = {
+    "python": "debugpy",
+    "debugpy": "debugpy",
+    "go": "delve",
+    "node": "js-debug",
+    "pwa-node": "js-debug",
+    "lldb": "codelldb",
+}
+
+# Keys handled by resolve_configuration (or that only matter to VS Code)
+_HANDLED_KEYS = {
+    "name",
+    "type",
+    "request",
+    "program",
+    "module",
+    "args",
+    "cwd",
+    "env",
+    "envFile",
+    "stopOnEntry",
+    "console",
+    "python",
+    "pythonArgs",
+    "justMyCode",
+    "skipFiles",
+    "presentation",
+    "preLaunchTask",
+    "postDebugTask",
+    "internalConsoleOptions",
+}
+
+_VARIABLE = re.compile(r"\$\{([^}]+)\}")
+
+
+def strip_jsonc(text: str) -> str:
+    """Remove // and /* */ comments and trailing commas, leaving strings alone.
+
+    Comments go first so a comma followed only by a comment (a commented-out
+    last entry) is recognised as trailing.
+    """
+    return _strip_trailing_commas(_strip_comments(text))
+
+
+def _string_end(text: str, start: int) -> int:
+    """Index just past the string literal opening at start, honouring escapes."""
+    j = start + 1
+    while j < len(text) and text[j] != '"':
+        j += 2 if text[j] == "\\" else 1
+    return j + 1
+
+
+def _strip_comments(text: str) -> str:
+    out: list[str] = []
+    i = 0
+    n = len(text)
+    while i < n:
+        if text[i] == '"':
+            j = _string_end(text, i)
+            out.append(text[i:j])
+            i = j
+        elif text.startswith("//", i):
+            end = text.find("\n", i)
+            i = n if end == -1 else end
+        elif text.startswith("/*", i):
+            end = text.find("*/", i + 2)
+            i = n if end == -1 else end + 2
+        else:
+            out.append(text[i])
+            i += 1
+    return "".join(out)
+
+
+def _strip_trailing_commas(text: str) -> str:
+    out: list[str] = []
+    i = 0
+    n = len(text)
+    while i < n:
+        char = text[i]
+        if char == '"':
+            j = _string_end(text, i)
+            out.append(text[i:j])
+            i = j
+            continue
+        if char == ",":
+            # Drop the comma if only whitespace separates it from a closing bracket
+            j = i + 1
+            while j < n and text[j].isspace():
+                j += 1
+            if j < n and text[j] in "]}":
+                i += 1
+                continue
+        out.append(char)
+        i += 1
+    return "".join(out)
+
+
+def read_launch_json(project_root: Path) -> list[dict[str, Any]]:
+    """Read the configurations from a project's launch.json.
+
+    Returns:
+        The "configurations" entries, or [] if the project has no launch.json
+
+    Raises:
+        LaunchConfigError: If the file isn't valid JSON(C)
+    """
+    path = project_root / LAUNCH_JSON
+    if not path.is_file():
+        return []
+    try:
+        data = json.loads(strip_jsonc(path.read_text(encoding="utf-8")))
+    except (OSError, ValueError) as e:
+        raise LaunchConfigError(f"Cannot read {path}: {e}", {"path": str(path)})
+    configurations = data.get("configurations", []) if isinstance(data, dict) else []
+    return [c for c in configurations if isinstance(c, dict)]
+
+
+def find_configuration(configurations: list[dict[str, Any]], name: str) -> dict[str, Any]:
+    """Look up a configuration by its name.
+
+    Raises:
+        LaunchConfigError: If no configuration has that name
+    """
+    for entry in configurations:
+        if entry.get("name") == name:
+            return entry
+    names = [str(c.get("name")) for c in configurations]
+    raise LaunchConfigError(
+        f"No launch configuration named '{name}'; available: {', '.join(names) or 'none'}",
+        {"available": names},
+    )
+
+
+def _variables(project_root: Path) -> dict[str, str]:
+    """Values for the predefined variables that make sense outside an editor."""
+    root = str(project_root)
+    return {
+        "workspaceFolder": root,
+        "workspaceRoot": root,
+        "workspaceFolderBasename": project_root.name,
+        "userHome": str(Path.home()),
+        "cwd": root,
+        "pathSeparator": os.sep,
+        "/": os.sep,
+    }
+
+
+def _substitute(value: Any, variables: dict[str, str], unresolved: set[str]) -> Any:
+    """Replace ${...} variables in strings, recursing through lists and dicts."""
+    if isinstance(value, str):
+
+        def replace(match: re.Match[str]) -> str:
+            name = match.group(1)
+            if name.startswith("env:"):
+                # Like VS Code, an unset variable becomes an empty string
+                return os.environ.get(name[4:], "")
+            if name in variables:
+                return variables[name]
+            unresolved.add(name)
+            return match.group(0)
+
+        return _VARIABLE.sub(replace, value)
+    if isinstance(value, list):
+        return [_substitute(v, variables, unresolved) for v in value]
+    if isinstance(value, dict):
+        return {k: _substitute(v, variables, unresolved) for k, v in value.items()}
+    return value
+
+
+def _read_env_file(path: Path) -> dict[str, str]:
+    """Parse a dotenv file: KEY=VALUE lines, optional quotes and "export"."""
+    try:
+        lines = path.read_text(encoding="utf-8").splitlines()
+    except OSError as e:
+        raise LaunchConfigError(f"Cannot read envFile {path}: {e}", {"env_file": str(path)})
+    env: dict[str, str] = {}
+    for line in lines:
+        line = line.strip()
+        if not line or line.startswith("#") or "=" not in line:
+            continue
+        key, value = line.split("=", 1)
+        key = key.removeprefix("export ").strip()
+        value = value.strip()
+        if len(value) >= 2 and value[0] == value[-1] and value[0] in "\"'":
+            value = value[1:-1]
+        env[key] = value
+    return env
+
+
+def adapter_for_type(entry: dict[str, Any]) -> str:
+    """Registered adapter name for a configuration's "type".
+
+    Raises:
+        LaunchConfigError: If the type isn't one we can debug
+    """
+    config_type = entry.get("type")
+    adapter = ADAPTER_TYPES.get(str(config_type))
+    if adapter is None:
+        raise LaunchConfigError(
+            f"Unsupported launch configuration type '{config_type}'; "
+            f"supported: {', '.join(sorted(ADAPTER_TYPES))}",
+            {"type": config_type, "supported": sorted(ADAPTER_TYPES)},
+        )
+    return adapter
+
+
+def resolve_configuration(entry: dict[str, Any], project_root: Path) -> dict[str, Any]:
+    """Turn a launch.json entry into LaunchConfig keyword arguments.
+
+    Raises:
+        LaunchConfigError: If the entry isn't a launch request, its type is
+            unknown or it uses variables that can't be resolved
+    """
+    name = entry.get("name")
+    adapter = adapter_for_type(entry)
+    request = entry.get("request", "launch")
+    if request != "launch":
+        raise LaunchConfigError(
+            f"Configuration '{name}' is a '{request}' request; use debug_attach instead",
+            {"request": request},
+        )
+
+    unresolved: set[str] = set()
+    resolved = _substitute(entry, _variables(project_root), unresolved)
+    if unresolved:
+        names = sorted(unresolved)
+        raise LaunchConfigError(
+            f"Configuration '{name}' uses variables that can't be resolved: "
+            + ", ".join(f"${{{v}}}" for v in names),
+            {"unresolved": names},
+        )
+
+    env: dict[str, str | None] = {}
+    if resolved.get("envFile"):
+        env_file = Path(resolved["envFile"]).expanduser()
+        if not env_file.is_absolute():
+            env_file = project_root / env_file
+        env.update(_read_env_file(env_file))
+    # Explicit env entries take precedence over the file, as in VS Code
+    env.update(resolved.get("env") or {})
+
+    args = resolved.get("args") or []
+    if isinstance(args, str):
+        args = shlex.split(args)
+
+    kwargs: dict[str, Any] = {
+        "program": resolved.get("program"),
+        "module": resolved.get("module"),
+        "args": [str(a) for a in args],
+        "env": env,
+        "stop_on_entry": bool(resolved.get("stopOnEntry", False)),
+        "adapter": adapter,
+    }
+    if resolved.get("cwd"):
+        kwargs["cwd"] = resolved["cwd"]
+    if resolved.get("console"):
+        kwargs["console"] = resolved["console"]
+    if resolved.get("python"):
+        kwargs["python_path"] = resolved["python"]
+    if resolved.get("pythonArgs"):
+        kwargs["python_args"] = [str(a) for a in resolved["pythonArgs"]]
+    if "justMyCode" in resolved:
+        kwargs["just_my_code"] = bool(resolved["justMyCode"])
+    if resolved.get("skipFiles"):
+        # js-debug's "<node_internals>/**" stands for Node's standard library
+        skip = [str(p) for p in resolved["skipFiles"]]
+        patterns = [p for p in skip if not p.startswith("<node_internals>")]
+        kwargs["step_filters"] = {
+            "patterns": patterns,
+            "skip_stdlib": len(patterns) < len(skip),
+        }
+    return kwargs
+
+
+def describe_configuration(entry: dict[str, Any], project_root: Path) -> dict[str, Any]:
+    """Summary of a configuration for listing, saying whether it can be launched."""
+    summary: dict[str, Any] = {
+        "name": entry.get("name"),
+        "type": entry.get("type"),
+        "request": entry.get("request", "launch"),
+        "adapter": ADAPTER_TYPES.get(str(entry.get("type"))),
+        "program": entry.get("program"),
+        "module": entry.get("module"),
+        "args": entry.get("args") or [],
+        "cwd": entry.get("cwd"),
+        "ignored_keys": sorted(k for k in entry if k not in _HANDLED_KEYS),
+        "launchable": True,
+    }
+    try:
+        resolve_configuration(entry, project_root)
+    except LaunchConfigError as e:
+        summary["launchable"] = False
+        summary["reason"] = e.message
+    return summary
@@ -10,9 +10,10 @@ class OutputLine:
     """Single line of output."""
 
     line_number: int
-    category: str  # "stdout", "stderr", "console"
+    category: str  # "stdout", "stderr", "console", "prelaunch"
     content: str
     timestamp: datetime = field(default_factory=lambda: datetime.now(timezone.utc))
+    logpoint_id: int | None = None  # Breakpoint ID when emitted by a logpoint
 
 
 @dataclass
@@ -46,12 +47,16 @@ class OutputBuffer:
         self._total_dropped: int = 0
         self._line_counter: int = 0
 
-    def append(self, category: str, content: str) -> None:
+    def append(self, category: str, content: str, logpoint_id: int | None = None) -> OutputLine:
         """Add output to the buffer.
 
         Args:
-            category: Output category ("stdout", "stderr", "console")
+            category: Output category ("stdout", "stderr", "console", "prelaunch")
             content: The output content
+            logpoint_id: ID of the logpoint that produced this output (optional)
+
+        Returns:
+            The stored entry; its line_number is a monotonically increasing sequence
         """
         entry_size = len(content.encode("utf-8"))
 
@@ -66,16 +71,20 @@ class OutputBuffer:
             line_number=self._line_counter,
             category=category,
             content=content,
+            logpoint_id=logpoint_id,
         )
 
         self._entries.append(entry)
         self._current_size += entry_size
+        return entry
 
     def get_page(
         self,
         offset: int = 0,
         limit: int = 1000,
         category: str | None = None,
+        logpoints_only: bool = False,
+        logpoint_id: int | None = None,
     ) -> OutputPage:
         """Get a page of output.
 
@@ -83,6 +92,8 @@ class OutputBuffer:
             offset: Starting index
             limit: Maximum number of entries to return
             category: Filter by category (optional)
+            logpoints_only: Only return output emitted by logpoints
+            logpoint_id: Only return output from this logpoint (optional)
 
         Returns:
             OutputPage with the requested entries
@@ -93,6 +104,11 @@ class OutputBuffer:
         else:
             entries = list(self._entries)
 
+        if logpoints_only:
+            entries = [e for e in entries if e.logpoint_id is not None]
+        if logpoint_id is not None:
+            entries = [e for e in entries if e.logpoint_id == logpoint_id]
+
         total = len(entries)
         page_entries = entries[offset : offset + limit]
 
@@ -105,17 +121,27 @@ class OutputBuffer:
             truncated=self._total_dropped > 0,
         )
 
-    def get_since(self, line_number: int, limit: int = 1000) -> OutputPage:
+    def get_since(
+        self,
+        line_number: int,
+        limit: int = 1000,
+        category: str | None = None,
+    ) -> OutputPage:
         """Get output since a specific line number.
 
         Args:
             line_number: Get entries after this line number
             limit: Maximum number of entries to return
+            category: Filter by category (optional)
 
         Returns:
             OutputPage with entries after line_number
         """
-        entries = [e for e in self._entries if e.line_number > line_number]
+        entries = [
+            e
+            for e in self._entries
+            if e.line_number > line_number and (category is None or e.category == category)
+        ]
         page_entries = entries[:limit]
 
         return OutputPage(
@@ -0,0 +1,213 @@
+"""Output log file with size-based rotation.
+
+Unlike the in-memory output buffer, which drops its oldest lines once full,
+the log keeps a session's output on disk: the current file plus a number of
+rotated ones (output.log.1 is the newest rotated file).
+"""
+
+import asyncio
+import contextlib
+import logging
+import os
+import re
+from datetime import datetime, timezone
+from pathlib import Path
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.utils.output_buffer import OutputLine, OutputPage
+
+logger = logging.getLogger(__name__)
+
+# "<timestamp> [<category>] <text>", one record per line of output
+_RECORD_PATTERN = re.compile(r"^(\S+) \[(\w+)\] (.*)$")
+
+
+class OutputLog:
+    """Tee output lines to a rotating log file.
+
+    Lines are queued by push() and written by a background task, with the
+    file I/O itself in a worker thread, so a slow disk can't hold up event
+    processing. When the bounded queue is full, lines are left out of the
+    log (they remain in the output buffer) and a note records how many.
+    """
+
+    def __init__(
+        self,
+        path: Path,
+        max_bytes: int | None = None,
+        backup_count: int | None = None,
+        queue_size: int | None = None,
+    ):
+        """Initialize the log.
+
+        Args:
+            path: Log file; rotated files get .1, .2, ... appended
+            max_bytes: Size at which the file is rotated
+            backup_count: Rotated files kept
+            queue_size: Lines that may wait to be written
+        """
+        self.path = path
+        self.max_bytes = settings.output_log_max_bytes if max_bytes is None else max_bytes
+        self.backup_count = (
+            settings.output_log_backup_count if backup_count is None else backup_count
+        )
+        size = settings.output_log_queue_size if queue_size is None else queue_size
+        self._queue: asyncio.Queue[OutputLine | None] = asyncio.Queue(maxsize=size)
+        self._task: asyncio.Task[None] | None = None
+        self._unlogged = 0  # Lines left out since the last note
+        self.dropped_lines = 0
+        self.rotated_out = False  # Whether a rotated file has been deleted
+
+    def start(self) -> None:
+        """Create the file (and its directory) and start the writer.
+
+        Raises:
+            OSError: If the file can't be created
+        """
+        self.path.parent.mkdir(parents=True, exist_ok=True)
+        self.path.touch()
+        self._task = asyncio.create_task(self._run())
+
+    def push(self, session_id: str, line: OutputLine) -> None:
+        """Queue a line for writing (usable as a session output listener)."""
+        if self._task is None or self._task.done():
+            return
+        try:
+            self._queue.put_nowait(line)
+        except asyncio.QueueFull:
+            self._unlogged += 1
+            self.dropped_lines += 1
+
+    async def close(self) -> None:
+        """Write the queued lines and stop the writer."""
+        if self._task is None:
+            return
+        if not self._task.done():
+            await self._queue.put(None)
+            with contextlib.suppress(Exception):
+                await self._task
+        self._task = None
+
+    async def read(
+        self,
+        offset: int = 0,
+        limit: int = 1000,
+        category: str | None = None,
+    ) -> OutputPage:
+        """Read logged lines, oldest first, across the rotated files.
+
+        Queued lines are written first. Line numbers count records from the
+        start of the oldest file kept.
+        """
+        if self._task is not None and not self._task.done():
+            await self._queue.join()
+        return await asyncio.to_thread(self._read, offset, limit, category)
+
+    @property
+    def files(self) -> list[Path]:
+        """Existing log files, oldest first."""
+        candidates = [
+            self.path.with_name(f"{self.path.name}.{n}")
+            for n in range(self.backup_count, 0, -1)
+        ]
+        return [p for p in [*candidates, self.path] if p.exists()]
+
+    async def _run(self) -> None:
+        """Write queued lines in batches until closed."""
+        while True:
+            line = await self._queue.get()
+            batch = [line]
+            while not self._queue.empty():
+                batch.append(self._queue.get_nowait())
+            closing = batch[-1] is None
+            records = [r for entry in batch if entry is not None for r in self._format(entry)]
+            if self._unlogged:
+                records.append(self._note(f"{self._unlogged} output lines not logged (queue full)"))
+                self._unlogged = 0
+            try:
+                if records:
+                    await asyncio.to_thread(self._write, records)
+            except OSError as e:
+                logger.warning(f"Output log {self.path}: write failed: {e}")
+            finally:
+                for _ in batch:
+                    self._queue.task_done()
+            if closing:
+                return
+
+    @staticmethod
+    def _format(line: OutputLine) -> list[str]:
+        """Records for one output line, one per line of its text."""
+        timestamp = line.timestamp.isoformat(timespec="milliseconds")
+        return [f"{timestamp} [{line.category}] {text}\n" for text in line.content.splitlines()]
+
+    @staticmethod
+    def _note(text: str) -> str:
+        """A console record written by the log itself."""
+        timestamp = datetime.now(timezone.utc).isoformat(timespec="milliseconds")
+        return f"{timestamp} [console] {text}\n"
+
+    def _write(self, records: list[str]) -> None:
+        """Append records, rotating before one would grow the file past max_bytes."""
+        size = self.path.stat().st_size if self.path.exists() else 0
+        f = self.path.open("a", encoding="utf-8")
+        try:
+            for record in records:
+                record_size = len(record.encode("utf-8"))
+                if size and size + record_size > self.max_bytes:
+                    f.close()
+                    self._rotate()
+                    f = self.path.open("a", encoding="utf-8")
+                    size = 0
+                f.write(record)
+                size += record_size
+        finally:
+            f.close()
+
+    def _rotate(self) -> None:
+        """Shift output.log -> .1 -> .2 ..., deleting the oldest past backup_count."""
+        if self.backup_count == 0:
+            self.path.unlink()
+            self.rotated_out = True
+            return
+        oldest = self.path.with_name(f"{self.path.name}.{self.backup_count}")
+        if oldest.exists():
+            oldest.unlink()
+            self.rotated_out = True
+        for n in range(self.backup_count - 1, 0, -1):
+            source = self.path.with_name(f"{self.path.name}.{n}")
+            if source.exists():
+                os.replace(source, self.path.with_name(f"{self.path.name}.{n + 1}"))
+        os.replace(self.path, self.path.with_name(f"{self.path.name}.1"))
+
+    def _read(self, offset: int, limit: int, category: str | None) -> OutputPage:
+        """Parse the log files into output lines (see read)."""
+        entries: list[OutputLine] = []
+        number = 0
+        for path in self.files:
+            with contextlib.suppress(FileNotFoundError):
+                with path.open(encoding="utf-8", errors="replace") as f:
+                    for record in f:
+                        match = _RECORD_PATTERN.match(record.rstrip("\n"))
+                        if match is None:
+                            continue
+                        number += 1
+                        if category is not None and match[2] != category:
+                            continue
+                        entries.append(
+                            OutputLine(
+                                line_number=number,
+                                category=match[2],
+                                content=match[3],
+                                timestamp=datetime.fromisoformat(match[1]),
+                            )
+                        )
+
+        return OutputPage(
+            lines=entries[offset : offset + limit],
+            offset=offset,
+            limit=limit,
+            total=len(entries),
+            has_more=offset + limit < len(entries),
+            truncated=self.rotated_out,
+        )
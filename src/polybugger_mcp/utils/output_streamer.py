@@ -0,0 +1,123 @@
+"""Coalescing streamer for pushing debuggee output to clients."""
+
+import asyncio
+import logging
+from collections.abc import Awaitable, Callable
+from typing import Any
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.utils.output_buffer import OutputLine
+
+logger = logging.getLogger(__name__)
+
+SendFunc = Callable[[dict[str, Any]], Awaitable[None]]
+
+
+class OutputStreamer:
+    """Batch output lines into size-capped notifications.
+
+    Lines are collected for a short interval, and consecutive lines of the
+    same session and category are joined into one chunk. Once the pending
+    payload reaches max_bytes, further lines are skipped until the next
+    flush. Skipped lines remain in the session's output buffer and show up
+    as a gap in the sequence numbers, which clients can fill from the
+    buffered output (since_seq).
+    """
+
+    def __init__(
+        self,
+        send: SendFunc,
+        interval: float | None = None,
+        max_bytes: int | None = None,
+    ):
+        """Initialize the streamer.
+
+        Args:
+            send: Coroutine function delivering one chunk to the client
+            interval: Seconds to collect lines before flushing
+            max_bytes: Maximum output bytes per flush
+        """
+        self._send = send
+        self.interval = (
+            settings.output_stream_interval_seconds if interval is None else interval
+        )
+        self.max_bytes = settings.output_stream_max_bytes if max_bytes is None else max_bytes
+        self._pending: list[tuple[str, OutputLine]] = []
+        self._pending_bytes = 0
+        self._flush_task: asyncio.Task[None] | None = None
+        self._closed = False
+        self.skipped_lines = 0
+
+    def push(self, session_id: str, line: OutputLine) -> None:
+        """Queue a line for the next flush (usable as a session output listener)."""
+        if self._closed:
+            return
+
+        size = len(line.content.encode("utf-8"))
+        if self._pending and self._pending_bytes + size > self.max_bytes:
+            self.skipped_lines += 1
+        else:
+            self._pending.append((session_id, line))
+            self._pending_bytes += size
+
+        if self._flush_task is None:
+            self._flush_task = asyncio.get_running_loop().create_task(self._flush_later())
+
+    async def _flush_later(self) -> None:
+        try:
+            await asyncio.sleep(self.interval)
+        finally:
+            self._flush_task = None
+        await self.flush()
+
+    async def flush(self) -> None:
+        """Send everything pending now."""
+        pending = self._pending
+        self._pending = []
+        self._pending_bytes = 0
+
+        for chunk in self._coalesce(pending):
+            try:
+                await self._send(chunk)
+            except Exception as e:
+                logger.debug(f"Failed to stream output: {e}")
+
+    async def close(self) -> None:
+        """Stop accepting lines and send what is still pending."""
+        self._closed = True
+        if self._flush_task is not None:
+            self._flush_task.cancel()
+            self._flush_task = None
+        await self.flush()
+
+    def _coalesce(self, pending: list[tuple[str, OutputLine]]) -> list[dict[str, Any]]:
+        """Join runs of lines with the same session and category."""
+        chunks: list[dict[str, Any]] = []
+        for session_id, line in pending:
+            last = chunks[-1] if chunks else None
+            if (
+                last is not None
+                and last["session_id"] == session_id
+                and last["category"] == line.category
+            ):
+                last["text"] += line.content
+                last["last_seq"] = line.line_number
+                continue
+            chunks.append(
+                {
+                    "session_id": session_id,
+                    "category": line.category,
+                    "first_seq": line.line_number,
+                    "last_seq": line.line_number,
+                    "text": line.content,
+                    "truncated": False,
+                }
+            )
+
+        # A single oversized line is the only way to exceed max_bytes
+        for chunk in chunks:
+            encoded = chunk["text"].encode("utf-8")
+            if len(encoded) > self.max_bytes:
+                chunk["text"] = encoded[: self.max_bytes].decode("utf-8", errors="ignore")
+                chunk["truncated"] = True
+        return chunks
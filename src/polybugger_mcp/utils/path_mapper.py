@@ -0,0 +1,69 @@
+"""Translation between local source paths and the paths a debuggee sees.
+
+Used when the target runs in a container or on another host, where a file
+the client knows as /Users/me/project/app.py is /app/app.py to the adapter.
+"""
+
+from collections.abc import Iterable
+
+
+def _strip_separator(root: str) -> str:
+    """Drop trailing separators, keeping a bare root ("/") intact."""
+    stripped = root.rstrip("/\\")
+    return stripped or root[:1]
+
+
+def _separator(root: str) -> str:
+    """Path separator used by a root (backslash only for Windows-style roots)."""
+    return "\\" if "\\" in root and "/" not in root else "/"
+
+
+def _is_under(path: str, root: str) -> bool:
+    """Whether path is root or inside it, matching whole components."""
+    if root in ("/", "\\"):
+        return path.startswith(root)
+    return path == root or (path.startswith(root) and path[len(root)] in "/\\")
+
+
+class PathMapper:
+    """Map paths between local and remote roots.
+
+    When roots overlap, the longest matching prefix wins. Prefixes only match
+    on whole path components, and paths outside every mapping pass through
+    unchanged.
+    """
+
+    def __init__(self, mappings: Iterable[tuple[str, str]] = ()):
+        """Initialize the mapper.
+
+        Args:
+            mappings: (local_root, remote_root) pairs
+        """
+        self._mappings = [
+            (_strip_separator(local), _strip_separator(remote)) for local, remote in mappings
+        ]
+
+    def __bool__(self) -> bool:
+        return bool(self._mappings)
+
+    def to_remote(self, path: str) -> str:
+        """Translate a local path to the debuggee's view."""
+        return self._translate(path, self._mappings)
+
+    def to_local(self, path: str) -> str:
+        """Translate a path reported by the adapter to the local view."""
+        return self._translate(path, [(remote, local) for local, remote in self._mappings])
+
+    @staticmethod
+    def _translate(path: str, pairs: list[tuple[str, str]]) -> str:
+        matches = [(source, target) for source, target in pairs if _is_under(path, source)]
+        if not matches:
+            return path
+
+        source, target = max(matches, key=lambda pair: len(pair[0]))
+        rest = path[len(source) :].lstrip("/\\")
+        if not rest:
+            return target
+        sep = _separator(target)
+        rest = rest.replace("\\" if sep == "/" else "/", sep)
+        return target.rstrip("/\\") + sep + rest
@@ -0,0 +1,138 @@
+"""Pre-launch steps: commands such as builds run before the adapter starts.
+
+Steps run one after another, each in its own process group like the
+adapters, so a build tool's children go with it when a step times out or
+the session is terminated mid-build.
+"""
+
+import asyncio
+import codecs
+from collections.abc import Callable
+from pathlib import Path
+
+from polybugger_mcp.core.exceptions import PreLaunchError
+from polybugger_mcp.models.dap import PreLaunchStep
+from polybugger_mcp.utils.processes import register_process_group, stop_process
+
+# Lines of a failed step's stderr reported with the error
+STDERR_TAIL_LINES = 20
+_STDERR_TAIL_BYTES = 16 * 1024
+_READ_CHUNK = 64 * 1024
+
+
+class PreLaunchRunner:
+    """Run a launch's pre-launch steps in order, feeding their output to a callback.
+
+    Stdout and stderr are passed on in chunks as they arrive; cancel() kills
+    the running step's process group and fails the run.
+    """
+
+    def __init__(self, on_output: Callable[[str], None]):
+        self._on_output = on_output
+        self._process: asyncio.subprocess.Process | None = None
+        self._cancelled = False
+
+    async def run(
+        self,
+        steps: list[PreLaunchStep],
+        project_root: Path,
+        default_cwd: Path,
+        env: dict[str, str] | None = None,
+    ) -> None:
+        """Run every step, stopping at the first that doesn't succeed.
+
+        Args:
+            steps: Steps in the order to run them
+            project_root: Base of relative step cwds
+            default_cwd: Working directory of steps without a cwd
+            env: Environment of the commands (default: this process's)
+
+        Raises:
+            PreLaunchError: If a step can't start, exits nonzero, times out
+                or is cancelled
+        """
+        for number, step in enumerate(steps, start=1):
+            await self._run_step(number, step, project_root, default_cwd, env)
+
+    async def cancel(self) -> None:
+        """Kill the running step, if any; the run fails as cancelled."""
+        self._cancelled = True
+        if self._process is not None:
+            await stop_process(self._process)
+
+    async def _run_step(
+        self,
+        number: int,
+        step: PreLaunchStep,
+        project_root: Path,
+        default_cwd: Path,
+        env: dict[str, str] | None,
+    ) -> None:
+        command = " ".join([step.command, *step.args])
+        if self._cancelled:
+            raise PreLaunchError(number, command, "was cancelled")
+        cwd = default_cwd
+        if step.cwd is not None:
+            cwd = Path(step.cwd).expanduser()
+            if not cwd.is_absolute():
+                cwd = project_root / cwd
+        if not cwd.is_dir():
+            raise PreLaunchError(number, command, f"has no working directory {cwd}")
+
+        self._on_output(f"$ {command}\n")
+        try:
+            process = await asyncio.create_subprocess_exec(
+                step.command,
+                *step.args,
+                cwd=cwd,
+                env=env,
+                stdin=asyncio.subprocess.DEVNULL,
+                stdout=asyncio.subprocess.PIPE,
+                stderr=asyncio.subprocess.PIPE,
+                start_new_session=True,  # Own process group, see cancel
+            )
+        except OSError as e:
+            raise PreLaunchError(number, command, f"could not start: {e.strerror or e}") from e
+        register_process_group(process)
+        self._process = process
+
+        stderr = bytearray()
+        timed_out = False
+        try:
+            assert process.stdout is not None and process.stderr is not None
+            if not self._cancelled:  # cancel() may have come while it started
+                await asyncio.wait_for(
+                    asyncio.gather(
+                        self._forward(process.stdout, None),
+                        self._forward(process.stderr, stderr),
+                        process.wait(),
+                    ),
+                    timeout=step.timeout,
+                )
+        except asyncio.TimeoutError:
+            timed_out = True
+        finally:
+            self._process = None
+            await stop_process(process)
+
+        tail = "\n".join(stderr.decode(errors="replace").splitlines()[-STDERR_TAIL_LINES:])
+        if self._cancelled:
+            raise PreLaunchError(number, command, "was cancelled", process.returncode, tail)
+        if timed_out:
+            raise PreLaunchError(
+                number, command, f"timed out after {step.timeout:g}s", None, tail
+            )
+        if process.returncode != 0:
+            raise PreLaunchError(
+                number, command, f"exited with code {process.returncode}", process.returncode, tail
+            )
+
+    async def _forward(self, stream: asyncio.StreamReader, keep: bytearray | None) -> None:
+        """Pass a stream's output on until it closes, keeping its last bytes in keep."""
+        decoder = codecs.getincrementaldecoder("utf-8")(errors="replace")
+        while chunk := await stream.read(_READ_CHUNK):
+            if text := decoder.decode(chunk):
+                self._on_output(text)
+            if keep is not None:
+                keep += chunk
+                del keep[:-_STDERR_TAIL_BYTES]
@@ -0,0 +1,78 @@
+"""Process groups of the adapters, debuggees and pre-launch steps we start.
+
+Each process is started with start_new_session so that stopping it can
+take its whole group, children included.
+"""
+
+import asyncio
+import atexit
+import contextlib
+import os
+import signal
+import sys
+
+# Process groups of adapters and debuggees we started. Each is killed when
+# stopped normally; any left when the interpreter exits are killed then, so a
+# server that dies without cleanup leaves no dlv or python processes behind
+_process_groups: set[int] = set()
+
+
+@atexit.register
+def _kill_process_groups() -> None:
+    """Kill every process group still registered."""
+    for pgid in list(_process_groups):
+        with contextlib.suppress(OSError):
+            os.killpg(pgid, signal.SIGKILL)
+    _process_groups.clear()
+
+
+def register_process_group(process: asyncio.subprocess.Process) -> None:
+    """Track a process started with start_new_session for cleanup at exit."""
+    if sys.platform != "win32":
+        _process_groups.add(process.pid)
+
+
+def unregister_process_group(process: asyncio.subprocess.Process) -> None:
+    """Stop tracking a process group, once it is being stopped another way."""
+    _process_groups.discard(process.pid)
+
+
+def signal_group(process: asyncio.subprocess.Process, sig: signal.Signals) -> None:
+    """Send a signal to a process's whole group (just the process on Windows)."""
+    with contextlib.suppress(ProcessLookupError, PermissionError):
+        if sys.platform != "win32":
+            os.killpg(process.pid, sig)
+        elif sig == signal.SIGTERM:
+            process.terminate()
+        else:
+            process.kill()
+
+
+async def stop_process(
+    process: asyncio.subprocess.Process,
+    kill_group: bool = True,
+    timeout: float = 5.0,
+) -> None:
+    """Terminate a process, killing it if it lingers.
+
+    With kill_group the whole process group is signalled, even when the
+    leader already exited, so children it left behind (the debuggee under
+    dlv or debugpy's launcher) go too. Without it only the process itself
+    is stopped, leaving a debuggee that should keep running alone.
+    """
+    unregister_process_group(process)
+    if process.returncode is None:
+        if kill_group:
+            signal_group(process, signal.SIGTERM)
+        else:
+            with contextlib.suppress(ProcessLookupError):
+                process.terminate()
+        try:
+            await asyncio.wait_for(process.wait(), timeout=timeout)
+        except asyncio.TimeoutError:
+            with contextlib.suppress(ProcessLookupError):
+                process.kill()
+            with contextlib.suppress(asyncio.TimeoutError):
+                await asyncio.wait_for(process.wait(), timeout=timeout)
+    if kill_group:
+        signal_group(process, signal.SIGKILL)
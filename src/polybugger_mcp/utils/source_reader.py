@@ -117,7 +117,19 @@ def get_source_context(
             "after": [],
             "line_numbers": {"start": line_number, "current": line_number, "end": line_number},
         }
+    return get_lines_context(lines, line_number, context_lines)
 
+
+def get_lines_context(
+    lines: list[str],
+    line_number: int,
+    context_lines: int = 2,
+) -> dict[str, Any]:
+    """Get context around a line of already loaded source, like get_source_context.
+
+    Used for sources without a file, such as content fetched from the
+    debug adapter by sourceReference.
+    """
     # Convert to 0-based index
     idx = line_number - 1
     total_lines = len(lines)
@@ -241,6 +253,55 @@ def extract_call_expression(source_line: str) -> str | None:
     return None
 
 
+def extract_symbol_at(source_line: str, column: int) -> str | None:
+    """Extract the identifier at a column, with the dotted names before it.
+
+    Columns count characters of the decoded line, so multi-byte UTF-8
+    text doesn't shift them. Names after the identifier are left out, so
+    hovering over "b" in "a.b.c" gives "a.b".
+
+    Args:
+        source_line: A line of source code
+        column: 1-based column
+
+    Returns:
+        The expression, or None on whitespace, an operator, past the end,
+        or on an attribute of something other than a name (f(x).y)
+
+    Examples:
+        >>> extract_symbol_at("    total = order.items.count()", 21)
+        "order.items"
+    """
+    idx = column - 1
+    if not 0 <= idx < len(source_line) or not _is_name_char(source_line[idx]):
+        return None
+
+    start = idx
+    while start > 0 and _is_name_char(source_line[start - 1]):
+        start -= 1
+    end = idx + 1
+    while end < len(source_line) and _is_name_char(source_line[end]):
+        end += 1
+    if source_line[start].isdigit():
+        return None  # A number literal, not a name
+
+    # Extend left through "name." prefixes
+    while start > 0 and source_line[start - 1] == ".":
+        prefix_end = start - 1
+        prefix_start = prefix_end
+        while prefix_start > 0 and _is_name_char(source_line[prefix_start - 1]):
+            prefix_start -= 1
+        if prefix_start == prefix_end or source_line[prefix_start].isdigit():
+            return None  # An attribute of a call, subscript or literal
+        start = prefix_start
+    return source_line[start:end]
+
+
+def _is_name_char(char: str) -> bool:
+    """Whether a character can be part of an identifier."""
+    return char == "_" or char.isalnum()
+
+
 def format_source_with_line_numbers(
     lines: list[str],
     start_line: int,
@@ -0,0 +1,74 @@
+"""Rules for code that steps should pass through rather than stop in.
+
+Applied by the session after each step (see Session._redirect_step_stop):
+a step that lands in filtered code is followed by step-outs until execution
+is back in unfiltered code. This works the same for every adapter and can
+change mid-session, unlike launch-time options such as debugpy's justMyCode.
+"""
+
+import os
+import re
+from fnmatch import fnmatchcase
+from pathlib import Path
+from typing import Any
+
+# Standard library locations: CPython's lib/pythonX.Y, Go's GOROOT sources
+# and Node's built-in modules
+_STDLIB = re.compile(
+    r"[/\\]lib[/\\]python\d+(\.\d+)?[/\\]|[/\\]go[/\\]src[/\\]|^<node_internals>|^node:"
+)
+# Third-party installs: pip, node_modules, Go's module cache, cargo registry
+_THIRD_PARTY = re.compile(
+    r"[/\\](site-packages|dist-packages|node_modules)[/\\]"
+    r"|[/\\]pkg[/\\]mod[/\\]|[/\\]\.cargo[/\\]registry[/\\]"
+)
+
+
+class StepFilter:
+    """Decide whether a source path is filtered.
+
+    Glob patterns match the absolute path or the path relative to the
+    project root; "*" also matches across directories.
+    """
+
+    def __init__(
+        self,
+        patterns: list[str] | None = None,
+        skip_stdlib: bool = False,
+        skip_site_packages: bool = False,
+        project_root: Path | None = None,
+    ):
+        self.patterns = list(patterns or [])
+        self.skip_stdlib = skip_stdlib
+        self.skip_site_packages = skip_site_packages
+        self._project_root = project_root
+        goroot = os.environ.get("GOROOT")
+        self._goroot = os.path.join(goroot, "src") if goroot else None
+
+    def __bool__(self) -> bool:
+        return bool(self.patterns or self.skip_stdlib or self.skip_site_packages)
+
+    def matches(self, path: str | None) -> bool:
+        """Whether steps should not stop in this file (False for no path)."""
+        if not path:
+            return False
+        if self.skip_site_packages and _THIRD_PARTY.search(path):
+            return True
+        if self.skip_stdlib and not _THIRD_PARTY.search(path):
+            if _STDLIB.search(path) or (self._goroot and path.startswith(self._goroot)):
+                return True
+        candidates = [path.replace("\\", "/")]
+        if self._project_root is not None:
+            try:
+                candidates.append(Path(path).relative_to(self._project_root).as_posix())
+            except ValueError:
+                pass
+        return any(fnmatchcase(c, p) for p in self.patterns for c in candidates)
+
+    def describe(self) -> dict[str, Any]:
+        """Current rules, for tool responses."""
+        return {
+            "patterns": self.patterns,
+            "skip_stdlib": self.skip_stdlib,
+            "skip_site_packages": self.skip_site_packages,
+        }
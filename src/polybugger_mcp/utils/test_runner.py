@@ -0,0 +1,108 @@
+"""Launch configurations for debugging a single test, and spotting unknown tests.
+
+Test identifiers follow each runner's own syntax:
+
+- pytest: a node id, e.g. "tests/test_api.py::TestLogin::test_expired"
+- unittest: a dotted name, e.g. "tests.test_api.TestLogin.test_expired"
+- go: "TestName", "./pkg/store::TestPut" or with subtests "TestPut/empty";
+  delve builds the package's test binary and runs only the matching test
+
+A runner given a name it can't find doesn't fail to start: it runs nothing
+and exits non-zero. missing_test_message recognises that from the runner output.
+"""
+
+import re
+from pathlib import Path
+from typing import Any
+
+from polybugger_mcp.core.exceptions import LaunchConfigError
+
+# Framework -> registered adapter name
+FRAMEWORKS = {
+    "pytest": "debugpy",
+    "unittest": "debugpy",
+    "go": "delve",
+}
+
+# Output lines meaning the runner didn't find the requested test
+_NOT_FOUND = {
+    "pytest": [
+        re.compile(r"^ERROR: (?:file or directory )?not found: .*$", re.MULTILINE),
+        re.compile(r"^=* ?no tests ran\b.*$", re.MULTILINE),
+    ],
+    "unittest": [
+        # Names that fail to load become a synthetic _FailedTest
+        re.compile(
+            r"unittest\.loader\._FailedTest.*?^((?:Attribute|ModuleNotFound|Import)Error: [^\n]*)$",
+            re.MULTILINE | re.DOTALL,
+        ),
+        re.compile(r"^(?:Ran 0 tests\b.*|NO TESTS RAN)$", re.MULTILINE),
+    ],
+    "go": [
+        re.compile(r"^testing: warning: no tests to run$", re.MULTILINE),
+    ],
+}
+
+
+def _go_run_pattern(name: str) -> str:
+    """-test.run pattern matching exactly the named test and subtests."""
+    return "/".join(f"^{re.escape(part)}$" for part in name.split("/"))
+
+
+def build_test_launch(
+    framework: str,
+    test_id: str,
+    project_root: Path,
+    args: list[str] | None = None,
+) -> dict[str, Any]:
+    """LaunchConfig keyword arguments that run one test under the debugger.
+
+    Args:
+        framework: "pytest", "unittest" or "go"
+        test_id: The test, in the framework's own syntax (see module docs)
+        project_root: Project the test belongs to
+        args: Extra runner arguments, appended after the test selection
+
+    Raises:
+        LaunchConfigError: If the framework is unknown or test_id is empty
+    """
+    adapter = FRAMEWORKS.get(framework)
+    if adapter is None:
+        raise LaunchConfigError(
+            f"Unsupported test framework '{framework}'; "
+            f"supported: {', '.join(sorted(FRAMEWORKS))}",
+            {"framework": framework, "supported": sorted(FRAMEWORKS)},
+        )
+    test_id = test_id.strip()
+    if not test_id:
+        raise LaunchConfigError("test_id is required", {"framework": framework})
+    extra = list(args or [])
+
+    if framework == "go":
+        package, _, name = test_id.rpartition("::")
+        package_dir = (project_root / (package or ".")).resolve()
+        return {
+            "program": str(package_dir),
+            "mode": "test",
+            "args": ["-test.run", _go_run_pattern(name), *extra],
+            # go test runs each package's tests from the package directory
+            "cwd": str(package_dir),
+            "adapter": adapter,
+        }
+    if framework == "pytest":
+        # -p no:cacheprovider keeps the run from writing .pytest_cache
+        return {
+            "module": "pytest",
+            "args": [test_id, "-p", "no:cacheprovider", *extra],
+            "adapter": adapter,
+        }
+    return {"module": "unittest", "args": [test_id, *extra], "adapter": adapter}
+
+
+def missing_test_message(framework: str, output: str) -> str | None:
+    """The runner's message if its output says the requested test doesn't exist."""
+    for pattern in _NOT_FOUND.get(framework, []):
+        match = pattern.search(output)
+        if match:
+            return (match.group(1) if match.groups() else match.group(0)).strip(" =")
+    return None
@@ -484,6 +484,41 @@ class TUIFormatter:
 
         return "\n".join(lines)
 
+    def format_hex_dump(
+        self,
+        data: bytes,
+        address: str | None = None,
+        bytes_per_line: int = 16,
+    ) -> str:
+        """Format memory as a hex dump with an ASCII gutter.
+
+        Args:
+            data: Bytes read
+            address: Address of the first byte ("0x..."); offsets from 0
+                are shown when it isn't numeric
+
+        Returns:
+            One line per bytes_per_line bytes.
+
+        Example Output:
+            000000c000012000  68 65 6c 6c 6f 00 00 00  2a 00 00 00 00 00 00 00  |hello...*.......|
+        """
+        try:
+            start = int(address, 0) if address else 0
+        except ValueError:
+            start = 0
+
+        half = bytes_per_line // 2
+        lines: list[str] = []
+        for offset in range(0, len(data), bytes_per_line):
+            chunk = data[offset : offset + bytes_per_line]
+            hex_bytes = [f"{b:02x}" for b in chunk]
+            hex_text = " ".join(hex_bytes[:half]) + "  " + " ".join(hex_bytes[half:])
+            ascii_text = "".join(chr(b) if 0x20 <= b < 0x7F else "." for b in chunk)
+            width = bytes_per_line * 3 + 1
+            lines.append(f"{start + offset:016x}  {hex_text:<{width}} |{ascii_text}|")
+        return "\n".join(lines)
+
     def format_inspection(
         self,
         inspection: dict[str, Any],
@@ -0,0 +1,21 @@
+// Go test fixture: two goroutines handing values over a channel.
+package main
+
+import "fmt"
+
+func producer(ch chan<- int) {
+	for i := 0; i < 3; i++ {
+		ch <- i // Line 8: blocks until main receives
+	}
+	close(ch)
+}
+
+func main() {
+	ch := make(chan int)
+	go producer(ch)
+	sum := 0
+	for v := range ch { // Line 17: breakpoint target
+		sum += v // Line 18
+	}
+	fmt.Printf("Sum: %d\n", sum)
+}
@@ -0,0 +1,8 @@
+// Go fixture that exits before any breakpoint could matter.
+package main
+
+import "os"
+
+func main() {
+	os.Exit(3) // Line 7
+}
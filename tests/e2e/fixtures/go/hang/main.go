@@ -0,0 +1,32 @@
+// Go fixture that hangs: 50 goroutines blocked on a channel nobody sends on,
+// 3 more stuck on a mutex held forever. main keeps sleeping so the runtime's
+// deadlock detector doesn't end the program.
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+func worker(ch <-chan int) {
+	<-ch // Line 12: never receives
+}
+
+func locker(mu *sync.Mutex) {
+	mu.Lock() // Line 16: never acquired
+}
+
+func main() {
+	ch := make(chan int)
+	for i := 0; i < 50; i++ {
+		go worker(ch)
+	}
+	var mu sync.Mutex
+	mu.Lock()
+	for i := 0; i < 3; i++ {
+		go locker(&mu)
+	}
+	for {
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,20 @@
+// Go fixture with a pointer-receiver method, for function breakpoints by name.
+package main
+
+import "fmt"
+
+type Server struct {
+	handled int
+}
+
+func (s *Server) Handle(request string) string {
+	s.handled++ // Line 11: first line of main.(*Server).Handle
+	return "ok: " + request
+}
+
+func main() {
+	s := &Server{}
+	for _, request := range []string{"a", "b"} {
+		fmt.Println(s.Handle(request))
+	}
+}
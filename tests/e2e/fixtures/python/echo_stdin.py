@@ -0,0 +1,6 @@
+"""Reads two lines from stdin and echoes them back."""
+
+first = input()
+second = input()
+print(f"echo: {first}")
+print(f"echo: {second}")
@@ -0,0 +1,17 @@
+"""Fixture with a module global and a closure, for scope tests."""
+
+THRESHOLD = 42
+
+
+def make_counter(start):
+    step = 2
+
+    def bump(n):
+        total = start + step * n  # Line 10: breakpoint target
+        return total
+
+    return bump
+
+
+if __name__ == "__main__":
+    print(make_counter(1)(THRESHOLD))
@@ -0,0 +1,11 @@
+"""Starts a child process, writes both PIDs to the file in argv[1], then waits."""
+
+import os
+import subprocess
+import sys
+import time
+
+child = subprocess.Popen([sys.executable, "-c", "import time; time.sleep(300)"])
+with open(sys.argv[1], "w") as f:
+    f.write(f"{os.getpid()} {child.pid}\n")
+time.sleep(300)
@@ -13,7 +13,8 @@ import pytest
 import pytest_asyncio
 
 from polybugger_mcp.adapters.delve_adapter import DelveAdapter, GoLaunchConfig
-from polybugger_mcp.models.dap import SourceBreakpoint
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.models.dap import LaunchConfig, SourceBreakpoint
 from polybugger_mcp.models.events import EventType
 
 FIXTURES_DIR = Path(__file__).parent / "fixtures" / "go"
@@ -227,3 +228,181 @@ class TestDelveAdapter:
         assert "result" in result
         # Delve returns the result as a string, may include type info
         assert "30" in str(result["result"]), f"Expected '30' in {result}"
+
+
+class TestStickyGoroutineStep:
+    """Stepping a goroutine that hands values to another over a channel."""
+
+    @pytest_asyncio.fixture
+    async def session(self):  # type: ignore[misc]
+        """Create a Go session on the channels fixture with cleanup."""
+        _session = Session(
+            session_id="test-go-sticky",
+            project_root=FIXTURES_DIR / "channels",
+            language="go",
+        )
+        await _session.initialize_adapter()
+        yield _session
+        try:
+            await _session.cleanup()
+        except Exception:
+            pass
+
+    async def _stop_in_receive_loop(self, session: Session) -> dict[str, Any]:
+        """Launch and wait for the breakpoint on main's range loop."""
+        fixture = FIXTURES_DIR / "channels" / "main.go"
+        await session.set_breakpoints(str(fixture), [SourceBreakpoint(line=17)])
+        stops_before = session.stop_count
+        await session.launch(LaunchConfig(program=str(fixture)))
+        stop = await session.wait_for_stop(stops_before, timeout=30.0)
+        assert stop["status"] == "stopped"
+        # Only the first stop matters; the producer must not hit it again
+        await session.set_breakpoints(str(fixture), [])
+        return stop
+
+    @pytest.mark.asyncio
+    async def test_step_stays_on_stepped_goroutine(self, session: Session) -> None:
+        """Test that sticky steps never report a stop on another goroutine."""
+        stop = await self._stop_in_receive_loop(session)
+        main_thread = stop["thread_id"]
+
+        for _ in range(6):
+            stops_before = session.stop_count
+            await session.step_over(main_thread)
+            stop = await session.wait_for_stop(stops_before, timeout=30.0)
+            if stop["status"] != "stopped":
+                break
+            assert stop["thread_id"] == main_thread
+            assert "step_thread_id" not in stop
+
+    @pytest.mark.asyncio
+    async def test_non_sticky_step_is_annotated(self, session: Session) -> None:
+        """Test that a step landing on another goroutine says which was stepped."""
+        stop = await self._stop_in_receive_loop(session)
+        main_thread = stop["thread_id"]
+
+        for _ in range(6):
+            stops_before = session.stop_count
+            await session.step_over(stop["thread_id"], sticky=False)
+            stop = await session.wait_for_stop(stops_before, timeout=30.0)
+            if stop["status"] != "stopped":
+                break
+            if stop["thread_id"] != main_thread:
+                assert stop["step_thread_id"] == main_thread
+                break
+
+
+class TestFastExit:
+    """Launching programs that finish almost as soon as they start."""
+
+    @pytest_asyncio.fixture
+    async def session(self):  # type: ignore[misc]
+        """Create a Go session on the fixtures directory with cleanup."""
+        _session = Session(
+            session_id="test-go-fast-exit",
+            project_root=FIXTURES_DIR,
+            language="go",
+        )
+        await _session.initialize_adapter()
+        yield _session
+        try:
+            await _session.cleanup()
+        except Exception:
+            pass
+
+    @pytest.mark.asyncio
+    async def test_breakpoint_in_short_program_hit(self, session: Session) -> None:
+        """Test that breakpoints are in place before the program can run past them."""
+        fixture = FIXTURES_DIR / "simple.go"
+        await session.set_breakpoints(str(fixture), [SourceBreakpoint(line=14)])
+        stops_before = session.stop_count
+
+        await session.launch(LaunchConfig(program=str(fixture)))
+        stop = await session.wait_for_stop(stops_before, timeout=30.0)
+
+        assert stop["status"] == "stopped"
+        assert stop["reason"] == "breakpoint"
+
+    @pytest.mark.asyncio
+    async def test_immediate_exit_reported(self, session: Session) -> None:
+        """Test that a program exiting at once ends the session instead of hanging."""
+        fixture = FIXTURES_DIR / "exit_fast" / "main.go"
+        stops_before = session.stop_count
+
+        await session.launch(LaunchConfig(program=str(fixture)))
+        stop = await session.wait_for_stop(stops_before, timeout=30.0)
+
+        assert stop["status"] == "terminated"
+        assert stop["exit_code"] == 3
+
+
+class TestGoroutineDump:
+    """Dumping the goroutines of a program that hangs."""
+
+    @pytest_asyncio.fixture
+    async def session(self):  # type: ignore[misc]
+        """Create a Go session on the hang fixture with cleanup."""
+        _session = Session(
+            session_id="test-go-dump",
+            project_root=FIXTURES_DIR / "hang",
+            language="go",
+        )
+        await _session.initialize_adapter()
+        yield _session
+        try:
+            await _session.cleanup()
+        except Exception:
+            pass
+
+    @pytest.mark.asyncio
+    async def test_blocked_goroutines_grouped(self, session: Session) -> None:
+        """Test that the 50 channel waiters form one group flagged as a deadlock."""
+        await session.launch(LaunchConfig(program=str(FIXTURES_DIR / "hang" / "main.go")))
+        await asyncio.sleep(2.0)  # Let every goroutine block
+
+        dump = await session.dump_goroutines(max_frames=5)
+
+        assert dump["paused"] is True
+        assert dump["goroutine_count"] >= 54
+        receivers = next(g for g in dump["groups"] if g["wait_reason"] == "chan receive")
+        assert receivers["count"] == 50
+        assert receivers["potential_deadlock"] is True
+        assert any(f["name"] == "main.worker" for f in receivers["frames"])
+        lockers = next(g for g in dump["groups"] if g["wait_reason"] == "sync.Mutex.Lock")
+        assert lockers["count"] == 3
+
+
+class TestFunctionBreakpoints:
+    """Breaking on Go functions by their qualified names."""
+
+    @pytest_asyncio.fixture
+    async def session(self):  # type: ignore[misc]
+        """Create a Go session on the methods fixture with cleanup."""
+        _session = Session(
+            session_id="test-go-function-bp",
+            project_root=FIXTURES_DIR / "methods",
+            language="go",
+        )
+        await _session.initialize_adapter()
+        yield _session
+        try:
+            await _session.cleanup()
+        except Exception:
+            pass
+
+    @pytest.mark.asyncio
+    async def test_receiver_method_breakpoint_hit(self, session: Session) -> None:
+        """Test that a pointer-receiver method set before launch binds and stops."""
+        fixture = FIXTURES_DIR / "methods" / "main.go"
+        await session.set_function_breakpoint("main.(*Server).Handle")
+        stops_before = session.stop_count
+
+        await session.launch(LaunchConfig(program=str(fixture)))
+        stop = await session.wait_for_stop(stops_before, timeout=30.0)
+
+        assert stop["status"] == "stopped"
+        assert stop["reason"] == "function breakpoint"
+        (entry,) = session.describe_function_breakpoints()
+        assert entry["name"] == "main.(*Server).Handle"
+        assert entry["verified"] is True
+        assert entry["bound_file"].endswith("main.go")
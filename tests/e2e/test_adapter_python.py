@@ -11,6 +11,7 @@ import pytest
 import pytest_asyncio
 
 from polybugger_mcp.adapters.debugpy_adapter import DebugpyAdapter
+from polybugger_mcp.core.session import Session
 from polybugger_mcp.models.dap import LaunchConfig, SourceBreakpoint
 from polybugger_mcp.models.events import EventType
 
@@ -318,3 +319,73 @@ for i in range(5):
         import os
 
         os.unlink(script_path)
+
+    @pytest.mark.asyncio
+    async def test_stdin_round_trip(self, adapter: DebugpyAdapter) -> None:
+        """Test feeding two input() lines through the stdin pipe."""
+        await adapter.initialize()
+
+        output: list[str] = []
+        terminated = asyncio.Event()
+
+        async def event_handler(event_type: EventType, data: dict[str, Any]) -> None:
+            if event_type == EventType.OUTPUT:
+                output.append(data.get("output", ""))
+            elif event_type == EventType.TERMINATED:
+                terminated.set()
+
+        adapter._event_callback = event_handler
+
+        config = LaunchConfig(program=str(FIXTURES_DIR / "echo_stdin.py"), stdin_mode="pipe")
+        await adapter.launch(config)
+
+        await adapter.write_stdin(b"hello\n")
+        await adapter.write_stdin(b"world\n")
+        await asyncio.wait_for(terminated.wait(), timeout=10.0)
+
+        text = "".join(output)
+        assert "echo: hello" in text
+        assert "echo: world" in text
+
+    @pytest.mark.asyncio
+    async def test_globals_and_closure_scopes(self, adapter: DebugpyAdapter) -> None:
+        """Test that a module global and closure-captured variables are reachable."""
+        await adapter.initialize()
+
+        fixture = FIXTURES_DIR / "scopes.py"
+
+        stopped = asyncio.Event()
+        stopped_thread_id: int | None = None
+
+        async def event_handler(event_type: EventType, data: dict[str, Any]) -> None:
+            nonlocal stopped_thread_id
+            if event_type == EventType.STOPPED:
+                stopped_thread_id = data.get("threadId")
+                stopped.set()
+
+        adapter._event_callback = event_handler
+
+        async def configure() -> None:
+            await adapter.set_breakpoints(
+                source_path=str(fixture),
+                breakpoints=[SourceBreakpoint(line=10)],  # inside the closure
+            )
+
+        config = LaunchConfig(program=str(fixture))
+        await adapter.launch(config, configure_callback=configure)
+        await asyncio.wait_for(stopped.wait(), timeout=10.0)
+
+        assert stopped_thread_id is not None
+        frames = await adapter.stack_trace(stopped_thread_id)
+        scopes = await adapter.scopes(frames[0].id)
+
+        locals_scope = Session.select_scope(scopes)
+        globals_scope = Session.select_scope(scopes, "globals")
+        assert locals_scope is not None
+        assert globals_scope is not None
+        assert globals_scope is not locals_scope
+
+        local_names = {v.name for v in await adapter.variables(locals_scope.variables_reference)}
+        global_names = {v.name for v in await adapter.variables(globals_scope.variables_reference)}
+        assert {"n", "start", "step"} <= local_names  # free variables of the closure
+        assert "THRESHOLD" in global_names
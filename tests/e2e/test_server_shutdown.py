@@ -0,0 +1,166 @@
+"""Server shutdown tests: stopping the MCP server leaves no debuggee behind.
+
+These run the real stdio server in a subprocess, launch a program under
+debugpy and stop the server the ways a host does.
+"""
+
+import json
+import os
+import signal
+import subprocess
+import sys
+import time
+from pathlib import Path
+from typing import Any
+
+import pytest
+
+FIXTURES_DIR = Path(__file__).parent / "fixtures" / "python"
+
+pytestmark = pytest.mark.skipif(sys.platform == "win32", reason="needs POSIX signals")
+
+
+def _alive(pid: int) -> bool:
+    """Whether a process exists and isn't a zombie awaiting its parent."""
+    try:
+        with open(f"/proc/{pid}/stat") as f:
+            return f.read().rsplit(")", 1)[1].split()[0] != "Z"
+    except FileNotFoundError:
+        return False
+    except OSError:
+        try:
+            os.kill(pid, 0)
+        except ProcessLookupError:
+            return False
+        return True
+
+
+def _wait_gone(pids: list[int], timeout: float = 10.0) -> list[int]:
+    """Wait for processes to end; returns those still alive at the timeout."""
+    deadline = time.monotonic() + timeout
+    while (alive := [pid for pid in pids if _alive(pid)]) and time.monotonic() < deadline:
+        time.sleep(0.1)
+    return alive
+
+
+class ServerProcess:
+    """The MCP server over stdio, speaking line-delimited JSON-RPC."""
+
+    def __init__(self, data_dir: Path):
+        env = {**os.environ, "POLYBUGGER_MCP_DATA_DIR": str(data_dir)}
+        self.process = subprocess.Popen(
+            [sys.executable, "-m", "polybugger_mcp.mcp_server"],
+            stdin=subprocess.PIPE,
+            stdout=subprocess.PIPE,
+            stderr=subprocess.DEVNULL,
+            env=env,
+            text=True,
+        )
+        self._next_id = 1
+
+    def send(self, method: str, params: dict[str, Any] | None = None) -> None:
+        """Send a notification."""
+        message = {"jsonrpc": "2.0", "method": method, "params": params or {}}
+        assert self.process.stdin is not None
+        self.process.stdin.write(json.dumps(message) + "\n")
+        self.process.stdin.flush()
+
+    def request(self, method: str, params: dict[str, Any] | None = None) -> dict[str, Any]:
+        """Send a request and return its result, skipping notifications."""
+        request_id = self._next_id
+        self._next_id += 1
+        message = {"jsonrpc": "2.0", "id": request_id, "method": method, "params": params or {}}
+        assert self.process.stdin is not None and self.process.stdout is not None
+        self.process.stdin.write(json.dumps(message) + "\n")
+        self.process.stdin.flush()
+        while line := self.process.stdout.readline():
+            response = json.loads(line)
+            if response.get("id") == request_id:
+                assert "error" not in response, response
+                return response["result"]
+        raise AssertionError(f"server exited before answering {method}")
+
+    def call_tool(self, name: str, **arguments: Any) -> dict[str, Any]:
+        """Call a tool and return its structured result."""
+        result = self.request("tools/call", {"name": name, "arguments": arguments})
+        assert not result.get("isError"), result
+        return json.loads(result["content"][0]["text"])
+
+    def initialize(self) -> None:
+        """Run the MCP handshake."""
+        self.request(
+            "initialize",
+            {
+                "protocolVersion": "2024-11-05",
+                "capabilities": {},
+                "clientInfo": {"name": "shutdown-test", "version": "0"},
+            },
+        )
+        self.send("notifications/initialized")
+
+    def kill(self) -> None:
+        """Kill the server if a test left it running."""
+        if self.process.poll() is None:
+            self.process.kill()
+            self.process.wait()
+
+
+@pytest.fixture
+def server(tmp_path):
+    """Start the MCP server, initialized and ready for tool calls."""
+    server = ServerProcess(tmp_path / "data")
+    try:
+        server.initialize()
+        yield server
+    finally:
+        server.kill()
+
+
+def launch_program(server: ServerProcess, tmp_path: Path) -> list[int]:
+    """Launch the child-spawning fixture; returns the debuggee and child PIDs."""
+    pid_file = tmp_path / "pids.txt"
+    session = server.call_tool("debug_create_session", project_root=str(tmp_path))
+    assert "error" not in session, session
+    launched = server.call_tool(
+        "debug_launch",
+        program=str(FIXTURES_DIR / "spawns_child.py"),
+        args=[str(pid_file)],
+        session_id=session["session_id"],
+    )
+    assert "error" not in launched, launched
+
+    deadline = time.monotonic() + 30.0
+    while not pid_file.exists() or not pid_file.read_text().endswith("\n"):
+        assert time.monotonic() < deadline, "the program never wrote its PIDs"
+        time.sleep(0.1)
+    pids = [int(pid) for pid in pid_file.read_text().split()]
+    assert all(_alive(pid) for pid in pids)
+    return pids
+
+
+class TestServerShutdown:
+    """Tests for ending every debug session when the server stops."""
+
+    @pytest.mark.timeout(90)
+    @pytest.mark.parametrize("sig", [signal.SIGTERM, signal.SIGINT])
+    def test_signal_kills_debuggees(self, server, tmp_path, sig):
+        """Test that a signal ends the debuggee and its children, then the server."""
+        pids = launch_program(server, tmp_path)
+
+        server.process.send_signal(sig)
+
+        assert server.process.wait(timeout=30) == -sig
+        assert _wait_gone(pids) == []
+        # The session is kept for recovery on the next start
+        assert list((tmp_path / "data" / "sessions").glob("*.json"))
+
+    @pytest.mark.timeout(90)
+    def test_transport_close_kills_debuggees(self, server, tmp_path):
+        """Test that the host closing stdin ends the debuggee too."""
+        pids = launch_program(server, tmp_path)
+
+        assert server.process.stdin is not None
+        server.process.stdin.close()
+
+        server.process.wait(timeout=30)
+        assert _wait_gone(pids) == []
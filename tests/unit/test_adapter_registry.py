@@ -0,0 +1,187 @@
+"""Tests for the adapter registry and adapter selection at launch."""
+
+import pytest
+
+import polybugger_mcp.core.session as session_module
+from polybugger_mcp.adapters.base import Language
+from polybugger_mcp.adapters.factory import (
+    AdapterDescriptor,
+    AdapterRegistry,
+    UnknownAdapterError,
+    adapter_registry,
+    get_supported_languages,
+)
+from polybugger_mcp.core.exceptions import LaunchError
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.models.dap import LaunchConfig
+
+
+class FakeAdapter:
+    """Adapter stub that records its lifecycle."""
+
+    instances: list["FakeAdapter"] = []
+
+    def __init__(self, session_id, output_callback=None, event_callback=None):
+        self.initialized = False
+        self.disconnected = False
+        self.launched_with = None
+        FakeAdapter.instances.append(self)
+
+    async def initialize(self):
+        self.initialized = True
+
+    async def disconnect(self, terminate=True):
+        self.disconnected = True
+
+    async def launch(self, config, configure_callback=None):
+        self.launched_with = config
+
+
+def fake_descriptor(name, *languages, extensions=(), available=True):
+    return AdapterDescriptor(
+        name=name,
+        adapter_class=FakeAdapter,  # type: ignore[arg-type]
+        languages=languages,
+        extensions=extensions,
+        find_binary=lambda: "/bin/fake" if available else None,
+        install_hint=f"install {name}",
+    )
+
+
+@pytest.fixture
+def registry(monkeypatch):
+    """Swap the process-wide registry for one holding fake adapters."""
+    registry = AdapterRegistry()
+    registry.register(fake_descriptor("fakepy", Language.PYTHON, extensions=(".py",)))
+    registry.register(fake_descriptor("fakego", Language.GO, extensions=(".go",)))
+    monkeypatch.setattr(session_module, "adapter_registry", registry)
+    monkeypatch.setattr("polybugger_mcp.adapters.factory.adapter_registry", registry)
+    FakeAdapter.instances = []
+    return registry
+
+
+class TestBuiltinRegistry:
+    """Tests for the built-in adapter registrations."""
+
+    def test_extension_selection(self):
+        """Test that Go sources pick delve and Python sources pick debugpy."""
+        assert adapter_registry.for_file("tests/e2e/fixtures/go/simple.go").name == "delve"
+        assert adapter_registry.for_file("script.py").name == "debugpy"
+        assert adapter_registry.for_file("App.TS").name == "js-debug"
+        assert adapter_registry.for_file("main.rs").name == "codelldb"
+
+    def test_unknown_extension_lists_adapters(self):
+        """Test that an unhandled extension names the available adapters."""
+        with pytest.raises(UnknownAdapterError) as exc_info:
+            adapter_registry.for_file("notes.txt")
+
+        assert "debugpy" in exc_info.value.message
+        assert set(exc_info.value.details["available"]) >= {"debugpy", "delve"}
+
+    def test_no_extension_defers(self):
+        """Test that programs without an extension aren't matched."""
+        assert adapter_registry.for_file("./cmd/server") is None
+
+    def test_languages_from_registrations(self):
+        """Test that supported languages come from registered adapters."""
+        assert {"python", "go", "javascript", "typescript", "rust"} <= set(
+            get_supported_languages()
+        )
+
+
+class TestRegistry:
+    """Tests for registering adapters."""
+
+    def test_register_downstream_adapter(self, registry):
+        """Test that a registered adapter is found by name, language and extension."""
+        registry.register(fake_descriptor("fakerb", Language.RUBY, extensions=(".rb",)))
+
+        assert registry.get("fakerb").name == "fakerb"
+        assert registry.for_language(Language.RUBY).name == "fakerb"
+        assert registry.for_file("app.rb").name == "fakerb"
+
+    def test_first_registration_keeps_precedence(self, registry):
+        """Test that a later adapter for the same language isn't the default."""
+        registry.register(fake_descriptor("otherpy", Language.PYTHON, extensions=(".py",)))
+
+        assert registry.for_language(Language.PYTHON).name == "fakepy"
+        assert registry.for_file("x.py").name == "fakepy"
+
+    def test_unknown_name(self, registry):
+        """Test that an unknown adapter name lists the registered ones."""
+        with pytest.raises(UnknownAdapterError, match="fakepy, fakego"):
+            registry.get("gdb")
+
+
+class TestLaunchSelection:
+    """Tests for switching adapters when launching."""
+
+    @pytest.mark.asyncio
+    async def test_extension_switches_adapter(self, registry, tmp_path):
+        """Test that launching a .go file from a Python session switches to Go."""
+        session = Session(session_id="s1", project_root=tmp_path)
+        await session.initialize_adapter()
+        first = session.adapter
+
+        await session.launch(LaunchConfig(program="main.go"))
+
+        assert first.disconnected is True
+        assert session.adapter_name == "fakego"
+        assert session.language == "go"
+        assert session.adapter.launched_with.program == "main.go"
+
+    @pytest.mark.asyncio
+    async def test_matching_adapter_kept(self, registry, tmp_path):
+        """Test that a program the current adapter handles doesn't restart it."""
+        session = Session(session_id="s1", project_root=tmp_path)
+        await session.initialize_adapter()
+
+        await session.launch(LaunchConfig(program="app.py"))
+
+        assert len(FakeAdapter.instances) == 1
+        assert session.adapter_name == "fakepy"
+
+    @pytest.mark.asyncio
+    async def test_unknown_extension_keeps_adapter(self, registry, tmp_path):
+        """Test that native binaries with arbitrary extensions use the session's adapter."""
+        session = Session(session_id="s1", project_root=tmp_path)
+        await session.initialize_adapter("fakego")
+
+        await session.launch(LaunchConfig(program="./pkg.test"))
+
+        assert len(FakeAdapter.instances) == 1
+        assert session.adapter_name == "fakego"
+        assert session.adapter.launched_with.program == "./pkg.test"
+
+    @pytest.mark.asyncio
+    async def test_unknown_extension_without_adapter(self, registry, tmp_path):
+        """Test that an unknown extension fails when no adapter is loaded yet."""
+        session = Session(session_id="s1", project_root=tmp_path)
+
+        with pytest.raises(UnknownAdapterError, match="'.exe'"):
+            await session.launch(LaunchConfig(program="app.exe"))
+
+    @pytest.mark.asyncio
+    async def test_explicit_adapter_wins(self, registry, tmp_path):
+        """Test that an explicit adapter overrides the extension."""
+        session = Session(session_id="s1", project_root=tmp_path)
+        await session.initialize_adapter()
+
+        await session.launch(LaunchConfig(program="./cmd/server", adapter="fakego"))
+
+        assert session.adapter_name == "fakego"
+
+    @pytest.mark.asyncio
+    async def test_missing_binary_keeps_session(self, registry, tmp_path):
+        """Test that an uninstalled adapter fails before the current one is dropped."""
+        registry.register(
+            fake_descriptor("fakejs", Language.JAVASCRIPT, extensions=(".js",), available=False)
+        )
+        session = Session(session_id="s1", project_root=tmp_path)
+        await session.initialize_adapter()
+
+        with pytest.raises(LaunchError, match="install fakejs"):
+            await session.launch(LaunchConfig(program="app.js"))
+
+        assert session.adapter_name == "fakepy"
+        assert session.adapter.disconnected is False
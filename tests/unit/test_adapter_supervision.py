@@ -0,0 +1,230 @@
+"""Tests for detecting a dead debug adapter and cleaning up its processes."""
+
+import asyncio
+import sys
+from collections import deque
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.adapters.dap_client import DAPClient
+from polybugger_mcp.core.exceptions import (
+    AdapterExitedError,
+    DAPConnectionError,
+    DAPTimeoutError,
+)
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.events import EventType
+from polybugger_mcp.utils import processes
+
+
+class NullWriter:
+    """Stream writer stub discarding what is written."""
+
+    def write(self, data):
+        pass
+
+    async def drain(self):
+        pass
+
+    def close(self):
+        pass
+
+    async def wait_closed(self):
+        pass
+
+
+class SupervisedAdapter:
+    """Adapter stub using the base class process supervision."""
+
+    STDERR_TAIL_LINES = DebugAdapter.STDERR_TAIL_LINES
+    LOCAL_CONNECT_TIMEOUT = DebugAdapter.LOCAL_CONNECT_TIMEOUT
+    _supervise = DebugAdapter._supervise
+    _drain_stderr = DebugAdapter._drain_stderr
+    _stop_adapter_process = DebugAdapter._stop_adapter_process
+    _connection_lost = DebugAdapter._connection_lost
+    _connect_local = DebugAdapter._connect_local
+
+    def __init__(self):
+        self.stderr_tail = deque(maxlen=self.STDERR_TAIL_LINES)
+        self._stderr_task = None
+        self._process = None
+        self._exit_reported = False
+        self._client = None
+        self.events: list[tuple[EventType, dict]] = []
+
+    async def _event_callback(self, event_type, data):
+        self.events.append((event_type, data))
+
+
+class TestDAPClientDisconnect:
+    """Tests for DAPClient noticing the adapter's stream end."""
+
+    @pytest.mark.asyncio
+    async def test_eof_fails_pending_request(self):
+        """Test that a request waiting on a dead adapter fails without timing out."""
+        reader = asyncio.StreamReader()
+        reasons: list[str] = []
+
+        async def lost(reason):
+            reasons.append(reason)
+
+        client = DAPClient(reader, NullWriter(), disconnect_callback=lost)  # type: ignore[arg-type]
+        await client.start()
+        request = asyncio.create_task(client.send_request("threads"))
+        await asyncio.sleep(0)
+
+        reader.feed_eof()
+        with pytest.raises(AdapterExitedError):
+            await asyncio.wait_for(request, timeout=1.0)
+
+        assert reasons == ["the adapter closed the connection"]
+        assert not client.is_connected
+        with pytest.raises(AdapterExitedError):
+            await client.send_request("threads")
+
+    @pytest.mark.asyncio
+    async def test_eof_after_disconnect_expected(self):
+        """Test that the adapter hanging up after a disconnect request is normal."""
+        reader = asyncio.StreamReader()
+        reasons: list[str] = []
+
+        async def lost(reason):
+            reasons.append(reason)
+
+        client = DAPClient(reader, NullWriter(), disconnect_callback=lost)  # type: ignore[arg-type]
+        await client.start()
+        request = asyncio.create_task(client.send_request("disconnect", timeout=0.2))
+        await asyncio.sleep(0)
+
+        reader.feed_eof()
+        with pytest.raises(DAPTimeoutError):
+            await request
+        await client.stop()
+
+        assert reasons == []
+
+
+class TestConnectionLost:
+    """Tests for the adapter's crash report."""
+
+    @pytest.mark.asyncio
+    async def test_report_has_exit_code_and_stderr(self):
+        """Test that the report quotes the exit code and the last stderr line."""
+        adapter = SupervisedAdapter()
+        process = await asyncio.create_subprocess_exec(
+            sys.executable,
+            "-c",
+            "import sys; sys.stderr.write('fatal: out of memory\\n'); sys.exit(3)",
+            stderr=asyncio.subprocess.PIPE,
+            start_new_session=True,
+        )
+        adapter._supervise(process)
+
+        await adapter._connection_lost("the adapter closed the connection")
+
+        event_type, data = adapter.events[0]
+        assert event_type == EventType.SESSION_ENDED
+        assert data["exit_code"] == 3
+        assert data["stderr"] == ["fatal: out of memory"]
+        assert "exit code 3" in data["message"]
+        await adapter._stop_adapter_process()
+        assert process.pid not in processes._process_groups
+
+    @pytest.mark.asyncio
+    async def test_stop_process_terminates(self):
+        """Test that a running adapter process is stopped and unregistered."""
+        adapter = SupervisedAdapter()
+        process = await asyncio.create_subprocess_exec(
+            sys.executable,
+            "-c",
+            "import time; time.sleep(60)",
+            start_new_session=True,
+        )
+        adapter._supervise(process)
+
+        await adapter._stop_adapter_process()
+
+        assert process.returncode is not None
+        assert process.pid not in processes._process_groups
+
+
+class TestConnectLocal:
+    """Tests for connecting to an adapter process just started."""
+
+    @pytest.mark.asyncio
+    async def test_exit_before_listening_reported(self):
+        """Test that an adapter dying before it listens fails at once with its stderr."""
+        adapter = SupervisedAdapter()
+        process = await asyncio.create_subprocess_exec(
+            sys.executable,
+            "-c",
+            "import sys; sys.stderr.write('No module named debugpy\\n'); sys.exit(1)",
+            stderr=asyncio.subprocess.PIPE,
+            start_new_session=True,
+        )
+        adapter._supervise(process)
+
+        with pytest.raises(DAPConnectionError, match="exited with code 1: No module named"):
+            await asyncio.wait_for(adapter._connect_local(9, "debugpy"), timeout=5.0)
+        await adapter._stop_adapter_process()
+
+    @pytest.mark.asyncio
+    async def test_connects_while_running(self):
+        """Test that the connection to a running adapter is returned."""
+        adapter = SupervisedAdapter()
+        accepted = asyncio.Event()
+        server = await asyncio.start_server(
+            lambda reader, writer: accepted.set(), "127.0.0.1", 0
+        )
+        port = server.sockets[0].getsockname()[1]
+        process = await asyncio.create_subprocess_exec(
+            sys.executable, "-c", "import time; time.sleep(60)", start_new_session=True
+        )
+        adapter._supervise(process)
+
+        async with server:
+            _, writer = await adapter._connect_local(port, "dlv")
+            await asyncio.wait_for(accepted.wait(), timeout=5.0)
+            writer.close()
+        await adapter._stop_adapter_process()
+
+
+class TestSessionEnded:
+    """Tests for Session handling of a dead adapter."""
+
+    EXIT = {
+        "message": "Debug adapter exited unexpectedly: the adapter closed the connection "
+        "(exit code -9)",
+        "reason": "the adapter closed the connection",
+        "exit_code": -9,
+        "stderr": [],
+    }
+
+    @pytest.fixture
+    def session(self, tmp_path):
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session._state = SessionState.RUNNING
+        return session
+
+    @pytest.mark.asyncio
+    async def test_running_session_terminated(self, session):
+        """Test that the session ends, queues sessionEnded and logs the reason."""
+        await session._handle_event(EventType.SESSION_ENDED, self.EXIT)
+
+        assert session.state == SessionState.TERMINATED
+        events = await session.event_queue.get_all()
+        assert [e.type for e in events] == [EventType.SESSION_ENDED]
+        assert "exit code -9" in session.output_buffer.get_page(0, 10).lines[0].content
+        result = await session.wait_for_stop(0, timeout=0.1)
+        assert result["adapter_exit"]["exit_code"] == -9
+
+    @pytest.mark.asyncio
+    async def test_after_program_end_only_recorded(self, session):
+        """Test that an adapter exiting after the program ended is not an error."""
+        session._state = SessionState.TERMINATED
+
+        await session._handle_event(EventType.SESSION_ENDED, self.EXIT)
+
+        assert session.adapter_exit == self.EXIT
+        assert await session.event_queue.get_all() == []
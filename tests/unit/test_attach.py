@@ -0,0 +1,123 @@
+"""Tests for attaching to running processes."""
+
+import asyncio
+import os
+import subprocess
+import sys
+
+import pytest
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.core.exceptions import LaunchError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import AttachConfig
+
+
+class DisconnectRecorder:
+    """Adapter stub recording how it was disconnected."""
+
+    def __init__(self):
+        self.terminate: bool | None = None
+
+    async def disconnect(self, terminate: bool = False):
+        self.terminate = terminate
+
+
+class AttachingAdapter(DisconnectRecorder):
+    """Adapter stub that attaches successfully."""
+
+    is_launched = False
+
+    async def attach(self, config, configure_callback=None, **kwargs):
+        if configure_callback:
+            await configure_callback()
+        self.is_launched = True
+
+
+class HangingAttachAdapter(DisconnectRecorder):
+    """Adapter stub whose attach never completes, as with nothing listening."""
+
+    async def attach(self, config, configure_callback=None, **kwargs):
+        await asyncio.Event().wait()
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session for testing."""
+    return Session(session_id="test_session", project_root=tmp_path)
+
+
+def _unused_pid() -> int:
+    """Return the PID of a process that has already exited."""
+    proc = subprocess.Popen([sys.executable, "-c", "pass"])
+    proc.wait()
+    return proc.pid
+
+
+class TestAttach:
+    """Tests for the attach lifecycle."""
+
+    @pytest.mark.asyncio
+    async def test_attached_session_leaves_target_running(self, session):
+        """Test that ending an attached session doesn't terminate the debuggee."""
+        adapter = AttachingAdapter()
+        session.adapter = adapter
+
+        await session.attach(AttachConfig(port=5678))
+        assert session.attached is True
+        assert session.state == SessionState.RUNNING
+
+        await session.cleanup()
+        assert adapter.terminate is False
+
+    @pytest.mark.asyncio
+    async def test_attached_session_explicit_terminate(self, session):
+        """Test that termination can be requested for attached sessions."""
+        adapter = AttachingAdapter()
+        session.adapter = adapter
+        await session.attach(AttachConfig(port=5678))
+
+        await session.cleanup(terminate_debuggee=True)
+        assert adapter.terminate is True
+
+    @pytest.mark.asyncio
+    async def test_launched_session_terminates_target(self, session):
+        """Test that launched sessions still terminate the debuggee by default."""
+        adapter = DisconnectRecorder()
+        session.adapter = adapter
+
+        await session.cleanup()
+        assert adapter.terminate is True
+
+    @pytest.mark.skipif(sys.platform == "win32", reason="PID probe is POSIX only")
+    @pytest.mark.asyncio
+    async def test_attach_missing_pid_fails_fast(self, session):
+        """Test that attaching to a nonexistent PID gives a clear error."""
+        session.adapter = AttachingAdapter()
+
+        with pytest.raises(LaunchError, match="No process with PID"):
+            await session.attach(AttachConfig(process_id=_unused_pid()))
+        assert session.state == SessionState.FAILED
+
+    @pytest.mark.skipif(
+        sys.platform == "win32" or os.geteuid() == 0,
+        reason="Needs a non-root POSIX user",
+    )
+    @pytest.mark.asyncio
+    async def test_attach_foreign_pid_permission_error(self, session):
+        """Test that attaching to another user's process reports permissions."""
+        session.adapter = AttachingAdapter()
+
+        with pytest.raises(LaunchError, match="Permission denied"):
+            await session.attach(AttachConfig(process_id=1))
+
+    @pytest.mark.asyncio
+    async def test_attach_times_out(self, session, monkeypatch):
+        """Test that an attach nobody answers fails after the attach timeout."""
+        monkeypatch.setattr(settings, "dap_attach_timeout_seconds", 0.2)
+        monkeypatch.setattr(settings, "dap_launch_timeout_seconds", 60.0)
+        session.adapter = HangingAttachAdapter()
+
+        with pytest.raises(LaunchError, match="Timed out attaching to localhost:5678"):
+            await asyncio.wait_for(session.attach(AttachConfig(port=5678)), timeout=5.0)
+        assert session.state == SessionState.FAILED
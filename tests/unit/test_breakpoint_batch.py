@@ -0,0 +1,72 @@
+"""Tests for adding breakpoints to a file's existing set."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import DAPError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, SourceBreakpoint
+
+
+class FileAdapter:
+    """Adapter stub recording each file's breakpoint lines as sent."""
+
+    is_launched = True
+
+    def __init__(self):
+        self.sent: list[list[int]] = []
+        self.failing = False
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        if self.failing:
+            raise DAPError(code="DAP_REQUEST_FAILED", message="source not loaded")
+        lines = [bp.line for bp in breakpoints if bp.enabled]
+        self.sent.append(lines)
+        return [Breakpoint(id=line, verified=line != 13, line=line) for line in lines]
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a running session with breakpoints on lines 3 and 5."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = FileAdapter()  # type: ignore[assignment]
+    session._state = SessionState.RUNNING
+    path = str(tmp_path / "app.py")
+    session._breakpoints[path] = [
+        SourceBreakpoint(line=3),
+        SourceBreakpoint(line=5, enabled=False),
+    ]
+    return session, path
+
+
+class TestAddBreakpoints:
+    """Tests for Session.add_breakpoints."""
+
+    @pytest.mark.asyncio
+    async def test_merges_with_existing(self, session):
+        """Test that existing breakpoints are kept and same-line ones replaced."""
+        session, path = session
+
+        results = await session.add_breakpoints(
+            path, [SourceBreakpoint(line=13), SourceBreakpoint(line=3, condition="n > 2")]
+        )
+
+        assert [r.line for r in results] == [13, 3]
+        assert results[0].verified is False
+        assert session.adapter.sent == [[3, 13]]
+        stored = session._breakpoints[path]
+        assert [(bp.line, bp.condition, bp.enabled) for bp in stored] == [
+            (3, "n > 2", True),
+            (5, None, False),
+            (13, None, True),
+        ]
+
+    @pytest.mark.asyncio
+    async def test_failure_keeps_previous(self, session):
+        """Test that a refused request leaves the file's stored breakpoints alone."""
+        session, path = session
+        session.adapter.failing = True
+
+        with pytest.raises(DAPError):
+            await session.add_breakpoints(path, [SourceBreakpoint(line=8)])
+
+        assert [bp.line for bp in session._breakpoints[path]] == [3, 5]
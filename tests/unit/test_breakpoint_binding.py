@@ -0,0 +1,102 @@
+"""Tests for where adapters bind breakpoints and later verification."""
+
+import pytest
+
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a running session with one moved and one pending breakpoint."""
+    session = Session(session_id="test_session", project_root=tmp_path, language="go")
+    session._state = SessionState.RUNNING
+    path = str(tmp_path / "main.go")
+    breakpoints = [SourceBreakpoint(line=4), SourceBreakpoint(line=12)]
+    session._breakpoints[path] = breakpoints
+    session._record_breakpoint_results(
+        path,
+        breakpoints,
+        [
+            Breakpoint(id=1, verified=True, line=6, column=2),
+            Breakpoint(id=2, verified=False, message="could not find main.go:12"),
+        ],
+    )
+    return session, path
+
+
+class TestBinding:
+    """Tests for requested versus bound locations."""
+
+    def test_describe_requested_and_bound(self, session):
+        """Test that listed breakpoints show both locations."""
+        session, path = session
+
+        moved, pending = session.describe_breakpoints()[path]
+
+        assert (moved["line"], moved["bound_line"], moved["bound_column"]) == (4, 6, 2)
+        assert moved["moved"] is True
+        assert moved["verified"] is True
+        assert (pending["line"], pending["bound_line"], pending["moved"]) == (12, None, False)
+        assert pending["message"] == "could not find main.go:12"
+
+
+class TestBreakpointEvents:
+    """Tests for breakpoint events changing verification later."""
+
+    @pytest.mark.asyncio
+    async def test_pending_breakpoint_verified(self, session):
+        """Test that a late bind updates the record and notifies once."""
+        session, path = session
+
+        await session._handle_event(
+            EventType.BREAKPOINT,
+            {"reason": "changed", "breakpoint": {"id": 2, "verified": True, "line": 13}},
+        )
+
+        pending = session.describe_breakpoints()[path][1]
+        assert (pending["verified"], pending["bound_line"], pending["moved"]) == (True, 13, True)
+        assert pending["message"] is None
+        events = await session.event_queue.get_all()
+        assert [e.type for e in events] == [EventType.BREAKPOINT, EventType.BREAKPOINT_VERIFIED]
+        assert events[1].data == {
+            "id": 2,
+            "file": path,
+            "line": 12,
+            "verified": True,
+            "bound_line": 13,
+            "bound_column": None,
+            "moved": True,
+            "message": None,
+        }
+
+    @pytest.mark.asyncio
+    async def test_change_to_verified_breakpoint(self, session):
+        """Test that moving a verified breakpoint doesn't notify verification."""
+        session, path = session
+
+        await session._handle_event(
+            EventType.BREAKPOINT,
+            {"reason": "changed", "breakpoint": {"id": 1, "verified": True, "line": 7}},
+        )
+
+        assert session.describe_breakpoints()[path][0]["bound_line"] == 7
+        events = await session.event_queue.get_all()
+        assert [e.type for e in events] == [EventType.BREAKPOINT]
+
+    @pytest.mark.asyncio
+    async def test_removed_and_unknown(self, session):
+        """Test that removals drop the adapter state and foreign ids are ignored."""
+        session, path = session
+
+        await session._handle_event(
+            EventType.BREAKPOINT, {"reason": "removed", "breakpoint": {"id": 1}}
+        )
+        await session._handle_event(
+            EventType.BREAKPOINT, {"reason": "new", "breakpoint": {"id": 99, "verified": True}}
+        )
+
+        moved = session.describe_breakpoints()[path][0]
+        assert (moved["verified"], moved["bound_line"]) == (False, None)
+        assert 99 not in session._breakpoint_ids
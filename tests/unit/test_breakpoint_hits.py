@@ -0,0 +1,150 @@
+"""Tests for breakpoint hit counting."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, Source, SourceBreakpoint, StackFrame
+from polybugger_mcp.models.events import EventType
+
+
+class StackOnlyAdapter:
+    """Adapter stub that only answers stack trace requests."""
+
+    def __init__(self, path: str, line: int):
+        self.path = path
+        self.line = line
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=1, name="f", source=Source(path=self.path), line=self.line)]
+
+
+@pytest.fixture
+async def session(tmp_path):
+    """Create a paused-capable session with two breakpoints recorded."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._state = SessionState.RUNNING
+    path = str(tmp_path / "app.py")
+    breakpoints = [SourceBreakpoint(line=5), SourceBreakpoint(line=6)]
+    session._breakpoints[path] = breakpoints
+    # Both requested lines were moved by the adapter onto line 6
+    session._record_breakpoint_results(
+        path,
+        breakpoints,
+        [Breakpoint(id=1, verified=True, line=6), Breakpoint(id=2, verified=True, line=6)],
+    )
+    return session, path
+
+
+class TestBreakpointHitCounts:
+    """Tests for correlating stopped events with breakpoints."""
+
+    @pytest.mark.asyncio
+    async def test_hit_breakpoint_ids_increment(self, session):
+        """Test that hitBreakpointIds increments the matching breakpoint."""
+        session, path = session
+        await session._handle_event(
+            EventType.STOPPED, {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [2]}
+        )
+
+        described = session.describe_breakpoints()[path]
+        assert described[0]["hit_count"] == 0
+        assert described[1]["hit_count"] == 1
+        assert described[1]["id"] == 2
+
+    @pytest.mark.asyncio
+    async def test_shared_line_counts_all_ids(self, session):
+        """Test that several breakpoints reported for one stop are all counted."""
+        session, path = session
+        await session._handle_event(
+            EventType.STOPPED,
+            {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [1, 2]},
+        )
+
+        described = session.describe_breakpoints()[path]
+        assert [bp["hit_count"] for bp in described] == [1, 1]
+
+    @pytest.mark.asyncio
+    async def test_step_stop_not_counted(self, session):
+        """Test that non-breakpoint stops leave counters untouched."""
+        session, path = session
+        await session._handle_event(
+            EventType.STOPPED, {"reason": "step", "threadId": 1, "hitBreakpointIds": [1]}
+        )
+
+        assert all(bp["hit_count"] == 0 for bp in session.describe_breakpoints()[path])
+
+    @pytest.mark.asyncio
+    async def test_location_fallback_without_ids(self, session):
+        """Test that stops without hitBreakpointIds are matched by location."""
+        session, path = session
+        session.adapter = StackOnlyAdapter(path, 6)
+
+        await session._handle_event(EventType.STOPPED, {"reason": "breakpoint", "threadId": 1})
+        await asyncio.gather(*session._background_tasks)
+
+        described = session.describe_breakpoints()[path]
+        assert [bp["hit_count"] for bp in described] == [1, 1]
+
+    @pytest.mark.asyncio
+    async def test_location_hits_counted_before_publish(self, session, monkeypatch):
+        """Test that a stop matched by location is queued with its hits already counted."""
+        session, path = session
+        session.adapter = StackOnlyAdapter(path, 6)
+        counts_when_queued = []
+        put = session.event_queue.put
+
+        async def recording_put(event_type, data):
+            counts_when_queued.append(dict(session._hit_counts))
+            await put(event_type, data)
+
+        monkeypatch.setattr(session.event_queue, "put", recording_put)
+        await session._handle_event(EventType.STOPPED, {"reason": "breakpoint", "threadId": 1})
+        await asyncio.gather(*session._background_tasks)
+
+        assert counts_when_queued == [{(path, 5): 1, (path, 6): 1}]
+
+    @pytest.mark.asyncio
+    async def test_location_hits_counted_before_waiters_wake(self, session, monkeypatch):
+        """Test that stop waiters are only notified once location-matched hits are counted."""
+        session, path = session
+        session.adapter = StackOnlyAdapter(path, 6)
+        counts_when_notified = []
+        notify_all = session._stop_changed.notify_all
+
+        def recording_notify_all():
+            counts_when_notified.append(dict(session._hit_counts))
+            notify_all()
+
+        monkeypatch.setattr(session._stop_changed, "notify_all", recording_notify_all)
+        await session._handle_event(EventType.STOPPED, {"reason": "breakpoint", "threadId": 1})
+        await asyncio.gather(*session._background_tasks)
+
+        assert counts_when_notified == [{(path, 5): 1, (path, 6): 1}]
+
+    @pytest.mark.asyncio
+    async def test_reset_hit_counts(self, session):
+        """Test that reset reports current counts and then zeroes them."""
+        session, path = session
+        await session._handle_event(
+            EventType.STOPPED, {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [1]}
+        )
+
+        reported = session.describe_breakpoints(reset_hit_counts=True)[path]
+        assert reported[0]["hit_count"] == 1
+        assert session.describe_breakpoints()[path][0]["hit_count"] == 0
+
+    def test_resend_keeps_counts_for_remaining_lines(self, session):
+        """Test that resending a file keeps counts of breakpoints still requested."""
+        session, path = session
+        session._hit_counts[(path, 5)] = 3
+        session._hit_counts[(path, 6)] = 4
+
+        remaining = [SourceBreakpoint(line=5)]
+        session._record_breakpoint_results(
+            path, remaining, [Breakpoint(id=7, verified=True, line=5)]
+        )
+
+        assert session._hit_counts == {(path, 5): 3}
+        assert session._breakpoint_ids == {7: (path, 5)}
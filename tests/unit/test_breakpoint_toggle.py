@@ -0,0 +1,97 @@
+"""Tests for enabling and disabling breakpoints without removing them."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import BreakpointNotFoundError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+class RenumberingAdapter:
+    """Adapter stub that numbers breakpoints afresh on every send, like debugpy."""
+
+    is_launched = True
+
+    def __init__(self):
+        self.next_id = 1
+        self.sent: list[list[int]] = []
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        enabled = [bp for bp in breakpoints if bp.enabled]
+        self.sent.append([bp.line for bp in enabled])
+        results = []
+        for bp in enabled:
+            results.append(Breakpoint(id=self.next_id, verified=True, line=bp.line))
+            self.next_id += 1
+        return results
+
+
+@pytest.fixture
+async def session(tmp_path):
+    """Create a running session with three breakpoints in one file."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = RenumberingAdapter()  # type: ignore[assignment]
+    session._state = SessionState.RUNNING
+    path = str(tmp_path / "app.py")
+    await session.set_breakpoints(path, [SourceBreakpoint(line=n) for n in (3, 5, 7)])
+    return session, path
+
+
+class TestBreakpointToggle:
+    """Tests for Session.set_breakpoints_enabled."""
+
+    @pytest.mark.asyncio
+    async def test_disable_by_id_keeps_ids_and_counts(self, session):
+        """Test that a resend leaves ids and hit counts of every breakpoint alone."""
+        session, path = session
+        await session._handle_event(
+            EventType.STOPPED, {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [1, 3]}
+        )
+
+        changed = await session.set_breakpoints_enabled(False, ids=[1])
+
+        assert changed == [{"file": path, "line": 3, "id": 1}]
+        assert session.adapter.sent[-1] == [5, 7]
+        described = session.describe_breakpoints()[path]
+        assert [bp["id"] for bp in described] == [1, 2, 3]
+        assert [bp["enabled"] for bp in described] == [False, True, True]
+        assert [bp["hit_count"] for bp in described] == [1, 0, 1]
+        assert described[0]["verified"] is False
+
+        # The adapter's new ids still count hits against the right breakpoint
+        await session._handle_event(
+            EventType.STOPPED, {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [5]}
+        )
+        assert session.describe_breakpoints()[path][2]["hit_count"] == 2
+
+    @pytest.mark.asyncio
+    async def test_enable_file_resends(self, session):
+        """Test that re-enabling resends the file and restores the original id."""
+        session, path = session
+        await session.set_breakpoints_enabled(False)
+        assert session.adapter.sent[-1] == []
+
+        changed = await session.set_breakpoints_enabled(True, file_path=path)
+
+        assert [bp["id"] for bp in changed] == [1, 2, 3]
+        assert session.adapter.sent[-1] == [3, 5, 7]
+        assert all(bp["verified"] for bp in session.describe_breakpoints()[path])
+
+    @pytest.mark.asyncio
+    async def test_unchanged_files_not_resent(self, session):
+        """Test that breakpoints already in the requested state cause no resend."""
+        session, _ = session
+        sends = len(session.adapter.sent)
+
+        assert await session.set_breakpoints_enabled(True) == []
+        assert len(session.adapter.sent) == sends
+
+    @pytest.mark.asyncio
+    async def test_unknown_id(self, session):
+        """Test that an id naming no breakpoint is refused before anything changes."""
+        session, path = session
+
+        with pytest.raises(BreakpointNotFoundError):
+            await session.set_breakpoints_enabled(False, ids=[2, 99])
+        assert all(bp.enabled for bp in session._breakpoints[path])
@@ -0,0 +1,82 @@
+"""Tests for expression completions."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.exceptions import FrameNotFoundError, InvalidSessionStateError
+from polybugger_mcp.core.session import Session, SessionState
+
+
+class CompletionsAdapter:
+    """Adapter stub answering Completions requests."""
+
+    get_completions = DebugAdapter.get_completions
+
+    def __init__(self, supported: bool = True):
+        self.capabilities = {"supportsCompletionsRequest": supported}
+        self.requests: list[tuple[str, dict]] = []
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append((command, arguments))
+        return {
+            "targets": [
+                {"label": "headers", "type": "property", "sortText": "b"},
+                {"label": "__class__", "type": "property", "sortText": "z"},
+                {"label": "get", "type": "method", "sortText": "a"},
+            ]
+        }
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = CompletionsAdapter()
+    session._state = SessionState.PAUSED
+    return session
+
+
+class TestGetCompletions:
+    """Tests for Session.get_completions."""
+
+    @pytest.mark.asyncio
+    async def test_cursor_defaults_to_end(self, session):
+        """Test that the column defaults to just past the text (1-based)."""
+        await session.get_completions("request.")
+
+        assert session.adapter.requests == [("completions", {"text": "request.", "column": 9})]
+
+    @pytest.mark.asyncio
+    async def test_frame_and_sort_order(self, session):
+        """Test that the frame is passed and items follow sortText."""
+        session._frame_ids.add(5)
+
+        items = await session.get_completions("request.h", column=9, frame_id=5)
+
+        assert session.adapter.requests[0][1] == {"text": "request.h", "column": 9, "frameId": 5}
+        assert [i["label"] for i in items] == ["get", "headers", "__class__"]
+
+    @pytest.mark.asyncio
+    async def test_unsupported_returns_empty(self, session):
+        """Test that adapters without completions return nothing without a request."""
+        session.adapter = CompletionsAdapter(supported=False)
+
+        assert await session.get_completions("x.") == []
+        assert session.adapter.requests == []
+
+    @pytest.mark.asyncio
+    async def test_requires_pause(self, session):
+        """Test that completions need a stopped program."""
+        session._state = SessionState.RUNNING
+
+        with pytest.raises(InvalidSessionStateError):
+            await session.get_completions("x.")
+
+    @pytest.mark.asyncio
+    async def test_stale_frame(self, session):
+        """Test that frames from before a resume are refused."""
+        session._frame_ids.add(5)
+        session._invalidate_variables()
+
+        with pytest.raises(FrameNotFoundError):
+            await session.get_completions("x.", frame_id=5)
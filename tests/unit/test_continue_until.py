@@ -0,0 +1,101 @@
+"""Tests for continuing until an expression is true."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.exceptions import DAPError, NoBreakpointsError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, Source, SourceBreakpoint, StackFrame
+from polybugger_mcp.models.events import EventType
+
+
+class LoopAdapter:
+    """Adapter stub for a loop that stops at each probe with i one larger."""
+
+    is_launched = True
+
+    def __init__(self, session: Session):
+        self.session = session
+        self.capabilities: dict = {}
+        self.i = 0
+        self.sent: list[list[int]] = []
+        self.failing = False
+        self.events: list[asyncio.Task] = []
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        self.sent.append([bp.line for bp in breakpoints])
+        return [Breakpoint(id=bp.line, verified=True, line=bp.line) for bp in breakpoints]
+
+    async def continue_execution(self, thread_id=None):
+        self.i += 1
+        stopped = self.session._handle_event(
+            EventType.STOPPED, {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [12]}
+        )
+        self.events.append(asyncio.create_task(stopped))
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=100 + self.i, name="work", line=12, source=Source(path="/app/w.py"))]
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        if self.failing:
+            raise DAPError(code="DAP_REQUEST_FAILED", message="name 'queue' is not defined")
+        assert expression == "i > 3"
+        return {"result": str(self.i > 3), "variablesReference": 0}
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session paused in the loop."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = LoopAdapter(session)  # type: ignore[assignment]
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestContinueUntil:
+    """Tests for Session.continue_until."""
+
+    @pytest.mark.asyncio
+    async def test_stops_when_true(self, session):
+        """Test that false stops are continued and probes removed afterwards."""
+        session._breakpoints["/app/w.py"] = [SourceBreakpoint(line=30)]
+
+        result = await session.continue_until("i > 3", probes={"/app/w.py": [12]})
+
+        assert result["status"] == "stopped"
+        assert result["condition_met"] is True
+        assert result["auto_continues"] == 3
+        assert session.state == SessionState.PAUSED
+        assert session.adapter.sent == [[30, 12], [30]]
+
+    @pytest.mark.asyncio
+    async def test_max_iterations(self, session):
+        """Test that a never-true condition stops after max_iterations continues."""
+        session._breakpoints["/app/w.py"] = [SourceBreakpoint(line=12)]
+
+        result = await session.continue_until("i > 3", max_iterations=2)
+
+        assert result["status"] == "max_iterations"
+        assert result["condition_met"] is False
+        assert result["auto_continues"] == 2
+        assert session.adapter.sent == []
+
+    @pytest.mark.asyncio
+    async def test_evaluation_errors_counted(self, session):
+        """Test that failed evaluations count as false and are reported."""
+        session.adapter.failing = True
+
+        result = await session.continue_until(
+            "queue.size() > 100", probes={"/app/w.py": [12]}, max_iterations=1
+        )
+
+        assert result["evaluation_errors"] == 2
+        assert result["last_error"] == "name 'queue' is not defined"
+
+    @pytest.mark.asyncio
+    async def test_needs_somewhere_to_stop(self, session):
+        """Test that running without probes or breakpoints is refused."""
+        with pytest.raises(NoBreakpointsError):
+            await session.continue_until("i > 3")
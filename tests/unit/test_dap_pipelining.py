@@ -0,0 +1,164 @@
+"""Tests for concurrent DAP requests and the ordering the session keeps."""
+
+import asyncio
+import json
+
+import pytest
+import pytest_asyncio
+
+from polybugger_mcp.adapters.dap_client import DAPClient
+from polybugger_mcp.core.exceptions import DAPClientClosedError, InvalidSessionStateError
+from polybugger_mcp.core.session import Session, SessionState
+
+
+class RecordingWriter:
+    """Stream writer stub keeping each written DAP message."""
+
+    def __init__(self):
+        self.data = b""
+
+    def write(self, data):
+        self.data += data
+
+    async def drain(self):
+        await asyncio.sleep(0)
+
+    def close(self):
+        pass
+
+    async def wait_closed(self):
+        pass
+
+    @property
+    def messages(self) -> list[dict]:
+        parts = self.data.split(b"Content-Length: ")[1:]
+        return [json.loads(p.split(b"\r\n\r\n", 1)[1]) for p in parts]
+
+
+def _response(request_seq: int, command: str, body: dict) -> bytes:
+    content = json.dumps(
+        {
+            "seq": 100 + request_seq,
+            "type": "response",
+            "request_seq": request_seq,
+            "command": command,
+            "success": True,
+            "body": body,
+        }
+    ).encode()
+    return b"Content-Length: %d\r\n\r\n" % len(content) + content
+
+
+@pytest_asyncio.fixture
+async def client():
+    """Start a client over an in-memory stream."""
+    reader = asyncio.StreamReader()
+    writer = RecordingWriter()
+    client = DAPClient(reader, writer)  # type: ignore[arg-type]
+    await client.start()
+    yield client, reader, writer
+    await client.stop()
+
+
+class TestPipelining:
+    """Tests for requests in flight at the same time."""
+
+    @pytest.mark.asyncio
+    async def test_responses_matched_by_seq(self, client):
+        """Test that a fast request completes while a slow one is outstanding."""
+        client, reader, writer = client
+        slow = asyncio.create_task(client.send_request("variables", {"variablesReference": 7}))
+        pause = asyncio.create_task(client.send_request("pause", {"threadId": 1}))
+        await asyncio.sleep(0.01)
+
+        reader.feed_data(_response(2, "pause", {}))
+        assert await asyncio.wait_for(pause, timeout=1.0) == {}
+        assert not slow.done()
+
+        reader.feed_data(_response(1, "variables", {"variables": []}))
+        assert await asyncio.wait_for(slow, timeout=1.0) == {"variables": []}
+        assert [m["command"] for m in writer.messages] == ["variables", "pause"]
+
+    @pytest.mark.asyncio
+    async def test_cancelled_request_not_sent(self, client):
+        """Test that a request given up before its turn is never written."""
+        client, _, writer = client
+        first = asyncio.create_task(client.send_request("threads"))
+        second = asyncio.create_task(client.send_request("stackTrace"))
+        await asyncio.sleep(0)
+        second.cancel()
+        await asyncio.sleep(0.01)
+
+        assert [m["command"] for m in writer.messages] == ["threads"]
+        assert not first.done()
+        first.cancel()
+
+    @pytest.mark.asyncio
+    async def test_stop_fails_outstanding(self, client):
+        """Test that closing the client fails every outstanding request."""
+        client, _, _ = client
+        requests = [
+            asyncio.create_task(client.send_request(command))
+            for command in ("variables", "evaluate")
+        ]
+        await asyncio.sleep(0.01)
+
+        await client.stop()
+
+        for task, command in zip(requests, ("variables", "evaluate")):
+            with pytest.raises(DAPClientClosedError, match=command):
+                await task
+
+    @pytest.mark.asyncio
+    async def test_request_after_stop_fails(self, client):
+        """Test that a request sent once the client is stopped fails at once."""
+        client, reader, writer = client
+        request = asyncio.create_task(client.send_request("initialize"))
+        await asyncio.sleep(0.01)
+        reader.feed_data(_response(1, "initialize", {}))
+        await request
+        await client.stop()
+
+        with pytest.raises(DAPClientClosedError, match="threads"):
+            await asyncio.wait_for(client.send_request("threads"), timeout=1.0)
+        assert [m["command"] for m in writer.messages] == ["initialize"]
+
+
+class ResumeAdapter:
+    """Adapter stub whose continue request waits until released."""
+
+    def __init__(self):
+        self.capabilities = {}
+        self.sticky_steps = False
+        self.release = asyncio.Event()
+        self.requests: list[str] = []
+
+    async def continue_execution(self, thread_id):
+        self.requests.append("continue")
+        await self.release.wait()
+
+    async def step_over(self, thread_id):
+        self.requests.append("next")
+
+
+class TestResumeOrdering:
+    """Tests for the session refusing overlapping resumes."""
+
+    @pytest.mark.asyncio
+    async def test_step_refused_while_continue_pending(self, tmp_path):
+        """Test that a step can't be sent while a continue awaits its response."""
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.adapter = ResumeAdapter()  # type: ignore[assignment]
+        session._state = SessionState.PAUSED
+        session.current_thread_id = 1
+
+        resuming = asyncio.create_task(session.continue_())
+        await asyncio.sleep(0)
+
+        with pytest.raises(InvalidSessionStateError, match="resuming"):
+            await session.step_over()
+
+        session.adapter.release.set()
+        await resuming
+        assert session.adapter.requests == ["continue"]
+        assert session.state == SessionState.RUNNING
@@ -0,0 +1,171 @@
+"""Tests for data breakpoints (watchpoints)."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.exceptions import (
+    CapabilityNotSupportedError,
+    DataBreakpointError,
+    VariableNotFoundError,
+)
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.events import EventType
+
+
+class WatchpointAdapter:
+    """Adapter stub answering data breakpoint requests like a DAP server would."""
+
+    data_breakpoint_info = DebugAdapter.data_breakpoint_info
+    set_data_breakpoints = DebugAdapter.set_data_breakpoints
+
+    def __init__(self, supported: bool = True):
+        self.capabilities = {"supportsDataBreakpoints": supported}
+        self.is_launched = True
+        self.requests: list[tuple[str, dict]] = []
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append((command, arguments))
+        if command == "dataBreakpointInfo":
+            if arguments["name"] == "const":
+                return {"dataId": None, "description": "constants can't be watched"}
+            return {
+                "dataId": f"{arguments.get('variablesReference')}/{arguments['name']}",
+                "description": f"obj.{arguments['name']}",
+                "accessTypes": ["write", "readWrite"],
+            }
+        if command == "setDataBreakpoints":
+            return {
+                "breakpoints": [
+                    {"id": 100 + i, "verified": bp.get("condition") != "bad"}
+                    for i, bp in enumerate(arguments["breakpoints"])
+                ]
+            }
+        raise AssertionError(f"unexpected request {command}")
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session with a watchpoint-capable adapter."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = WatchpointAdapter()
+    session._state = SessionState.PAUSED
+    return session
+
+
+class TestSetDataBreakpoint:
+    """Tests for Session.set_data_breakpoint."""
+
+    @pytest.mark.asyncio
+    async def test_info_then_set(self, session):
+        """Test that the dataId from DataBreakpointInfo is installed."""
+        result = await session.set_data_breakpoint("count", variables_ref=7)
+
+        assert session.adapter.requests[0] == (
+            "dataBreakpointInfo",
+            {"name": "count", "variablesReference": 7},
+        )
+        assert session.adapter.requests[1] == (
+            "setDataBreakpoints",
+            {"breakpoints": [{"dataId": "7/count", "accessType": "write"}]},
+        )
+        assert result["id"] == 100
+        assert result["verified"] is True
+        assert result["description"] == "obj.count"
+
+    @pytest.mark.asyncio
+    async def test_all_watchpoints_resent_and_replaced(self, session):
+        """Test that each set resends every watchpoint, replacing the same data."""
+        await session.set_data_breakpoint("a", variables_ref=7)
+        await session.set_data_breakpoint("b", variables_ref=7)
+        await session.set_data_breakpoint("a", variables_ref=7, access_type="readWrite")
+
+        sent = session.adapter.requests[-1][1]["breakpoints"]
+        assert [(bp["dataId"], bp["accessType"]) for bp in sent] == [
+            ("7/b", "write"),
+            ("7/a", "readWrite"),
+        ]
+        assert len(session.describe_data_breakpoints()) == 2
+
+    @pytest.mark.asyncio
+    async def test_unsupported_adapter_sends_nothing(self, session):
+        """Test that missing capability is reported without any request."""
+        session.adapter = WatchpointAdapter(supported=False)
+
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.set_data_breakpoint("count", variables_ref=7)
+        assert session.adapter.requests == []
+
+    @pytest.mark.asyncio
+    async def test_unwatchable_data(self, session):
+        """Test that a null dataId explains why the data can't be watched."""
+        with pytest.raises(DataBreakpointError, match="constants can't be watched"):
+            await session.set_data_breakpoint("const", variables_ref=7)
+
+    @pytest.mark.asyncio
+    async def test_unsupported_access_type(self, session):
+        """Test that access types outside accessTypes are refused before setting."""
+        with pytest.raises(DataBreakpointError, match="access type 'read'"):
+            await session.set_data_breakpoint("count", variables_ref=7, access_type="read")
+        assert [cmd for cmd, _ in session.adapter.requests] == ["dataBreakpointInfo"]
+
+    @pytest.mark.asyncio
+    async def test_unverified_watchpoint_removed(self, session):
+        """Test that a watchpoint the adapter rejects is not kept."""
+        with pytest.raises(DataBreakpointError):
+            await session.set_data_breakpoint("count", variables_ref=7, condition="bad")
+
+        assert session.describe_data_breakpoints() == []
+        assert session.adapter.requests[-1][1] == {"breakpoints": []}
+
+    @pytest.mark.asyncio
+    async def test_stale_reference_rejected(self, session):
+        """Test that references from before a resume are refused."""
+        session._remember_reference(7, None, None)
+        session._invalidate_variables()
+
+        with pytest.raises(VariableNotFoundError):
+            await session.set_data_breakpoint("count", variables_ref=7)
+
+    @pytest.mark.asyncio
+    async def test_clear(self, session):
+        """Test that clearing sends an empty set."""
+        await session.set_data_breakpoint("count", variables_ref=7)
+
+        await session.clear_data_breakpoints()
+
+        assert session.describe_data_breakpoints() == []
+        assert session.adapter.requests[-1] == ("setDataBreakpoints", {"breakpoints": []})
+
+
+class TestDataBreakpointStops:
+    """Tests for stops caused by a watchpoint."""
+
+    @pytest.mark.asyncio
+    async def test_stop_names_fired_watchpoint(self, session):
+        """Test that a data breakpoint stop says which watchpoint fired."""
+        await session.set_data_breakpoint("a", variables_ref=7)
+        await session.set_data_breakpoint("b", variables_ref=7)
+        session._state = SessionState.RUNNING
+
+        await session._handle_event(
+            EventType.STOPPED,
+            {"reason": "data breakpoint", "threadId": 1, "hitBreakpointIds": [101]},
+        )
+
+        event = (await session.event_queue.get_all())[-1]
+        assert [bp["description"] for bp in event.data["data_breakpoints"]] == ["obj.b"]
+        assert session.stop_description == "Data breakpoint on obj.b"
+
+    @pytest.mark.asyncio
+    async def test_single_watchpoint_matched_without_ids(self, session):
+        """Test that a lone watchpoint is named even when the adapter omits ids."""
+        await session.set_data_breakpoint("a", variables_ref=7)
+
+        await session._handle_event(
+            EventType.STOPPED,
+            {"reason": "data breakpoint", "threadId": 1, "description": "Value changed"},
+        )
+
+        event = (await session.event_queue.get_all())[-1]
+        assert event.data["data_breakpoints"][0]["name"] == "a"
+        assert session.stop_description == "Value changed"
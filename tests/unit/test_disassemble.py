@@ -0,0 +1,130 @@
+"""Tests for disassembly and instruction-level stepping."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.exceptions import CapabilityNotSupportedError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+
+
+class NativeAdapter:
+    """Adapter stub for a compiled target with disassembly support."""
+
+    disassemble = DebugAdapter.disassemble
+    step_instruction = DebugAdapter.step_instruction
+
+    def __init__(self, supported: bool = True):
+        self.capabilities = {
+            "supportsDisassembleRequest": supported,
+            "supportsSteppingGranularity": supported,
+        }
+        self.sticky_steps = False
+        self.requests: list[tuple[str, dict]] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [
+            StackFrame(
+                id=10,
+                name="main.main",
+                source=Source(path="/src/main.go"),
+                line=17,
+                instructionPointerReference="0x1004",
+            )
+        ]
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append((command, arguments))
+        if command == "disassemble":
+            return {
+                "instructions": [
+                    {
+                        "address": "0x1000",
+                        "instruction": "MOVQ AX, 0x8(SP)",
+                        "location": {"path": "/src/main.go"},
+                        "line": 16,
+                    },
+                    {"address": "0x1004", "instruction": "CALL main.producer", "line": 17},
+                    {"address": "0x1009", "instruction": "NOPL", "line": 17},
+                ]
+            }
+        return {}
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session paused in compiled code."""
+    session = Session(session_id="test_session", project_root=tmp_path, language="go")
+    session.adapter = NativeAdapter()
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestDisassemble:
+    """Tests for Session.disassemble."""
+
+    @pytest.mark.asyncio
+    async def test_defaults_to_top_frame_instruction(self, session):
+        """Test that the stopped frame's instruction pointer is used by default."""
+        reference, _ = await session.disassemble(instructions_before=2, instructions_after=3)
+
+        assert reference == "0x1004"
+        assert session.adapter.requests[-1] == (
+            "disassemble",
+            {
+                "memoryReference": "0x1004",
+                "instructionOffset": -2,
+                "instructionCount": 6,
+                "resolveSymbols": True,
+            },
+        )
+
+    @pytest.mark.asyncio
+    async def test_source_markers_interleaved(self, session):
+        """Test that a source marker precedes each new line's instructions."""
+        _, listing = await session.disassemble(memory_reference="0x1000")
+
+        assert [(e["type"], e.get("line")) for e in listing] == [
+            ("source", 16),
+            ("instruction", None),
+            ("source", 17),
+            ("instruction", None),
+            ("instruction", None),
+        ]
+        assert listing[2]["file"] == "/src/main.go"
+        assert [e["current"] for e in listing if e["type"] == "instruction"] == [
+            True,
+            False,
+            False,
+        ]
+
+    @pytest.mark.asyncio
+    async def test_unsupported_adapter(self, session):
+        """Test that adapters without disassembly are refused before any request."""
+        session.adapter = NativeAdapter(supported=False)
+
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.disassemble(memory_reference="0x1000")
+        assert session.adapter.requests == []
+
+
+class TestInstructionStep:
+    """Tests for granularity="instruction" steps."""
+
+    @pytest.mark.asyncio
+    async def test_step_sends_instruction_granularity(self, session):
+        """Test that instruction steps pass the granularity to the adapter."""
+        await session.step_over(granularity="instruction")
+
+        assert session.adapter.requests == [("next", {"threadId": 1, "granularity": "instruction"})]
+
+    @pytest.mark.asyncio
+    async def test_unsupported_step_keeps_state(self, session):
+        """Test that a refused instruction step leaves the session paused."""
+        session.adapter = NativeAdapter(supported=False)
+
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.step_into(granularity="instruction")
+        assert session.state == SessionState.PAUSED
+
@@ -0,0 +1,105 @@
+"""Tests for exception breakpoint filters and exception stops."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.exceptions import InvalidExceptionFilterError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint
+from polybugger_mcp.models.events import EventType
+
+
+class ExceptionAdapter:
+    """Adapter stub advertising debugpy-style exception filters."""
+
+    def __init__(self, launched: bool = True):
+        self.is_launched = launched
+        self.capabilities = {
+            "supportsExceptionInfoRequest": True,
+            "exceptionBreakpointFilters": [
+                {"filter": "raised", "label": "Raised Exceptions", "supportsCondition": True},
+                {"filter": "uncaught", "label": "Uncaught Exceptions"},
+            ],
+        }
+        self.sent: list[tuple[list[str], list | None]] = []
+
+    async def set_exception_breakpoints(self, filters, filter_options=None):
+        self.sent.append((filters, filter_options))
+        count = len(filters) + len(filter_options or [])
+        return [Breakpoint(verified=True) for _ in range(count)]
+
+    async def get_exception_info(self, thread_id):
+        return {
+            "exceptionId": "ValueError",
+            "description": "bad value",
+            "breakMode": "unhandled",
+            "details": {"typeName": "ValueError", "stackTrace": "Traceback ...\n"},
+        }
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a running session with an exception-capable adapter."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._state = SessionState.RUNNING
+    session.adapter = ExceptionAdapter()
+    return session
+
+
+class TestExceptionBreakpoints:
+    """Tests for configuring exception filters."""
+
+    @pytest.mark.asyncio
+    async def test_apply_filters_with_condition(self, session):
+        """Test that conditioned filters are sent as filterOptions."""
+        result = await session.set_exception_breakpoints(
+            ["uncaught", "raised"], {"raised": "ValueError"}
+        )
+
+        assert result == {"applied": ["uncaught", "raised"], "rejected": [], "pending": False}
+        assert session.adapter.sent == [
+            (["uncaught"], [{"filterId": "raised", "condition": "ValueError"}])
+        ]
+
+    @pytest.mark.asyncio
+    async def test_unknown_filter_rejected(self, session):
+        """Test that filters the adapter doesn't advertise are refused."""
+        with pytest.raises(InvalidExceptionFilterError) as exc_info:
+            await session.set_exception_breakpoints(["panic"])
+
+        assert exc_info.value.details["available_filters"] == ["raised", "uncaught"]
+        assert session.adapter.sent == []
+
+    @pytest.mark.asyncio
+    async def test_condition_requires_support(self, session):
+        """Test that conditions are refused for filters without supportsCondition."""
+        with pytest.raises(InvalidExceptionFilterError):
+            await session.set_exception_breakpoints(["uncaught"], {"uncaught": "True"})
+
+    @pytest.mark.asyncio
+    async def test_pending_before_launch(self, session):
+        """Test that filters are stored until launch when not yet launched."""
+        session.adapter.is_launched = False
+
+        result = await session.set_exception_breakpoints(["raised"])
+
+        assert result["pending"] is True
+        assert session._exception_filters == ["raised"]
+        assert session.adapter.sent == []
+
+    @pytest.mark.asyncio
+    async def test_exception_stop_includes_info(self, session):
+        """Test that exception stops are queued with ExceptionInfo attached."""
+        await session._handle_event(EventType.STOPPED, {"reason": "exception", "threadId": 1})
+        await asyncio.gather(*session._background_tasks)
+
+        events = await session.event_queue.get_all()
+        assert len(events) == 1
+        assert events[0].data["exception"] == {
+            "type": "ValueError",
+            "message": "bad value",
+            "traceback": "Traceback ...\n",
+            "break_mode": "unhandled",
+        }
+        assert session.state == SessionState.PAUSED
@@ -0,0 +1,162 @@
+"""Tests for reporting chained exceptions."""
+
+import sys
+
+import pytest
+
+from polybugger_mcp.adapters.debugpy_adapter import DebugpyAdapter
+from polybugger_mcp.adapters.delve_adapter import DelveAdapter
+from polybugger_mcp.core.exceptions import DAPError, NotStoppedOnExceptionError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+
+
+def _load(key):
+    raise KeyError(key)
+
+
+def _parse(key):
+    try:
+        _load(key)
+    except KeyError as e:
+        raise ValueError("bad config") from e
+
+
+def _start():
+    try:
+        _parse("port")
+    except ValueError:
+        raise RuntimeError("startup failed")  # noqa: B904
+
+
+def _exc_info():
+    try:
+        _start()
+    except RuntimeError:
+        return sys.exc_info()
+
+
+# Stands in for the frame pydevd stopped in
+NAMESPACE = {"__exception__": _exc_info()}
+
+
+async def evaluate_full(expression, frame_id=None):
+    """Evaluate like debugpy: the result's repr, or a DAP error."""
+    try:
+        return repr(eval(expression, {}, NAMESPACE))
+    except Exception as e:
+        raise DAPError(code="DAP_REQUEST_FAILED", message=str(e)) from e
+
+
+DELVE_STACK = (
+    "Stack:\n"
+    "\t 0  0x000000000043b1c5 in runtime.gopanic\n"
+    "\t     at /usr/local/go/src/runtime/panic.go:770\n"
+    "\t 1  0x00000000004a1b2c in main.main\n"
+    "\t     at /src/main.go:12\n"
+)
+
+
+class TestAdapterChains:
+    """Tests for the adapters' exception chain walks."""
+
+    @pytest.mark.asyncio
+    async def test_debugpy_follows_cause_and_context(self):
+        """Test that debugpy reports every chained exception, innermost first."""
+        adapter = DebugpyAdapter(session_id="test")
+        adapter.evaluate_full = evaluate_full  # type: ignore[method-assign]
+
+        chain = await adapter.get_exception_chain(1, frame_id=5, max_depth=10)
+
+        assert [(e["type"], e["relation"]) for e in chain] == [
+            ("KeyError", "cause"),
+            ("ValueError", "context"),
+            ("RuntimeError", None),
+        ]
+        assert chain[0]["message"] == "'port'"
+        assert chain[0]["frames"][-1]["name"] == "_load"
+
+        chain = await adapter.get_exception_chain(1, frame_id=5, max_depth=2)
+        assert [e["type"] for e in chain] == ["ValueError", "RuntimeError"]
+
+    @pytest.mark.asyncio
+    async def test_delve_panic(self):
+        """Test that a Go panic maps to its value and the goroutine's frames."""
+        adapter = DelveAdapter(session_id="test")
+
+        async def get_exception_info(thread_id):
+            return {
+                "exceptionId": "panic",
+                "description": "index out of range [3] with length 3",
+                "details": {"stackTrace": DELVE_STACK},
+            }
+
+        adapter.get_exception_info = get_exception_info  # type: ignore[method-assign]
+
+        chain = await adapter.get_exception_chain(1, frame_id=None, max_depth=10)
+
+        assert chain == [
+            {
+                "type": "panic",
+                "message": "index out of range [3] with length 3",
+                "frames": [
+                    {
+                        "name": "runtime.gopanic",
+                        "file": "/usr/local/go/src/runtime/panic.go",
+                        "line": 770,
+                    },
+                    {"name": "main.main", "file": "/src/main.go", "line": 12},
+                ],
+            }
+        ]
+
+
+class ChainAdapter:
+    """Adapter stub with a three-exception chain."""
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=9, name="f", line=1, source=Source(path="/app/f.py"))]
+
+    async def get_exception_chain(self, thread_id, frame_id, max_depth):
+        assert frame_id == 9
+        chain = [
+            {"type": "KeyError", "message": "'a'", "frames": []},
+            {"type": "TypeError", "message": "t", "frames": []},
+            {"type": "ValueError", "message": "b", "frames": []},
+        ]
+        return chain[-max_depth:]
+
+
+class TestSessionExceptionChain:
+    """Tests for Session.get_exception_chain."""
+
+    @pytest.fixture
+    def session(self, tmp_path):
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.adapter = ChainAdapter()  # type: ignore[assignment]
+        session._state = SessionState.PAUSED
+        session.current_thread_id = 1
+        session.stop_reason = "exception"
+        return session
+
+    @pytest.mark.asyncio
+    async def test_truncated(self, session):
+        """Test that a chain longer than max_depth keeps its root cause and outermost."""
+        result = await session.get_exception_chain(max_depth=2)
+
+        assert result["truncated"] is True
+        assert [e["type"] for e in result["exceptions"]] == ["KeyError", "ValueError"]
+
+        result = await session.get_exception_chain(max_depth=1)
+        assert [e["type"] for e in result["exceptions"]] == ["ValueError"]
+
+        result = await session.get_exception_chain(max_depth=3)
+        assert result["truncated"] is False
+
+    @pytest.mark.asyncio
+    async def test_not_stopped_on_exception(self, session):
+        """Test that other stops are refused."""
+        session.stop_reason = "breakpoint"
+
+        with pytest.raises(NotStoppedOnExceptionError, match="not stopped on an exception"):
+            await session.get_exception_chain()
@@ -0,0 +1,112 @@
+"""Tests for expanding a variable's object graph in one call."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import VariableNotFoundError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Variable
+
+
+class GraphAdapter:
+    """Adapter stub serving a small linked structure with a cycle."""
+
+    def __init__(self):
+        self.children = {
+            1: [Variable(name="head", value="<Node a>", variablesReference=2)],
+            2: [
+                Variable(name="name", value="'a'", type="str"),
+                Variable(name="next", value="<Node b>", variablesReference=3),
+            ],
+            3: [
+                Variable(name="prev", value="<Node a>", variablesReference=2),
+                Variable(
+                    name="items", value="[0, 1, ...]", variablesReference=4, indexedVariables=8
+                ),
+            ],
+            4: [Variable(name=str(i), value=str(i), type="int") for i in range(8)],
+        }
+        self.requests: list[int] = []
+
+    async def get_variables(self, variables_ref, start=0, count=100, filter=None):
+        self.requests.append(variables_ref)
+        return self.children[variables_ref][start : start + count]
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session whose locals hold the linked structure."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = GraphAdapter()  # type: ignore[assignment]
+    session._state = SessionState.PAUSED
+    return session
+
+
+class TestExpandVariable:
+    """Tests for Session.expand_variable."""
+
+    @pytest.mark.asyncio
+    async def test_nested_tree_with_cycle(self, session):
+        """Test that a reference seen before is marked, not fetched again."""
+        result = await session.expand_variable(1, depth=4)
+
+        head = result["tree"]["children"][0]
+        node_b = head["children"][1]
+        prev, items = node_b["children"]
+        assert prev["cycle"] == 2
+        assert "children" not in prev
+        assert [c["value"] for c in items["children"]] == [str(i) for i in range(8)]
+        assert session.adapter.requests == [1, 2, 3, 4]
+        assert result["nodes"] == 13
+        assert result["truncated"] == []
+
+    @pytest.mark.asyncio
+    async def test_depth_limit(self, session):
+        """Test that containers below the depth limit are reported."""
+        result = await session.expand_variable(1, depth=2)
+
+        node_b = result["tree"]["children"][0]["children"][1]
+        assert node_b["truncated"] == "depth"
+        assert result["truncated"] == [
+            {"path": ["head", "next"], "variables_reference": 3, "reason": "depth"}
+        ]
+
+    @pytest.mark.asyncio
+    async def test_children_per_level(self, session):
+        """Test that a container with more children than fetched says so."""
+        result = await session.expand_variable(3, depth=1, max_children=5)
+
+        assert result["truncated"] == [
+            {"path": ["prev"], "variables_reference": 2, "reason": "depth"},
+            {"path": ["items"], "variables_reference": 4, "reason": "depth"},
+        ]
+
+        result = await session.expand_variable(4, depth=1, max_children=5)
+
+        assert len(result["tree"]["children"]) == 5
+        assert result["tree"]["truncated"] == "max_children"
+
+    @pytest.mark.asyncio
+    async def test_node_cap(self, session):
+        """Test that the total cap stops the expansion and marks where."""
+        result = await session.expand_variable(1, depth=4, max_nodes=5)
+
+        assert result["nodes"] == 5
+        reasons = {tuple(t["path"]): t["reason"] for t in result["truncated"]}
+        assert reasons == {("head", "next", "items"): "max_nodes"}
+
+    @pytest.mark.asyncio
+    async def test_values_clipped(self, session):
+        """Test that long values are truncated and flagged."""
+        result = await session.expand_variable(2, depth=1, value_limit=2)
+
+        name = result["tree"]["children"][0]
+        assert (name["value"], name["value_truncated"]) == ("'a", True)
+
+    @pytest.mark.asyncio
+    async def test_stale_reference(self, session):
+        """Test that a reference from before a resume is rejected."""
+        await session.expand_variable(1, depth=1)
+        session._invalidate_variables()
+
+        with pytest.raises(VariableNotFoundError):
+            await session.expand_variable(2)
@@ -0,0 +1,85 @@
+"""Tests for evaluating in frames other than the topmost."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import FrameNotFoundError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Scope, StackFrame
+
+
+class FrameAdapter:
+    """Adapter stub with a three-frame stack; frame IDs restart on each stop."""
+
+    def __init__(self):
+        self.evaluated: list[tuple[str, int | None, str]] = []
+        self.scoped: list[int] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=i, name=f"f{i}", line=i * 10) for i in (1, 2, 3)][:levels]
+
+    async def get_scopes(self, frame_id):
+        self.scoped.append(frame_id)
+        return [Scope(name="Locals", variablesReference=frame_id * 100)]
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        self.evaluated.append((expression, frame_id, context))
+        return {"result": "1", "type": "int", "variablesReference": 0}
+
+    async def continue_execution(self, thread_id):
+        pass
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session with a stub adapter."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = FrameAdapter()
+    session._state = SessionState.PAUSED
+    return session
+
+
+class TestFrameEvaluation:
+    """Tests for frame validation across stops."""
+
+    @pytest.mark.asyncio
+    async def test_evaluate_in_outer_frame(self, session):
+        """Test that the frame ID and context reach the adapter."""
+        await session.get_stack_trace(1)
+
+        await session.evaluate("x", frame_id=3, context="hover")
+
+        assert session.adapter.evaluated == [("x", 3, "hover")]
+
+    @pytest.mark.asyncio
+    async def test_stale_frame_rejected_after_resume(self, session):
+        """Test that a frame from before a resume fails without reaching the adapter."""
+        await session.get_stack_trace(1)
+        await session.continue_()
+        session._state = SessionState.PAUSED
+
+        with pytest.raises(FrameNotFoundError, match="no longer valid"):
+            await session.evaluate("x", frame_id=3)
+        with pytest.raises(FrameNotFoundError):
+            await session.get_scopes(3)
+
+        assert session.adapter.evaluated == []
+        assert session.adapter.scoped == []
+
+    @pytest.mark.asyncio
+    async def test_reused_frame_id_accepted(self, session):
+        """Test that a frame ID reported again by a new stack trace is valid."""
+        await session.get_stack_trace(1)
+        await session.continue_()
+        session._state = SessionState.PAUSED
+        await session.get_stack_trace(1)
+
+        scopes = await session.get_scopes(2)
+
+        assert scopes[0].variables_reference == 200
+
+    @pytest.mark.asyncio
+    async def test_unseen_frame_left_to_adapter(self, session):
+        """Test that frame IDs never seen are passed through."""
+        await session.evaluate("x", frame_id=7, context="repl")
+
+        assert session.adapter.evaluated == [("x", 7, "repl")]
@@ -0,0 +1,113 @@
+"""Tests for fetching complete values past the adapter's truncation."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.adapters.debugpy_adapter import DebugpyAdapter
+from polybugger_mcp.core.exceptions import ContinuationTokenError, VariableNotFoundError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Scope, Variable
+
+
+class ValueAdapter:
+    """Adapter stub recording evaluations; long values are clipped outside clipboard."""
+
+    evaluate_full = DebugAdapter.evaluate_full
+
+    def __init__(self, clipboard: bool = True):
+        self.capabilities = {"supportsClipboardContext": clipboard}
+        self.evaluated: list[tuple[str, int | None, str]] = []
+
+    async def get_scopes(self, frame_id):
+        return [Scope(name="Locals", variablesReference=1)]
+
+    async def get_variables(self, variables_ref, start=0, count=100, filter=None):
+        if variables_ref == 1:
+            return [Variable(name="obj", value="<Obj>", variablesReference=2)]
+        return [Variable(name="blob", value="'xx...", evaluateName="obj.blob")]
+
+    async def evaluate(self, expression, frame_id=None, context="repl"):
+        self.evaluated.append((expression, frame_id, context))
+        full = "x" * 5000
+        return {"result": full if context == "clipboard" else full[:64] + "..."}
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session whose frame 10 is known."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = ValueAdapter()
+    session._state = SessionState.PAUSED
+    session._frame_ids.add(10)
+    return session
+
+
+class TestGetFullValue:
+    """Tests for Session.get_full_value."""
+
+    @pytest.mark.asyncio
+    async def test_variable_uses_evaluate_name_and_frame(self, session):
+        """Test that a listed variable is re-evaluated in its frame by evaluateName."""
+        await session.get_scopes(10)
+        await session.get_variables(1)
+        await session.get_variables(2)
+
+        token, text = await session.get_full_value(variables_ref=2, name="blob")
+
+        assert session.adapter.evaluated == [("obj.blob", 10, "clipboard")]
+        assert text == "x" * 5000
+        assert session.full_value_text(token) == text
+
+    @pytest.mark.asyncio
+    async def test_name_used_without_evaluate_name(self, session):
+        """Test that a variable without evaluateName is evaluated by name."""
+        await session.get_scopes(10)
+        await session.get_variables(1)
+
+        await session.get_full_value(variables_ref=1, name="obj")
+
+        assert session.adapter.evaluated == [("obj", 10, "clipboard")]
+
+    @pytest.mark.asyncio
+    async def test_repl_without_clipboard(self, session):
+        """Test that adapters without the clipboard context fall back to repl."""
+        session.adapter = ValueAdapter(clipboard=False)
+
+        _, text = await session.get_full_value(expression="data")
+
+        assert session.adapter.evaluated == [("data", None, "repl")]
+        assert text.endswith("...")
+
+    @pytest.mark.asyncio
+    async def test_tokens_expire_on_resume(self, session):
+        """Test that fetched values and references are dropped when execution resumes."""
+        await session.get_scopes(10)
+        token, _ = await session.get_full_value(expression="data")
+
+        session._invalidate_variables()
+
+        with pytest.raises(ContinuationTokenError):
+            session.full_value_text(token)
+        with pytest.raises(VariableNotFoundError):
+            await session.get_full_value(variables_ref=1, name="obj")
+
+
+class TestDebugpyFullValue:
+    """Tests for the debugpy fallback through repr()."""
+
+    @pytest.mark.asyncio
+    async def test_repr_literal_decoded(self):
+        """Test that repr() output is evaluated in the debuggee and decoded."""
+        adapter = DebugpyAdapter(session_id="test_session")
+        calls = []
+
+        async def evaluate(expression, frame_id=None, context="watch"):
+            calls.append((expression, context))
+            return {"result": repr("line 1\nquote ' end")}
+
+        adapter.evaluate = evaluate  # type: ignore[method-assign]
+
+        text = await adapter.evaluate_full("msg", frame_id=3)
+
+        assert calls == [("repr(msg)", "repl")]
+        assert text == "line 1\nquote ' end"
@@ -0,0 +1,182 @@
+"""Tests for function breakpoints set by symbol name."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import CapabilityNotSupportedError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+class FunctionBreakpointAdapter:
+    """Delve-like stub that binds known functions and leaves others pending."""
+
+    def __init__(self, supported: bool = True):
+        self.capabilities = {"supportsFunctionBreakpoints": supported}
+        self.is_launched = True
+        self.function_requests: list[list[dict]] = []
+        self.line_requests: list[str] = []
+        self.next_id = 1
+        self.functions = {
+            "main.calculate": ("/app/main.go", 10),
+            "main.(*Server).Handle": ("/app/server.go", 42),
+        }
+
+    async def set_function_breakpoints(self, breakpoints):
+        self.function_requests.append(breakpoints)
+        results = []
+        for bp in breakpoints:
+            location = self.functions.get(bp["name"])
+            if location is None:
+                results.append(Breakpoint(id=self.next_id, verified=False, message="not found"))
+            else:
+                path, line = location
+                results.append(
+                    Breakpoint(id=self.next_id, verified=True, line=line, source={"path": path})
+                )
+            # Adapters number every resent breakpoint afresh
+            self.next_id += 1
+        return results
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        self.line_requests.append(source_path)
+        return [Breakpoint(id=500 + bp.line, verified=True, line=bp.line) for bp in breakpoints]
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a running delve session with the function breakpoint stub."""
+    session = Session(session_id="test_session", project_root=tmp_path, language="go")
+    session.adapter = FunctionBreakpointAdapter()  # type: ignore[assignment]
+    session._state = SessionState.RUNNING
+    return session
+
+
+class TestSetFunctionBreakpoint:
+    """Tests for Session.set_function_breakpoint."""
+
+    @pytest.mark.asyncio
+    async def test_receiver_method_round_trips(self, session):
+        """Test that a Go receiver method name is sent and listed unchanged."""
+        result = await session.set_function_breakpoint("main.(*Server).Handle")
+
+        assert session.adapter.function_requests == [[{"name": "main.(*Server).Handle"}]]
+        assert result["name"] == "main.(*Server).Handle"
+        assert result["verified"] is True
+        assert (result["bound_file"], result["bound_line"]) == ("/app/server.go", 42)
+        assert session.describe_function_breakpoints()[0]["name"] == "main.(*Server).Handle"
+
+    @pytest.mark.asyncio
+    async def test_all_resent_and_same_name_replaced(self, session):
+        """Test that each set resends every function, replacing the same name."""
+        await session.set_function_breakpoint("main.calculate")
+        await session.set_function_breakpoint("main.missing")
+        await session.set_function_breakpoint("main.calculate", condition="n > 3")
+
+        assert session.adapter.function_requests[-1] == [
+            {"name": "main.missing"},
+            {"name": "main.calculate", "condition": "n > 3"},
+        ]
+        missing, calculate = session.describe_function_breakpoints()
+        assert (missing["verified"], missing["message"]) == (False, "not found")
+        assert calculate["condition"] == "n > 3"
+
+    @pytest.mark.asyncio
+    async def test_line_breakpoints_leave_functions_alone(self, session, tmp_path):
+        """Test that resending a file's line breakpoints keeps function breakpoints."""
+        await session.set_function_breakpoint("main.calculate")
+        path = str(tmp_path / "main.go")
+
+        await session.set_breakpoints(path, [SourceBreakpoint(line=5)])
+        await session.set_breakpoints(path, [])
+
+        assert len(session.adapter.function_requests) == 1
+        assert session.describe_function_breakpoints()[0]["verified"] is True
+
+    @pytest.mark.asyncio
+    async def test_pending_until_launch(self, session, tmp_path):
+        """Test that breakpoints set before launch are sent with the source ones."""
+        session.adapter.is_launched = False
+
+        result = await session.set_function_breakpoint("main.calculate")
+        session._breakpoints[str(tmp_path / "main.go")] = [SourceBreakpoint(line=5)]
+        await session._send_source_breakpoints()
+
+        assert (result["verified"], result["message"]) == (False, "Pending launch")
+        assert session.adapter.function_requests == [[{"name": "main.calculate"}]]
+        assert session.describe_function_breakpoints()[0]["verified"] is True
+
+    @pytest.mark.asyncio
+    async def test_unsupported_adapter(self, session):
+        """Test that adapters without the capability are refused before sending."""
+        session.adapter = FunctionBreakpointAdapter(supported=False)  # type: ignore[assignment]
+
+        with pytest.raises(CapabilityNotSupportedError) as exc_info:
+            await session.set_function_breakpoint("app.handler")
+
+        assert exc_info.value.details["capability"] == "supportsFunctionBreakpoints"
+        assert session.adapter.function_requests == []
+        assert session.describe_function_breakpoints() == []
+
+    @pytest.mark.asyncio
+    async def test_clear(self, session):
+        """Test that clearing sends an empty list once."""
+        await session.set_function_breakpoint("main.calculate")
+
+        await session.clear_function_breakpoints()
+        await session.clear_function_breakpoints()
+
+        assert session.adapter.function_requests[1:] == [[]]
+        assert session.describe_function_breakpoints() == []
+
+
+class TestFunctionBreakpointEvents:
+    """Tests for breakpoint events and stops naming function breakpoints."""
+
+    @pytest.mark.asyncio
+    async def test_late_verification(self, session):
+        """Test that a later bind updates the entry and notifies once."""
+        await session.set_function_breakpoint("main.lazy")
+        bp_id = session.describe_function_breakpoints()[0]["id"]
+
+        body = {"id": bp_id, "verified": True, "line": 7, "source": {"path": "/app/lazy.go"}}
+        await session._handle_event(EventType.BREAKPOINT, {"reason": "changed", "breakpoint": body})
+        await session._handle_event(EventType.BREAKPOINT, {"reason": "changed", "breakpoint": body})
+
+        entry = session.describe_function_breakpoints()[0]
+        assert (entry["verified"], entry["bound_line"], entry["message"]) == (True, 7, None)
+        events = await session.event_queue.get_all()
+        assert [e.type for e in events].count(EventType.BREAKPOINT_VERIFIED) == 1
+        verified = next(e for e in events if e.type == EventType.BREAKPOINT_VERIFIED)
+        assert verified.data["function"] == "main.lazy"
+        assert verified.data["file"] == "/app/lazy.go"
+
+    @pytest.mark.asyncio
+    async def test_removed_by_adapter(self, session):
+        """Test that a removal unbinds the entry but keeps it listed."""
+        await session.set_function_breakpoint("main.calculate")
+        bp_id = session.describe_function_breakpoints()[0]["id"]
+
+        await session._handle_event(
+            EventType.BREAKPOINT, {"reason": "removed", "breakpoint": {"id": bp_id}}
+        )
+
+        entry = session.describe_function_breakpoints()[0]
+        assert (entry["verified"], entry["bound_line"]) == (False, None)
+
+    @pytest.mark.asyncio
+    async def test_hits_counted(self, session):
+        """Test that function breakpoint stops count hits and reset on a new run."""
+        await session.set_function_breakpoint("main.calculate")
+        bp_id = session.describe_function_breakpoints()[0]["id"]
+
+        for _ in range(2):
+            await session._handle_event(
+                EventType.STOPPED,
+                {"reason": "function breakpoint", "threadId": 1, "hitBreakpointIds": [bp_id]},
+            )
+            session._state = SessionState.RUNNING
+
+        assert session.describe_function_breakpoints()[0]["hit_count"] == 2
+        session._reset_function_breakpoints()
+        assert session.describe_function_breakpoints()[0]["hit_count"] == 0
@@ -0,0 +1,169 @@
+"""Tests for goroutine dumps with grouped stacks."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import CapabilityNotSupportedError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame, Thread
+from polybugger_mcp.utils.goroutines import group_goroutines, parse_goroutine_name, wait_reason
+
+MAIN = "/app/main.go"
+
+# Stacks as delve reports them, top first: (function, file, line)
+CHAN_WAIT = [
+    ("runtime.gopark", "/go/src/runtime/proc.go", 474),
+    ("runtime.chanrecv", "/go/src/runtime/chan.go", 667),
+    ("runtime.chanrecv1", "/go/src/runtime/chan.go", 509),
+    ("main.worker", MAIN, 12),
+    ("main.main.gowrap1", MAIN, 22),
+    ("runtime.goexit", "/go/src/runtime/asm_amd64.s", 1264),
+]
+MUTEX_WAIT = [
+    ("runtime.gopark", "/go/src/runtime/proc.go", 474),
+    ("runtime.semacquire1", "/go/src/runtime/sema.go", 192),
+    ("internal/sync.runtime_SemacquireMutex", "/go/src/runtime/sema.go", 95),
+    ("internal/sync.(*Mutex).lockSlow", "/go/src/internal/sync/mutex.go", 149),
+    ("sync.(*Mutex).Lock", "/go/src/sync/mutex.go", 46),
+    ("main.locker", MAIN, 16),
+]
+SLEEPING = [
+    ("runtime.gopark", "/go/src/runtime/proc.go", 474),
+    ("time.Sleep", "/go/src/runtime/time.go", 368),
+    ("main.main", MAIN, 30),
+]
+
+
+class GoroutineAdapter:
+    """Delve stub with main sleeping, 50 channel receivers and 3 mutex waiters."""
+
+    def __init__(self):
+        self.stacks: dict[int, list[tuple[str, str, int]]] = {1: SLEEPING}
+        for goroutine in range(5, 55):
+            self.stacks[goroutine] = CHAN_WAIT
+        for goroutine in range(55, 58):
+            # Different worker lines, so the waiters don't all group together
+            self.stacks[goroutine] = MUTEX_WAIT[:-1] + [("main.locker", MAIN, goroutine)]
+        self.levels: list[int] = []
+        self.paused = False
+
+    async def get_threads(self):
+        threads = [Thread(id=1, name="* [Go 1] main.main (Thread 4430)")]
+        threads += [
+            Thread(id=g, name=f"[Go {g}] {self.stacks[g][-3][0]}")
+            for g in sorted(self.stacks)
+            if g != 1
+        ]
+        return threads
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        self.levels.append(levels)
+        return [
+            StackFrame(id=thread_id * 100 + n, name=name, line=line, source=Source(path=path))
+            for n, (name, path, line) in enumerate(self.stacks[thread_id][:levels])
+        ]
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a delve session paused in the goroutine stub."""
+    session = Session(session_id="test_session", project_root=tmp_path, language="go")
+    session.adapter = GoroutineAdapter()  # type: ignore[assignment]
+    session.adapter_name = "delve"
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestGoroutineHelpers:
+    """Tests for thread name parsing and wait reasons."""
+
+    def test_parse_goroutine_name(self):
+        """Test that goroutine and OS thread ids come out of delve's names."""
+        assert parse_goroutine_name("* [Go 1] main.main (Thread 4430)") == {
+            "goroutine_id": 1,
+            "location": "main.main",
+            "os_thread": 4430,
+        }
+        assert parse_goroutine_name("[Go 17] main.worker")["os_thread"] is None
+        assert parse_goroutine_name("Thread 3")["goroutine_id"] is None
+
+    def test_wait_reason(self):
+        """Test that reasons match runtime.Stack headers, sleeps aren't deadlocks."""
+        assert wait_reason([f for f, _, _ in CHAN_WAIT]) == ("chan receive", True)
+        assert wait_reason([f for f, _, _ in MUTEX_WAIT]) == ("sync.Mutex.Lock", True)
+        assert wait_reason([f for f, _, _ in SLEEPING]) == ("sleep", False)
+        assert wait_reason(["main.compute", "main.main"]) == (None, False)
+
+    def test_grouping_and_truncation(self):
+        """Test that stacks differing only below max_frames share a group."""
+
+        def goroutine(gid, line):
+            return {
+                "goroutine_id": gid,
+                "state": "waiting",
+                "wait_reason": "chan receive",
+                "potential_deadlock": True,
+                "frames": [
+                    {"name": "runtime.gopark", "file": "proc.go", "line": 474},
+                    {"name": "main.worker", "file": MAIN, "line": line},
+                ],
+            }
+
+        goroutines = [goroutine(1, 12), goroutine(2, 12), goroutine(3, 40)]
+
+        one_frame = group_goroutines(goroutines, max_frames=1)
+        two_frames = group_goroutines(goroutines, max_frames=2)
+
+        assert [(g["count"], g["goroutine_ids"]) for g in one_frame] == [(3, [1, 2, 3])]
+        assert one_frame[0]["frames_truncated"] is True
+        assert len(one_frame[0]["frames"]) == 1
+        assert [(g["count"], g["goroutine_ids"]) for g in two_frames] == [(2, [1, 2]), (1, [3])]
+        assert two_frames[0]["frames_truncated"] is False
+
+
+class TestDumpGoroutines:
+    """Tests for Session.dump_goroutines."""
+
+    @pytest.mark.asyncio
+    async def test_blocked_goroutines_grouped(self, session):
+        """Test that 50 goroutines blocked on one channel form one flagged group."""
+        dump = await session.dump_goroutines(max_frames=4)
+
+        assert dump["goroutine_count"] == 54
+        assert dump["paused"] is False
+        assert dump["potential_deadlock"] is True
+        receivers = dump["groups"][0]
+        assert receivers["count"] == 50
+        assert receivers["goroutine_ids"] == list(range(5, 55))
+        assert receivers["wait_reason"] == "chan receive"
+        assert receivers["state"] == "waiting"
+        assert receivers["potential_deadlock"] is True
+        assert [f["name"] for f in receivers["frames"]] == [
+            "runtime.gopark",
+            "runtime.chanrecv",
+            "runtime.chanrecv1",
+            "main.worker",
+        ]
+        assert receivers["frames_truncated"] is True
+        # One extra frame is fetched to detect truncation
+        assert set(session.adapter.levels) == {5}
+
+    @pytest.mark.asyncio
+    async def test_frames_beyond_max_not_compared(self, session):
+        """Test that mutex waiters differing below the top frames group together."""
+        few = await session.dump_goroutines(max_frames=5)
+        many = await session.dump_goroutines(max_frames=6)
+
+        assert [g["count"] for g in few["groups"]] == [50, 3, 1]
+        assert [g["count"] for g in many["groups"]] == [50, 1, 1, 1, 1]
+        sleeper = few["groups"][-1]
+        assert sleeper["wait_reason"] == "sleep"
+        assert sleeper["potential_deadlock"] is False
+
+    @pytest.mark.asyncio
+    async def test_requires_delve(self, session):
+        """Test that other adapters are refused."""
+        session.adapter_name = "debugpy"
+
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.dump_goroutines()
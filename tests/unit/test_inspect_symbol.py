@@ -0,0 +1,93 @@
+"""Tests for hover-style symbol inspection by source position."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import InvalidSessionStateError, NoSymbolAtPositionError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+from polybugger_mcp.utils.source_reader import clear_cache
+
+
+class HoverAdapter:
+    """Adapter stub stopped in helper.py, called from app.py."""
+
+    def __init__(self, app: str, helper: str):
+        self.app = app
+        self.helper = helper
+        self.evaluations: list[tuple[str, int | None, str]] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [
+            StackFrame(id=20, name="helper", line=2, source=Source(path=self.helper)),
+            StackFrame(id=10, name="main", line=3, source=Source(path=self.app)),
+        ][:levels]
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        self.evaluations.append((expression, frame_id, context))
+        return {"result": "[1, 2]", "type": "list", "variablesReference": 7}
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session paused with app.py and helper.py on the stack."""
+    clear_cache()
+    (tmp_path / "app.py").write_text("import helper\n\nrésumé = helper.load(données.items)\n")
+    (tmp_path / "helper.py").write_text("def load(x):\n    return x\n")
+    (tmp_path / "other.py").write_text("value = 1\n")
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = HoverAdapter(  # type: ignore[assignment]
+        str(tmp_path / "app.py"), str(tmp_path / "helper.py")
+    )
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestInspectSymbol:
+    """Tests for Session.inspect_symbol."""
+
+    @pytest.mark.asyncio
+    async def test_evaluates_in_frame_of_file(self, session):
+        """Test that the symbol is evaluated with hover in the frame in that file."""
+        # Column 31 is in "items", after multi-byte names on the line
+        result = await session.inspect_symbol("app.py", 3, 31)
+
+        assert session.adapter.evaluations == [("données.items", 10, "hover")]
+        assert result == {
+            "expression": "données.items",
+            "result": "[1, 2]",
+            "type": "list",
+            "variables_reference": 7,
+            "frame_id": 10,
+            "frame_matched": True,
+        }
+
+    @pytest.mark.asyncio
+    async def test_falls_back_to_top_frame(self, session):
+        """Test that a file with no frame on the stack uses the top frame."""
+        result = await session.inspect_symbol("other.py", 1, 1)
+
+        assert session.adapter.evaluations == [("value", 20, "hover")]
+        assert result["frame_matched"] is False
+
+    @pytest.mark.asyncio
+    async def test_no_symbol_at_operator(self, session):
+        """Test that an operator position is an error, with nothing evaluated."""
+        with pytest.raises(NoSymbolAtPositionError, match="not an identifier"):
+            await session.inspect_symbol("app.py", 3, 8)
+
+        assert session.adapter.evaluations == []
+
+    @pytest.mark.asyncio
+    async def test_unreadable_line(self, session):
+        """Test that a line past the end of the file is an error."""
+        with pytest.raises(NoSymbolAtPositionError, match="line not readable"):
+            await session.inspect_symbol("app.py", 50, 1)
+
+    @pytest.mark.asyncio
+    async def test_requires_paused(self, session):
+        """Test that inspection needs a paused session."""
+        session._state = SessionState.RUNNING
+
+        with pytest.raises(InvalidSessionStateError):
+            await session.inspect_symbol("app.py", 3, 1)
@@ -0,0 +1,74 @@
+"""Tests for the launch working directory and environment."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import LaunchError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import LaunchConfig
+
+
+class RecordingAdapter:
+    """Adapter stub keeping the config it was launched with."""
+
+    def __init__(self):
+        self.capabilities = {}
+        self.config: LaunchConfig | None = None
+
+    async def launch(self, config, configure_callback=None, **kwargs):
+        self.config = config
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session whose project root has a subdirectory."""
+    (tmp_path / "app").mkdir()
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = RecordingAdapter()
+    session._state = SessionState.CREATED
+    return session
+
+
+class TestLaunchEnvironment:
+    """Tests for cwd resolution and env merging in Session.launch."""
+
+    @pytest.mark.asyncio
+    async def test_relative_cwd_resolved_against_project_root(self, session, tmp_path):
+        """Test that a relative cwd is taken from the project root."""
+        await session.launch(LaunchConfig(program="main", cwd="app"))
+
+        assert session.adapter.config.cwd == str((tmp_path / "app").resolve())
+        assert session.launch_cwd == session.adapter.config.cwd
+
+    @pytest.mark.asyncio
+    async def test_missing_cwd_fails_before_adapter(self, session):
+        """Test that a nonexistent cwd fails without touching the adapter."""
+        with pytest.raises(LaunchError, match="Working directory does not exist"):
+            await session.launch(LaunchConfig(program="main", cwd="missing"))
+
+        assert session.adapter.config is None
+        assert session.state == SessionState.FAILED
+
+    @pytest.mark.asyncio
+    async def test_env_merged_with_unsets(self, session, monkeypatch):
+        """Test that overrides merge over the inherited env and None unsets."""
+        monkeypatch.setenv("KEEP_ME", "1")
+        monkeypatch.setenv("DROP_ME", "1")
+
+        await session.launch(
+            LaunchConfig(program="main", env={"DROP_ME": None, "ADDED": "yes"}),
+        )
+
+        assert session.launch_env["KEEP_ME"] == "1"
+        assert session.launch_env["ADDED"] == "yes"
+        assert "DROP_ME" not in session.launch_env
+        assert session.adapter.config.env == {"DROP_ME": None, "ADDED": "yes"}
+
+    @pytest.mark.asyncio
+    async def test_output_log_path_resolved_against_project_root(self, session, tmp_path):
+        """Test that a relative output_log_path opens a log under the project root."""
+        await session.launch(LaunchConfig(program="main", output_log_path="logs/out.log"))
+
+        assert session.output_log is not None
+        assert session.output_log.path == (tmp_path / "logs" / "out.log").resolve()
+        assert session.output_log.path.exists()
+        await session.output_log.close()
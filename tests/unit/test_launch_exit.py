@@ -0,0 +1,145 @@
+"""Tests for programs that exit, or stop at entry, during the launch handshake."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.exceptions import ProgramExitedError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, LaunchConfig, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+class HandshakeAdapter:
+    """Adapter stub that replays scripted events after configuration.
+
+    Its launch never returns on its own when the script ends with an exit,
+    like an adapter still waiting for a process that is already gone.
+    """
+
+    def __init__(self, session: Session, events: list[tuple[EventType, dict]]):
+        self.session = session
+        self.events = events
+        self.capabilities: dict = {}
+        self.log: list[str] = []
+        self.launched_with: LaunchConfig | None = None
+
+    async def launch(self, config, configure_callback=None, **kwargs):
+        self.launched_with = config
+        if configure_callback:
+            await configure_callback()
+        self.log.append("configurationDone")
+        for event_type, data in self.events:
+            self.log.append(event_type.value)
+            await self.session._handle_event(event_type, data)
+        if any(t == EventType.EXITED for t, _ in self.events):
+            await asyncio.Event().wait()
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        self.log.append("setBreakpoints")
+        return [Breakpoint(verified=True, line=bp.line) for bp in breakpoints]
+
+    async def set_exception_breakpoints(self, filters, options=None):
+        return []
+
+    async def continue_execution(self, thread_id=None):
+        self.log.append(f"continue:{thread_id}")
+
+
+class EntryStopAdapter(HandshakeAdapter):
+    """Stub for an adapter whose launches stop at entry (like delve)."""
+
+    launch_stops_at_entry = True
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session with one breakpoint set."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._breakpoints[str(tmp_path / "main.go")] = [SourceBreakpoint(line=5)]
+    return session
+
+
+class TestLaunchExit:
+    """Tests for exited/terminated events arriving mid-launch."""
+
+    @pytest.mark.asyncio
+    async def test_exit_fails_launch(self, session):
+        """Test that launch reports the exit instead of waiting for the adapter."""
+        session.adapter = HandshakeAdapter(  # type: ignore[assignment]
+            session,
+            [
+                (EventType.OUTPUT, {"category": "stdout", "output": "bye\n"}),
+                (EventType.EXITED, {"exitCode": 3}),
+                (EventType.TERMINATED, {}),
+            ],
+        )
+
+        with pytest.raises(ProgramExitedError) as raised:
+            await asyncio.wait_for(session.launch(LaunchConfig(program="main")), timeout=5.0)
+
+        assert raised.value.code == "PROGRAM_EXITED"
+        assert raised.value.details == {"exit_code": 3, "output": "bye\n"}
+        assert session.state == SessionState.TERMINATED
+
+    @pytest.mark.asyncio
+    async def test_exit_before_entry_stop(self, session):
+        """Test that an exit while waiting for the entry stop fails the launch too."""
+        session.adapter = EntryStopAdapter(  # type: ignore[assignment]
+            session, [(EventType.EXITED, {"exitCode": 0})]
+        )
+
+        with pytest.raises(ProgramExitedError, match="code 0"):
+            await asyncio.wait_for(session.launch(LaunchConfig(program="main")), timeout=5.0)
+
+
+class TestHiddenEntryStop:
+    """Tests for the entry stop the session takes on the user's behalf."""
+
+    @pytest.mark.asyncio
+    async def test_breakpoints_sent_at_entry(self, session):
+        """Test that breakpoints go out while stopped at entry and the stop is hidden."""
+        adapter = EntryStopAdapter(
+            session, [(EventType.STOPPED, {"reason": "entry", "threadId": 7})]
+        )
+        session.adapter = adapter  # type: ignore[assignment]
+
+        await session.launch(LaunchConfig(program="main"))
+
+        assert adapter.launched_with is not None
+        assert adapter.launched_with.stop_on_entry is True
+        assert adapter.log == ["configurationDone", "stopped", "setBreakpoints", "continue:7"]
+        assert session.state == SessionState.RUNNING
+        assert session.stop_count == 0
+        assert session.event_queue.pending_count == 0
+        assert session.history.entries(event_type="stopped") == []
+
+    @pytest.mark.asyncio
+    async def test_late_entry_stop_resumed(self, session):
+        """Test that an entry stop reported after launch returned is still hidden."""
+        adapter = EntryStopAdapter(session, [])
+        session.adapter = adapter  # type: ignore[assignment]
+
+        await asyncio.wait_for(session.launch(LaunchConfig(program="main")), timeout=1.0)
+        assert session.state == SessionState.RUNNING
+        await session._handle_event(EventType.STOPPED, {"reason": "entry", "threadId": 7})
+        await asyncio.gather(*session._background_tasks)
+
+        assert adapter.log == ["configurationDone", "setBreakpoints", "continue:7"]
+        assert session.state == SessionState.RUNNING
+        assert session.stop_count == 0
+        assert session.event_queue.pending_count == 0
+
+    @pytest.mark.asyncio
+    async def test_requested_entry_stop_shown(self, session):
+        """Test that an entry stop the user asked for is reported as usual."""
+        adapter = EntryStopAdapter(
+            session, [(EventType.STOPPED, {"reason": "entry", "threadId": 7})]
+        )
+        session.adapter = adapter  # type: ignore[assignment]
+
+        await session.launch(LaunchConfig(program="main", stop_on_entry=True))
+
+        assert adapter.log == ["setBreakpoints", "configurationDone", "stopped"]
+        assert session.state == SessionState.PAUSED
+        assert session.stop_reason == "entry"
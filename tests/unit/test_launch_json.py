@@ -0,0 +1,137 @@
+"""Tests for reading launch configurations from .vscode/launch.json."""
+
+import json
+
+import pytest
+
+from polybugger_mcp.core.exceptions import LaunchConfigError
+from polybugger_mcp.utils.launch_json import (
+    describe_configuration,
+    find_configuration,
+    read_launch_json,
+    resolve_configuration,
+    strip_jsonc,
+)
+
+LAUNCH_JSON = """{
+    // Comments are allowed
+    "version": "0.2.0",
+    "configurations": [
+        {
+            "name": "Server",
+            "type": "debugpy",
+            "request": "launch",
+            "module": "app.server", /* inline */
+            "args": ["--port", "8080",],
+            "cwd": "${workspaceFolder}/src",
+            "envFile": "${workspaceFolder}/.env",
+            "env": {"LOG_LEVEL": "debug"},
+            "justMyCode": false,
+            "django": true,
+        },
+        {
+            "name": "Current file",
+            "type": "python",
+            "request": "launch",
+            "program": "${file}",
+        },
+    ],
+}
+"""
+
+
+@pytest.fixture
+def project(tmp_path):
+    """Create a project with a launch.json and an env file."""
+    (tmp_path / ".vscode").mkdir()
+    (tmp_path / ".vscode" / "launch.json").write_text(LAUNCH_JSON)
+    (tmp_path / ".env").write_text("# settings\nexport API_URL='http://x'\nLOG_LEVEL=info\n")
+    return tmp_path
+
+
+class TestStripJsonc:
+    """Tests for JSONC comment and trailing comma removal."""
+
+    def test_strings_untouched(self):
+        """Test that comment markers and commas inside strings are kept."""
+        text = '{"url": "http://a//b", "s": "a,]", "q": "x\\"//y"} // done'
+
+        assert strip_jsonc(text) == '{"url": "http://a//b", "s": "a,]", "q": "x\\"//y"} '
+
+    def test_trailing_commas_removed(self):
+        """Test that commas before closing brackets are dropped."""
+        assert strip_jsonc("[1, 2, ]") == "[1, 2 ]"
+
+    def test_comma_before_commented_out_entry(self):
+        """Test that a comma followed only by comments is still trailing."""
+        text = '{"configurations": [{"name": "a"},\n// {"name": "b"}\n/* old */]}'
+
+        assert json.loads(strip_jsonc(text)) == {"configurations": [{"name": "a"}]}
+
+
+class TestResolveConfiguration:
+    """Tests for turning entries into launch arguments."""
+
+    def test_module_entry(self, project):
+        """Test variables, envFile and env precedence in a module launch."""
+        entry = find_configuration(read_launch_json(project), "Server")
+
+        kwargs = resolve_configuration(entry, project)
+
+        assert kwargs["module"] == "app.server"
+        assert kwargs["args"] == ["--port", "8080"]
+        assert kwargs["cwd"] == f"{project}/src"
+        assert kwargs["adapter"] == "debugpy"
+        assert kwargs["env"] == {"API_URL": "http://x", "LOG_LEVEL": "debug"}
+        assert kwargs["just_my_code"] is False
+
+    def test_skip_files_become_step_filters(self, project):
+        """Test that js-debug skipFiles map onto step filters."""
+        entry = {
+            "name": "Node",
+            "type": "node",
+            "program": "index.js",
+            "skipFiles": ["<node_internals>/**", "**/vendor/**"],
+        }
+
+        kwargs = resolve_configuration(entry, project)
+
+        assert kwargs["step_filters"] == {"patterns": ["**/vendor/**"], "skip_stdlib": True}
+
+    def test_unresolved_variables_listed(self, project):
+        """Test that editor-only variables are refused by name."""
+        entry = find_configuration(read_launch_json(project), "Current file")
+
+        with pytest.raises(LaunchConfigError) as exc_info:
+            resolve_configuration(entry, project)
+
+        assert exc_info.value.details["unresolved"] == ["file"]
+        assert "${file}" in exc_info.value.message
+
+    def test_unknown_type_named(self, project):
+        """Test that an unsupported type is reported with its name."""
+        entry = {"name": "Chrome", "type": "chrome", "request": "launch"}
+
+        with pytest.raises(LaunchConfigError) as exc_info:
+            resolve_configuration(entry, project)
+
+        assert "'chrome'" in exc_info.value.message
+        assert exc_info.value.details["type"] == "chrome"
+
+    def test_unknown_name(self, project):
+        """Test that a missing configuration lists the available names."""
+        with pytest.raises(LaunchConfigError) as exc_info:
+            find_configuration(read_launch_json(project), "Worker")
+
+        assert exc_info.value.details["available"] == ["Server", "Current file"]
+
+    def test_describe_reports_ignored_keys(self, project):
+        """Test that listings flag keys with no effect and unlaunchable entries."""
+        server, current = read_launch_json(project)
+
+        assert describe_configuration(server, project)["ignored_keys"] == ["django"]
+        assert describe_configuration(current, project)["launchable"] is False
+
+    def test_missing_file(self, tmp_path):
+        """Test that a project without launch.json has no configurations."""
+        assert read_launch_json(tmp_path) == []
@@ -0,0 +1,128 @@
+"""Tests for loaded-source and module introspection."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.events import EventType
+
+
+class SourcesAdapter:
+    """Adapter stub answering LoadedSources/Modules requests."""
+
+    get_loaded_sources = DebugAdapter.get_loaded_sources
+    get_modules = DebugAdapter.get_modules
+
+    def __init__(self, supported: bool = True):
+        self.capabilities = {
+            "supportsLoadedSourcesRequest": supported,
+            "supportsModulesRequest": supported,
+        }
+        self.requests: list[str] = []
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append(command)
+        if command == "loadedSources":
+            return {
+                "sources": [
+                    {"name": "app.py", "path": self.app_path},
+                    {"name": "<string>", "sourceReference": 4},
+                    {"name": "util.py", "path": "/site-packages/pkg/util.py"},
+                ]
+            }
+        if command == "modules":
+            return {
+                "modules": [
+                    {
+                        "id": 1,
+                        "name": "pkg",
+                        "path": "/site-packages/pkg/__init__.py",
+                        "version": "2.1.0",
+                    },
+                    {"id": 2, "name": "app", "path": self.app_path},
+                ]
+            }
+        raise AssertionError(f"unexpected request {command}")
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a running session with one source file on disk."""
+    app = tmp_path / "app.py"
+    app.write_text("print('hi')\n")
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = SourcesAdapter()
+    session.adapter.app_path = str(app)
+    session._state = SessionState.RUNNING
+    return session
+
+
+class TestLoadedSources:
+    """Tests for Session.list_loaded_sources."""
+
+    @pytest.mark.asyncio
+    async def test_request_results(self, session, tmp_path):
+        """Test that entries say whether their source can be shown."""
+        sources = await session.list_loaded_sources()
+
+        by_name = {s["name"]: s for s in sources}
+        assert by_name["app.py"]["sources_available"] is True
+        assert by_name["util.py"]["sources_available"] is False
+        assert by_name["<string>"]["source_reference"] == 4
+        assert by_name["<string>"]["sources_available"] is True
+
+    @pytest.mark.asyncio
+    async def test_filter(self, session):
+        """Test that filter keeps only matching names or paths."""
+        sources = await session.list_loaded_sources(filter="site-packages")
+
+        assert [s["name"] for s in sources] == ["util.py"]
+
+    @pytest.mark.asyncio
+    async def test_events_fill_cache_without_request(self, session):
+        """Test that loadedSource events are tracked when the request is unsupported."""
+        session.adapter = SourcesAdapter(supported=False)
+
+        await session._handle_event(
+            EventType.LOADED_SOURCE, {"reason": "new", "source": {"path": "/a.py"}}
+        )
+        await session._handle_event(
+            EventType.LOADED_SOURCE, {"reason": "new", "source": {"path": "/b.py"}}
+        )
+        await session._handle_event(
+            EventType.LOADED_SOURCE, {"reason": "removed", "source": {"path": "/a.py"}}
+        )
+
+        sources = await session.list_loaded_sources()
+        assert [s["path"] for s in sources] == ["/b.py"]
+        assert session.adapter.requests == []
+
+
+class TestModules:
+    """Tests for Session.list_modules."""
+
+    @pytest.mark.asyncio
+    async def test_request_results(self, session):
+        """Test that module entries carry version and path."""
+        modules = await session.list_modules()
+
+        assert [m["name"] for m in modules] == ["app", "pkg"]
+        assert modules[1]["version"] == "2.1.0"
+        assert modules[0]["sources_available"] is True
+
+    @pytest.mark.asyncio
+    async def test_module_events_update_entries(self, session):
+        """Test that a changed module event updates the cached entry."""
+        session.adapter = SourcesAdapter(supported=False)
+
+        await session._handle_event(
+            EventType.MODULE, {"reason": "new", "module": {"id": 7, "name": "lib"}}
+        )
+        await session._handle_event(
+            EventType.MODULE,
+            {"reason": "changed", "module": {"id": 7, "name": "lib", "version": "1.0"}},
+        )
+
+        modules = await session.list_modules(filter="lib")
+        assert len(modules) == 1
+        assert modules[0]["version"] == "1.0"
@@ -0,0 +1,62 @@
+"""Tests for tagging logpoint output."""
+
+import pytest
+
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.models.dap import Breakpoint, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session with one logpoint and one regular breakpoint."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    path = str(tmp_path / "simple.go")
+    breakpoints = [
+        SourceBreakpoint(line=7, log_message="result={result}"),
+        SourceBreakpoint(line=14),
+    ]
+    session._breakpoints[path] = breakpoints
+    session._record_breakpoint_results(
+        path,
+        breakpoints,
+        [Breakpoint(id=1, verified=True, line=7), Breakpoint(id=2, verified=True, line=14)],
+    )
+    return session, path
+
+
+class TestLogpointOutput:
+    """Tests for correlating output events with logpoints."""
+
+    @pytest.mark.asyncio
+    async def test_logpoint_output_tagged(self, session):
+        """Test that output carrying a logpoint's location is tagged with its id."""
+        session, path = session
+        await session._handle_event(
+            EventType.OUTPUT,
+            {
+                "category": "stdout",
+                "output": "> [Go 1]: result=30\n",
+                "source": {"path": path},
+                "line": 7,
+            },
+        )
+        await session._handle_event(
+            EventType.OUTPUT, {"category": "stdout", "output": "Result: 30\n"}
+        )
+
+        page = session.output_buffer.get_page(logpoints_only=True)
+        assert page.total == 1
+        assert page.lines[0].logpoint_id == 1
+        assert session.output_buffer.get_page().total == 2
+
+    @pytest.mark.asyncio
+    async def test_regular_breakpoint_location_not_tagged(self, session):
+        """Test that output at a non-logpoint breakpoint line is not tagged."""
+        session, path = session
+        await session._handle_event(
+            EventType.OUTPUT,
+            {"category": "stdout", "output": "hello\n", "source": {"path": path}, "line": 14},
+        )
+
+        assert session.output_buffer.get_page().lines[0].logpoint_id is None
@@ -15,26 +15,57 @@ class TestMCPServerRegistration:
         assert "debug_list_languages" in tools  # Multi-language support
         assert "debug_list_sessions" in tools
         assert "debug_get_session" in tools
+        assert "debug_keep_alive" in tools
+        assert "debug_get_session_history" in tools
         assert "debug_terminate_session" in tools
+        assert "debug_restart_session" in tools
 
         # Breakpoint tools
         assert "debug_set_breakpoints" in tools
+        assert "debug_set_breakpoints_batch" in tools
         assert "debug_get_breakpoints" in tools
         assert "debug_clear_breakpoints" in tools
+        assert "debug_enable_breakpoints" in tools
+        assert "debug_disable_breakpoints" in tools
+        assert "debug_set_exception_breakpoints" in tools
+        assert "debug_set_function_breakpoint" in tools
+        assert "debug_set_data_breakpoint" in tools
 
         # Execution tools
         assert "debug_launch" in tools
+        assert "debug_attach" in tools
+        assert "debug_list_launch_configs" in tools
+        assert "debug_test" in tools
+        assert "debug_send_stdin" in tools
         assert "debug_continue" in tools
+        assert "debug_run_to_line" in tools
+        assert "debug_continue_until" in tools
         assert "debug_step" in tools  # Merged: over/into/out
+        assert "debug_set_step_filters" in tools
         assert "debug_pause" in tools
 
         # Inspection tools
+        assert "debug_list_threads" in tools
+        assert "debug_dump_goroutines" in tools
+        assert "debug_list_loaded_sources" in tools
+        assert "debug_list_modules" in tools
+        assert "debug_get_source" in tools
         assert "debug_get_stacktrace" in tools
+        assert "debug_get_exception_info" in tools
         assert "debug_get_scopes" in tools
         assert "debug_get_variables" in tools
+        assert "debug_expand_variable" in tools
         assert "debug_evaluate" in tools
+        assert "debug_inspect_symbol" in tools
+        assert "debug_get_completions" in tools
+        assert "debug_set_variable" in tools
         assert "debug_inspect_variable" in tools
         assert "debug_get_call_chain" in tools
+        assert "debug_get_stop_snapshot" in tools
+        assert "debug_get_full_value" in tools
+        assert "debug_disassemble" in tools
+        assert "debug_read_memory" in tools
+        assert "debug_write_memory" in tools
 
         # Watch tools
         assert "debug_watch" in tools  # Merged: add/remove/list
@@ -43,6 +74,7 @@ class TestMCPServerRegistration:
         # Event/output tools
         assert "debug_poll_events" in tools
         assert "debug_get_output" in tools
+        assert "debug_stream_output" in tools
 
         # Recovery tools
         assert "debug_list_recoverable" in tools
@@ -51,8 +83,9 @@ class TestMCPServerRegistration:
     def test_tool_count(self):
         """Test total number of tools."""
         tools = list(mcp._tool_manager._tools.keys())
-        # 24 tools: session (5), breakpoint (3), execution (4), inspection (6), watch (2), event/output (2), recovery (2)
-        assert len(tools) == 24
+        # session (8), breakpoint (9), execution (11), inspection (21), watch (2),
+        # event/output (3), recovery (2)
+        assert len(tools) == 56
 
     def test_server_name(self):
         """Test server name is set."""
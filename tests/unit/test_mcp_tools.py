@@ -1,33 +1,118 @@
 """Tests for MCP server tool functions."""
 
+from datetime import timedelta
+
 import pytest
 
 import polybugger_mcp.mcp_server as mcp_server
-from polybugger_mcp.core.session import SessionManager
+from polybugger_mcp.adapters.renderers import ValueRenderer
+from polybugger_mcp.core.session import SessionManager, SessionState
 from polybugger_mcp.mcp_server import (
     _get_manager,
+    debug_attach,
     debug_clear_breakpoints,
     debug_continue,
+    debug_continue_until,
     debug_create_session,
+    debug_disable_breakpoints,
+    debug_disassemble,
+    debug_dump_goroutines,
     debug_evaluate,
     debug_evaluate_watches,
+    debug_expand_variable,
     debug_get_breakpoints,
+    debug_get_completions,
+    debug_get_exception_info,
+    debug_get_full_value,
     debug_get_output,
     debug_get_scopes,
     debug_get_session,
+    debug_get_session_history,
+    debug_get_source,
     debug_get_stacktrace,
     debug_get_variables,
+    debug_inspect_symbol,
+    debug_keep_alive,
     debug_launch,
+    debug_list_launch_configs,
     debug_list_recoverable,
     debug_list_sessions,
+    debug_list_threads,
     debug_pause,
     debug_poll_events,
+    debug_read_memory,
     debug_recover_session,
+    debug_send_stdin,
     debug_set_breakpoints,
+    debug_set_breakpoints_batch,
+    debug_set_function_breakpoint,
+    debug_set_variable,
     debug_step,
+    debug_stream_output,
     debug_terminate_session,
+    debug_test,
     debug_watch,
+    debug_write_memory,
 )
+from polybugger_mcp.models.dap import Breakpoint, Thread
+
+
+class _GoroutineAdapter:
+    """Stand-in adapter reporting many goroutines."""
+
+    async def get_threads(self):
+        return [Thread(id=i, name=f"goroutine {i}") for i in range(1, 251)]
+
+    async def disconnect(self, terminate=True):
+        pass
+
+
+class _LongValueAdapter:
+    """Stand-in adapter whose values are 100 characters long."""
+
+    capabilities = {"supportsClipboardContext": True}
+    renderer = ValueRenderer()
+
+    async def evaluate(self, expression, frame_id=None, context="repl"):
+        return {"result": "0123456789" * 10, "type": "str"}
+
+    async def evaluate_full(self, expression, frame_id=None):
+        return "0123456789" * 10
+
+    async def disconnect(self, terminate=True):
+        pass
+
+
+class _FunctionBreakpointAdapter:
+    """Stand-in adapter that may or may not advertise function breakpoints."""
+
+    is_launched = True
+
+    def __init__(self, supported):
+        self.capabilities = {"supportsFunctionBreakpoints": supported}
+
+    async def set_function_breakpoints(self, breakpoints):
+        return [Breakpoint(id=i + 1, verified=True, line=3) for i in range(len(breakpoints))]
+
+    async def disconnect(self, terminate=True):
+        pass
+
+
+class _RejectingAdapter:
+    """Stand-in adapter that refuses any conditional breakpoint."""
+
+    is_launched = True
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        return [
+            Breakpoint(verified=False, line=bp.line, message="invalid syntax")
+            if bp.condition
+            else Breakpoint(verified=True, line=bp.line)
+            for bp in breakpoints
+        ]
+
+    async def disconnect(self, terminate=True):
+        pass
 
 
 @pytest.fixture
@@ -103,6 +188,14 @@ class TestSessionTools:
         assert "error" in result
         assert result["code"] == "SESSION_LIMIT"
 
+    @pytest.mark.asyncio
+    async def test_create_session_tcp_requires_port(self, session_manager, tmp_path):
+        """Test that the tcp transport is refused without a port."""
+        result = await debug_create_session(project_root=str(tmp_path), transport="tcp")
+
+        assert result["code"] == "INVALID_CONFIG"
+        assert await session_manager.list_sessions() == []
+
     @pytest.mark.asyncio
     async def test_list_sessions(self, session_manager, tmp_path):
         """Test debug_list_sessions tool."""
@@ -115,6 +208,41 @@ class TestSessionTools:
         assert result["total"] == 1
         assert len(result["sessions"]) == 1
 
+    @pytest.mark.asyncio
+    async def test_keep_alive_then_idle_timeout(self, session_manager, tmp_path):
+        """Test that debug_keep_alive sets the timeout and subscribes to session end."""
+
+        class FakeClient:
+            def __init__(self):
+                self.messages = []
+
+            async def send_log_message(self, level, data, logger=None):
+                self.messages.append((level, data, logger))
+
+        class FakeContext:
+            session = FakeClient()
+
+        ctx = FakeContext()
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+
+        result = await debug_keep_alive(ctx, timeout_minutes=2)
+        assert result["idle_timeout_seconds"] == 120
+        assert result["idle_remaining_seconds"] > 110
+        assert (await debug_keep_alive(ctx, timeout_minutes=0))["code"] == "INVALID_RANGE"
+
+        session.last_activity -= timedelta(minutes=3)
+        session_manager.add_end_listener(mcp_server._notify_session_ended)
+        await session_manager._cleanup_stale_sessions()
+
+        assert ctx.session.messages == [
+            (
+                "warning",
+                {"event": "session_ended", "session_id": session.id, "reason": "idle_timeout"},
+                mcp_server.SESSION_LOGGER,
+            )
+        ]
+
     @pytest.mark.asyncio
     async def test_get_session(self, session_manager, tmp_path):
         """Test debug_get_session tool."""
@@ -126,6 +254,17 @@ class TestSessionTools:
         assert result["session_id"] == session_id
         assert result["state"] == "created"
 
+    @pytest.mark.asyncio
+    async def test_get_session_redacts_env(self, session_manager, tmp_path):
+        """Test that redact_env keeps variable names but hides values."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.launch_env = {"API_TOKEN": "secret"}
+
+        result = await debug_get_session(redact_env=True)
+
+        assert result["env"] == {"API_TOKEN": "<redacted>"}
+
     @pytest.mark.asyncio
     async def test_get_session_not_found(self, session_manager):
         """Test debug_get_session with non-existent session."""
@@ -134,6 +273,46 @@ class TestSessionTools:
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
 
+    @pytest.mark.asyncio
+    async def test_get_session_defaults_to_only_session(self, session_manager, tmp_path):
+        """Test that session_id can be omitted while one session exists."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_get_session()
+
+        assert result["session_id"] == create_result["session_id"]
+
+    @pytest.mark.asyncio
+    async def test_omitted_session_id_ambiguous(self, session_manager, tmp_path):
+        """Test that omitting session_id with several sessions is an error."""
+        first = await debug_create_session(project_root=str(tmp_path), name="client")
+        second = await debug_create_session(project_root=str(tmp_path), name="server")
+
+        result = await debug_get_session()
+
+        assert result["code"] == "SESSION_AMBIGUOUS"
+        assert first["session_id"] in result["error"]
+        assert second["session_id"] in result["error"]
+
+    @pytest.mark.asyncio
+    async def test_omitted_session_id_without_sessions(self, session_manager):
+        """Test that omitting session_id with no sessions is an error."""
+        result = await debug_get_session()
+
+        assert result["code"] == "NO_SESSION"
+
+    @pytest.mark.asyncio
+    async def test_terminate_one_of_several_sessions(self, session_manager, tmp_path):
+        """Test that terminating one session leaves the others usable."""
+        first = await debug_create_session(project_root=str(tmp_path), name="client")
+        second = await debug_create_session(project_root=str(tmp_path), name="server")
+
+        await debug_terminate_session(session_id=first["session_id"])
+
+        result = await debug_get_session()
+        assert result["session_id"] == second["session_id"]
+        assert result["state"] == "created"
+
     @pytest.mark.asyncio
     async def test_terminate_session(self, session_manager, tmp_path):
         """Test debug_terminate_session tool."""
@@ -145,6 +324,30 @@ class TestSessionTools:
         assert result["status"] == "terminated"
         assert result["session_id"] == session_id
 
+    @pytest.mark.asyncio
+    async def test_history_after_terminate(self, session_manager, tmp_path):
+        """Test that tool calls are recorded and readable once the session ends."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session_id = create_result["session_id"]
+        await debug_set_breakpoints(file_path=str(tmp_path / "app.py"), lines=[3])
+        await debug_get_session()
+        await debug_terminate_session(session_id=session_id)
+
+        result = await debug_get_session_history(session_id=session_id)
+
+        assert [e["type"] for e in result["entries"]] == [
+            "debug_create_session",
+            "debug_set_breakpoints",
+            "debug_terminate_session",
+        ]
+        assert result["entries"][1]["data"]["arguments"]["lines"] == [3]
+        assert result["session_ended"] is True
+
+        newer = await debug_get_session_history(
+            since=result["next_since"], session_id=session_id
+        )
+        assert newer["entries"] == []
+
     @pytest.mark.asyncio
     async def test_terminate_session_not_found(self, session_manager):
         """Test debug_terminate_session with non-existent session."""
@@ -250,6 +453,85 @@ class TestBreakpointTools:
         assert result["status"] == "cleared"
         assert result["files"] == "all"
 
+    @pytest.mark.asyncio
+    async def test_function_breakpoint_listed_and_cleared(self, session_manager, tmp_path):
+        """Test that function breakpoints show up in the listing and clear with the rest."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.adapter = _FunctionBreakpointAdapter(supported=True)
+
+        result = await debug_set_function_breakpoint(name="main.(*Server).Handle")
+        listed = await debug_get_breakpoints()
+        await debug_clear_breakpoints()
+
+        assert (result["status"], result["verified"], result["bound_line"]) == ("set", True, 3)
+        assert [bp["name"] for bp in listed["function_breakpoints"]] == ["main.(*Server).Handle"]
+        assert (await debug_get_breakpoints())["function_breakpoints"] == []
+
+    @pytest.mark.asyncio
+    async def test_function_breakpoint_not_supported(self, session_manager, tmp_path):
+        """Test that adapters without supportsFunctionBreakpoints are reported."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.adapter = _FunctionBreakpointAdapter(supported=False)
+
+        result = await debug_set_function_breakpoint(name="app.handler.process_request")
+
+        assert result["code"] == "NOT_SUPPORTED"
+        assert "supportsFunctionBreakpoints" in result["error"]
+
+    @pytest.mark.asyncio
+    async def test_set_breakpoints_batch(self, session_manager, tmp_path):
+        """Test that batch entries are added per file and reported in input order."""
+        first = str(tmp_path / "a.py")
+        second = str(tmp_path / "b.py")
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session_id = create_result["session_id"]
+        await debug_set_breakpoints(session_id=session_id, file_path=first, lines=[1])
+
+        result = await debug_set_breakpoints_batch(
+            breakpoints=[
+                {"file": second, "line": 4, "log_message": "b={b}"},
+                {"file": first, "line": 9, "condition": "x > 1"},
+                {"file": first},
+            ],
+            session_id=session_id,
+        )
+
+        assert result["files"] == 2
+        assert result["failed"] == 1
+        entries = result["breakpoints"]
+        assert [(e["file"], e["line"]) for e in entries] == [(second, 4), (first, 9), (first, None)]
+        assert entries[1]["condition"] == "x > 1"
+        assert entries[2]["code"] == "INVALID_ARGS"
+        listed = await debug_get_breakpoints(session_id=session_id)
+        assert [bp["line"] for bp in listed["files"][first]] == [1, 9]
+
+    @pytest.mark.asyncio
+    async def test_disable_breakpoints_in_file(self, session_manager, tmp_path):
+        """Test that disabled breakpoints stay listed, flagged as disabled."""
+        test_file = tmp_path / "test.py"
+        test_file.write_text("x = 1\ny = 2\n")
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session_id = create_result["session_id"]
+        await debug_set_breakpoints(session_id=session_id, file_path=str(test_file), lines=[1, 2])
+
+        result = await debug_disable_breakpoints(file_path=str(test_file), session_id=session_id)
+
+        assert result["status"] == "disabled"
+        assert [bp["line"] for bp in result["changed"]] == [1, 2]
+        listed = await debug_get_breakpoints(session_id=session_id)
+        assert [bp["enabled"] for bp in listed["files"][str(test_file)]] == [False, False]
+
+    @pytest.mark.asyncio
+    async def test_disable_breakpoints_needs_one_selector(self, session_manager):
+        """Test that exactly one of ids, file or all must be given."""
+        result = await debug_disable_breakpoints()
+        assert result["code"] == "INVALID_ARGS"
+
+        result = await debug_disable_breakpoints(breakpoint_ids=[1], all_breakpoints=True)
+        assert result["code"] == "INVALID_ARGS"
+
     @pytest.mark.asyncio
     async def test_clear_breakpoints_not_found(self, session_manager):
         """Test debug_clear_breakpoints with non-existent session."""
@@ -366,6 +648,34 @@ class TestBreakpointTools:
         assert breakpoints[1]["hit_condition"] == ">=3"
         assert breakpoints[1]["log_message"] is None
 
+    @pytest.mark.asyncio
+    async def test_set_breakpoints_rejected_condition(self, session_manager, tmp_path):
+        """Test that a condition refused by the adapter is reported as a structured error."""
+        test_file = tmp_path / "test.py"
+        test_file.write_text("x = 1\ny = 2\n")
+
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session_id = create_result["session_id"]
+        session = await session_manager.get_session(session_id)
+        await session.adapter.disconnect()
+        session.adapter = _RejectingAdapter()
+
+        result = await debug_set_breakpoints(
+            session_id=session_id,
+            file_path=str(test_file),
+            lines=[1, 2],
+            conditions=["x ==", None],
+        )
+
+        assert result["code"] == "INVALID_CONDITION"
+        assert len(result["rejected"]) == 1
+        assert result["rejected"][0]["line"] == 1
+        assert result["rejected"][0]["condition"] == "x =="
+        assert "invalid syntax" in result["rejected"][0]["message"]
+        # The valid breakpoint is still reported and the bad one kept for correction
+        assert result["breakpoints"][1]["verified"] is True
+        assert len(session._breakpoints[str(test_file)]) == 2
+
 
 class TestWatchTools:
     """Tests for watch expression tools."""
@@ -379,7 +689,8 @@ class TestWatchTools:
         result = await debug_watch(session_id=session_id, action="add", expression="x + y")
 
         assert "watches" in result
-        assert "x + y" in result["watches"]
+        assert result["watches"][0]["expression"] == "x + y"
+        assert result["watches"][0]["id"] == result["id"]
 
     @pytest.mark.asyncio
     async def test_watch_add_not_found(self, session_manager):
@@ -411,7 +722,34 @@ class TestWatchTools:
         result = await debug_watch(session_id=session_id, action="remove", expression="x")
 
         assert "watches" in result
-        assert "x" not in result["watches"]
+        assert "x" not in [w["expression"] for w in result["watches"]]
+
+    @pytest.mark.asyncio
+    async def test_watch_remove_by_id(self, session_manager, tmp_path):
+        """Test debug_watch remove by watch id keeps other ids stable."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session_id = create_result["session_id"]
+
+        first = await debug_watch(session_id=session_id, action="add", expression="x")
+        second = await debug_watch(session_id=session_id, action="add", expression="y")
+
+        result = await debug_watch(session_id=session_id, action="remove", watch_id=first["id"])
+
+        assert result["watches"] == [
+            {"id": second["id"], "expression": "y", "result": None, "type": None, "error": None}
+        ]
+
+    @pytest.mark.asyncio
+    async def test_watch_remove_unknown_id(self, session_manager, tmp_path):
+        """Test debug_watch remove with an id no watch has."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session_id = create_result["session_id"]
+
+        await debug_watch(session_id=session_id, action="add", expression="x")
+
+        result = await debug_watch(session_id=session_id, action="remove", watch_id=99)
+
+        assert result["code"] == "WATCH_NOT_FOUND"
 
     @pytest.mark.asyncio
     async def test_watch_remove_not_found(self, session_manager):
@@ -478,6 +816,58 @@ class TestOutputTools:
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
 
+    @pytest.mark.asyncio
+    async def test_get_output_since_seq(self, session_manager, tmp_path):
+        """Test debug_get_output with a sequence cursor and category."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        for i in range(4):
+            session.output_buffer.append("stderr" if i % 2 else "stdout", f"{i}\n")
+
+        result = await debug_get_output(since_seq=1, category="stdout")
+
+        assert [line["content"] for line in result["lines"]] == ["2\n"]
+        assert result["last_seq"] == 4
+
+    @pytest.mark.asyncio
+    async def test_get_output_from_file_without_log(self, session_manager, tmp_path):
+        """Test that reading the output file needs a launch with output_log_path."""
+        await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_get_output(from_file=True)
+
+        assert result["code"] == "NO_OUTPUT_LOG"
+
+    @pytest.mark.asyncio
+    async def test_stream_output(self, session_manager, tmp_path):
+        """Test that debug_stream_output sends output as log notifications."""
+
+        class FakeClient:
+            def __init__(self):
+                self.messages = []
+
+            async def send_log_message(self, level, data, logger=None):
+                self.messages.append((level, data, logger))
+
+        class FakeContext:
+            session = FakeClient()
+
+        ctx = FakeContext()
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+
+        result = await debug_stream_output(ctx)
+        assert result["streaming"] is True
+        session._handle_output("stdout", "hello\n")
+
+        result = await debug_stream_output(ctx, enabled=False)
+        assert result["streaming"] is False
+        session._handle_output("stdout", "after\n")
+
+        assert [(m[1]["text"], m[2]) for m in ctx.session.messages] == [
+            ("hello\n", mcp_server.OUTPUT_LOGGER)
+        ]
+
     @pytest.mark.asyncio
     async def test_poll_events(self, session_manager, tmp_path):
         """Test debug_poll_events tool."""
@@ -543,6 +933,85 @@ class TestLaunchTool:
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
 
+    @pytest.mark.asyncio
+    async def test_launch_invalid_stdin_mode(self, session_manager, tmp_path):
+        """Test debug_launch rejects unknown stdin modes."""
+        await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_launch(program="/test.py", stdin_mode="tty")
+
+        assert result["code"] == "INVALID_CONFIG"
+
+    @pytest.mark.asyncio
+    async def test_launch_unknown_config_name(self, session_manager, tmp_path):
+        """Test debug_launch with a config_name missing from launch.json."""
+        (tmp_path / ".vscode").mkdir()
+        (tmp_path / ".vscode" / "launch.json").write_text(
+            '{"configurations": [{"name": "App", "type": "python", "program": "app.py"}]}'
+        )
+        await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_launch(config_name="Worker")
+
+        assert result["code"] == "INVALID_LAUNCH_CONFIG"
+        assert result["available"] == ["App"]
+
+    @pytest.mark.asyncio
+    async def test_list_launch_configs(self, session_manager, tmp_path):
+        """Test debug_list_launch_configs reads the session's project."""
+        (tmp_path / ".vscode").mkdir()
+        (tmp_path / ".vscode" / "launch.json").write_text(
+            '{"configurations": [{"name": "App", "type": "go", "program": "."}]}'
+        )
+        await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_list_launch_configs()
+
+        assert [c["name"] for c in result["configurations"]] == ["App"]
+        assert result["configurations"][0]["adapter"] == "delve"
+
+    @pytest.mark.asyncio
+    async def test_debug_test_unknown_framework(self, session_manager, tmp_path):
+        """Test debug_test rejects frameworks it can't build a launch for."""
+        result = await debug_test(project_root=str(tmp_path), framework="jest", test_id="x")
+
+        assert result["code"] == "INVALID_CONFIG"
+        assert result["supported"] == ["go", "pytest", "unittest"]
+
+    @pytest.mark.asyncio
+    async def test_debug_test_failure_ends_session(self, session_manager, tmp_path):
+        """Test debug_test doesn't leave its session behind when the launch fails."""
+        result = await debug_test(project_root=str(tmp_path), framework="pytest", test_id="")
+
+        assert result["code"] == "INVALID_LAUNCH_CONFIG"
+        assert result["session_id"] not in [s.id for s in await session_manager.list_sessions()]
+
+    @pytest.mark.asyncio
+    async def test_send_stdin_before_launch(self, session_manager, tmp_path):
+        """Test debug_send_stdin before the program is running."""
+        await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_send_stdin(data="hello")
+
+        assert result["code"] == "INVALID_STATE"
+
+    @pytest.mark.asyncio
+    async def test_attach_requires_target(self, session_manager, tmp_path):
+        """Test debug_attach without a port or process id."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_attach(session_id=create_result["session_id"])
+
+        assert result["code"] == "INVALID_CONFIG"
+
+    @pytest.mark.asyncio
+    async def test_attach_not_found(self, session_manager):
+        """Test debug_attach with non-existent session."""
+        result = await debug_attach(session_id="nonexistent", port=5678)
+
+        assert "error" in result
+        assert result["code"] == "NOT_FOUND"
+
 
 class TestExecutionToolsNotFound:
     """Tests for execution tools with non-existent sessions."""
@@ -594,6 +1063,15 @@ class TestExecutionToolsNotFound:
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
 
+    @pytest.mark.asyncio
+    async def test_invalid_wait_for_stop(self, session_manager):
+        """Test that a non-positive wait_for_stop_seconds is rejected up front."""
+        result = await debug_continue(wait_for_stop_seconds=0, session_id="nonexistent")
+        assert result["code"] == "INVALID_ARGS"
+
+        result = await debug_step(mode="over", wait_for_stop_seconds=-1)
+        assert result["code"] == "INVALID_ARGS"
+
 
 class TestInspectionToolsNotFound:
     """Tests for inspection tools with non-existent sessions."""
@@ -605,6 +1083,61 @@ class TestInspectionToolsNotFound:
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
 
+    @pytest.mark.asyncio
+    async def test_get_source_not_found(self, session_manager):
+        """Test debug_get_source with non-existent session."""
+        result = await debug_get_source(source_reference=3, session_id="nonexistent")
+        assert "error" in result
+        assert result["code"] == "NOT_FOUND"
+
+    @pytest.mark.asyncio
+    async def test_get_source_invalid_reference(self, session_manager):
+        """Test that debug_get_source refuses references that can't be virtual."""
+        result = await debug_get_source(source_reference=0)
+        assert result["code"] == "INVALID_RANGE"
+
+    @pytest.mark.asyncio
+    async def test_get_exception_info_depth_range(self, session_manager):
+        """Test that debug_get_exception_info refuses an out-of-range max_depth."""
+        result = await debug_get_exception_info(max_depth=0)
+        assert result["code"] == "INVALID_RANGE"
+
+    @pytest.mark.asyncio
+    async def test_read_memory_needs_one_target(self, session_manager):
+        """Test that debug_read_memory needs exactly one of reference and expression."""
+        result = await debug_read_memory()
+        assert result["code"] == "INVALID_ARGS"
+
+        result = await debug_read_memory(memory_reference="0x10", expression="p")
+        assert result["code"] == "INVALID_ARGS"
+
+    @pytest.mark.asyncio
+    async def test_write_memory_invalid_data(self, session_manager):
+        """Test that debug_write_memory refuses data that doesn't decode."""
+        result = await debug_write_memory(data="zz", memory_reference="0x10")
+        assert result["code"] == "INVALID_ARGS"
+
+        result = await debug_write_memory(data="2a", memory_reference="0x10", encoding="utf8")
+        assert result["code"] == "INVALID_ARGS"
+
+    @pytest.mark.asyncio
+    async def test_continue_until_needs_iteration_limit(self, session_manager):
+        """Test that debug_continue_until refuses an out-of-range max_iterations."""
+        result = await debug_continue_until(expression="x > 1", max_iterations=0)
+        assert result["code"] == "INVALID_RANGE"
+
+    @pytest.mark.asyncio
+    async def test_expand_variable_not_found(self, session_manager):
+        """Test debug_expand_variable with non-existent session."""
+        result = await debug_expand_variable(variables_reference=1, session_id="nonexistent")
+        assert result["code"] == "NOT_FOUND"
+
+    @pytest.mark.asyncio
+    async def test_expand_variable_depth_capped(self, session_manager):
+        """Test that debug_expand_variable refuses depths beyond the cap."""
+        result = await debug_expand_variable(variables_reference=1, depth=6)
+        assert result["code"] == "INVALID_RANGE"
+
     @pytest.mark.asyncio
     async def test_get_scopes_not_found(self, session_manager):
         """Test debug_get_scopes with non-existent session."""
@@ -626,9 +1159,203 @@ class TestInspectionToolsNotFound:
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
 
+    @pytest.mark.asyncio
+    async def test_evaluate_invalid_context(self, session_manager):
+        """Test debug_evaluate rejects unknown contexts before resolving a session."""
+        result = await debug_evaluate(expression="x", context="clipboard")
+        assert result["code"] == "INVALID_CONTEXT"
+
+    @pytest.mark.asyncio
+    async def test_inspect_symbol_invalid_position(self, session_manager):
+        """Test debug_inspect_symbol rejects positions before line/column 1."""
+        result = await debug_inspect_symbol(file="app.py", line=3, column=0)
+        assert result["code"] == "INVALID_RANGE"
+
+    @pytest.mark.asyncio
+    async def test_get_stacktrace_context_lines_range(self, session_manager):
+        """Test debug_get_stacktrace rejects context_lines outside 0-20."""
+        result = await debug_get_stacktrace(include_source_context=True, context_lines=21)
+        assert result["code"] == "INVALID_RANGE"
+
+    @pytest.mark.asyncio
+    async def test_get_variables_needs_target(self, session_manager):
+        """Test debug_get_variables without a reference or frame."""
+        result = await debug_get_variables()
+        assert result["code"] == "INVALID_ARGS"
+
+    @pytest.mark.asyncio
+    async def test_get_variables_max_count_alias(self, session_manager):
+        """Test that the deprecated max_count still sets the page size."""
+        result = await debug_get_variables(variables_reference=1, max_count=0)
+        assert result["code"] == "INVALID_RANGE"
+
     @pytest.mark.asyncio
     async def test_evaluate_watches_not_found(self, session_manager):
         """Test debug_evaluate_watches with non-existent session."""
         result = await debug_evaluate_watches(session_id="nonexistent")
         assert "error" in result
         assert result["code"] == "NOT_FOUND"
+
+    @pytest.mark.asyncio
+    async def test_set_variable_not_found(self, session_manager):
+        """Test debug_set_variable with non-existent session."""
+        result = await debug_set_variable(
+            session_id="nonexistent", value="1", name="x", variables_reference=1
+        )
+        assert result["code"] == "NOT_FOUND"
+
+    @pytest.mark.asyncio
+    async def test_set_variable_needs_target(self, session_manager):
+        """Test debug_set_variable without a name/reference or expression."""
+        result = await debug_set_variable(value="1", name="x")
+        assert result["code"] == "INVALID_ARGS"
+        result = await debug_set_variable(value="1", name="x", expression="x")
+        assert result["code"] == "INVALID_ARGS"
+
+
+class TestThreadTools:
+    """Tests for listing threads."""
+
+    @pytest.mark.asyncio
+    async def test_list_threads_paged(self, session_manager, tmp_path):
+        """Test debug_list_threads pages goroutines and marks the stopped one."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.adapter = _GoroutineAdapter()
+        session.current_thread_id = 120
+
+        result = await debug_list_threads(limit=50, offset=100)
+
+        assert [t["id"] for t in result["threads"]] == list(range(101, 151))
+        assert [t["id"] for t in result["threads"] if t["stopped"]] == [120]
+        assert result["stopped_thread_id"] == 120
+        assert result["total"] == 250
+        assert result["has_more"] is True
+
+    @pytest.mark.asyncio
+    async def test_list_threads_invalid_range(self, session_manager):
+        """Test debug_list_threads rejects a bad page before resolving a session."""
+        result = await debug_list_threads(limit=0)
+        assert result["code"] == "INVALID_RANGE"
+
+    @pytest.mark.asyncio
+    async def test_dump_goroutines_needs_delve(self, session_manager, tmp_path):
+        """Test debug_dump_goroutines refuses sessions on other adapters."""
+        await debug_create_session(project_root=str(tmp_path))
+
+        result = await debug_dump_goroutines()
+
+        assert result["code"] == "NOT_SUPPORTED"
+
+
+class TestValueTools:
+    """Tests for value truncation and full-value chunks."""
+
+    @pytest.fixture
+    async def session(self, session_manager, tmp_path):
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.adapter = _LongValueAdapter()
+        session._state = SessionState.PAUSED
+        return session
+
+    @pytest.mark.asyncio
+    async def test_evaluate_truncated(self, session):
+        """Test that evaluate clips to max_length and says so."""
+        result = await debug_evaluate(expression="s", max_length=25)
+
+        assert result["result"] == "0123456789012345678901234"
+        assert result["truncated"] is True
+        assert result["length"] == 100
+
+    @pytest.mark.asyncio
+    async def test_full_value_chunks(self, session):
+        """Test that continuation tokens walk through the whole value."""
+        first = await debug_get_full_value(expression="s", chunk_size=40)
+        second = await debug_get_full_value(continuation_token=first["continuation_token"])
+
+        assert first["value"] == ("0123456789" * 4)
+        assert first["complete"] is False
+        assert second["offset"] == 40
+        assert second["complete"] is True
+        assert second["continuation_token"] is None
+        assert first["value"] + second["value"] == "0123456789" * 10
+
+    @pytest.mark.asyncio
+    async def test_full_value_bad_token(self, session):
+        """Test that an unknown token is rejected."""
+        result = await debug_get_full_value(continuation_token="nope:0")
+        assert result["code"] == "INVALID_TOKEN"
+
+    @pytest.mark.asyncio
+    async def test_render_invalid(self, session):
+        """Test that an unknown render mode is rejected."""
+        result = await debug_evaluate(expression="s", render="pretty")
+        assert result["code"] == "INVALID_RENDER"
+
+    @pytest.mark.asyncio
+    async def test_render_not_supported(self, session):
+        """Test that adapters without a structured renderer say so."""
+        result = await debug_get_variables(variables_reference=1, render="summary")
+
+        assert result["code"] == "NOT_SUPPORTED"
+        assert result["supported"] == ["raw"]
+
+    @pytest.mark.asyncio
+    async def test_full_value_needs_target(self, session_manager):
+        """Test that a target is required before resolving the session."""
+        result = await debug_get_full_value(name="x")
+        assert result["code"] == "INVALID_ARGS"
+
+
+class TestDisassembleTools:
+    """Tests for disassembly and instruction steps on unsupported adapters."""
+
+    @pytest.fixture
+    async def session(self, session_manager, tmp_path):
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.adapter = _GoroutineAdapter()
+        session.adapter.capabilities = {}
+        session._state = SessionState.PAUSED
+        return session
+
+    @pytest.mark.asyncio
+    async def test_disassemble_names_supporting_adapters(self, session):
+        """Test that the capability error says which adapters disassemble."""
+        result = await debug_disassemble(memory_reference="0x1000")
+
+        assert result["code"] == "NOT_SUPPORTED"
+        assert "delve" in result["hint"]
+
+    @pytest.mark.asyncio
+    async def test_instruction_step_not_supported(self, session):
+        """Test that instruction steps on debugpy-like adapters are refused."""
+        result = await debug_step(mode="over", granularity="instruction")
+
+        assert result["code"] == "NOT_SUPPORTED"
+        assert "codelldb" in result["hint"]
+
+
+class TestCompletionTools:
+    """Tests for debug_get_completions."""
+
+    @pytest.mark.asyncio
+    async def test_unsupported_adapter_degrades(self, session_manager, tmp_path):
+        """Test that adapters without completions get an empty list and a note."""
+        create_result = await debug_create_session(project_root=str(tmp_path))
+        session = await session_manager.get_session(create_result["session_id"])
+        session.adapter = _GoroutineAdapter()
+        session.adapter.capabilities = {}
+        session._state = SessionState.PAUSED
+
+        result = await debug_get_completions(text="obj.")
+
+        assert result["completions"] == []
+        assert "note" in result
+
+    @pytest.mark.asyncio
+    async def test_column_out_of_range(self, session_manager):
+        """Test that a cursor outside the text is rejected."""
+        result = await debug_get_completions(text="obj.", column=9)
+        assert result["code"] == "INVALID_RANGE"
@@ -0,0 +1,106 @@
+"""Tests for reading and writing debuggee memory."""
+
+import base64
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.exceptions import CapabilityNotSupportedError, EvaluateError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+
+
+class MemoryAdapter:
+    """Adapter stub for a target whose mapping ends 4 bytes past 0xc000."""
+
+    read_memory = DebugAdapter.read_memory
+    write_memory = DebugAdapter.write_memory
+
+    def __init__(self, supported: bool = True):
+        self.capabilities = {
+            "supportsReadMemoryRequest": supported,
+            "supportsWriteMemoryRequest": supported,
+        }
+        self.requests: list[tuple[str, dict]] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=10, name="main.main", source=Source(path="/src/main.go"), line=8)]
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        self.requests.append(("evaluate", {"expression": expression, "frameId": frame_id}))
+        if expression == "buf":
+            return {"result": "*[]uint8", "variablesReference": 0, "memoryReference": "0xc000"}
+        return {"result": "3", "variablesReference": 0}
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append((command, arguments))
+        if command == "readMemory":
+            readable = max(0, min(arguments["count"], 4 - arguments["offset"]))
+            return {
+                "address": hex(0xC000 + arguments["offset"]),
+                "data": base64.b64encode(b"GO!\x00"[:readable]).decode(),
+                "unreadableBytes": arguments["count"] - readable,
+            }
+        return {}
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session paused in compiled code."""
+    session = Session(session_id="test_session", project_root=tmp_path, language="go")
+    session.adapter = MemoryAdapter()  # type: ignore[assignment]
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestReadMemory:
+    """Tests for Session.read_memory."""
+
+    @pytest.mark.asyncio
+    async def test_pointer_expression(self, session):
+        """Test that an expression is read at its memory reference, in the top frame."""
+        result = await session.read_memory(expression="buf", count=4)
+
+        assert result["memory_reference"] == "0xc000"
+        assert result["data"] == b"GO!\x00"
+        assert session.adapter.requests[0] == ("evaluate", {"expression": "buf", "frameId": 10})
+
+    @pytest.mark.asyncio
+    async def test_partial_read(self, session):
+        """Test that reading past mapped memory reports the unreadable rest."""
+        result = await session.read_memory(memory_reference="0xc000", offset=2, count=16)
+
+        assert result["address"] == "0xc002"
+        assert result["bytes_read"] == 2
+        assert result["unreadable_bytes"] == 14
+
+    @pytest.mark.asyncio
+    async def test_expression_without_reference(self, session):
+        """Test that values the adapter gives no memory reference are refused."""
+        with pytest.raises(EvaluateError, match="no memory reference"):
+            await session.read_memory(expression="len(buf)")
+
+    @pytest.mark.asyncio
+    async def test_unsupported_adapter(self, session):
+        """Test that adapters without memory access (like debugpy) are refused."""
+        session.adapter = MemoryAdapter(supported=False)
+
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.read_memory(memory_reference="0xc000")
+        assert session.adapter.requests == []
+
+
+class TestWriteMemory:
+    """Tests for Session.write_memory."""
+
+    @pytest.mark.asyncio
+    async def test_sends_base64(self, session):
+        """Test that bytes are sent base64-encoded at the offset."""
+        result = await session.write_memory(b"\x2a\xff", memory_reference="0xc000", offset=1)
+
+        assert session.adapter.requests[-1] == (
+            "writeMemory",
+            {"memoryReference": "0xc000", "offset": 1, "data": "Kv8=", "allowPartial": False},
+        )
+        assert result == {"memory_reference": "0xc000", "offset": 1, "bytes_written": 2}
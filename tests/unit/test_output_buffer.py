@@ -77,6 +77,15 @@ class TestOutputBuffer:
         assert len(page.lines) == 5
         assert page.lines[0].line_number == 6
 
+    def test_get_since_with_category(self, output_buffer: OutputBuffer) -> None:
+        """Test combining a sequence cursor with a category filter."""
+        for i in range(6):
+            output_buffer.append("stderr" if i % 2 else "stdout", f"Line {i}\n")
+
+        page = output_buffer.get_since(line_number=2, category="stderr")
+
+        assert [line.line_number for line in page.lines] == [4, 6]
+
     def test_ring_buffer_drops_old_entries(self) -> None:
         """Test that old entries are dropped when buffer is full."""
         # Create small buffer (100 bytes)
@@ -131,3 +140,16 @@ class TestOutputBuffer:
         page = output_buffer.get_page()
 
         assert page.lines[0].timestamp is not None
+
+    def test_logpoint_filter(self, output_buffer: OutputBuffer) -> None:
+        """Test filtering output down to logpoint messages."""
+        output_buffer.append("stdout", "program output\n")
+        output_buffer.append("stdout", "x=1\n", logpoint_id=3)
+        output_buffer.append("stdout", "y=2\n", logpoint_id=4)
+
+        page = output_buffer.get_page(logpoints_only=True)
+        assert [line.content for line in page.lines] == ["x=1\n", "y=2\n"]
+
+        page = output_buffer.get_page(logpoint_id=4)
+        assert page.total == 1
+        assert page.lines[0].logpoint_id == 4
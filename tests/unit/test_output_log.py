@@ -0,0 +1,115 @@
+"""Tests for the rotating output log file."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.utils.output_buffer import OutputBuffer
+from polybugger_mcp.utils.output_log import OutputLog
+
+
+def push_lines(log: OutputLog, lines: list[tuple[str, str]]) -> None:
+    buffer = OutputBuffer()
+    for category, content in lines:
+        log.push("s1", buffer.append(category, content))
+
+
+class TestOutputLog:
+    """Tests for writing, rotating and reading the log."""
+
+    @pytest.mark.asyncio
+    async def test_records_have_timestamp_and_category(self, tmp_path):
+        """Test that each line of output becomes one prefixed record."""
+        log = OutputLog(tmp_path / "out.log")
+        log.start()
+
+        push_lines(log, [("stdout", "a\nb\n"), ("stderr", "oops\n")])
+        await log.close()
+
+        records = (tmp_path / "out.log").read_text().splitlines()
+        assert [r.split(" ", 1)[1] for r in records] == [
+            "[stdout] a",
+            "[stdout] b",
+            "[stderr] oops",
+        ]
+
+    @pytest.mark.asyncio
+    async def test_rotation_keeps_backup_count_files(self, tmp_path):
+        """Test that the log rotates by size and deletes the oldest file."""
+        log = OutputLog(tmp_path / "out.log", max_bytes=200, backup_count=2)
+        log.start()
+
+        for i in range(20):
+            push_lines(log, [("stdout", f"line {i:02} {'x' * 40}\n")])
+            await asyncio.sleep(0)
+        await log.close()
+
+        assert sorted(p.name for p in tmp_path.iterdir()) == [
+            "out.log",
+            "out.log.1",
+            "out.log.2",
+        ]
+        assert log.rotated_out
+        assert all(p.stat().st_size <= 200 for p in log.files)
+
+    @pytest.mark.asyncio
+    async def test_read_spans_rotated_files(self, tmp_path):
+        """Test that reads page through the rotated files oldest first."""
+        log = OutputLog(tmp_path / "out.log", max_bytes=100, backup_count=5)
+        log.start()
+        for i in range(6):
+            push_lines(log, [("stderr" if i % 2 else "stdout", f"line {i} {'x' * 30}\n")])
+            await asyncio.sleep(0)
+
+        page = await log.read(offset=1, limit=3)
+        stderr = await log.read(category="stderr")
+
+        assert len(log.files) > 1
+        assert [line.content[:6] for line in page.lines] == ["line 1", "line 2", "line 3"]
+        assert page.total == 6
+        assert page.has_more
+        assert not page.truncated
+        assert [line.line_number for line in stderr.lines] == [2, 4, 6]
+        await log.close()
+
+    @pytest.mark.asyncio
+    async def test_full_queue_drops_lines(self, tmp_path):
+        """Test that a full queue drops lines instead of blocking, and notes it."""
+        log = OutputLog(tmp_path / "out.log", queue_size=2)
+        log.start()
+
+        push_lines(log, [("stdout", f"{i}\n") for i in range(5)])
+        await log.close()
+
+        records = (tmp_path / "out.log").read_text().splitlines()
+        assert log.dropped_lines == 3
+        assert [r.split(" ", 1)[1] for r in records[:2]] == ["[stdout] 0", "[stdout] 1"]
+        assert "3 output lines not logged" in records[2]
+
+    @pytest.mark.asyncio
+    async def test_push_after_close_ignored(self, tmp_path):
+        """Test that lines pushed after close are not queued."""
+        log = OutputLog(tmp_path / "out.log")
+        log.start()
+        await log.close()
+
+        push_lines(log, [("stdout", "late\n")])
+
+        assert (tmp_path / "out.log").read_text() == ""
+
+
+class TestSessionOutputLog:
+    """Tests for the session teeing output to its log."""
+
+    @pytest.mark.asyncio
+    async def test_output_written_until_cleanup(self, tmp_path):
+        """Test that session output reaches the log and cleanup closes it."""
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.output_log = OutputLog(tmp_path / "logs" / "out.log")
+        session.output_log.start()
+
+        session._handle_output("stdout", "hello\n")
+        await session.cleanup()
+
+        assert "[stdout] hello" in (tmp_path / "logs" / "out.log").read_text()
@@ -0,0 +1,103 @@
+"""Tests for streaming output notifications."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.utils.output_buffer import OutputBuffer
+from polybugger_mcp.utils.output_streamer import OutputStreamer
+
+
+class Recorder:
+    """Collects chunks passed to the streamer's send function."""
+
+    def __init__(self):
+        self.chunks: list[dict] = []
+
+    async def send(self, chunk):
+        self.chunks.append(chunk)
+
+
+def push_lines(streamer: OutputStreamer, lines: list[tuple[str, str]]) -> None:
+    buffer = OutputBuffer()
+    for category, content in lines:
+        streamer.push("s1", buffer.append(category, content))
+
+
+class TestOutputStreamer:
+    """Tests for coalescing and capping streamed output."""
+
+    @pytest.mark.asyncio
+    async def test_runs_coalesced_by_category(self):
+        """Test that consecutive lines of one category become one chunk."""
+        recorder = Recorder()
+        streamer = OutputStreamer(recorder.send, interval=0.01)
+
+        push_lines(streamer, [("stdout", "a\n"), ("stdout", "b\n"), ("stderr", "c\n")])
+        await asyncio.sleep(0.05)
+
+        assert [(c["category"], c["text"]) for c in recorder.chunks] == [
+            ("stdout", "a\nb\n"),
+            ("stderr", "c\n"),
+        ]
+        assert (recorder.chunks[0]["first_seq"], recorder.chunks[0]["last_seq"]) == (1, 2)
+        assert recorder.chunks[1]["first_seq"] == 3
+        assert recorder.chunks[0]["session_id"] == "s1"
+
+    @pytest.mark.asyncio
+    async def test_burst_capped_with_seq_gap(self):
+        """Test that lines beyond max_bytes are skipped, leaving a sequence gap."""
+        recorder = Recorder()
+        streamer = OutputStreamer(recorder.send, interval=0.01, max_bytes=10)
+
+        push_lines(streamer, [("stdout", "12345\n")] * 5)
+        await asyncio.sleep(0.05)
+        push_lines(streamer, [("stdout", "x\n")])
+        await streamer.close()
+
+        assert len(recorder.chunks[0]["text"].encode()) <= 10
+        assert recorder.chunks[0]["last_seq"] == 1
+        assert streamer.skipped_lines == 4
+        assert recorder.chunks[1]["text"] == "x\n"
+
+    @pytest.mark.asyncio
+    async def test_oversized_line_truncated(self):
+        """Test that a single line larger than max_bytes is cut down."""
+        recorder = Recorder()
+        streamer = OutputStreamer(recorder.send, interval=0.01, max_bytes=1024)
+
+        push_lines(streamer, [("stdout", "x" * 5000)])
+        await streamer.close()
+
+        assert len(recorder.chunks[0]["text"]) == 1024
+        assert recorder.chunks[0]["truncated"] is True
+
+    @pytest.mark.asyncio
+    async def test_close_flushes_and_stops(self):
+        """Test that close sends pending lines and ignores later ones."""
+        recorder = Recorder()
+        streamer = OutputStreamer(recorder.send, interval=10)
+
+        push_lines(streamer, [("stdout", "a\n")])
+        await streamer.close()
+        push_lines(streamer, [("stdout", "b\n")])
+        await asyncio.sleep(0)
+
+        assert [c["text"] for c in recorder.chunks] == ["a\n"]
+
+    @pytest.mark.asyncio
+    async def test_session_output_listener(self, tmp_path):
+        """Test that session output reaches a registered streamer."""
+        recorder = Recorder()
+        streamer = OutputStreamer(recorder.send, interval=10)
+        session = Session(session_id="s1", project_root=tmp_path)
+
+        session.add_output_listener(streamer.push)
+        session._handle_output("stdout", "hello\n")
+        session.remove_output_listener(streamer.push)
+        session._handle_output("stdout", "ignored\n")
+        await streamer.close()
+
+        assert recorder.chunks[0]["text"] == "hello\n"
+        assert recorder.chunks[0]["first_seq"] == 1
@@ -0,0 +1,106 @@
+"""Tests for source path mapping between local and debuggee paths."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, Source, SourceBreakpoint, StackFrame
+from polybugger_mcp.models.events import EventType
+from polybugger_mcp.utils.path_mapper import PathMapper
+
+
+class TestPathMapper:
+    """Tests for PathMapper translation rules."""
+
+    def test_both_directions(self):
+        """Test that paths under a mapping translate each way."""
+        mapper = PathMapper([("/Users/me/project", "/app")])
+
+        assert mapper.to_remote("/Users/me/project/pkg/app.py") == "/app/pkg/app.py"
+        assert mapper.to_local("/app/pkg/app.py") == "/Users/me/project/pkg/app.py"
+
+    def test_longest_prefix_wins(self):
+        """Test that the most specific of overlapping mappings is used."""
+        mapper = PathMapper(
+            [("/Users/me/project", "/app"), ("/Users/me/project/vendor/", "/opt/vendor")]
+        )
+
+        assert mapper.to_remote("/Users/me/project/vendor/lib.py") == "/opt/vendor/lib.py"
+        assert mapper.to_local("/opt/vendor/lib.py") == "/Users/me/project/vendor/lib.py"
+        assert mapper.to_remote("/Users/me/project/main.py") == "/app/main.py"
+
+    def test_unmapped_paths_pass_through(self):
+        """Test that paths outside every mapping, or sharing only a prefix, are untouched."""
+        mapper = PathMapper([("/Users/me/project", "/app")])
+
+        assert mapper.to_remote("/Users/me/projects/x.py") == "/Users/me/projects/x.py"
+        assert mapper.to_local("/usr/lib/python3/os.py") == "/usr/lib/python3/os.py"
+
+    def test_windows_remote_root(self):
+        """Test that separators follow the target root's style."""
+        mapper = PathMapper([("/home/me/src", "C:\\work\\src")])
+
+        assert mapper.to_remote("/home/me/src/a/b.py") == "C:\\work\\src\\a\\b.py"
+        assert mapper.to_local("C:\\work\\src\\a\\b.py") == "/home/me/src/a/b.py"
+
+
+class MappedAdapter:
+    """Adapter stub that only knows container paths."""
+
+    is_launched = True
+
+    def __init__(self):
+        self.sent_paths: list[str] = []
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        self.sent_paths.append(source_path)
+        return [
+            Breakpoint(id=1, verified=True, line=bp.line, source={"path": source_path})
+            for bp in breakpoints
+        ]
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=1, name="main", line=3, source=Source(path="/app/app.py"))]
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session whose debuggee sees /app."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = MappedAdapter()
+    session._state = SessionState.PAUSED
+    session.path_mapper = PathMapper([("/Users/me/project", "/app")])
+    return session
+
+
+class TestSessionPathMapping:
+    """Tests for path translation in the session."""
+
+    @pytest.mark.asyncio
+    async def test_breakpoints_sent_with_remote_path(self, session):
+        """Test that setBreakpoints uses the debuggee's path but results stay local."""
+        results = await session.set_breakpoints(
+            "/Users/me/project/app.py", [SourceBreakpoint(line=3)]
+        )
+
+        assert session.adapter.sent_paths == ["/app/app.py"]
+        assert results[0].source["path"] == "/Users/me/project/app.py"
+        assert session.describe_breakpoints()["/Users/me/project/app.py"][0]["verified"] is True
+
+    @pytest.mark.asyncio
+    async def test_stack_frames_use_local_paths(self, session):
+        """Test that frames come back with local paths."""
+        frames = await session.get_stack_trace(1)
+
+        assert frames[0].source.path == "/Users/me/project/app.py"
+
+    @pytest.mark.asyncio
+    async def test_hits_counted_through_mapping(self, session):
+        """Test that location-based hit counting matches mapped stop paths."""
+        await session.set_breakpoints("/Users/me/project/app.py", [SourceBreakpoint(line=3)])
+
+        await session._handle_event(EventType.STOPPED, {"reason": "breakpoint", "threadId": 1})
+        await asyncio.gather(*session._background_tasks)
+
+        assert session.describe_breakpoints()["/Users/me/project/app.py"][0]["hit_count"] == 1
@@ -0,0 +1,133 @@
+"""Tests for pre-launch steps run before the adapter starts."""
+
+import asyncio
+import sys
+
+import pytest
+
+from polybugger_mcp.core.exceptions import PreLaunchError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import LaunchConfig, PreLaunchStep
+from polybugger_mcp.utils.prelaunch import STDERR_TAIL_LINES, PreLaunchRunner
+
+
+def python_step(code: str, **kwargs) -> PreLaunchStep:
+    return PreLaunchStep(command=sys.executable, args=["-c", code], **kwargs)
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session whose adapter must never be selected."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+
+    async def no_adapter(config):
+        raise AssertionError("the adapter was started")
+
+    session._select_adapter = no_adapter  # type: ignore[method-assign]
+    return session
+
+
+def prelaunch_output(session: Session) -> str:
+    page = session.output_buffer.get_page(category="prelaunch")
+    return "".join(line.content for line in page.lines)
+
+
+class TestPreLaunchRunner:
+    """Tests for PreLaunchRunner."""
+
+    @pytest.mark.asyncio
+    async def test_steps_run_in_order(self, tmp_path):
+        """Test that each step runs in its cwd and its output is passed on."""
+        (tmp_path / "sub").mkdir()
+        output: list[str] = []
+        runner = PreLaunchRunner(output.append)
+
+        await runner.run(
+            [
+                python_step("import os; print(os.path.basename(os.getcwd()))", cwd="sub"),
+                python_step("import sys; print('second', file=sys.stderr)"),
+            ],
+            project_root=tmp_path,
+            default_cwd=tmp_path,
+        )
+
+        text = "".join(output)
+        assert text.index("sub\n") < text.index("second\n")
+        assert text.startswith(f"$ {sys.executable} -c")
+
+    @pytest.mark.asyncio
+    async def test_failure_stops_with_stderr_tail(self, tmp_path):
+        """Test that a failing step reports its exit code and last stderr lines."""
+        output: list[str] = []
+        failing = python_step(
+            "import sys\nfor i in range(50): print(f'error {i}', file=sys.stderr)\nsys.exit(3)"
+        )
+
+        with pytest.raises(PreLaunchError) as exc_info:
+            await PreLaunchRunner(output.append).run(
+                [failing, python_step("print('never')")], tmp_path, tmp_path
+            )
+
+        details = exc_info.value.details
+        assert details["step"] == 1
+        assert details["exit_code"] == 3
+        tail = details["stderr_tail"].splitlines()
+        assert len(tail) == STDERR_TAIL_LINES
+        assert tail[-1] == "error 49"
+        assert "never" not in "".join(output)
+
+    @pytest.mark.asyncio
+    async def test_timeout(self, tmp_path):
+        """Test that a step running past its timeout is killed and reported."""
+        with pytest.raises(PreLaunchError) as exc_info:
+            await PreLaunchRunner(lambda text: None).run(
+                [python_step("import time; time.sleep(30)", timeout=0.5)], tmp_path, tmp_path
+            )
+
+        assert "timed out after 0.5s" in exc_info.value.message
+        assert exc_info.value.details["exit_code"] is None
+
+    @pytest.mark.asyncio
+    async def test_missing_command(self, tmp_path):
+        """Test that a command that can't start fails its step."""
+        with pytest.raises(PreLaunchError, match="could not start"):
+            await PreLaunchRunner(lambda text: None).run(
+                [PreLaunchStep(command="no-such-build-tool")], tmp_path, tmp_path
+            )
+
+
+class TestLaunchPreSteps:
+    """Tests for pre-launch steps in Session.launch."""
+
+    @pytest.mark.asyncio
+    async def test_failed_step_aborts_launch(self, session, tmp_path):
+        """Test that the adapter isn't started and the output is tagged prelaunch."""
+        config = LaunchConfig(
+            program="main.go",
+            pre_launch=[python_step("import sys; print('generating'); sys.exit(2)")],
+        )
+
+        with pytest.raises(PreLaunchError) as exc_info:
+            await session.launch(config)
+
+        assert exc_info.value.details["exit_code"] == 2
+        assert "generating\n" in prelaunch_output(session)
+        assert session.state == SessionState.FAILED
+
+    @pytest.mark.asyncio
+    async def test_cleanup_kills_running_step(self, session, tmp_path):
+        """Test that terminating the session mid-step kills it and fails the launch."""
+        config = LaunchConfig(
+            program="main.go",
+            pre_launch=[python_step("import time; print('building', flush=True); time.sleep(30)")],
+        )
+        launch = asyncio.create_task(session.launch(config))
+        for _ in range(100):
+            if "building" in prelaunch_output(session):
+                break
+            await asyncio.sleep(0.05)
+
+        await session.cleanup()
+
+        with pytest.raises(PreLaunchError, match="was cancelled"):
+            await asyncio.wait_for(launch, timeout=10.0)
@@ -0,0 +1,177 @@
+"""Tests for restarting a session with its breakpoints."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import Language
+from polybugger_mcp.adapters.factory import AdapterDescriptor
+from polybugger_mcp.core import session as session_module
+from polybugger_mcp.core.exceptions import InvalidSessionStateError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, LaunchConfig, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+class RestartAdapter:
+    """Adapter stub; line 99 never verifies and line 5 binds to line 6."""
+
+    instances: list["RestartAdapter"] = []
+
+    def __init__(self, session_id, event_callback=None, output_callback=None):
+        self._event_callback = event_callback
+        self.capabilities: dict = {}
+        self.is_connected = False
+        self.is_launched = False
+        self.sent: list[tuple[str, list[SourceBreakpoint]]] = []
+        self.exception_filters: list[list[str]] = []
+        self.restarts = 0
+        self.disconnected = False
+        RestartAdapter.instances.append(self)
+
+    async def initialize(self):
+        self.is_connected = True
+        return self.capabilities
+
+    async def launch(self, config, configure_callback=None):
+        if configure_callback:
+            await configure_callback()
+        self.is_launched = True
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        self.sent.append((source_path, list(breakpoints)))
+        return [
+            Breakpoint(
+                verified=bp.line != 99,
+                line=6 if bp.line == 5 else bp.line,
+                message="no code at line" if bp.line == 99 else None,
+            )
+            for bp in breakpoints
+            if bp.enabled
+        ]
+
+    async def set_exception_breakpoints(self, filters, options=None):
+        self.exception_filters.append(list(filters))
+        return []
+
+    async def restart(self):
+        self.restarts += 1
+        # Some adapters report the old process exiting mid-restart
+        await self._event_callback(EventType.EXITED, {"exitCode": 0})
+
+    async def disconnect(self, terminate=False):
+        self.disconnected = True
+        self.is_connected = False
+
+
+@pytest.fixture
+def session(tmp_path, monkeypatch):
+    """Create a session launched on the stub adapter."""
+    RestartAdapter.instances = []
+    descriptor = AdapterDescriptor(
+        name="debugpy", adapter_class=RestartAdapter, languages=(Language.PYTHON,)
+    )
+    monkeypatch.setattr(session_module, "get_descriptor", lambda language, name=None: descriptor)
+    return Session(session_id="test_session", project_root=tmp_path)
+
+
+async def launch(session: Session, path: str) -> None:
+    await session.initialize_adapter()
+    await session.launch(LaunchConfig(program=path))
+
+
+class TestRestart:
+    """Tests for Session.restart."""
+
+    @pytest.mark.asyncio
+    async def test_relaunch_replays_breakpoints(self, session, tmp_path):
+        """Test that a relaunch sends breakpoints, conditions and filters to a new adapter."""
+        path = str(tmp_path / "app.py")
+        await launch(session, path)
+        await session.set_breakpoints(
+            path,
+            [
+                SourceBreakpoint(line=3, condition="x > 1"),
+                SourceBreakpoint(line=4, hit_condition="3"),
+            ],
+        )
+        session._exception_filters = ["raised"]
+        first = session.adapter
+
+        result = await session.restart()
+
+        second = session.adapter
+        assert result["method"] == "relaunch"
+        assert result["replayed_before_start"] is True
+        assert first.disconnected is True
+        assert second is not first
+        assert session.state == SessionState.RUNNING
+        replayed = second.sent[0][1]
+        assert [(bp.line, bp.condition, bp.hit_condition) for bp in replayed] == [
+            (3, "x > 1", None),
+            (4, None, "3"),
+        ]
+        assert second.exception_filters == [["raised"]]
+        assert result["breakpoints_replayed"] == 2
+        assert result["unverified"] == []
+
+    @pytest.mark.asyncio
+    async def test_unverified_and_moved_reported(self, session, tmp_path):
+        """Test that breakpoints failing or moving on the new run are reported."""
+        path = str(tmp_path / "app.py")
+        await session.set_breakpoints(
+            path,
+            [SourceBreakpoint(line=5), SourceBreakpoint(line=99), SourceBreakpoint(line=7)],
+        )
+        await launch(session, path)
+
+        result = await session.restart()
+
+        assert result["unverified"] == [{"file": path, "line": 99, "message": "no code at line"}]
+        assert result["moved"] == [{"file": path, "requested_line": 5, "line": 6}]
+
+    @pytest.mark.asyncio
+    async def test_relaunch_after_exit(self, session, tmp_path):
+        """Test that a program that already exited can be restarted."""
+        path = str(tmp_path / "app.py")
+        await launch(session, path)
+        await session._handle_event(EventType.TERMINATED, {})
+        assert session.state == SessionState.TERMINATED
+
+        result = await session.restart()
+
+        assert result["method"] == "relaunch"
+        assert session.state == SessionState.RUNNING
+
+    @pytest.mark.asyncio
+    async def test_native_restart(self, session, tmp_path):
+        """Test that the adapter's restart request is used when advertised."""
+        path = str(tmp_path / "app.py")
+        await launch(session, path)
+        session.adapter.capabilities["supportsRestartRequest"] = True
+        await session.set_breakpoints(path, [SourceBreakpoint(line=3)])
+        session._state = SessionState.PAUSED
+        adapter = session.adapter
+
+        result = await session.restart()
+
+        assert result["method"] == "native"
+        assert result["replayed_before_start"] is False
+        assert session.adapter is adapter
+        assert adapter.restarts == 1
+        assert adapter.disconnected is False
+        assert [bp.line for bp in adapter.sent[-1][1]] == [3]
+        # The exit reported mid-restart doesn't end the session
+        assert session.state == SessionState.RUNNING
+
+    @pytest.mark.asyncio
+    async def test_attached_rejected(self, session, tmp_path):
+        """Test that attached sessions can't be restarted."""
+        session.attached = True
+
+        with pytest.raises(InvalidSessionStateError):
+            await session.restart()
+
+    @pytest.mark.asyncio
+    async def test_never_launched_rejected(self, session):
+        """Test that a session that was never launched can't be restarted."""
+        with pytest.raises(InvalidSessionStateError):
+            await session.restart()
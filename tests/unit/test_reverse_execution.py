@@ -0,0 +1,84 @@
+"""Tests for reverse execution (step back / reverse continue)."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.exceptions import CapabilityNotSupportedError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.events import EventType
+
+
+class ReverseAdapter:
+    """Adapter stub using the base class reverse execution methods."""
+
+    step_back = DebugAdapter.step_back
+    reverse_continue = DebugAdapter.reverse_continue
+
+    def __init__(self, session: Session, supports_step_back: bool):
+        self.session = session
+        self.capabilities = {"supportsStepBack": supports_step_back}
+        self.requests: list[str] = []
+        self.stop_before_response = False
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append(command)
+        if self.stop_before_response:
+            # The stop lands before the request's response is processed
+            await self.session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+        return {}
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestReverseExecution:
+    """Tests for reverse stepping through the session."""
+
+    @pytest.mark.asyncio
+    async def test_unsupported_adapter_raises(self, session):
+        """Test that reverse execution needs supportsStepBack."""
+        session.adapter = ReverseAdapter(session, supports_step_back=False)
+
+        assert session.supports_reverse_execution is False
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.step_back()
+        assert session.adapter.requests == []
+        assert session.state == SessionState.PAUSED
+
+    @pytest.mark.asyncio
+    async def test_step_back_runs_like_forward_step(self, session):
+        """Test that stepping back moves the session to running."""
+        session.adapter = ReverseAdapter(session, supports_step_back=True)
+
+        await session.step_back()
+
+        assert session.adapter.requests == ["stepBack"]
+        assert session.state == SessionState.RUNNING
+
+    @pytest.mark.asyncio
+    async def test_stop_before_response_stays_paused(self, session):
+        """Test that a stop racing the reverse request isn't overwritten."""
+        session.adapter = ReverseAdapter(session, supports_step_back=True)
+        session.adapter.stop_before_response = True
+
+        await session.reverse_continue()
+
+        assert session.adapter.requests == ["reverseContinue"]
+        assert session.state == SessionState.PAUSED
+
+    @pytest.mark.asyncio
+    async def test_duplicate_stop_tolerated(self, session):
+        """Test that the same stop reported twice keeps the session paused."""
+        session.adapter = ReverseAdapter(session, supports_step_back=True)
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+
+        assert session.state == SessionState.PAUSED
+        assert len(await session.event_queue.get_all()) == 2
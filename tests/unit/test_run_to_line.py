@@ -0,0 +1,134 @@
+"""Tests for running to a line with a temporary breakpoint."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.exceptions import InvalidSessionStateError, UnverifiedBreakpointError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Breakpoint, SourceBreakpoint
+from polybugger_mcp.models.events import EventType
+
+
+class BreakpointAdapter:
+    """Adapter stub recording setBreakpoints calls; line 99 can't be verified."""
+
+    def __init__(self):
+        self.is_launched = True
+        self.sent: list[list[SourceBreakpoint]] = []
+        self.continued = 0
+        self._next_id = 1
+
+    async def set_breakpoints(self, source_path, breakpoints):
+        self.sent.append(list(breakpoints))
+        results = []
+        for bp in breakpoints:
+            if not bp.enabled:
+                continue
+            results.append(
+                Breakpoint(
+                    id=self._next_id,
+                    verified=bp.line != 99,
+                    line=bp.line,
+                    message="no code at line" if bp.line == 99 else None,
+                )
+            )
+            self._next_id += 1
+        return results
+
+    async def continue_execution(self, thread_id):
+        self.continued += 1
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session with a stub adapter."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = BreakpointAdapter()
+    session._state = SessionState.PAUSED
+    return session
+
+
+async def stop(session: Session) -> None:
+    """Deliver a stopped event and let background work finish."""
+    await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+    await asyncio.gather(*session._background_tasks)
+
+
+class TestRunToLine:
+    """Tests for Session.run_to_line."""
+
+    @pytest.mark.asyncio
+    async def test_temporary_breakpoint_removed_on_stop(self, session, tmp_path):
+        """Test that the temporary breakpoint is sent, then removed at the next stop."""
+        path = str(tmp_path / "app.py")
+        await session.set_breakpoints(path, [SourceBreakpoint(line=3)])
+
+        bp = await session.run_to_line(path, 10)
+
+        assert bp.verified is True
+        assert [b.line for b in session.adapter.sent[-1]] == [3, 10]
+        assert session.adapter.continued == 1
+
+        await stop(session)
+
+        assert [b.line for b in session.adapter.sent[-1]] == [3]
+        assert [b["line"] for b in session.describe_breakpoints()[path]] == [3]
+        assert session._run_to_line is None
+
+    @pytest.mark.asyncio
+    async def test_user_breakpoint_on_line_kept(self, session, tmp_path):
+        """Test that a conditional user breakpoint on the target line is restored."""
+        path = str(tmp_path / "app.py")
+        await session.set_breakpoints(path, [SourceBreakpoint(line=10, condition="x > 5")])
+
+        await session.run_to_line(path, 10)
+
+        assert [(b.line, b.condition) for b in session.adapter.sent[-1]] == [(10, None)]
+
+        await stop(session)
+
+        assert [(b.line, b.condition) for b in session.adapter.sent[-1]] == [(10, "x > 5")]
+        assert session._breakpoints[path][0].condition == "x > 5"
+
+    @pytest.mark.asyncio
+    async def test_unverified_fails_without_continuing(self, session, tmp_path):
+        """Test that an unbindable line is reported and execution stays paused."""
+        path = str(tmp_path / "app.py")
+
+        with pytest.raises(UnverifiedBreakpointError, match="no code at line"):
+            await session.run_to_line(path, 99)
+
+        assert session.adapter.continued == 0
+        assert session.adapter.sent[-1] == []
+        assert session._run_to_line is None
+        assert session.state == SessionState.PAUSED
+
+    @pytest.mark.asyncio
+    async def test_set_breakpoints_while_active(self, session, tmp_path):
+        """Test that editing breakpoints keeps the target but hides it from results."""
+        path = str(tmp_path / "app.py")
+        await session.run_to_line(path, 10)
+
+        results = await session.set_breakpoints(path, [SourceBreakpoint(line=4)])
+
+        assert [b.line for b in results] == [4]
+        assert [b.line for b in session.adapter.sent[-1]] == [4, 10]
+
+    @pytest.mark.asyncio
+    async def test_exit_clears_target(self, session, tmp_path):
+        """Test that program exit drops the temporary breakpoint."""
+        path = str(tmp_path / "app.py")
+        await session.run_to_line(path, 10)
+
+        await session._handle_event(EventType.TERMINATED, {})
+
+        assert session._run_to_line is None
+
+    @pytest.mark.asyncio
+    async def test_requires_paused(self, session, tmp_path):
+        """Test that running to a line needs a paused program."""
+        session._state = SessionState.RUNNING
+
+        with pytest.raises(InvalidSessionStateError):
+            await session.run_to_line(str(tmp_path / "app.py"), 10)
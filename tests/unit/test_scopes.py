@@ -0,0 +1,41 @@
+"""Tests for choosing among a frame's scopes."""
+
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.models.dap import Scope
+
+# What delve reports for a Go frame
+GO_SCOPES = [
+    Scope(name="Registers", variablesReference=3, expensive=True),
+    Scope(name="Arguments", presentationHint="arguments", variablesReference=1),
+    Scope(name="Locals", presentationHint="locals", variablesReference=2),
+    Scope(name="Globals (package main)", variablesReference=4, expensive=True),
+]
+
+
+class TestSelectScope:
+    """Tests for Session.select_scope."""
+
+    def test_default_prefers_locals(self):
+        """Test that the locals scope is picked by its presentation hint."""
+        assert Session.select_scope(GO_SCOPES).name == "Locals"
+
+    def test_default_skips_expensive_scopes(self):
+        """Test that without a locals hint the first cheap scope is used."""
+        scopes = [
+            Scope(name="Registers", variablesReference=3, expensive=True),
+            Scope(name="Frame", variablesReference=5),
+        ]
+        assert Session.select_scope(scopes).name == "Frame"
+
+    def test_by_name_prefix_and_case(self):
+        """Test that scopes are found by case-insensitive name or prefix."""
+        assert Session.select_scope(GO_SCOPES, "registers").variables_reference == 3
+        assert Session.select_scope(GO_SCOPES, "globals").variables_reference == 4
+
+    def test_by_presentation_hint(self):
+        """Test that presentation hints name scopes too."""
+        assert Session.select_scope(GO_SCOPES, "arguments").name == "Arguments"
+
+    def test_missing_scope(self):
+        """Test that an unknown name selects nothing."""
+        assert Session.select_scope(GO_SCOPES, "closure") is None
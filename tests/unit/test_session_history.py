@@ -0,0 +1,155 @@
+"""Tests for the per-session event and tool call history."""
+
+import asyncio
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.core.exceptions import SessionNotFoundError
+from polybugger_mcp.core.history import SessionHistory
+from polybugger_mcp.core.session import Session, SessionManager, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+from polybugger_mcp.models.events import EventType
+
+
+class TestSessionHistory:
+    """Tests for SessionHistory."""
+
+    def test_bounded(self):
+        """Test that the oldest entries are dropped past max_entries."""
+        history = SessionHistory(max_entries=3)
+        for n in range(5):
+            history.record("tool", "debug_step", {"n": n})
+
+        assert [e.seq for e in history.entries()] == [3, 4, 5]
+        assert history.dropped == 2
+        assert history.last_seq == 5
+
+    def test_output_folded(self):
+        """Test that consecutive output events share one summary entry."""
+        history = SessionHistory()
+        history.record_output("stdout", "hello\n")
+        history.record_output("stderr", "oops\n")
+        history.record("event", "stopped", {"reason": "breakpoint"})
+        history.record_output("stdout", "more\n")
+
+        first, _, second = history.entries()
+        assert first.data == {
+            "events": 2,
+            "chars": 11,
+            "categories": ["stdout", "stderr"],
+            "first": "hello\n",
+        }
+        assert second.data["events"] == 1
+
+    def test_filters(self):
+        """Test that since and event_type narrow the entries."""
+        history = SessionHistory()
+        history.record("event", "stopped", {})
+        history.record("tool", "debug_evaluate", {"arguments": {"expression": "x" * 500}})
+        history.record("event", "stopped", {})
+
+        assert [e.seq for e in history.entries(since=1, event_type="stopped")] == [3]
+        (evaluate,) = history.entries(event_type="tool")
+        assert len(evaluate.data["arguments"]["expression"]) < 500
+
+
+class StackAdapter:
+    """Adapter stub whose top frame is always main.py:7."""
+
+    def __init__(self):
+        self.stack_requests = 0
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        self.stack_requests += 1
+        return [StackFrame(id=1, name="main", line=7, source=Source(path="/app/main.py"))]
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        return {"result": "1", "type": "int", "variablesReference": 0}
+
+
+class TestEventRecording:
+    """Tests for debug events reaching the history."""
+
+    @pytest.mark.asyncio
+    async def test_stop_location_and_exit_code(self, tmp_path):
+        """Test that stops get their location and exits their code."""
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.adapter = StackAdapter()  # type: ignore[assignment]
+        session._state = SessionState.RUNNING
+
+        await session._handle_event(
+            EventType.STOPPED, {"reason": "breakpoint", "threadId": 1, "hitBreakpointIds": [4]}
+        )
+        await asyncio.gather(*session._background_tasks)
+        await session._handle_event(EventType.THREAD, {"reason": "exited", "threadId": 2})
+        await session._handle_event(EventType.EXITED, {"exitCode": 3})
+
+        stopped, exited = session.history.entries()
+        assert stopped.data == {
+            "reason": "breakpoint",
+            "thread_id": 1,
+            "hit_breakpoint_ids": [4],
+            "location": {"file": "/app/main.py", "line": 7, "function": "main"},
+        }
+        assert (exited.type, exited.data) == ("exited", {"exit_code": 3})
+
+    @pytest.mark.asyncio
+    async def test_stop_fetches_top_frame_once(self, tmp_path):
+        """Test that one stackTrace request serves the history, hits, watches and waiters."""
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.adapter = StackAdapter()  # type: ignore[assignment]
+        session._state = SessionState.RUNNING
+        session.add_watch("x")
+        location = {"file": "/app/main.py", "line": 7, "function": "main"}
+
+        await session._handle_event(EventType.STOPPED, {"reason": "breakpoint", "threadId": 1})
+        result = await session.wait_for_stop(0, timeout=1.0)
+
+        assert result["location"] == location
+        assert session.history.entries()[0].data["location"] == location
+        assert session.adapter.stack_requests == 1
+
+
+class TestRetention:
+    """Tests for histories outliving their sessions."""
+
+    @pytest.mark.asyncio
+    async def test_expires_after_retention(self, tmp_path):
+        """Test that an ended session's history is discarded once it expires."""
+        manager = SessionManager()
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.history.record("tool", "debug_launch", {})
+        manager._retain_history(session)
+
+        history, ended = await manager.get_history("test_session")
+        assert ended is True
+        assert history.last_seq == 1
+
+        expired = datetime.now(timezone.utc) - timedelta(
+            seconds=settings.history_retention_seconds + 1
+        )
+        manager._ended_histories["test_session"] = (history, expired)
+        await manager._cleanup_stale_sessions()
+
+        with pytest.raises(SessionNotFoundError):
+            await manager.get_history("test_session")
+
+    def test_tool_call_outcome(self, tmp_path):
+        """Test that a tool call records its arguments and error code."""
+        manager = SessionManager()
+        session = Session(session_id="test_session", project_root=tmp_path)
+        manager._sessions[session.id] = session
+
+        manager.record_tool_call(
+            "debug_evaluate",
+            {"expression": "x.y", "frame_id": None},
+            {"error": "name 'x' is not defined", "code": "EVALUATION_FAILED"},
+        )
+
+        (entry,) = session.history.entries()
+        assert entry.data == {
+            "arguments": {"expression": "x.y"},
+            "error": {"message": "name 'x' is not defined", "code": "EVALUATION_FAILED"},
+        }
@@ -0,0 +1,100 @@
+"""Tests for ending sessions on their idle timeout and maximum lifetime."""
+
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.core.session import Session, SessionManager
+from polybugger_mcp.persistence.breakpoints import BreakpointStore
+
+
+class DisconnectRecorder:
+    """Adapter stub remembering how it was disconnected."""
+
+    def __init__(self):
+        self.terminated: bool | None = None
+
+    async def disconnect(self, terminate=True):
+        self.terminated = terminate
+
+
+@pytest.fixture
+def manager(tmp_path):
+    """Create a session manager with one session, without background tasks."""
+    manager = SessionManager(breakpoint_store=BreakpointStore(base_dir=tmp_path / "bp"))
+    session = Session(session_id="test_session", project_root=tmp_path, timeout_minutes=5)
+    session.adapter = DisconnectRecorder()  # type: ignore[assignment]
+    manager._sessions[session.id] = session
+    return manager
+
+
+class TestSessionLimits:
+    """Tests for SessionManager._cleanup_stale_sessions."""
+
+    @pytest.mark.asyncio
+    async def test_idle_session_ended(self, manager):
+        """Test that an idle session is ended and the debuggee killed."""
+        session = manager._sessions["test_session"]
+        adapter = session.adapter
+        session.last_activity -= timedelta(minutes=6)
+        ended: list[tuple[str, str]] = []
+
+        async def listener(s, reason):
+            ended.append((s.id, reason))
+
+        manager.add_end_listener(listener)
+        await manager._cleanup_stale_sessions()
+
+        assert manager.active_count == 0
+        assert adapter.terminated is True
+        assert ended == [("test_session", "idle_timeout")]
+        history, removed = await manager.get_history("test_session")
+        assert removed is True
+        assert history.entries()[-1].data == {"reason": "idle_timeout"}
+
+    @pytest.mark.asyncio
+    async def test_lifetime_ends_active_session(self, manager):
+        """Test that the maximum lifetime applies however recent the activity."""
+        session = manager._sessions["test_session"]
+        session.created_at = datetime.now(timezone.utc) - timedelta(
+            seconds=settings.session_max_lifetime_seconds + 1
+        )
+        session.touch()
+        ended: list[str] = []
+
+        async def listener(s, reason):
+            ended.append(reason)
+
+        manager.add_end_listener(listener)
+        await manager._cleanup_stale_sessions()
+
+        assert ended == ["max_lifetime"]
+
+    @pytest.mark.asyncio
+    async def test_touch_resets_idle_timer(self, manager):
+        """Test that resolving a session for a tool call keeps it alive."""
+        session = manager._sessions["test_session"]
+        session.last_activity -= timedelta(minutes=6)
+
+        await manager.resolve_session()
+        await manager._cleanup_stale_sessions()
+
+        assert manager.active_count == 1
+        assert session.idle_remaining_seconds > 290
+
+    @pytest.mark.asyncio
+    async def test_failing_listener_does_not_stop_cleanup(self, manager, tmp_path):
+        """Test that an end listener raising doesn't keep other sessions alive."""
+        other = Session(session_id="other_session", project_root=tmp_path, timeout_minutes=1)
+        other.last_activity -= timedelta(minutes=2)
+        manager._sessions[other.id] = other
+        manager._sessions["test_session"].last_activity -= timedelta(minutes=6)
+
+        async def listener(s, reason):
+            raise RuntimeError("client gone")
+
+        manager.add_end_listener(listener)
+        await manager._cleanup_stale_sessions()
+
+        assert manager.active_count == 0
@@ -0,0 +1,120 @@
+"""Tests for changing variable values while paused."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.core.exceptions import (
+    CapabilityNotSupportedError,
+    DAPError,
+    InvalidSessionStateError,
+    VariableNotFoundError,
+)
+from polybugger_mcp.core.session import Session, SessionState
+
+
+class AssignAdapter:
+    """Adapter stub using the base class assignment methods."""
+
+    set_variable = DebugAdapter.set_variable
+    set_expression = DebugAdapter.set_expression
+
+    def __init__(self, capabilities: dict[str, bool]):
+        self.capabilities = capabilities
+        self.requests: list[tuple[str, dict]] = []
+        self.watch_value = "1"
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append((command, arguments))
+        if arguments["value"] == "'oops'":
+            raise DAPError(code="DAP_REQUEST_FAILED", message="TypeError: expected int")
+        self.watch_value = arguments["value"]
+        return {"value": arguments["value"], "type": "int", "variablesReference": 0}
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        return {"result": self.watch_value, "type": "int", "variablesReference": 0}
+
+    async def continue_execution(self, thread_id):
+        pass
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._state = SessionState.PAUSED
+    return session
+
+
+class TestSetVariable:
+    """Tests for setVariable / setExpression through the session."""
+
+    @pytest.mark.asyncio
+    async def test_set_variable_request(self, session):
+        """Test that a variable assignment maps onto DAP setVariable."""
+        session.adapter = AssignAdapter({"supportsSetVariable": True})
+
+        body = await session.set_variable(3, "count", "42")
+
+        assert body["value"] == "42"
+        assert session.adapter.requests == [
+            ("setVariable", {"variablesReference": 3, "name": "count", "value": "42"})
+        ]
+
+    @pytest.mark.asyncio
+    async def test_set_expression_request(self, session):
+        """Test that an expression assignment maps onto DAP setExpression."""
+        session.adapter = AssignAdapter({"supportsSetExpression": True})
+
+        await session.set_expression("obj.items[3]", "7", frame_id=5)
+
+        assert session.adapter.requests == [
+            ("setExpression", {"expression": "obj.items[3]", "value": "7", "frameId": 5})
+        ]
+
+    @pytest.mark.asyncio
+    async def test_unsupported_capability(self, session):
+        """Test that assignment needs the matching capability."""
+        session.adapter = AssignAdapter({"supportsSetVariable": True})
+
+        with pytest.raises(CapabilityNotSupportedError):
+            await session.set_expression("x", "1")
+        assert session.adapter.requests == []
+
+    @pytest.mark.asyncio
+    async def test_adapter_error_passed_through(self, session):
+        """Test that type errors keep the adapter's message."""
+        session.adapter = AssignAdapter({"supportsSetVariable": True})
+
+        with pytest.raises(DAPError, match="TypeError: expected int"):
+            await session.set_variable(3, "count", "'oops'")
+
+    @pytest.mark.asyncio
+    async def test_stale_reference_rejected(self, session):
+        """Test that references from before a resume can't be assigned through."""
+        session.adapter = AssignAdapter({"supportsSetVariable": True})
+        session._remember_reference(3, 1, None)
+        await session.continue_()
+        session._state = SessionState.PAUSED
+
+        with pytest.raises(VariableNotFoundError):
+            await session.set_variable(3, "count", "42")
+
+    @pytest.mark.asyncio
+    async def test_watches_refreshed(self, session):
+        """Test that cached watch values reflect the assignment."""
+        session.adapter = AssignAdapter({"supportsSetVariable": True})
+        session.add_watch("count")
+        await session.evaluate_watches()
+
+        await session.set_variable(3, "count", "42")
+
+        assert session.describe_watches()[0]["result"] == "42"
+
+    @pytest.mark.asyncio
+    async def test_requires_paused(self, session):
+        """Test that assignment is rejected while running."""
+        session.adapter = AssignAdapter({"supportsSetVariable": True})
+        session._state = SessionState.RUNNING
+
+        with pytest.raises(InvalidSessionStateError):
+            await session.set_variable(3, "count", "42")
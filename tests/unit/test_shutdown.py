@@ -0,0 +1,214 @@
+"""Tests for ending every session when the server shuts down."""
+
+import asyncio
+import os
+import sys
+
+import pytest
+
+import polybugger_mcp.mcp_server as mcp_server
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.adapters.dap_client import DAPClient
+from polybugger_mcp.core.exceptions import ServerShuttingDownError
+from polybugger_mcp.core.session import Session, SessionManager, SessionState
+from polybugger_mcp.models.session import SessionConfig
+from polybugger_mcp.persistence.breakpoints import BreakpointStore
+from polybugger_mcp.persistence.sessions import SessionStore
+
+
+class NullWriter:
+    """Stream writer stub discarding what is written."""
+
+    def write(self, data):
+        pass
+
+    async def drain(self):
+        pass
+
+    def close(self):
+        pass
+
+    async def wait_closed(self):
+        pass
+
+
+class ShutdownRecorder:
+    """Adapter stub remembering how it was shut down."""
+
+    def __init__(self, delay: float = 0.0):
+        self.calls: list[tuple[bool, float]] = []
+        self.delay = delay
+
+    async def shutdown(self, terminate, grace_seconds):
+        self.calls.append((terminate, grace_seconds))
+        await asyncio.sleep(self.delay)
+
+    async def continue_execution(self, thread_id):
+        pass
+
+
+class HangingAdapter:
+    """Adapter stub whose disconnect never finishes, using the base shutdown."""
+
+    shutdown = DebugAdapter.shutdown
+
+    def __init__(self, process, client):
+        self._process = process
+        self._terminal_process = None
+        self._client = client
+        self.disconnects: list[bool] = []
+
+    async def disconnect(self, terminate=False):
+        self.disconnects.append(terminate)
+        await asyncio.sleep(60)
+
+
+@pytest.fixture
+def manager(tmp_path):
+    """Create a session manager with a launched and an attached session."""
+    manager = SessionManager(
+        breakpoint_store=BreakpointStore(base_dir=tmp_path / "bp"),
+        session_store=SessionStore(base_dir=tmp_path / "sessions"),
+    )
+    for session_id, attached in (("launched", False), ("attached", True)):
+        session = Session(session_id=session_id, project_root=tmp_path)
+        session.adapter = ShutdownRecorder(delay=0.1)  # type: ignore[assignment]
+        session.attached = attached
+        session._state = SessionState.RUNNING
+        manager._sessions[session_id] = session
+    return manager
+
+
+class TestManagerShutdown:
+    """Tests for SessionManager.shutdown."""
+
+    @pytest.mark.asyncio
+    async def test_idempotent(self, manager, tmp_path):
+        """Test that concurrent and repeated calls shut each session down once."""
+        launched = manager._sessions["launched"]
+        attached = manager._sessions["attached"]
+        launched_adapter, attached_adapter = launched.adapter, attached.adapter
+
+        await asyncio.gather(manager.shutdown(grace_seconds=2.0), manager.stop())
+        await manager.shutdown()
+
+        assert launched_adapter.calls == [(True, 2.0)]
+        assert attached_adapter.calls == [(False, 2.0)]
+        assert launched.state == SessionState.TERMINATED
+        assert manager.active_count == 0
+        assert len(list((tmp_path / "sessions").glob("*.json"))) == 2
+
+    @pytest.mark.asyncio
+    async def test_terminate_attached(self, manager):
+        """Test that attached debuggees can be terminated too."""
+        attached_adapter = manager._sessions["attached"].adapter
+
+        await manager.shutdown(grace_seconds=1.0, terminate_attached=True)
+
+        assert attached_adapter.calls == [(True, 1.0)]
+
+    @pytest.mark.asyncio
+    async def test_sessions_refused_after(self, manager, tmp_path):
+        """Test that sessions can't be looked up or created once shutting down."""
+        await manager.shutdown()
+
+        with pytest.raises(ServerShuttingDownError):
+            await manager.resolve_session()
+        with pytest.raises(ServerShuttingDownError):
+            await manager.get_session("launched")
+        with pytest.raises(ServerShuttingDownError):
+            await manager.create_session(SessionConfig(project_root=str(tmp_path)))
+
+    @pytest.mark.asyncio
+    async def test_in_flight_wait_fails(self, manager):
+        """Test that a call waiting for a stop fails instead of reporting an exit."""
+        session = manager._sessions["launched"]
+        wait = asyncio.create_task(session.wait_for_stop(session.stop_count, timeout=30.0))
+        await asyncio.sleep(0)
+
+        await manager.shutdown()
+
+        with pytest.raises(ServerShuttingDownError):
+            await asyncio.wait_for(wait, timeout=1.0)
+
+
+class TestToolsDuringShutdown:
+    """Tests for MCP tools called while the server shuts down."""
+
+    @pytest.fixture(autouse=True)
+    def tool_manager(self, manager, monkeypatch):
+        """Serve the tools from the test's session manager."""
+        monkeypatch.setattr(mcp_server, "_session_manager", manager)
+
+    @pytest.mark.asyncio
+    async def test_calls_after_shutdown_refused(self, manager, tmp_path):
+        """Test that session tools answer SERVER_SHUTTING_DOWN instead of raising."""
+        await manager.shutdown()
+
+        results = [
+            await mcp_server.debug_create_session(project_root=str(tmp_path)),
+            await mcp_server.debug_get_session(session_id="launched"),
+            await mcp_server.debug_continue(session_id="launched"),
+            await mcp_server.debug_restart_session(session_id="launched"),
+        ]
+
+        assert [result["code"] for result in results] == ["SERVER_SHUTTING_DOWN"] * 4
+
+    @pytest.mark.asyncio
+    async def test_interrupted_wait_refused(self, manager):
+        """Test that a tool waiting for a stop when shutdown begins gets the error."""
+        manager._sessions["launched"]._state = SessionState.PAUSED
+        call = asyncio.create_task(
+            mcp_server.debug_continue(wait_for_stop_seconds=30.0, session_id="launched")
+        )
+        await asyncio.sleep(0.05)
+
+        await manager.shutdown()
+
+        result = await asyncio.wait_for(call, timeout=1.0)
+        assert result["code"] == "SERVER_SHUTTING_DOWN"
+
+
+@pytest.mark.skipif(sys.platform == "win32", reason="uses POSIX process groups")
+class TestAdapterShutdown:
+    """Tests for DebugAdapter.shutdown."""
+
+    @pytest.mark.asyncio
+    async def test_hanging_disconnect_killed_after_grace(self, tmp_path):
+        """Test that pending requests fail and the process group dies on time."""
+        pid_file = tmp_path / "child.pid"
+        process = await asyncio.create_subprocess_exec(
+            "sh",
+            "-c",
+            f"sleep 60 & echo $! > {pid_file}; wait",
+            start_new_session=True,
+        )
+        while not pid_file.exists() or not pid_file.read_text().strip():
+            await asyncio.sleep(0.05)
+        child = int(pid_file.read_text())
+
+        client = DAPClient(asyncio.StreamReader(), NullWriter())  # type: ignore[arg-type]
+        await client.start()
+        request = asyncio.create_task(client.send_request("threads"))
+        await asyncio.sleep(0)
+        adapter = HangingAdapter(process, client)
+
+        loop = asyncio.get_running_loop()
+        started = loop.time()
+        await adapter.shutdown(terminate=True, grace_seconds=0.3)
+
+        assert loop.time() - started < 5.0
+        assert adapter.disconnects == [True]
+        with pytest.raises(ServerShuttingDownError):
+            await asyncio.wait_for(request, timeout=1.0)
+        await client.stop()
+        assert await asyncio.wait_for(process.wait(), timeout=5.0) < 0
+        for _ in range(50):
+            try:
+                os.kill(child, 0)
+            except ProcessLookupError:
+                break
+            await asyncio.sleep(0.1)
+        else:
+            with open(f"/proc/{child}/stat") as f:
+                assert f.read().rsplit(")", 1)[1].split()[0] == "Z"
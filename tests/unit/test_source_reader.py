@@ -8,6 +8,7 @@ import pytest
 from polybugger_mcp.utils.source_reader import (
     clear_cache,
     extract_call_expression,
+    extract_symbol_at,
     format_source_with_line_numbers,
     get_function_context,
     get_source_context,
@@ -240,6 +241,34 @@ class TestExtractCallExpression:
         assert result is None
 
 
+class TestExtractSymbolAt:
+    """Tests for extract_symbol_at function."""
+
+    def test_identifier_with_dotted_prefix(self):
+        """Should include the names before the identifier but not after."""
+        line = "    total = order.items.count()"
+        assert extract_symbol_at(line, 5) == "total"
+        assert extract_symbol_at(line, 21) == "order.items"
+        assert extract_symbol_at(line, 25) == "order.items.count"
+
+    def test_whitespace_and_operators(self):
+        """Whitespace, operators and positions past the end have no symbol."""
+        assert extract_symbol_at("x = y + 1", 2) is None
+        assert extract_symbol_at("x = y + 1", 3) is None
+        assert extract_symbol_at("x = y + 1", 9) is None
+        assert extract_symbol_at("x = y", 40) is None
+
+    def test_attribute_of_call_has_no_symbol(self):
+        """An attribute of a call result can't be evaluated on its own."""
+        assert extract_symbol_at("f(x).y", 6) is None
+
+    def test_multibyte_columns_count_characters(self):
+        """Multi-byte UTF-8 text before the symbol doesn't shift the column."""
+        line = 's = "héllo wörld"; naïve.größe'
+        assert extract_symbol_at(line, 20) == "naïve"
+        assert extract_symbol_at(line, 27) == "naïve.größe"
+
+
 class TestFormatSourceWithLineNumbers:
     """Tests for format_source_with_line_numbers function."""
 
@@ -0,0 +1,120 @@
+"""Tests for source context attached to stack frames."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import DAPError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+from polybugger_mcp.utils.path_mapper import PathMapper
+from polybugger_mcp.utils.source_reader import clear_cache
+
+GENERATED = "\n".join(f"generated {n}" for n in range(1, 6))
+
+
+class SourceAdapter:
+    """Adapter stub serving one virtual source by reference."""
+
+    def __init__(self):
+        self.source_requests: list[int] = []
+
+    async def get_source(self, source_reference):
+        self.source_requests.append(source_reference)
+        if source_reference != 4:
+            raise DAPError("SOURCE", "Invalid sourceReference")
+        return {"content": GENERATED}
+
+
+@pytest.fixture
+def app(tmp_path):
+    clear_cache()
+    path = tmp_path / "app.py"
+    path.write_text("\n".join(f"line {n}" for n in range(1, 21)) + "\n")
+    return path
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session with the source stub."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = SourceAdapter()  # type: ignore[assignment]
+    session._state = SessionState.PAUSED
+    return session
+
+
+def frame(frame_id: int, line: int, **source) -> StackFrame:
+    return StackFrame(id=frame_id, name=f"f{frame_id}", line=line, source=Source(**source))
+
+
+class TestStackSourceContexts:
+    """Tests for Session.stack_source_contexts."""
+
+    @pytest.mark.asyncio
+    async def test_file_and_virtual_sources(self, session, app):
+        """Test that file frames and sourceReference frames both get their lines."""
+        frames = [frame(1, 10, path=str(app)), frame(2, 3, name="<gen>", sourceReference=4)]
+
+        contexts, capped = await session.stack_source_contexts(frames, context_lines=1)
+
+        assert capped is False
+        assert contexts[0] == {
+            "source": "line 10",
+            "context": {"before": ["line 9"], "after": ["line 11"]},
+            "line_numbers": {"start": 9, "current": 10, "end": 11},
+        }
+        assert contexts[1]["source"] == "generated 3"
+
+    @pytest.mark.asyncio
+    async def test_unreadable_sources_marked(self, session, tmp_path):
+        """Test that unreadable frames are marked instead of failing the trace."""
+        frames = [
+            frame(1, 5, path=str(tmp_path / "missing.py")),
+            frame(2, 1, name="<gone>", sourceReference=9),
+            StackFrame(id=3, name="native", line=0),
+        ]
+
+        contexts, _ = await session.stack_source_contexts(frames, context_lines=2)
+
+        assert contexts[0] == {"source_unavailable": "source not readable"}
+        assert contexts[1]["source_unavailable"].startswith("source request failed")
+        assert contexts[2] == {"source_unavailable": "frame has no source"}
+
+    @pytest.mark.asyncio
+    async def test_recursion_shares_reads(self, session, tmp_path):
+        """Test that repeated frames in one virtual source fetch it once."""
+        frames = [frame(n, 2, name="<gen>", sourceReference=4) for n in range(200)]
+
+        contexts, _ = await session.stack_source_contexts(frames, context_lines=0)
+
+        assert session.adapter.source_requests == [4]
+        assert all(c["source"] == "generated 2" for c in contexts)
+
+    @pytest.mark.asyncio
+    async def test_size_cap(self, session, app):
+        """Test that source stops being attached once the byte cap is reached."""
+        frames = [frame(n, 10, path=str(app)) for n in range(200)]
+
+        # Each frame attaches 23 bytes: three lines and their newlines
+        contexts, capped = await session.stack_source_contexts(
+            frames, context_lines=1, max_bytes=100
+        )
+
+        assert capped is True
+        assert [c.get("source") for c in contexts[:4]] == ["line 10"] * 4
+        assert contexts[4] == {"source_unavailable": "source size limit reached"}
+        assert contexts[-1] == {"source_unavailable": "source size limit reached"}
+
+    @pytest.mark.asyncio
+    async def test_path_mappings_applied(self, session, app, tmp_path):
+        """Test that frames from a remote path are read from the local copy."""
+        session.path_mapper = PathMapper([(str(tmp_path), "/srv/app")])
+        session.adapter.get_stack_trace = _remote_stack  # type: ignore[attr-defined]
+
+        frames = await session.get_stack_trace(1)
+        contexts, _ = await session.stack_source_contexts(frames, context_lines=0)
+
+        assert frames[0].source.path == str(app)
+        assert contexts[0]["source"] == "line 4"
+
+
+async def _remote_stack(thread_id, start_frame=0, levels=20):
+    return [frame(1, 4, path="/srv/app/app.py")]
@@ -0,0 +1,176 @@
+"""Tests for writing to the debuggee's stdin."""
+
+import asyncio
+import json
+import sys
+
+import pytest
+
+from polybugger_mcp.adapters.base import DebugAdapter
+from polybugger_mcp.adapters.dap_client import DAPClient
+from polybugger_mcp.core.exceptions import StdinUnavailableError
+from polybugger_mcp.core.session import Session, SessionState
+
+
+class TerminalAdapter:
+    """Adapter stub using the base class runInTerminal support."""
+
+    supports_stdin_pipe = True
+    _launch_in_terminal = DebugAdapter._launch_in_terminal
+    _run_in_terminal = DebugAdapter._run_in_terminal
+    write_stdin = DebugAdapter.write_stdin
+    _close_terminal_process = DebugAdapter._close_terminal_process
+
+    def __init__(self, stdin_mode: str = "pipe"):
+        self.session_id = "test_session"
+        self._stdin_mode = stdin_mode
+        self._terminal_process = None
+
+
+class RecordingWriter:
+    """Stream writer stub that keeps what was written."""
+
+    def __init__(self):
+        self.data = b""
+
+    def write(self, data):
+        self.data += data
+
+    async def drain(self):
+        pass
+
+
+def copy_stdin_command(path) -> list[str]:
+    """Command that copies its stdin into path."""
+    return [sys.executable, "-c", f"import sys; open({str(path)!r}, 'w').write(sys.stdin.read())"]
+
+
+class TestRunInTerminal:
+    """Tests for starting the debuggee ourselves."""
+
+    @pytest.mark.asyncio
+    async def test_pipe_round_trip(self, tmp_path):
+        """Test that written bytes reach the started process."""
+        adapter = TerminalAdapter("pipe")
+        out = tmp_path / "stdin.txt"
+
+        body = await adapter._run_in_terminal({"args": copy_stdin_command(out), "cwd": None})
+        await adapter.write_stdin(b"line one\n")
+        await adapter.write_stdin(b"line two\n")
+        process = adapter._terminal_process
+        await adapter._close_terminal_process(terminate=False)
+        await asyncio.wait_for(process.wait(), timeout=10.0)
+
+        assert body == {"processId": process.pid}
+        assert out.read_text() == "line one\nline two\n"
+
+    @pytest.mark.asyncio
+    async def test_closed_mode_has_no_pipe(self, tmp_path):
+        """Test that closed mode gives the program an empty stdin."""
+        adapter = TerminalAdapter("closed")
+        out = tmp_path / "stdin.txt"
+
+        await adapter._run_in_terminal({"args": copy_stdin_command(out)})
+        await asyncio.wait_for(adapter._terminal_process.wait(), timeout=10.0)
+
+        assert out.read_text() == ""
+        with pytest.raises(StdinUnavailableError):
+            await adapter.write_stdin(b"x\n")
+
+    @pytest.mark.asyncio
+    async def test_exited_program(self, tmp_path):
+        """Test that writing after the program exits fails clearly."""
+        adapter = TerminalAdapter("pipe")
+
+        await adapter._run_in_terminal({"args": [sys.executable, "-c", "pass"]})
+        await asyncio.wait_for(adapter._terminal_process.wait(), timeout=10.0)
+
+        with pytest.raises(StdinUnavailableError, match="exited"):
+            await adapter.write_stdin(b"x\n")
+
+    @pytest.mark.asyncio
+    async def test_reverse_request_answered(self, tmp_path):
+        """Test that DAPClient answers runInTerminal through the registered handler."""
+        writer = RecordingWriter()
+        client = DAPClient(reader=None, writer=writer)  # type: ignore[arg-type]
+        adapter = TerminalAdapter("pipe")
+        adapter._launch_in_terminal(client, "pipe")
+
+        await client._handle_message(
+            {
+                "seq": 7,
+                "type": "request",
+                "command": "runInTerminal",
+                "arguments": {"args": [sys.executable, "-c", "pass"]},
+            }
+        )
+        await asyncio.gather(*client._handler_tasks)
+        await adapter._close_terminal_process(terminate=True)
+
+        response = json.loads(writer.data.split(b"\r\n\r\n", 1)[1])
+        assert response["request_seq"] == 7
+        assert response["success"] is True
+        assert response["body"]["processId"] > 0
+
+    @pytest.mark.asyncio
+    async def test_unknown_reverse_request_rejected(self):
+        """Test that unsupported reverse requests get a failure response."""
+        writer = RecordingWriter()
+        client = DAPClient(reader=None, writer=writer)  # type: ignore[arg-type]
+
+        await client._handle_message({"seq": 3, "type": "request", "command": "startDebugging"})
+        await asyncio.gather(*client._handler_tasks)
+
+        response = json.loads(writer.data.split(b"\r\n\r\n", 1)[1])
+        assert response["success"] is False
+
+
+class TestSessionStdin:
+    """Tests for when a session can accept stdin."""
+
+    @pytest.fixture
+    def session(self, tmp_path):
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session._state = SessionState.RUNNING
+        session.stdin_mode = "pipe"
+        return session
+
+    @pytest.mark.asyncio
+    async def test_send_stdin(self, session, tmp_path):
+        """Test that stdin is written through the adapter."""
+        session.adapter = TerminalAdapter("pipe")
+        out = tmp_path / "stdin.txt"
+        await session.adapter._run_in_terminal({"args": copy_stdin_command(out)})
+
+        assert session.stdin_available is True
+        assert await session.send_stdin(b"abc\n") == 4
+        await session.adapter._close_terminal_process(terminate=True)
+
+    @pytest.mark.asyncio
+    async def test_inherit_mode_rejected(self, session):
+        """Test that non-pipe modes explain how to get a pipe."""
+        session.adapter = TerminalAdapter("inherit")
+        session.stdin_mode = "inherit"
+
+        with pytest.raises(StdinUnavailableError, match="stdin_mode 'pipe'"):
+            await session.send_stdin(b"x\n")
+
+    @pytest.mark.asyncio
+    async def test_adapter_owned_process_rejected(self, session):
+        """Test that adapters without runInTerminal support can't take stdin."""
+        session.adapter = TerminalAdapter("pipe")
+        session.adapter.supports_stdin_pipe = False
+        session.language = "go"
+
+        assert session.stdin_available is False
+        with pytest.raises(StdinUnavailableError, match="go debug adapter"):
+            await session.send_stdin(b"x\n")
+
+    @pytest.mark.asyncio
+    async def test_attached_rejected(self, session):
+        """Test that attached processes can't take stdin."""
+        session.adapter = TerminalAdapter("pipe")
+        session.attached = True
+
+        with pytest.raises(StdinUnavailableError, match="attached"):
+            await session.send_stdin(b"x\n")
@@ -0,0 +1,150 @@
+"""Tests for step filters and automatic stepping out of filtered code."""
+
+import pytest
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import LaunchConfig, Source, StackFrame, StepFilters
+from polybugger_mcp.models.events import EventType
+from polybugger_mcp.utils.step_filter import StepFilter
+
+LIBRARY = "/venv/lib/python3.12/site-packages/requests/api.py"
+
+
+class TestStepFilter:
+    """Tests for StepFilter path matching."""
+
+    def test_site_packages_and_node_modules(self):
+        """Test that third-party installs are recognised across languages."""
+        step_filter = StepFilter(skip_site_packages=True)
+
+        assert step_filter.matches(LIBRARY)
+        assert step_filter.matches("/app/node_modules/express/lib/router.js")
+        assert not step_filter.matches("/app/src/main.py")
+
+    def test_stdlib_excludes_site_packages(self):
+        """Test that skip_stdlib alone leaves installed packages alone."""
+        step_filter = StepFilter(skip_stdlib=True)
+
+        assert step_filter.matches("/usr/lib/python3.12/json/decoder.py")
+        assert not step_filter.matches(LIBRARY)
+
+    def test_patterns_relative_to_project(self, tmp_path):
+        """Test that globs match paths relative to the project root."""
+        step_filter = StepFilter(["tests/*", "*/vendor/*"], project_root=tmp_path)
+
+        assert step_filter.matches(str(tmp_path / "tests" / "conftest.py"))
+        assert step_filter.matches("/opt/vendor/lib.py")
+        assert not step_filter.matches(str(tmp_path / "app.py"))
+
+    def test_empty_filter_is_falsy(self):
+        """Test that a filter without rules is inactive."""
+        assert not StepFilter()
+
+
+class FilterAdapter:
+    """Adapter stub whose stack top follows a scripted list of files."""
+
+    supports_just_my_code = False
+
+    def __init__(self, locations: list[str]):
+        self.capabilities = {}
+        self.sticky_steps = False
+        self.locations = locations
+        self.steps: list[str] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        path = self.locations[min(len(self.steps) - 1, len(self.locations) - 1)]
+        return [StackFrame(id=1, name="f", source=Source(path=path), line=1)]
+
+    async def step_into(self, thread_id):
+        self.steps.append("into")
+
+    async def step_out(self, thread_id):
+        self.steps.append("out")
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session that skips installed packages."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    session.set_step_filters(skip_site_packages=True)
+    return session
+
+
+async def _stop(session: Session) -> None:
+    """Deliver a step stop and let the filter check finish."""
+    await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+    for task in list(session._background_tasks):
+        await task
+
+
+class TestAutoStep:
+    """Tests for stepping out of filtered code."""
+
+    @pytest.mark.asyncio
+    async def test_steps_out_until_user_code(self, session):
+        """Test that filtered stops are swallowed and counted."""
+        session.adapter = FilterAdapter([LIBRARY, LIBRARY, "/app/main.py"])
+        await session.step_into()
+
+        for _ in range(3):
+            await _stop(session)
+
+        assert session.adapter.steps == ["into", "out", "out"]
+        assert session.stop_count == 1
+        assert session.skipped_frames == 2
+        result = await session.wait_for_stop(0, timeout=0.1)
+        assert result["skipped_frames"] == 2
+
+    @pytest.mark.asyncio
+    async def test_unfiltered_stop_published(self, session):
+        """Test that a step landing in user code stops without extra steps."""
+        session.adapter = FilterAdapter(["/app/main.py"])
+        await session.step_into()
+
+        await _stop(session)
+
+        assert session.adapter.steps == ["into"]
+        assert session.stop_count == 1
+        assert session.skipped_frames == 0
+
+    @pytest.mark.asyncio
+    async def test_gives_up_after_cap(self, session, monkeypatch):
+        """Test that auto-stepping stops in filtered code after the cap."""
+        monkeypatch.setattr(settings, "step_filter_max_steps", 1)
+        session.adapter = FilterAdapter([LIBRARY])
+        await session.step_into()
+
+        await _stop(session)
+        await _stop(session)
+
+        assert session.adapter.steps == ["into", "out"]
+        assert session.stop_count == 1
+        assert "filtered code" in session.stop_description
+
+    def test_just_my_code_fallback(self, session):
+        """Test that just_my_code filters library code on adapters without it."""
+        session.adapter = FilterAdapter([])
+
+        step_filter = session._launch_step_filter(LaunchConfig(program="x", just_my_code=True))
+
+        assert step_filter.skip_stdlib and step_filter.skip_site_packages
+
+    def test_native_just_my_code_not_duplicated(self, session):
+        """Test that adapters honouring just_my_code get only explicit filters."""
+        session.adapter = FilterAdapter([])
+        session.adapter.supports_just_my_code = True
+        config = LaunchConfig(
+            program="x", just_my_code=True, step_filters=StepFilters(patterns=["*/gen/*"])
+        )
+
+        step_filter = session._launch_step_filter(config)
+
+        assert step_filter.describe() == {
+            "patterns": ["*/gen/*"],
+            "skip_stdlib": False,
+            "skip_site_packages": False,
+        }
@@ -0,0 +1,135 @@
+"""Tests for steps that stop on a different thread (goroutine)."""
+
+import pytest
+
+from polybugger_mcp.config import settings
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.events import EventType
+
+
+class StepAdapter:
+    """Adapter stub recording step requests."""
+
+    def __init__(self, sticky_steps: bool = True, fail: bool = False):
+        self.capabilities = {}
+        self.sticky_steps = sticky_steps
+        self.fail = fail
+        self.steps: list[tuple[str, int]] = []
+
+    async def step_over(self, thread_id):
+        self.steps.append(("over", thread_id))
+        if self.fail and len(self.steps) > 1:
+            raise RuntimeError("goroutine exited")
+
+    async def step_into(self, thread_id):
+        self.steps.append(("into", thread_id))
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session paused on thread 1."""
+    session = Session(session_id="test_session", project_root=tmp_path, language="go")
+    session.adapter = StepAdapter()
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+async def _settle(session: Session) -> None:
+    """Let spawned re-issue tasks finish."""
+    for task in list(session._background_tasks):
+        await task
+
+
+class TestStickyStep:
+    """Tests for the pending-step tracking in Session."""
+
+    @pytest.mark.asyncio
+    async def test_stop_on_other_thread_reissued(self, session):
+        """Test that a sticky step stopping elsewhere is re-issued on its thread."""
+        await session.step_over()
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 7})
+        await _settle(session)
+
+        assert session.adapter.steps == [("over", 1), ("over", 1)]
+        assert session.stop_count == 0
+        assert session.state == SessionState.RUNNING
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+
+        assert session.stop_count == 1
+        assert session.current_thread_id == 1
+        assert session.step_thread_id is None
+
+    @pytest.mark.asyncio
+    async def test_step_kind_kept_on_reissue(self, session):
+        """Test that the re-issued request is the same kind of step."""
+        await session.step_into()
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 7})
+        await _settle(session)
+
+        assert session.adapter.steps == [("into", 1), ("into", 1)]
+
+    @pytest.mark.asyncio
+    async def test_non_sticky_stop_annotated(self, session):
+        """Test that without sticky the stop says which thread was stepped."""
+        await session.step_over(sticky=False)
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 7})
+
+        assert session.adapter.steps == [("over", 1)]
+        assert session.current_thread_id == 7
+        assert session.step_thread_id == 1
+        assert session.stop_description == "Step on thread 1 stopped on thread 7"
+        result = await session.wait_for_stop(0, timeout=0.1)
+        assert result["step_thread_id"] == 1
+
+    @pytest.mark.asyncio
+    async def test_adapter_default(self, session):
+        """Test that sticky defaults to the adapter's sticky_steps."""
+        session.adapter = StepAdapter(sticky_steps=False)
+        await session.step_over()
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 7})
+
+        assert session.step_thread_id == 1
+
+    @pytest.mark.asyncio
+    async def test_retries_bounded(self, session, monkeypatch):
+        """Test that a step bouncing repeatedly is eventually reported."""
+        monkeypatch.setattr(settings, "sticky_step_max_retries", 2)
+        await session.step_over()
+
+        for _ in range(3):
+            await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 7})
+            await _settle(session)
+
+        assert len(session.adapter.steps) == 3
+        assert session.stop_count == 1
+        assert session.step_thread_id == 1
+
+    @pytest.mark.asyncio
+    async def test_failed_reissue_publishes_stop(self, session):
+        """Test that the swallowed stop is published if the re-issue fails."""
+        session.adapter = StepAdapter(fail=True)
+        await session.step_over()
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 7})
+        await _settle(session)
+
+        assert session.stop_count == 1
+        assert session.current_thread_id == 7
+        assert session.step_thread_id == 1
+
+    @pytest.mark.asyncio
+    async def test_breakpoint_on_other_thread_not_redirected(self, session):
+        """Test that only step stops are redirected; breakpoints elsewhere stand."""
+        await session.step_over()
+
+        await session._handle_event(EventType.STOPPED, {"reason": "breakpoint", "threadId": 7})
+
+        assert session.adapter.steps == [("over", 1)]
+        assert session.current_thread_id == 7
+        assert session.step_thread_id is None
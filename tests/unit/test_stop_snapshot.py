@@ -0,0 +1,141 @@
+"""Tests for the combined stopped-state snapshot."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import InvalidSessionStateError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Scope, Source, StackFrame, Variable
+from polybugger_mcp.utils.path_mapper import PathMapper
+from polybugger_mcp.utils.source_reader import clear_cache
+
+
+class SnapshotAdapter:
+    """Adapter stub stopped in main() at the given path and line."""
+
+    def __init__(self, path: str, line: int = 5):
+        self.path = path
+        self.line = line
+        self.fail_scopes = False
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        frames = [
+            StackFrame(id=10, name="main", line=self.line, source=Source(path=self.path)),
+            StackFrame(id=11, name="<module>", line=40, source=Source(path=self.path)),
+        ]
+        return frames[:levels]
+
+    async def get_scopes(self, frame_id):
+        if self.fail_scopes:
+            raise RuntimeError("scopes request failed")
+        return [
+            Scope(name="Globals", presentationHint="globals", variablesReference=2),
+            Scope(name="Locals", presentationHint="locals", variablesReference=1),
+        ]
+
+    async def get_variables(self, variables_ref, start=0, count=100, filter=None):
+        assert variables_ref == 1
+        return [
+            Variable(name="x", value="1", type="int"),
+            Variable(name="items", value="[...]", type="list", variablesReference=7),
+        ][:count]
+
+    async def evaluate(self, expression, frame_id=None, context="watch"):
+        if expression == "boom":
+            raise RuntimeError("name 'boom' is not defined")
+        return {"result": "2", "type": "int"}
+
+
+@pytest.fixture
+def source_file(tmp_path):
+    clear_cache()
+    path = tmp_path / "app.py"
+    path.write_text("\n".join(f"line {n}" for n in range(1, 21)) + "\n")
+    return path
+
+
+@pytest.fixture
+def session(tmp_path, source_file):
+    """Create a session stopped at a breakpoint."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = SnapshotAdapter(str(source_file))
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    session.stop_reason = "breakpoint"
+    return session
+
+
+class TestStopSnapshot:
+    """Tests for Session.get_stop_snapshot."""
+
+    @pytest.mark.asyncio
+    async def test_full_snapshot(self, session, source_file):
+        """Test that every section is filled from one call."""
+        session.add_watch("x + 1")
+
+        snapshot = await session.get_stop_snapshot(max_frames=1, context_lines=2)
+
+        assert snapshot["reason"] == "breakpoint"
+        assert [f["name"] for f in snapshot["frames"]] == ["main"]
+        assert [v["name"] for v in snapshot["locals"]] == ["x", "items"]
+        assert snapshot["locals"][1]["variables_reference"] == 7
+        assert [sc["name"] for sc in snapshot["scopes"]] == ["Globals", "Locals"]
+        assert snapshot["watches"][0]["result"] == "2"
+        assert snapshot["source"] == {
+            "file": str(source_file),
+            "source_reference": None,
+            "line": 5,
+            "start_line": 3,
+            "lines": ["line 3", "line 4", "line 5", "line 6", "line 7"],
+        }
+        assert snapshot["errors"] == {}
+
+    @pytest.mark.asyncio
+    async def test_frame_ids_usable_afterwards(self, session):
+        """Test that frame IDs from the snapshot are accepted by later calls."""
+        snapshot = await session.get_stop_snapshot()
+
+        scopes = await session.get_scopes(snapshot["frames"][1]["id"])
+        assert len(scopes) == 2
+
+    @pytest.mark.asyncio
+    async def test_unreadable_source_degrades(self, session, tmp_path):
+        """Test that a missing source file only empties the source section."""
+        session.adapter.path = str(tmp_path / "gone.py")
+
+        snapshot = await session.get_stop_snapshot()
+
+        assert snapshot["source"] is None
+        assert "gone.py" in snapshot["errors"]["source"]
+        assert [v["name"] for v in snapshot["locals"]] == ["x", "items"]
+
+    @pytest.mark.asyncio
+    async def test_failing_scopes_degrade(self, session):
+        """Test that a failed scopes request only empties the locals section."""
+        session.adapter.fail_scopes = True
+        session.add_watch("boom")
+
+        snapshot = await session.get_stop_snapshot()
+
+        assert snapshot["locals"] == []
+        assert snapshot["errors"]["locals"] == "scopes request failed"
+        assert snapshot["watches"][0]["error"] == "name 'boom' is not defined"
+        assert snapshot["source"] is not None
+
+    @pytest.mark.asyncio
+    async def test_source_read_through_path_mappings(self, session, source_file):
+        """Test that a remote frame path is read from the mapped local file."""
+        session.adapter.path = "/app/app.py"
+        session.path_mapper = PathMapper([(str(source_file.parent), "/app")])
+
+        snapshot = await session.get_stop_snapshot(context_lines=0)
+
+        assert snapshot["frames"][0]["file"] == str(source_file)
+        assert snapshot["source"]["lines"] == ["line 5"]
+
+    @pytest.mark.asyncio
+    async def test_requires_paused(self, session):
+        """Test that a running session is rejected."""
+        session._state = SessionState.RUNNING
+
+        with pytest.raises(InvalidSessionStateError):
+            await session.get_stop_snapshot()
@@ -0,0 +1,217 @@
+"""Tests for sessions connecting to a DAP server over TCP."""
+
+import asyncio
+import json
+import socket
+
+import pytest
+import pytest_asyncio
+
+from polybugger_mcp.adapters.dap_client import connect_tcp
+from polybugger_mcp.adapters.debugpy_adapter import DebugpyAdapter
+from polybugger_mcp.adapters.delve_adapter import DelveAdapter
+from polybugger_mcp.config import settings
+from polybugger_mcp.core.exceptions import DAPConnectionError, LaunchError
+from polybugger_mcp.core.session import Session
+from polybugger_mcp.models.dap import TcpTransport
+from polybugger_mcp.models.session import SessionConfig
+
+
+def _free_port() -> int:
+    with socket.socket() as s:
+        s.bind(("127.0.0.1", 0))
+        return s.getsockname()[1]
+
+
+class FakeDAPServer:
+    """Minimal DAP server answering every request with success."""
+
+    def __init__(self):
+        self.commands: list[str] = []
+        self.connections = 0
+        self.closed = asyncio.Event()
+        self._server: asyncio.Server | None = None
+
+    async def start(self, port: int = 0) -> int:
+        self._server = await asyncio.start_server(self._serve, "127.0.0.1", port)
+        return self._server.sockets[0].getsockname()[1]
+
+    async def stop(self):
+        if self._server is not None:
+            self._server.close()
+            await self._server.wait_closed()
+
+    async def _serve(self, reader, writer):
+        self.connections += 1
+        seq = 0
+        try:
+            while True:
+                header = await reader.readuntil(b"\r\n\r\n")
+                length = int(header.split(b":")[1].strip().split(b"\r\n")[0])
+                request = json.loads(await reader.readexactly(length))
+                self.commands.append(request["command"])
+                seq += 1
+                body = {"supportsConfigurationDoneRequest": True}
+                response = json.dumps(
+                    {
+                        "seq": seq,
+                        "type": "response",
+                        "request_seq": request["seq"],
+                        "command": request["command"],
+                        "success": True,
+                        "body": body if request["command"] == "initialize" else {},
+                    }
+                ).encode()
+                writer.write(b"Content-Length: %d\r\n\r\n" % len(response) + response)
+                await writer.drain()
+        except (asyncio.IncompleteReadError, ConnectionError):
+            pass
+        finally:
+            writer.close()
+            self.closed.set()
+
+
+@pytest_asyncio.fixture
+async def server():
+    """Run a fake DAP server for the test."""
+    server = FakeDAPServer()
+    yield server
+    await server.stop()
+
+
+class TestConnectTcp:
+    """Tests for connect_tcp."""
+
+    @pytest.mark.asyncio
+    async def test_retries_until_listening(self, server):
+        """Test that a server starting after the first attempt is still reached."""
+        port = _free_port()
+        connecting = asyncio.create_task(connect_tcp("127.0.0.1", port, timeout=5.0))
+        await asyncio.sleep(0.3)
+        await server.start(port)
+
+        _, writer = await connecting
+        writer.close()
+
+        await asyncio.wait_for(server.closed.wait(), timeout=2.0)
+        assert server.connections == 1
+
+    @pytest.mark.asyncio
+    async def test_gives_up_after_retries(self):
+        """Test that the retry count bounds the attempts before the timeout."""
+        with pytest.raises(DAPConnectionError, match=r"after 3 attempt\(s\)"):
+            await connect_tcp("127.0.0.1", _free_port(), timeout=30.0, retries=2)
+
+
+class TestRemoteAdapter:
+    """Tests for an adapter connecting instead of starting a process."""
+
+    @pytest.mark.asyncio
+    async def test_initialize_without_process(self, server):
+        """Test that a remote adapter initializes over the connection only."""
+        port = await server.start()
+        adapter = DebugpyAdapter(session_id="test_session")
+        adapter.remote = TcpTransport(port=port)
+
+        capabilities = await adapter.initialize()
+
+        assert capabilities["supportsConfigurationDoneRequest"] is True
+        assert adapter._process is None
+        assert server.commands == ["initialize"]
+        await adapter.disconnect(terminate=False)
+
+    @pytest.mark.asyncio
+    async def test_disconnect_leaves_remote_running(self, server):
+        """Test that disconnecting only closes the connection."""
+        port = await server.start()
+        adapter = DebugpyAdapter(session_id="test_session")
+        adapter.remote = TcpTransport(port=port)
+        await adapter.initialize()
+
+        await adapter.disconnect(terminate=False)
+        await asyncio.wait_for(server.closed.wait(), timeout=2.0)
+
+        assert server.commands == ["initialize"]
+
+    @pytest.mark.asyncio
+    async def test_terminate_sends_disconnect(self, server):
+        """Test that an explicit terminate still asks the remote to end the debuggee."""
+        port = await server.start()
+        adapter = DebugpyAdapter(session_id="test_session")
+        adapter.remote = TcpTransport(port=port)
+        await adapter.initialize()
+
+        await adapter.disconnect(terminate=True)
+
+        assert server.commands == ["initialize", "disconnect"]
+
+
+class TestDelveHeadless:
+    """Tests for delve connecting to a `dlv --headless` server for remote attach."""
+
+    @pytest.mark.asyncio
+    async def test_retries_until_listening(self, server):
+        """Test that a headless server starting after the first attempt is reached."""
+        port = _free_port()
+        adapter = DelveAdapter(session_id="test_session")
+        connecting = asyncio.create_task(adapter._connect_remote("127.0.0.1", port))
+        await asyncio.sleep(0.3)
+        await server.start(port)
+
+        await asyncio.wait_for(connecting, timeout=5.0)
+
+        assert server.connections == 1
+        await adapter._cleanup(terminate=False)
+
+    @pytest.mark.asyncio
+    async def test_unreachable_fails_attach(self, monkeypatch):
+        """Test that no server within the attach timeout is a launch error."""
+        monkeypatch.setattr(settings, "dap_attach_timeout_seconds", 0.3)
+        adapter = DelveAdapter(session_id="test_session")
+
+        with pytest.raises(LaunchError, match="no DAP server reachable") as exc_info:
+            await adapter._connect_remote("127.0.0.1", _free_port())
+
+        assert exc_info.value.details["host"] == "127.0.0.1"
+
+
+class TestTcpSession:
+    """Tests for the tcp transport session settings."""
+
+    def test_tcp_requires_port(self, tmp_path):
+        """Test that a tcp session without a port is rejected."""
+        with pytest.raises(ValueError, match="requires a port"):
+            SessionConfig(project_root=str(tmp_path), transport="tcp")
+
+    def test_remote_from_config(self, tmp_path):
+        """Test that the transport fields become the adapter's TcpTransport."""
+        config = SessionConfig(
+            project_root=str(tmp_path),
+            transport="tcp",
+            host="10.0.0.5",
+            port=4711,
+            connect_retries=3,
+        )
+
+        assert config.remote() == TcpTransport(host="10.0.0.5", port=4711, connect_retries=3)
+        assert SessionConfig(project_root=str(tmp_path), port=4711).remote() is None
+
+    @pytest.mark.asyncio
+    async def test_cleanup_keeps_remote_debuggee(self, tmp_path):
+        """Test that ending a tcp session doesn't terminate by default."""
+        terminated: list[bool] = []
+
+        class RecordingAdapter:
+            async def disconnect(self, terminate=False):
+                terminated.append(terminate)
+
+        session = Session(
+            session_id="test_session",
+            project_root=tmp_path,
+            remote=TcpTransport(port=4711),
+        )
+        session.adapter = RecordingAdapter()  # type: ignore[assignment]
+
+        await session.cleanup()
+
+        assert terminated == [False]
@@ -0,0 +1,178 @@
+"""Tests for debugging a single test by name."""
+
+import pytest
+
+from polybugger_mcp.adapters.base import LaunchConfig as BaseLaunchConfig
+from polybugger_mcp.adapters.delve_adapter import DelveAdapter
+from polybugger_mcp.core.exceptions import LaunchConfigError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import LaunchConfig
+from polybugger_mcp.models.events import EventType
+from polybugger_mcp.utils.test_runner import build_test_launch, missing_test_message
+
+PYTEST_NOT_FOUND = """\
+============================= test session starts ==============================
+collected 0 items
+
+============================ no tests ran in 0.01s =============================
+ERROR: not found: /proj/tests/test_api.py::test_nope
+(no match in any of [<Module test_api.py>])
+"""
+
+UNITTEST_NOT_FOUND = """\
+E
+======================================================================
+ERROR: test_nope (unittest.loader._FailedTest.test_nope)
+----------------------------------------------------------------------
+AttributeError: type object 'TestLogin' has no attribute 'test_nope'
+
+----------------------------------------------------------------------
+Ran 1 test in 0.000s
+
+FAILED (errors=1)
+"""
+
+
+class TestBuildTestLaunch:
+    """Tests for build_test_launch."""
+
+    def test_pytest(self, tmp_path):
+        """Test that pytest runs the node id as a module."""
+        kwargs = build_test_launch("pytest", "tests/test_api.py::test_login", tmp_path, ["-s"])
+
+        assert kwargs["module"] == "pytest"
+        assert kwargs["args"][0] == "tests/test_api.py::test_login"
+        assert kwargs["args"][-1] == "-s"
+        assert kwargs["adapter"] == "debugpy"
+
+    def test_unittest(self, tmp_path):
+        """Test that unittest gets the dotted name."""
+        kwargs = build_test_launch("unittest", "tests.test_api.TestLogin", tmp_path)
+
+        assert (kwargs["module"], kwargs["args"]) == ("unittest", ["tests.test_api.TestLogin"])
+
+    def test_go_uses_test_mode(self, tmp_path):
+        """Test that go tests build the package test binary and run one test."""
+        kwargs = build_test_launch("go", "./store::TestPut/empty", tmp_path)
+
+        package = str((tmp_path / "store").resolve())
+        assert (kwargs["mode"], kwargs["program"], kwargs["cwd"]) == ("test", package, package)
+        assert kwargs["args"] == ["-test.run", "^TestPut$/^empty$"]
+        assert kwargs["adapter"] == "delve"
+
+    def test_go_root_package(self, tmp_path):
+        """Test that a bare test name runs in the project root package."""
+        kwargs = build_test_launch("go", "TestMain", tmp_path)
+
+        assert kwargs["program"] == str(tmp_path.resolve())
+
+    def test_unknown_framework(self, tmp_path):
+        """Test that unknown frameworks and empty ids are rejected."""
+        with pytest.raises(LaunchConfigError, match="Unsupported test framework"):
+            build_test_launch("jest", "x", tmp_path)
+        with pytest.raises(LaunchConfigError, match="test_id is required"):
+            build_test_launch("pytest", " ", tmp_path)
+
+
+class TestMissingTestMessage:
+    """Tests for recognising runs that found no such test."""
+
+    def test_pytest(self):
+        """Test that pytest's collection error is reported."""
+        message = missing_test_message("pytest", PYTEST_NOT_FOUND)
+
+        assert message == "ERROR: not found: /proj/tests/test_api.py::test_nope"
+        assert missing_test_message("pytest", "===== no tests ran in 0.01s =====\n") == (
+            "no tests ran in 0.01s"
+        )
+
+    def test_unittest(self):
+        """Test that unittest's failed load gives the lookup error."""
+        message = missing_test_message("unittest", UNITTEST_NOT_FOUND)
+
+        assert message == "AttributeError: type object 'TestLogin' has no attribute 'test_nope'"
+
+    def test_go(self):
+        """Test that go's warning is recognised."""
+        output = "testing: warning: no tests to run\nPASS\n"
+
+        assert missing_test_message("go", output) == "testing: warning: no tests to run"
+
+    def test_failing_test_is_not_missing(self):
+        """Test that an ordinary failure is not mistaken for a missing test."""
+        output = "FAILED tests/test_api.py::test_login - assert 1 == 2\n1 failed in 0.02s\n"
+
+        assert missing_test_message("pytest", output) is None
+        assert missing_test_message("go", "--- FAIL: TestPut (0.00s)\nFAIL\n") is None
+
+
+class LaunchClient:
+    """DAP client stub that records requests and answers launch with initialized."""
+
+    def __init__(self, adapter: DelveAdapter):
+        self.adapter = adapter
+        self.requests: list[tuple[str, dict | None]] = []
+
+    async def send_request(self, command, arguments=None, timeout=None):
+        self.requests.append((command, arguments))
+        if command == "launch":
+            self.adapter._initialized_event.set()
+        return {}
+
+
+class TestDelveLaunchMode:
+    """Tests for the delve launch mode."""
+
+    @pytest.mark.asyncio
+    async def test_base_config_launches_in_debug_mode(self):
+        """Test that the language-agnostic LaunchConfig, which has no mode, still launches."""
+        adapter = DelveAdapter(session_id="test")
+        client = LaunchClient(adapter)
+        adapter._client = client
+        adapter._initialized = True
+
+        await adapter.launch(BaseLaunchConfig(program="./cmd/app"))
+
+        launch = dict(client.requests)["launch"]
+        assert (launch["mode"], launch["program"]) == ("debug", "./cmd/app")
+        assert adapter.is_launched
+
+
+class TestSessionTestRun:
+    """Tests for sessions started by launch_test."""
+
+    @pytest.fixture
+    def session(self, tmp_path):
+        """Create a running session for a pytest run."""
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.test_run = {"framework": "pytest", "test_id": "tests/test_api.py::test_nope"}
+        session._launch_config = LaunchConfig(module="pytest")
+        session._state = SessionState.RUNNING
+        return session
+
+    @pytest.mark.asyncio
+    async def test_exit_reports_missing_test(self, session):
+        """Test that exiting after a failed lookup queues testNotFound."""
+        await session._handle_event(
+            EventType.OUTPUT, {"category": "stdout", "output": PYTEST_NOT_FOUND}
+        )
+        await session._handle_event(EventType.EXITED, {"exitCode": 4})
+        await session._handle_event(EventType.TERMINATED, {})
+
+        assert session.test_not_found.startswith("ERROR: not found:")
+        events = await session.event_queue.get_all()
+        missing = [e for e in events if e.type == EventType.TEST_NOT_FOUND]
+        assert len(missing) == 1
+        assert missing[0].data["test_id"] == "tests/test_api.py::test_nope"
+        stop = await session.wait_for_stop(session.stop_count, timeout=1.0)
+        assert stop["test_not_found"] == session.test_not_found
+
+    @pytest.mark.asyncio
+    async def test_earlier_output_ignored(self, session):
+        """Test that output from before the current run isn't scanned."""
+        session._handle_output("stdout", PYTEST_NOT_FOUND)
+        session._run_output_start = session.output_buffer.last_line_number
+
+        await session._handle_event(EventType.EXITED, {"exitCode": 0})
+
+        assert session.test_not_found is None
@@ -430,6 +430,19 @@ class TestTUIFormatter:
         # Should contain truncation
         assert "..." in output
 
+    def test_format_hex_dump(self, formatter: TUIFormatter) -> None:
+        """Test hex dump lines with addresses and an ASCII gutter."""
+        data = b"hello\x00\x00\x00*" + bytes(7) + b"abc"
+        output = formatter.format_hex_dump(data, "0xc000012000")
+
+        lines = output.split("\n")
+        assert lines[0] == (
+            "000000c000012000  68 65 6c 6c 6f 00 00 00  2a 00 00 00 00 00 00 00  |hello...*.......|"
+        )
+        assert lines[1].startswith("000000c000012010  61 62 63 ")
+        assert lines[1].endswith(" |abc|")
+        assert len(lines[1]) == len(lines[0]) - 13
+
 
 class TestConvenienceFunctions:
     """Tests for module-level convenience functions."""
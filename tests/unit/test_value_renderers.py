@@ -0,0 +1,121 @@
+"""Tests for structured value rendering."""
+
+import dataclasses
+
+import pytest
+
+from polybugger_mcp.adapters.renderers import PythonRenderer, RenderError
+from polybugger_mcp.core.exceptions import DAPError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Variable
+
+
+@dataclasses.dataclass
+class Point:
+    x: int = 1
+    y: int = 2
+
+
+# Stands in for the debuggee's frame
+NAMESPACE = {
+    "mapping": {n: str(n) for n in range(50)},
+    "numbers": list(range(20)),
+    "point": Point(),
+    "plain": object(),
+    "opaque": {"key": object()},
+}
+
+
+async def evaluate(expression: str) -> str:
+    """Evaluate like debugpy's evaluate_full: the complete repr, or a DAP error."""
+    try:
+        return repr(eval(expression, {}, NAMESPACE))
+    except Exception as e:
+        raise DAPError(
+            code="DAP_REQUEST_FAILED", message=f"Traceback ...\n{type(e).__name__}: {e}"
+        ) from e
+
+
+class TestPythonRenderer:
+    """Tests for the Python renderer's templates."""
+
+    @pytest.mark.asyncio
+    async def test_summary_of_containers(self):
+        """Test that containers report their length and first items."""
+        renderer = PythonRenderer()
+
+        result = await renderer.render(evaluate, "mapping", "summary", 2, 1000)
+        assert result["summary"] == {
+            "kind": "mapping",
+            "type": "dict",
+            "len": 50,
+            "items": {"0": "'0'", "1": "'1'"},
+        }
+
+        result = await renderer.render(evaluate, "point", "summary", 1, 1000)
+        assert result["summary"]["fields"] == ["x"]
+        assert result["summary"]["field_count"] == 2
+
+    @pytest.mark.asyncio
+    async def test_summary_of_other_types(self):
+        """Test that types without a template are refused by name."""
+        with pytest.raises(RenderError, match="'object'"):
+            await PythonRenderer().render(evaluate, "plain", "summary", 10, 1000)
+
+    @pytest.mark.asyncio
+    async def test_json_capped(self):
+        """Test that JSON past the cap comes back cut off and flagged."""
+        renderer = PythonRenderer()
+
+        assert await renderer.render(evaluate, "numbers", "json", 10, 1000) == {
+            "json": list(range(20))
+        }
+        result = await renderer.render(evaluate, "numbers", "json", 10, 16)
+        assert result == {"json_text": "[0, 1, 2, 3, 4, ", "json_truncated": True}
+
+    @pytest.mark.asyncio
+    async def test_json_not_serializable(self):
+        """Test that the debuggee's encoding error is reported."""
+        with pytest.raises(RenderError, match="not JSON serializable"):
+            await PythonRenderer().render(evaluate, "opaque", "json", 10, 1000)
+
+
+class RenderingAdapter:
+    """Adapter stub evaluating against NAMESPACE."""
+
+    renderer = PythonRenderer()
+
+    def __init__(self):
+        self.frames: list[int | None] = []
+
+    async def evaluate_full(self, expression, frame_id=None):
+        self.frames.append(frame_id)
+        return await evaluate(expression)
+
+
+class TestRenderVariables:
+    """Tests for Session.render_variables."""
+
+    @pytest.mark.asyncio
+    async def test_compound_variables_only(self, tmp_path):
+        """Test that scalars are skipped and failures fall back to raw."""
+        session = Session(session_id="test_session", project_root=tmp_path)
+        session.adapter = RenderingAdapter()  # type: ignore[assignment]
+        session._state = SessionState.PAUSED
+        session._reference_frames[5] = 3
+        variables = [
+            Variable(name="numbers", value="[0, 1, ...]", variablesReference=6),
+            Variable(name="plain", value="<object>", variablesReference=7),
+            Variable(name="count", value="3", type="int"),
+        ]
+
+        rendered = await session.render_variables(5, variables, "summary")
+
+        assert set(rendered) == {"numbers", "plain"}
+        assert rendered["numbers"]["render"] == "summary"
+        assert rendered["numbers"]["summary"]["len"] == 20
+        assert rendered["plain"] == {
+            "render": "raw",
+            "render_error": "no summary for values of type 'object'",
+        }
+        assert session.adapter.frames == [3, 3, 3]
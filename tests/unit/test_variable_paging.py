@@ -0,0 +1,106 @@
+"""Tests for variable paging and reference invalidation."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import VariableNotFoundError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Scope, Variable
+from polybugger_mcp.models.events import EventType
+
+
+class PagingAdapter:
+    """Adapter stub serving a scope holding one large list."""
+
+    def __init__(self):
+        self.variable_requests: list[tuple] = []
+        self.continued = 0
+
+    async def get_scopes(self, frame_id):
+        return [Scope(name="Locals", variablesReference=1, namedVariables=1)]
+
+    async def get_variables(self, variables_ref, start=0, count=100, filter=None):
+        self.variable_requests.append((variables_ref, start, count, filter))
+        if variables_ref == 1:
+            return [
+                Variable(
+                    name="items",
+                    value="[...]",
+                    type="list",
+                    variablesReference=2,
+                    indexedVariables=10000,
+                    namedVariables=1,
+                )
+            ]
+        return [Variable(name=str(i), value=str(i)) for i in range(start, start + count)]
+
+    async def continue_execution(self, thread_id):
+        self.continued += 1
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session backed by the paging stub."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session._state = SessionState.PAUSED
+    session.adapter = PagingAdapter()
+    return session
+
+
+class TestVariablePaging:
+    """Tests for start/count passthrough and child counts."""
+
+    @pytest.mark.asyncio
+    async def test_page_arguments_forwarded(self, session):
+        """Test that start, count and filter reach the adapter."""
+        await session.get_scopes(1)
+        await session.get_variables(1)
+        page = await session.get_variables(2, start=500, count=50, filter="indexed")
+
+        assert session.adapter.variable_requests[-1] == (2, 500, 50, "indexed")
+        assert [v.name for v in page][:2] == ["500", "501"]
+
+    @pytest.mark.asyncio
+    async def test_child_counts_cached(self, session):
+        """Test that counts reported for a container are remembered by reference."""
+        await session.get_scopes(1)
+        await session.get_variables(1)
+
+        assert session.variable_counts(2) == {"named": 1, "indexed": 10000}
+        assert session.variable_counts(99) == {"named": None, "indexed": None}
+
+
+class TestReferenceInvalidation:
+    """Tests for rejecting references from before a resume."""
+
+    @pytest.mark.asyncio
+    async def test_stale_reference_after_continue(self, session):
+        """Test that references fetched before continue are rejected afterwards."""
+        await session.get_scopes(1)
+        await session.get_variables(1)
+
+        await session.continue_()
+
+        with pytest.raises(VariableNotFoundError):
+            await session.get_variables(2)
+        assert session.variable_counts(2)["indexed"] is None
+
+    @pytest.mark.asyncio
+    async def test_continued_event_invalidates(self, session):
+        """Test that a CONTINUED event from the adapter also invalidates references."""
+        await session.get_scopes(1)
+        await session._handle_event(EventType.CONTINUED, {"threadId": 1})
+
+        with pytest.raises(VariableNotFoundError):
+            await session.get_variables(1)
+
+    @pytest.mark.asyncio
+    async def test_reference_reissued_after_stop(self, session):
+        """Test that a reference handed out again after stopping is usable."""
+        await session.get_scopes(1)
+        await session.continue_()
+        session._state = SessionState.PAUSED
+
+        await session.get_scopes(1)
+        variables = await session.get_variables(1)
+
+        assert variables[0].name == "items"
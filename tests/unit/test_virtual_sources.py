@@ -0,0 +1,125 @@
+"""Tests for sources fetched from the adapter by sourceReference."""
+
+import pytest
+
+from polybugger_mcp.core.exceptions import DAPError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Scope, Source, StackFrame
+
+FROZEN = "\n".join(f"frozen line {n}" for n in range(1, 11))
+
+
+class VirtualSourceAdapter:
+    """Adapter stub stopped in a frozen module with no file on disk."""
+
+    def __init__(self, path: str):
+        self.path = path
+        self.source_requests: list[int] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [
+            StackFrame(
+                id=10,
+                name="_find_and_load",
+                line=6,
+                source=Source(name="<frozen importlib._bootstrap>", sourceReference=3),
+            ),
+            StackFrame(id=11, name="main", line=2, source=Source(path=self.path)),
+        ]
+
+    async def get_source(self, source_reference):
+        self.source_requests.append(source_reference)
+        if source_reference != 3:
+            raise DAPError("SOURCE", "Invalid sourceReference")
+        return {"content": FROZEN, "mimeType": "text/x-python"}
+
+    async def get_scopes(self, frame_id):
+        return [Scope(name="Locals", variablesReference=1, expensive=False)]
+
+    async def get_variables(self, variables_ref, start=0, count=100, filter=None):
+        return []
+
+    async def disconnect(self, terminate=False):
+        pass
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a session paused in a frame with a virtual source."""
+    script = tmp_path / "app.py"
+    script.write_text("import json\nmain()\n")
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = VirtualSourceAdapter(str(script))
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+class TestGetSource:
+    """Tests for Session.get_source."""
+
+    @pytest.mark.asyncio
+    async def test_fetched_once(self, session):
+        """Test that content is cached per reference."""
+        first = await session.get_source(3)
+        second = await session.get_source(3)
+
+        assert first["content"] == FROZEN
+        assert first["mime_type"] == "text/x-python"
+        assert first["line_count"] == 10
+        assert second is first
+        assert session.adapter.source_requests == [3]
+
+    @pytest.mark.asyncio
+    async def test_unknown_reference(self, session):
+        """Test that an adapter error for a bad reference is raised, not cached."""
+        with pytest.raises(DAPError):
+            await session.get_source(99)
+
+        assert 99 not in session._source_cache
+
+    @pytest.mark.asyncio
+    async def test_cleared_on_cleanup(self, session):
+        """Test that ending the session drops the cached content."""
+        await session.get_source(3)
+
+        await session.cleanup()
+
+        assert session._source_cache == {}
+
+
+class TestVirtualFrames:
+    """Tests for frames whose source comes from a sourceReference."""
+
+    @pytest.mark.asyncio
+    async def test_source_kind(self, session):
+        """Test that virtual frames are told apart from files on disk."""
+        frames = await session.get_stack_trace()
+
+        assert [session.source_kind(f) for f in frames] == ["virtual", "file"]
+        assert session.source_kind(StackFrame(id=1, name="f", line=1)) == "unavailable"
+
+    @pytest.mark.asyncio
+    async def test_snapshot_uses_fetched_source(self, session):
+        """Test that the stop snapshot shows lines of a virtual top frame."""
+        snapshot = await session.get_stop_snapshot(context_lines=1)
+
+        assert snapshot["frames"][0]["source_kind"] == "virtual"
+        assert snapshot["frames"][0]["source_reference"] == 3
+        assert snapshot["source"] == {
+            "file": None,
+            "source_reference": 3,
+            "line": 6,
+            "start_line": 5,
+            "lines": ["frozen line 5", "frozen line 6", "frozen line 7"],
+        }
+
+    @pytest.mark.asyncio
+    async def test_call_chain_context(self, session):
+        """Test that the call chain includes context from virtual sources."""
+        result = await session.get_call_chain(context_lines=0)
+
+        top, caller = result["call_chain"]
+        assert top["source_kind"] == "virtual"
+        assert top["source"] == "frozen line 6"
+        assert caller["source"] == "main()"
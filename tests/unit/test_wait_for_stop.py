@@ -0,0 +1,148 @@
+"""Tests for waiting on the next stop after resuming."""
+
+import asyncio
+
+import pytest
+
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import Source, StackFrame
+from polybugger_mcp.models.events import EventType
+
+
+class RunningAdapter:
+    """Adapter stub whose resumes return before the program stops."""
+
+    is_launched = True
+
+    def __init__(self):
+        self.paused: list[int] = []
+
+    async def continue_execution(self, thread_id):
+        pass
+
+    async def pause(self, thread_id):
+        self.paused.append(thread_id)
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=1, name="loop", line=12, column=1, source=Source(path="/app.py"))]
+
+    async def get_exception_info(self, thread_id):
+        await asyncio.sleep(0)  # Answered after other events have been handled
+        return {
+            "exceptionId": "ValueError",
+            "description": "bad input",
+            "breakMode": "unhandled",
+            "details": {"fullTypeName": "builtins.ValueError", "message": "bad input"},
+        }
+
+
+@pytest.fixture
+def session(tmp_path):
+    """Create a paused session with a stub adapter."""
+    session = Session(session_id="test_session", project_root=tmp_path)
+    session.adapter = RunningAdapter()
+    session._state = SessionState.PAUSED
+    session.current_thread_id = 1
+    return session
+
+
+async def stop_later(session: Session, reason: str = "breakpoint", delay: float = 0.05) -> None:
+    await asyncio.sleep(delay)
+    await session._handle_event(EventType.STOPPED, {"reason": reason, "threadId": 3})
+
+
+class TestWaitForStop:
+    """Tests for Session.wait_for_stop."""
+
+    @pytest.mark.asyncio
+    async def test_returns_stop_details(self, session):
+        """Test that a stop after the resume is reported with its location."""
+        stops_before = session.stop_count
+        await session.continue_()
+        assert session.state == SessionState.RUNNING
+
+        asyncio.create_task(stop_later(session))
+        result = await session.wait_for_stop(stops_before, timeout=5)
+
+        assert result["status"] == "stopped"
+        assert result["reason"] == "breakpoint"
+        assert result["thread_id"] == 3
+        assert result["location"] == {"file": "/app.py", "line": 12, "function": "loop"}
+
+    @pytest.mark.asyncio
+    async def test_stop_before_wait_not_missed(self, session):
+        """Test that a stop arriving before the wait starts still counts."""
+        stops_before = session.stop_count
+        await session.continue_()
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+
+        result = await session.wait_for_stop(stops_before, timeout=0.1)
+
+        assert result["status"] == "stopped"
+        assert result["reason"] == "step"
+
+    @pytest.mark.asyncio
+    async def test_exception_stop_includes_exception(self, session):
+        """Test that an exception stop is reported with its exception info fetched."""
+        stops_before = session.stop_count
+        await session.continue_()
+
+        asyncio.create_task(stop_later(session, reason="exception"))
+        result = await session.wait_for_stop(stops_before, timeout=5)
+
+        assert result["reason"] == "exception"
+        assert result["exception"]["type"] == "builtins.ValueError"
+        assert result["exception"]["message"] == "bad input"
+
+    @pytest.mark.asyncio
+    async def test_timeout_still_running(self, session):
+        """Test that a program that never stops reports still_running."""
+        stops_before = session.stop_count
+        await session.continue_()
+
+        result = await session.wait_for_stop(stops_before, timeout=0.05)
+
+        assert result == {"status": "still_running", "state": "running"}
+
+    @pytest.mark.asyncio
+    async def test_concurrent_waiters_share_stop(self, session):
+        """Test that one stop wakes every waiter instead of being consumed by one."""
+        stops_before = session.stop_count
+        await session.continue_()
+
+        waiters = [
+            asyncio.create_task(session.wait_for_stop(stops_before, timeout=5)) for _ in range(3)
+        ]
+        await stop_later(session, reason="pause")
+        results = await asyncio.gather(*waiters)
+
+        assert [r["reason"] for r in results] == ["pause", "pause", "pause"]
+
+    @pytest.mark.asyncio
+    async def test_exit_ends_wait(self, session):
+        """Test that the program exiting ends the wait."""
+        stops_before = session.stop_count
+        await session.continue_()
+
+        async def exit_later() -> None:
+            await asyncio.sleep(0.05)
+            await session._handle_event(EventType.TERMINATED, {})
+
+        asyncio.create_task(exit_later())
+        result = await session.wait_for_stop(stops_before, timeout=5)
+
+        assert result == {"status": "terminated", "state": "terminated"}
+
+    @pytest.mark.asyncio
+    async def test_pause_runaway_program(self, session):
+        """Test pausing a running program and waiting for it to stop."""
+        await session.continue_()
+        stops_before = session.stop_count
+
+        await session.pause()
+        asyncio.create_task(stop_later(session, reason="pause"))
+        result = await session.wait_for_stop(stops_before, timeout=5)
+
+        assert session.adapter.paused == [1]
+        assert result["reason"] == "pause"
+        assert session.state == SessionState.PAUSED
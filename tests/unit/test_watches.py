@@ -1,8 +1,29 @@
 """Tests for watch expression functionality."""
 
+import asyncio
+
 import pytest
 
-from polybugger_mcp.core.session import Session
+from polybugger_mcp.core.exceptions import WatchNotFoundError
+from polybugger_mcp.core.session import Session, SessionState
+from polybugger_mcp.models.dap import StackFrame
+from polybugger_mcp.models.events import EventType
+
+
+class WatchAdapter:
+    """Adapter stub that evaluates watches in a fixed top frame."""
+
+    def __init__(self):
+        self.evaluated: list[tuple[str, int | None, str]] = []
+
+    async def get_stack_trace(self, thread_id, start_frame=0, levels=20):
+        return [StackFrame(id=7, name="f", line=3)]
+
+    async def evaluate(self, expression, frame_id=None, context="repl"):
+        self.evaluated.append((expression, frame_id, context))
+        if expression == "missing":
+            raise RuntimeError("name 'missing' is not defined")
+        return {"result": "42", "type": "int", "variablesReference": 0}
 
 
 @pytest.fixture
@@ -81,6 +102,55 @@ class TestWatchExpressions:
         watches.append("y")
         assert "y" not in session.list_watches()
 
+    def test_watch_ids_are_stable(self, session: Session):
+        """Test that removing a watch doesn't renumber the others."""
+        session.add_watch("x")
+        session.add_watch("y")
+        y_id = session.watch_id("y")
+
+        session.remove_watch_by_id(session.watch_id("x"))
+        session.add_watch("z")
+
+        assert session.watch_id("y") == y_id
+        assert session.watch_id("z") not in (None, y_id)
+        assert [w["expression"] for w in session.describe_watches()] == ["y", "z"]
+
+    def test_remove_unknown_id(self, session: Session):
+        """Test that removing an unknown watch id raises instead of passing silently."""
+        session.add_watch("x")
+
+        with pytest.raises(WatchNotFoundError):
+            session.remove_watch_by_id(99)
+
+        assert session.list_watches() == ["x"]
+
+
+class TestWatchesOnStop:
+    """Tests for re-evaluating watches when the session stops."""
+
+    @pytest.mark.asyncio
+    async def test_stop_event_includes_watches(self, session: Session):
+        """Test that watches are evaluated in the top frame and attached to the stop."""
+        session._state = SessionState.RUNNING
+        session.adapter = WatchAdapter()
+        session.add_watch("x")
+        session.add_watch("missing")
+
+        await session._handle_event(EventType.STOPPED, {"reason": "step", "threadId": 1})
+        await asyncio.gather(*session._background_tasks)
+
+        events = await session.event_queue.get_all()
+        watches = events[0].data["watches"]
+        assert watches[0]["result"] == "42"
+        assert watches[0]["error"] is None
+        assert watches[1]["result"] is None
+        assert "not defined" in watches[1]["error"]
+        assert session.adapter.evaluated == [("x", 7, "watch"), ("missing", 7, "watch")]
+
+        described = session.describe_watches()
+        assert described[0]["id"] == session.watch_id("x")
+        assert described[0]["type"] == "int"
+
 
 class TestWatchPersistence:
     """Tests for watch expression persistence in session recovery."""